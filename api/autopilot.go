@@ -3,7 +3,10 @@ package api
 import (
 	"errors"
 	"fmt"
+	"math"
+	"slices"
 
+	"go.sia.tech/core/types"
 	"go.sia.tech/renterd/v2/internal/utils"
 )
 
@@ -15,6 +18,32 @@ var (
 	// ErrInvalidReleaseVersion is returned if the version is an invalid release
 	// string.
 	ErrInvalidReleaseVersion = errors.New("invalid release version")
+
+	// ErrSpendingBudgetExceeded is returned when spending funds would exceed
+	// the spending budget of the current period.
+	ErrSpendingBudgetExceeded = errors.New("spending budget of the current period exceeded")
+
+	// ErrInvalidCostForecastRequest is returned if a cost forecast is
+	// requested for an invalid workload.
+	ErrInvalidCostForecastRequest = errors.New("invalid cost forecast request")
+
+	// ErrNoUsableHosts is returned if there are no usable hosts to base a
+	// cost forecast on.
+	ErrNoUsableHosts = errors.New("no usable hosts")
+
+	// ErrContractSetNotFound is returned if a contract set isn't configured
+	// in the autopilot config.
+	ErrContractSetNotFound = errors.New("contract set not found")
+
+	// ErrContractSetHostNotAllowed is returned if a host is filtered out by
+	// the host filter of a contract set.
+	ErrContractSetHostNotAllowed = errors.New("host is not allowed by the contract set")
+)
+
+const (
+	// CostForecastMaxPeriods is the maximum number of periods that can be
+	// forecasted at once.
+	CostForecastMaxPeriods = 120
 )
 
 type (
@@ -23,6 +52,30 @@ type (
 		Enabled   bool            `json:"enabled"`
 		Contracts ContractsConfig `json:"contracts"`
 		Hosts     HostsConfig     `json:"hosts"`
+
+		// ContractSets contains named contract sets that are maintained
+		// alongside the default set, which is configured by Contracts.
+		ContractSets map[string]ContractSetConfig `json:"contractSets,omitempty"`
+	}
+
+	// ContractSetConfig configures a named contract set. The autopilot
+	// maintains the contracts of every set independently, the spending budget
+	// and renewal cap of the default set apply to all sets.
+	ContractSetConfig struct {
+		Contracts ContractsConfig       `json:"contracts"`
+		Hosts     ContractSetHostFilter `json:"hosts"`
+	}
+
+	// ContractSetHostFilter restricts the hosts the autopilot forms contracts
+	// with for a contract set.
+	ContractSetHostFilter struct {
+		// Labels contains the labels of which a host needs to have at least
+		// one, if empty hosts aren't filtered by their labels.
+		Labels []string `json:"labels,omitempty"`
+
+		// MaxStoragePrice is the maximum storage price of a host in the set,
+		// a zero value doesn't limit the storage price.
+		MaxStoragePrice types.Currency `json:"maxStoragePrice"`
 	}
 
 	// ContractsConfig contains all contract settings used in the autopilot.
@@ -34,6 +87,20 @@ type (
 		Upload      uint64 `json:"upload"`
 		Storage     uint64 `json:"storage"`
 		Prune       bool   `json:"prune"`
+
+		// Budget caps the funds committed to contracts that are formed,
+		// renewed or refreshed within a period, a zero budget disables the
+		// cap.
+		Budget types.Currency `json:"budget"`
+
+		// StaggerRenewals spreads out the renewals of contracts that end at
+		// the same height over the first half of the renew window.
+		StaggerRenewals bool `json:"staggerRenewals"`
+
+		// MaxRenewalsPerCycle caps the number of contracts that are renewed
+		// in a single maintenance cycle, a zero value disables the cap.
+		// Contracts that are no longer usable are renewed regardless.
+		MaxRenewalsPerCycle uint64 `json:"maxRenewalsPerCycle"`
 	}
 
 	// HostsConfig contains all hosts settings used in the autopilot.
@@ -41,6 +108,47 @@ type (
 		MaxConsecutiveScanFailures uint64 `json:"maxConsecutiveScanFailures"`
 		MaxDowntimeHours           uint64 `json:"maxDowntimeHours"`
 		MinProtocolVersion         string `json:"minProtocolVersion"`
+
+		// ScoreWeights configures how much every component of a host's score
+		// contributes to the total score, if not set the default weights are
+		// used.
+		ScoreWeights *HostScoreWeights `json:"scoreWeights,omitempty"`
+
+		// LabelRules configures how hosts are treated depending on the
+		// labels an operator attached to them.
+		LabelRules *HostLabelRules `json:"labelRules,omitempty"`
+	}
+
+	// HostLabelRules configures how the autopilot treats labelled hosts.
+	HostLabelRules struct {
+		// NoNewContracts contains labels of hosts the autopilot doesn't
+		// form new contracts with, existing contracts are kept and renewed.
+		NoNewContracts []string `json:"noNewContracts,omitempty"`
+	}
+
+	// HostScoreWeights contains the weights of the components of a host's
+	// score.
+	HostScoreWeights struct {
+		Age              ScoreWeight `json:"age"`
+		Collateral       ScoreWeight `json:"collateral"`
+		Interactions     ScoreWeight `json:"interactions"`
+		Latency          ScoreWeight `json:"latency"`
+		Prices           ScoreWeight `json:"prices"`
+		StorageRemaining ScoreWeight `json:"storageRemaining"`
+		Throughput       ScoreWeight `json:"throughput"`
+		Uptime           ScoreWeight `json:"uptime"`
+	}
+
+	// ScoreWeight describes how a sub-score s in the range [0, 1] affects the
+	// host's score. The sub-score is first raised to the power of the
+	// exponent and then interpolated linearly between 1 and the result
+	// depending on the weight. A weight of 0 therefore disables the
+	// component while a weight of 1 applies it in full:
+	//
+	//	1 - weight + weight * s^exponent
+	ScoreWeight struct {
+		Weight   float64 `json:"weight"`
+		Exponent float64 `json:"exponent"`
 	}
 )
 
@@ -62,6 +170,20 @@ var (
 			MinProtocolVersion:         "1.6.0",
 		},
 	}
+
+	// DefaultHostScoreWeights are the weights used when the autopilot config
+	// doesn't specify any. They apply every component in full, except for the
+	// latency and throughput, which are opt-in.
+	DefaultHostScoreWeights = HostScoreWeights{
+		Age:              ScoreWeight{Weight: 1, Exponent: 1},
+		Collateral:       ScoreWeight{Weight: 1, Exponent: 1},
+		Interactions:     ScoreWeight{Weight: 1, Exponent: 1},
+		Latency:          ScoreWeight{Weight: 0, Exponent: 1},
+		Prices:           ScoreWeight{Weight: 1, Exponent: 1},
+		StorageRemaining: ScoreWeight{Weight: 1, Exponent: 1},
+		Throughput:       ScoreWeight{Weight: 0, Exponent: 1},
+		Uptime:           ScoreWeight{Weight: 1, Exponent: 1},
+	}
 )
 
 type (
@@ -93,6 +215,48 @@ type (
 		BuildState
 	}
 
+	// ContractReconcileResult contains the result of reconciling a single
+	// contract, if reconciliation failed Error is set.
+	ContractReconcileResult struct {
+		ContractID types.FileContractID `json:"contractID"`
+		HostKey    types.PublicKey      `json:"hostKey"`
+		ContractReconcileResponse
+		Error string `json:"error,omitempty"`
+	}
+
+	// ContractsReconcileResponse is the response type for the
+	// /contracts/reconcile endpoint.
+	ContractsReconcileResponse struct {
+		Contracts []ContractReconcileResult `json:"contracts"`
+		Missing   uint64                    `json:"missing"`
+		Prunable  uint64                    `json:"prunable"`
+	}
+
+	// MigrationEstimate contains the estimated amount of data that needs to be
+	// transferred to migrate a set of slabs and what that would cost at
+	// current host prices.
+	MigrationEstimate struct {
+		Slabs         uint64         `json:"slabs"`
+		Unrepairable  uint64         `json:"unrepairable"`
+		DownloadBytes uint64         `json:"downloadBytes"`
+		UploadBytes   uint64         `json:"uploadBytes"`
+		DownloadCost  types.Currency `json:"downloadCost"`
+		UploadCost    types.Currency `json:"uploadCost"`
+	}
+
+	// MigrationEstimateResponse is the response type for the
+	// /migrations/estimate endpoint.
+	MigrationEstimateResponse struct {
+		MigrationEstimate
+		DurationMS DurationMS `json:"durationMs"`
+
+		// Buckets contains the estimate per bucket. The buckets overlap, a
+		// slab that is referenced by objects in multiple buckets is counted in
+		// full for each of them, so the estimates of the buckets can add up to
+		// more than the total.
+		Buckets map[string]MigrationEstimate `json:"buckets"`
+	}
+
 	ConfigEvaluationRequest struct {
 		AutopilotConfig    AutopilotConfig    `json:"autopilotConfig"`
 		GougingSettings    GougingSettings    `json:"gougingSettings"`
@@ -121,9 +285,156 @@ type (
 			NotScanned            uint64 `json:"notScanned"`
 		} `json:"unusable"`
 		Recommendation *ConfigRecommendation `json:"recommendation,omitempty"`
+		Selection      ConfigSelectionDiff   `json:"selection"`
+	}
+
+	// ConfigSelectionDiff describes how the hosts the autopilot prefers
+	// change when the evaluated config replaces the current one. The
+	// preferred hosts are the usable hosts with the highest scores, as many as
+	// the config's contract amount.
+	ConfigSelectionDiff struct {
+		Added     []types.PublicKey `json:"added"`
+		Removed   []types.PublicKey `json:"removed"`
+		Unchanged uint64            `json:"unchanged"`
+	}
+
+	// CostForecastRequest is the request type for the /forecast endpoint, it
+	// describes a hypothetical workload.
+	CostForecastRequest struct {
+		// Storage is the amount of data stored in bytes, excluding
+		// redundancy.
+		Storage uint64 `json:"storage"`
+
+		// Upload and Download are the number of bytes uploaded and
+		// downloaded per month, excluding redundancy.
+		Upload   uint64 `json:"upload"`
+		Download uint64 `json:"download"`
+
+		// Period is the length of a contract's period in blocks and Periods
+		// the number of periods to forecast. The period defaults to the one
+		// in the contracts config.
+		Period  uint64 `json:"period"`
+		Periods uint64 `json:"periods"`
+
+		// Redundancy defaults to the redundancy of the upload settings.
+		Redundancy RedundancySettings `json:"redundancy"`
+	}
+
+	// CostForecastResponse is the response type for the /forecast endpoint.
+	CostForecastResponse struct {
+		Hosts     uint64                `json:"hosts"`
+		Usable    uint64                `json:"usable"`
+		Contracts uint64                `json:"contracts"`
+		Prices    HostPriceDistribution `json:"prices"`
+
+		Periods []CostForecastPeriod `json:"periods"`
+		Total   types.Currency       `json:"total"`
+	}
+
+	// CostForecast breaks down the forecasted cost of a period.
+	CostForecast struct {
+		ContractFees types.Currency `json:"contractFees"`
+		SiafundFees  types.Currency `json:"siafundFees"`
+		Storage      types.Currency `json:"storage"`
+		Upload       types.Currency `json:"upload"`
+		Download     types.Currency `json:"download"`
+		Total        types.Currency `json:"total"`
+	}
+
+	// CostForecastPeriod contains the forecasted cost of a single period.
+	CostForecastPeriod struct {
+		StartHeight uint64 `json:"startHeight"`
+		EndHeight   uint64 `json:"endHeight"`
+		CostForecast
+	}
+
+	// HostPriceDistribution describes the distribution of the prices of the
+	// usable hosts.
+	HostPriceDistribution struct {
+		ContractPrice PriceDistribution `json:"contractPrice"`
+		Collateral    PriceDistribution `json:"collateral"`
+		StoragePrice  PriceDistribution `json:"storagePrice"`
+		IngressPrice  PriceDistribution `json:"ingressPrice"`
+		EgressPrice   PriceDistribution `json:"egressPrice"`
+	}
+
+	// PriceDistribution describes the distribution of a price.
+	PriceDistribution struct {
+		Min    types.Currency `json:"min"`
+		Median types.Currency `json:"median"`
+		Max    types.Currency `json:"max"`
+	}
+
+	// MaintenanceSimulationRequest is the request type for the
+	// /maintenance/simulate endpoint. The config and gouging settings
+	// default to the ones currently in use.
+	MaintenanceSimulationRequest struct {
+		AutopilotConfig *AutopilotConfig `json:"autopilotConfig,omitempty"`
+		GougingSettings *GougingSettings `json:"gougingSettings,omitempty"`
+	}
+
+	// MaintenanceSimulationResponse is the response type for the
+	// /maintenance/simulate endpoint, it contains the actions a round of
+	// contract maintenance would perform.
+	MaintenanceSimulationResponse struct {
+		// Skipped contains the reason the maintenance would be skipped, if
+		// it would be.
+		Skipped string `json:"skipped,omitempty"`
+
+		Archived  []SimulatedContractUpdate  `json:"archived"`
+		MarkedBad []SimulatedContractUpdate  `json:"markedBad"`
+		Renewed   []SimulatedContractFunding `json:"renewed"`
+		Refreshed []SimulatedContractFunding `json:"refreshed"`
+		Formed    []SimulatedContractFunding `json:"formed"`
+	}
+
+	// SimulatedContractUpdate describes a contract that would be archived
+	// or marked as bad.
+	SimulatedContractUpdate struct {
+		ContractID  types.FileContractID `json:"contractID"`
+		HostKey     types.PublicKey      `json:"hostKey"`
+		ContractSet string               `json:"contractSet,omitempty"`
+		Reason      string               `json:"reason"`
+	}
+
+	// SimulatedContractFunding describes a contract that would be formed,
+	// renewed or refreshed, the contract ID is zero for formations.
+	SimulatedContractFunding struct {
+		ContractID     types.FileContractID `json:"contractID"`
+		HostKey        types.PublicKey      `json:"hostKey"`
+		ContractSet    string               `json:"contractSet,omitempty"`
+		Reason         string               `json:"reason,omitempty"`
+		EndHeight      uint64               `json:"endHeight"`
+		RenterFunds    types.Currency       `json:"renterFunds"`
+		HostCollateral types.Currency       `json:"hostCollateral"`
 	}
 )
 
+// Add adds the given estimate to the estimate.
+func (me MigrationEstimate) Add(o MigrationEstimate) MigrationEstimate {
+	return MigrationEstimate{
+		Slabs:         me.Slabs + o.Slabs,
+		Unrepairable:  me.Unrepairable + o.Unrepairable,
+		DownloadBytes: me.DownloadBytes + o.DownloadBytes,
+		UploadBytes:   me.UploadBytes + o.UploadBytes,
+		DownloadCost:  me.DownloadCost.Add(o.DownloadCost),
+		UploadCost:    me.UploadCost.Add(o.UploadCost),
+	}
+}
+
+// Validate returns an error if the cost forecast request is not considered
+// valid.
+func (r CostForecastRequest) Validate() error {
+	if r.Period == 0 {
+		return fmt.Errorf("%w: period must be greater than 0", ErrInvalidCostForecastRequest)
+	} else if r.Periods == 0 || r.Periods > CostForecastMaxPeriods {
+		return fmt.Errorf("%w: periods must be between 1 and %d", ErrInvalidCostForecastRequest, CostForecastMaxPeriods)
+	} else if err := r.Redundancy.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCostForecastRequest, err)
+	}
+	return nil
+}
+
 func (cc ContractsConfig) Validate() error {
 	if cc.Period == 0 {
 		return errors.New("period must be greater than 0")
@@ -133,11 +444,119 @@ func (cc ContractsConfig) Validate() error {
 	return nil
 }
 
+// ContractSet returns the config of the contract set with given name, the
+// empty name refers to the default set.
+func (ap AutopilotConfig) ContractSet(name string) (ContractSetConfig, bool) {
+	if name == "" {
+		return ContractSetConfig{Contracts: ap.Contracts}, true
+	}
+	set, ok := ap.ContractSets[name]
+	return set, ok
+}
+
+// ValidateContractSets returns an error if any of the given contract sets is
+// invalid.
+func ValidateContractSets(sets map[string]ContractSetConfig) error {
+	for name, set := range sets {
+		if name == "" {
+			return errors.New("contract set name can't be empty")
+		} else if err := set.Contracts.Validate(); err != nil {
+			return fmt.Errorf("contract set %q is invalid: %w", name, err)
+		} else if set.Contracts.Amount == 0 {
+			return fmt.Errorf("contract set %q is invalid: amount must be greater than 0", name)
+		} else if _, err := NormalizeHostLabels(set.Hosts.Labels); err != nil {
+			return fmt.Errorf("contract set %q is invalid: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Allows returns whether the autopilot may form contracts with the given host
+// for the contract set.
+func (f ContractSetHostFilter) Allows(h Host) bool {
+	if len(f.Labels) > 0 && !slices.ContainsFunc(h.Labels, func(label string) bool {
+		return slices.Contains(f.Labels, label)
+	}) {
+		return false
+	}
+	return f.MaxStoragePrice.IsZero() || h.V2Settings.Prices.StoragePrice.Cmp(f.MaxStoragePrice) <= 0
+}
+
 func (hc HostsConfig) Validate() error {
 	if hc.MaxDowntimeHours > 99*365*24 {
 		return ErrMaxDowntimeHoursTooHigh
 	} else if hc.MinProtocolVersion != "" && !utils.IsVersion(hc.MinProtocolVersion) {
 		return fmt.Errorf("%w: '%s'", ErrInvalidReleaseVersion, hc.MinProtocolVersion)
+	} else if hc.ScoreWeights != nil {
+		if err := hc.ScoreWeights.Validate(); err != nil {
+			return fmt.Errorf("invalid score weights: %w", err)
+		}
+	}
+	if hc.LabelRules != nil {
+		if _, err := NormalizeHostLabels(hc.LabelRules.NoNewContracts); err != nil {
+			return fmt.Errorf("invalid label rules: %w", err)
+		}
+	}
+	return nil
+}
+
+// AllowsNewContracts returns whether the autopilot is allowed to form new
+// contracts with a host that has the given labels.
+func (r *HostLabelRules) AllowsNewContracts(labels []string) bool {
+	if r == nil {
+		return true
+	}
+	for _, label := range labels {
+		if slices.Contains(r.NoNewContracts, label) {
+			return false
+		}
+	}
+	return true
+}
+
+// Weights returns the configured score weights or the default weights if none
+// are configured.
+func (hc HostsConfig) Weights() HostScoreWeights {
+	if hc.ScoreWeights == nil {
+		return DefaultHostScoreWeights
+	}
+	return *hc.ScoreWeights
+}
+
+func (w HostScoreWeights) Validate() error {
+	for name, sw := range map[string]ScoreWeight{
+		"age":              w.Age,
+		"collateral":       w.Collateral,
+		"interactions":     w.Interactions,
+		"latency":          w.Latency,
+		"prices":           w.Prices,
+		"storageRemaining": w.StorageRemaining,
+		"throughput":       w.Throughput,
+		"uptime":           w.Uptime,
+	} {
+		if err := sw.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Apply applies the weight to the given sub-score. A sub-score of 0 indicates
+// a severe issue with the host and is never weighted.
+func (sw ScoreWeight) Apply(score float64) float64 {
+	if score <= 0 {
+		return 0
+	}
+	return 1 - sw.Weight + sw.Weight*math.Pow(score, sw.Exponent)
+}
+
+func (sw ScoreWeight) Validate() error {
+	if math.IsNaN(sw.Weight) || sw.Weight < 0 || sw.Weight > 1 {
+		return fmt.Errorf("weight must be between 0 and 1, got %v", sw.Weight)
+	} else if sw.Weight == 0 {
+		return nil // disabled, the exponent is irrelevant
+	} else if math.IsNaN(sw.Exponent) || math.IsInf(sw.Exponent, 0) || sw.Exponent <= 0 {
+		return fmt.Errorf("exponent must be greater than 0, got %v", sw.Exponent)
 	}
 	return nil
 }
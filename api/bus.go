@@ -17,6 +17,7 @@ var (
 	ErrInvalidDatabase       = errors.New("invalid database type")
 	ErrBackupNotSupported    = errors.New("backups not supported for used database")
 	ErrExplorerDisabled      = errors.New("explorer is disabled")
+	ErrGeoIPDisabled         = errors.New("no GeoIP database configured")
 )
 
 type (
@@ -31,8 +32,9 @@ type (
 type (
 	// UploadParams contains the metadata needed by a worker to upload an object.
 	UploadParams struct {
-		CurrentHeight uint64
-		UploadPacking bool
+		CurrentHeight     uint64
+		UploadPacking     bool
+		PlacementSettings PlacementSettings
 		GougingParams
 	}
 
@@ -79,6 +81,21 @@ type (
 		URL     string `json:"url,omitempty"`
 	}
 
+	// HostBenchmarkRequest is the request type for the /host/benchmark
+	// endpoint.
+	HostBenchmarkRequest struct {
+		Timeout DurationMS `json:"timeout"`
+	}
+
+	// HostBenchmarkResponse is the response type for the /host/benchmark
+	// endpoint.
+	HostBenchmarkResponse struct {
+		UploadThroughput   uint64     `json:"uploadThroughput"`
+		DownloadThroughput uint64     `json:"downloadThroughput"`
+		TimeToFirstByte    DurationMS `json:"timeToFirstByte"`
+		BenchmarkError     string     `json:"benchmarkError,omitempty"`
+	}
+
 	// HostScanRequest is the request type for the /host/scan endpoint.
 	HostScanRequest struct {
 		Timeout DurationMS `json:"timeout"`
@@ -96,5 +113,7 @@ type (
 		Enabled   *bool            `json:"enabled"`
 		Contracts *ContractsConfig `json:"contracts"`
 		Hosts     *HostsConfig     `json:"hosts"`
+
+		ContractSets *map[string]ContractSetConfig `json:"contractSets,omitempty"`
 	}
 )
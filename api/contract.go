@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"go.sia.tech/core/types"
 )
@@ -31,6 +32,10 @@ var (
 	// ErrContractNotFound is returned when a contract can't be retrieved from
 	// the database.
 	ErrContractNotFound = errors.New("couldn't find contract")
+
+	// ErrInvalidContractRenewalSettings is returned when the renewal settings
+	// of a manual contract are invalid.
+	ErrInvalidContractRenewalSettings = errors.New("invalid contract renewal settings")
 )
 
 type ContractState string
@@ -64,11 +69,36 @@ type (
 		InitialRenterFunds types.Currency   `json:"initialRenterFunds"`
 		Spending           ContractSpending `json:"spending"`
 
+		// RenterCost is what the renter paid to form, renew or refresh the
+		// contract, it excludes the funds that were rolled over from the
+		// contract it was renewed from
+		RenterCost types.Currency `json:"renterCost"`
+
+		// ContractSet is the named contract set the contract belongs to, it's
+		// empty for contracts in the default set
+		ContractSet string `json:"contractSet,omitempty"`
+
+		// manual contracts are not maintained by the autopilot, they are only
+		// renewed if renewal settings are configured
+		Manual  bool                     `json:"manual"`
+		Renewal *ContractRenewalSettings `json:"renewal,omitempty"`
+
 		// following fields are only set on archived contracts
 		ArchivalReason string               `json:"archivalReason,omitempty"`
 		RenewedTo      types.FileContractID `json:"renewedTo,omitempty"`
 	}
 
+	// ContractRenewalSettings configures how the autopilot renews a manual
+	// contract. The contract is renewed once it's within the renew window and
+	// the renewal ends a period plus renew window after the height at which
+	// it's renewed.
+	ContractRenewalSettings struct {
+		Period           uint64         `json:"period"`
+		RenewWindow      uint64         `json:"renewWindow"`
+		RenterFunds      types.Currency `json:"renterFunds"`
+		MinNewCollateral types.Currency `json:"minNewCollateral"`
+	}
+
 	// ContractPrunableData wraps a contract's size information with its id.
 	ContractPrunableData struct {
 		ID types.FileContractID `json:"id"`
@@ -126,6 +156,21 @@ type (
 		HostKey        types.PublicKey `json:"hostKey"`
 		RenterFunds    types.Currency  `json:"renterFunds"`
 		RenterAddress  types.Address   `json:"renterAddress"`
+
+		// ContractSet is the contract set the formed contract is added to.
+		ContractSet string `json:"contractSet,omitempty"`
+
+		// Manual and Renewal optionally mark the formed contract as manual,
+		// see ContractManualRequest.
+		Manual  bool                     `json:"manual,omitempty"`
+		Renewal *ContractRenewalSettings `json:"renewal,omitempty"`
+	}
+
+	// ContractManualRequest is the request type for the /contract/:id/manual
+	// endpoint.
+	ContractManualRequest struct {
+		Manual  bool                     `json:"manual"`
+		Renewal *ContractRenewalSettings `json:"renewal,omitempty"`
 	}
 
 	// ContractKeepaliveRequest is the request type for the /contract/:id/keepalive
@@ -150,6 +195,23 @@ type (
 		Error        string `json:"error,omitempty"`
 	}
 
+	// ContractReconcileRequest is the request type for the
+	// /contract/:id/reconcile endpoint.
+	ContractReconcileRequest struct {
+		Timeout DurationMS `json:"timeout"`
+	}
+
+	// ContractReconcileResponse is the response type for the
+	// /contract/:id/reconcile endpoint. Missing is the amount of data the
+	// database expected on the contract that wasn't found on the host and has
+	// been marked as lost, Prunable is the amount of data on the host that
+	// isn't referenced by any object.
+	ContractReconcileResponse struct {
+		ContractSize uint64 `json:"size"`
+		Missing      uint64 `json:"missing"`
+		Prunable     uint64 `json:"prunable"`
+	}
+
 	// ContractAcquireRequest is the request type for the /contract/:id/release
 	// endpoint.
 	ContractReleaseRequest struct {
@@ -194,6 +256,52 @@ func (x ContractSpending) Add(y ContractSpending) (z ContractSpending) {
 	return
 }
 
+// PeriodSpending returns what the renter paid for the given contracts that
+// were formed, renewed or refreshed within the period that ends at the given
+// block height. It is what counts towards the spending budget of the period.
+func PeriodSpending(contracts []ContractMetadata, bh, period uint64) (spending types.Currency) {
+	minStartHeight := PeriodStartHeight(bh, period)
+	for _, c := range contracts {
+		if c.StartHeight >= minStartHeight {
+			spending = spending.Add(c.RenterCost)
+		}
+	}
+	return
+}
+
+// PeriodStartHeight returns the lowest start height of contracts that count
+// towards the spending budget of the period that ends at the given block
+// height.
+func PeriodStartHeight(bh, period uint64) uint64 {
+	if bh < period {
+		return 0
+	}
+	return bh - period + 1
+}
+
+// Validate returns an error if the renewal settings are invalid.
+func (rs ContractRenewalSettings) Validate() error {
+	if rs.Period == 0 {
+		return fmt.Errorf("%w: period must be greater than 0", ErrInvalidContractRenewalSettings)
+	} else if rs.RenewWindow == 0 {
+		return fmt.Errorf("%w: renewWindow must be greater than 0", ErrInvalidContractRenewalSettings)
+	} else if rs.RenterFunds.IsZero() {
+		return fmt.Errorf("%w: renterFunds must be greater than 0", ErrInvalidContractRenewalSettings)
+	}
+	return nil
+}
+
+// Validate returns an error if the request is invalid, renewal settings can
+// only be configured for manual contracts.
+func (r ContractManualRequest) Validate() error {
+	if r.Renewal == nil {
+		return nil
+	} else if !r.Manual {
+		return fmt.Errorf("%w: only manual contracts can have renewal settings", ErrInvalidContractRenewalSettings)
+	}
+	return r.Renewal.Validate()
+}
+
 func (cm ContractMetadata) EndHeight() uint64 {
 	return cm.WindowStart
 }
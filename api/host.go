@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -24,12 +25,22 @@ const (
 	UsabilityFilterModeAll      = "all"
 	UsabilityFilterModeUsable   = "usable"
 	UsabilityFilterModeUnusable = "unusable"
+
+	// MaxHostLabelLength is the maximum length of a host label in bytes.
+	MaxHostLabelLength = 255
 )
 
 var (
 	// ErrHostNotFound is returned when a host can't be retrieved from the
 	// database.
 	ErrHostNotFound = errors.New("host doesn't exist in hostdb")
+
+	// ErrInvalidHostLabel is returned when a host label is empty or too long.
+	ErrInvalidHostLabel = errors.New("invalid host label")
+
+	// ErrInvalidBlocklistEntry is returned when a blocklist entry is a
+	// malformed CIDR range, wildcard or ASN.
+	ErrInvalidBlocklistEntry = errors.New("invalid blocklist entry")
 )
 
 var (
@@ -60,8 +71,16 @@ type (
 		UsabilityMode   string            `json:"usabilityMode"`
 		AddressContains string            `json:"addressContains"`
 		KeyIn           []types.PublicKey `json:"keyIn"`
+		Labels          []string          `json:"labels"`
 		MaxLastScan     TimeRFC3339       `json:"maxLastScan"`
 	}
+
+	// UpdateHostLabelsRequest is the request type for the /host/:hostkey/labels
+	// endpoint, it replaces the labels and notes of a host.
+	UpdateHostLabelsRequest struct {
+		Labels []string `json:"labels"`
+		Notes  string   `json:"notes"`
+	}
 )
 
 type (
@@ -87,6 +106,7 @@ type (
 		FilterMode      string
 		UsabilityMode   string
 		KeyIn           []types.PublicKey
+		Labels          []string
 		Limit           int
 		MaxLastScan     TimeRFC3339
 		Offset          int
@@ -100,16 +120,31 @@ type (
 		PublicKey         types.PublicKey   `json:"publicKey"`
 		V2Settings        rhp4.HostSettings `json:"v2Settings,omitempty"`
 		Interactions      HostInteractions  `json:"interactions"`
+		Benchmark         HostBenchmark     `json:"benchmark"`
 		Scanned           bool              `json:"scanned"`
 		Blocked           bool              `json:"blocked"`
 		Checks            HostChecks        `json:"checks,omitempty"`
 		StoredData        uint64            `json:"storedData"`
 		V2SiamuxAddresses []string          `json:"v2SiamuxAddresses"`
+		Labels            []string          `json:"labels,omitempty"`
+		Notes             string            `json:"notes,omitempty"`
 	}
 
 	HostInfo struct {
 		PublicKey         types.PublicKey `json:"publicKey"`
 		V2SiamuxAddresses []string        `json:"v2SiamuxAddresses"`
+
+		// Location is only set by the bus if shard placement is restricted
+		// by diversity rules.
+		Location *HostLocation `json:"location,omitempty"`
+	}
+
+	// HostLocation contains the network location of a host. The country and
+	// ASN are only known if the bus was configured with a GeoIP database.
+	HostLocation struct {
+		Country string   `json:"country,omitempty"`
+		ASN     uint32   `json:"asn,omitempty"`
+		Subnets []string `json:"subnets,omitempty"`
 	}
 
 	HostInteractions struct {
@@ -118,6 +153,7 @@ type (
 		LastScanSuccess         bool          `json:"lastScanSuccess"`
 		LostSectors             uint64        `json:"lostSectors"`
 		SecondToLastScanSuccess bool          `json:"secondToLastScanSuccess"`
+		ScanLatency             time.Duration `json:"scanLatency"`
 		Uptime                  time.Duration `json:"uptime"`
 		Downtime                time.Duration `json:"downtime"`
 
@@ -125,9 +161,21 @@ type (
 		FailedInteractions     float64 `json:"failedInteractions"`
 	}
 
+	// HostBenchmark contains the result of the last benchmark of a host. The
+	// throughput and time to first byte are only updated by successful
+	// benchmarks, the throughput is measured in bytes per second.
+	HostBenchmark struct {
+		Timestamp          time.Time     `json:"timestamp"`
+		Success            bool          `json:"success"`
+		UploadThroughput   uint64        `json:"uploadThroughput"`
+		DownloadThroughput uint64        `json:"downloadThroughput"`
+		TimeToFirstByte    time.Duration `json:"timeToFirstByte"`
+	}
+
 	HostScan struct {
 		HostKey    types.PublicKey   `json:"hostKey"`
 		V2Settings rhp4.HostSettings `json:"v2Settings,omitempty"`
+		Latency    time.Duration     `json:"latency,omitempty"`
 		Success    bool              `json:"success"`
 		Timestamp  time.Time         `json:"timestamp"`
 	}
@@ -149,7 +197,9 @@ type (
 		Age              float64 `json:"age"`
 		Collateral       float64 `json:"collateral"`
 		Interactions     float64 `json:"interactions"`
+		Latency          float64 `json:"latency"`
 		StorageRemaining float64 `json:"storageRemaining"`
+		Throughput       float64 `json:"throughput"`
 		Uptime           float64 `json:"uptime"`
 		Version          float64 `json:"version"`
 		Prices           float64 `json:"prices"`
@@ -188,6 +238,23 @@ func (h Host) Info() HostInfo {
 	}
 }
 
+// NormalizeHostLabels trims, deduplicates and sorts the given labels. It
+// returns an error if a label is empty or exceeds the maximum length.
+func NormalizeHostLabels(labels []string) ([]string, error) {
+	normalized := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			return nil, fmt.Errorf("%w: label can't be empty", ErrInvalidHostLabel)
+		} else if len(label) > MaxHostLabelLength {
+			return nil, fmt.Errorf("%w: label '%s' exceeds the maximum length of %d", ErrInvalidHostLabel, label, MaxHostLabelLength)
+		}
+		normalized = append(normalized, label)
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
+
 // IsAnnounced returns whether the host has been announced.
 func (h Host) IsAnnounced() bool {
 	return !h.LastAnnouncement.IsZero()
@@ -219,7 +286,7 @@ func (h HostInfo) SiamuxAddr() string {
 }
 
 func (sb HostScoreBreakdown) String() string {
-	return fmt.Sprintf("Age: %v, Col: %v, Int: %v, SR: %v, UT: %v, V: %v, Pr: %v", sb.Age, sb.Collateral, sb.Interactions, sb.StorageRemaining, sb.Uptime, sb.Version, sb.Prices)
+	return fmt.Sprintf("Age: %v, Col: %v, Int: %v, Lat: %v, SR: %v, TP: %v, UT: %v, V: %v, Pr: %v", sb.Age, sb.Collateral, sb.Interactions, sb.Latency, sb.StorageRemaining, sb.Throughput, sb.Uptime, sb.Version, sb.Prices)
 }
 
 func (hgb HostGougingBreakdown) Gouging() bool {
@@ -252,7 +319,7 @@ func (hgb HostGougingBreakdown) String() string {
 }
 
 func (sb HostScoreBreakdown) Score() float64 {
-	return sb.Age * sb.Collateral * sb.Interactions * sb.StorageRemaining * sb.Uptime * sb.Version * sb.Prices
+	return sb.Age * sb.Collateral * sb.Interactions * sb.Latency * sb.StorageRemaining * sb.Throughput * sb.Uptime * sb.Version * sb.Prices
 }
 
 func (ub HostUsabilityBreakdown) IsUsable() bool {
@@ -12,7 +12,9 @@ func TestMarshalHostScoreBreakdownJSON(t *testing.T) {
 			Age:              1.1,
 			Collateral:       1.1,
 			Interactions:     1.1,
+			Latency:          1.1,
 			StorageRemaining: 1.1,
+			Throughput:       1.1,
 			Uptime:           1.1,
 			Version:          1.1,
 			Prices:           1.1,
@@ -21,7 +23,7 @@ func TestMarshalHostScoreBreakdownJSON(t *testing.T) {
 	b, err := json.MarshalIndent(hc, " ", " ")
 	if err != nil {
 		t.Fatal(err)
-	} else if !strings.Contains(string(b), "\"score\": 1.9487171000000014") {
+	} else if !strings.Contains(string(b), "\"score\": 2.357947691000002") {
 		t.Fatal("expected a score field")
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	rhpv4 "go.sia.tech/core/rhp/v4"
 	"go.sia.tech/core/types"
 )
 
@@ -16,10 +17,18 @@ const (
 
 	MetricContract      = "contract"
 	MetricContractPrune = "contractprune"
+	MetricHealth        = "health"
+	MetricHost          = "host"
+	MetricHostNetwork   = "hostnetwork"
 	MetricPerformance   = "performance"
 	MetricWallet        = "wallet"
 )
 
+// HealthMetricBuckets are the boundaries of the buckets the slabs are grouped
+// in when recording health metrics. Slabs with a negative health are in the
+// first bucket, fully healthy slabs are in the last one.
+var HealthMetricBuckets = []float64{0, 0.25, 0.5, 0.75, 1}
+
 type (
 	PerformanceMetricsQueryOpts struct {
 		Action  string
@@ -66,6 +75,87 @@ type (
 		HostVersion string
 	}
 
+	HealthMetric struct {
+		Timestamp TimeRFC3339 `json:"timestamp"`
+		SlabHealthStats
+	}
+
+	HealthMetricsQueryOpts struct{}
+
+	// HostMetric is a snapshot of a host's prices, remaining storage, uptime
+	// and score. The prices are per byte, the storage price is per byte per
+	// block.
+	HostMetric struct {
+		Timestamp TimeRFC3339     `json:"timestamp"`
+		HostKey   types.PublicKey `json:"hostKey"`
+
+		StoragePrice     types.Currency `json:"storagePrice"`
+		IngressPrice     types.Currency `json:"ingressPrice"`
+		EgressPrice      types.Currency `json:"egressPrice"`
+		RemainingStorage uint64         `json:"remainingStorage"`
+
+		// Uptime is the fraction of time the host was online
+		Uptime float64 `json:"uptime"`
+		Score  float64 `json:"score"`
+		Usable bool    `json:"usable"`
+	}
+
+	HostMetricsQueryOpts struct {
+		HostKey types.PublicKey
+	}
+
+	// HostNetworkMetric describes the distribution of the host metrics across
+	// all hosts in a period. Every host is represented by the last metric
+	// recorded for it in the period.
+	HostNetworkMetric struct {
+		Timestamp TimeRFC3339 `json:"timestamp"`
+
+		Hosts  uint64 `json:"hosts"`
+		Usable uint64 `json:"usable"`
+
+		StoragePrice     Percentiles[types.Currency] `json:"storagePrice"`
+		IngressPrice     Percentiles[types.Currency] `json:"ingressPrice"`
+		EgressPrice      Percentiles[types.Currency] `json:"egressPrice"`
+		RemainingStorage Percentiles[uint64]         `json:"remainingStorage"`
+		Uptime           Percentiles[float64]        `json:"uptime"`
+		Score            Percentiles[float64]        `json:"score"`
+	}
+
+	HostNetworkMetricsQueryOpts struct{}
+
+	// Percentiles contains the 10th, 25th, 50th, 75th and 90th percentile of a
+	// set of values.
+	Percentiles[T any] struct {
+		P10 T `json:"p10"`
+		P25 T `json:"p25"`
+		P50 T `json:"p50"`
+		P75 T `json:"p75"`
+		P90 T `json:"p90"`
+	}
+
+	// SlabHealthStats describes the health distribution of all slabs, the
+	// amount of data is the size of the data stored in the slabs, not the
+	// size of the shards.
+	SlabHealthStats struct {
+		Buckets []HealthBucket `json:"buckets"`
+
+		// BytesBelowCutoff is the amount of data in slabs with a health at or
+		// below the health cutoff, i.e. slabs that are up for migration.
+		HealthCutoff     float64 `json:"healthCutoff"`
+		BytesBelowCutoff uint64  `json:"bytesBelowCutoff"`
+	}
+
+	// HealthBucket contains the slabs with a health in [Start, End), a nil
+	// bound means the bucket is unbounded on that side.
+	HealthBucket struct {
+		Start *float64 `json:"start,omitempty"`
+		End   *float64 `json:"end,omitempty"`
+
+		Slabs     uint64  `json:"slabs"`
+		MinHealth float64 `json:"minHealth"`
+		Bytes     uint64  `json:"bytes"`
+	}
+
 	WalletMetric struct {
 		Timestamp TimeRFC3339 `json:"timestamp"`
 
@@ -78,6 +168,28 @@ type (
 	WalletMetricsQueryOpts struct{}
 )
 
+// NewHostMetric returns a metric for the given host using its current settings
+// and checks.
+func NewHostMetric(h Host, timestamp time.Time) HostMetric {
+	var uptime float64
+	if total := h.Interactions.Uptime + h.Interactions.Downtime; total > 0 {
+		uptime = float64(h.Interactions.Uptime) / float64(total)
+	}
+	return HostMetric{
+		Timestamp: TimeRFC3339(timestamp),
+		HostKey:   h.PublicKey,
+
+		StoragePrice:     h.V2Settings.Prices.StoragePrice,
+		IngressPrice:     h.V2Settings.Prices.IngressPrice,
+		EgressPrice:      h.V2Settings.Prices.EgressPrice,
+		RemainingStorage: h.V2Settings.RemainingStorage * rhpv4.SectorSize,
+
+		Uptime: uptime,
+		Score:  h.Checks.ScoreBreakdown.Score(),
+		Usable: h.Checks.UsabilityBreakdown.IsUsable(),
+	}
+}
+
 type (
 	ContractPruneMetricRequestPUT struct {
 		Metrics []ContractPruneMetric `json:"metrics"`
@@ -86,4 +198,8 @@ type (
 	ContractMetricRequestPUT struct {
 		Metrics []ContractMetric `json:"metrics"`
 	}
+
+	HealthMetricRequestPUT struct {
+		Metrics []HealthMetric `json:"metrics"`
+	}
 )
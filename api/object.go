@@ -74,6 +74,20 @@ type (
 		Key      string      `json:"key"`
 		Size     int64       `json:"size"`
 		MimeType string      `json:"mimeType,omitempty"`
+
+		// access statistics, recorded by the workers whenever the object is
+		// read
+		LastAccessedAt TimeRFC3339 `json:"lastAccessedAt,omitzero"`
+		ReadCount      uint64      `json:"readCount"`
+	}
+
+	// ObjectAccessRecord contains the number of times an object was read
+	// since the last record and when it was last read.
+	ObjectAccessRecord struct {
+		Bucket     string      `json:"bucket"`
+		Key        string      `json:"key"`
+		ReadCount  uint64      `json:"readCount"`
+		AccessedAt TimeRFC3339 `json:"accessedAt"`
 	}
 
 	// ObjectUserMetadata contains user-defined metadata about an object and can
@@ -91,6 +105,14 @@ type (
 		HeadObjectResponse
 	}
 
+	// GetObjectRangesResponse is the response type for GET /worker/object
+	// requests that ask for multiple ranges.
+	GetObjectRangesResponse struct {
+		Content io.ReadSeekCloser
+		Ranges  []DownloadRange
+		HeadObjectResponse
+	}
+
 	// HeadObjectResponse is the response type for the HEAD /worker/object endpoint.
 	HeadObjectResponse struct {
 		ContentDisposition string
@@ -746,6 +746,10 @@ func (m DownloadStatsResponse) PrometheusMetric() (metrics []prometheus.Metric)
 		{
 			Name:  "renterd_worker_stats_numdownloaders",
 			Value: float64(m.NumDownloaders),
+		},
+		{
+			Name:  "renterd_worker_stats_numprefetchedslabs",
+			Value: float64(m.NumPrefetchedSlabs),
 		}}
 }
 
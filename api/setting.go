@@ -22,6 +22,19 @@ var (
 )
 
 var (
+	// DefaultBandwidthSettings define the default bandwidth settings the bus
+	// is configured with on startup, by default no bucket is limited.
+	DefaultBandwidthSettings = BandwidthSettings{
+		Buckets: map[string]BandwidthLimits{},
+	}
+
+	// DefaultPlacementSettings define the default placement settings the bus
+	// is configured with on startup, by default no bucket is pinned.
+	DefaultPlacementSettings = PlacementSettings{
+		Groups:  map[string][]types.PublicKey{},
+		Buckets: map[string]string{},
+	}
+
 	// DefaultGougingSettings define the default gouging settings the bus is
 	// configured with on startup.
 	DefaultGougingSettings = GougingSettings{
@@ -52,6 +65,17 @@ var (
 		TotalShards: 6,
 	}
 
+	// DefaultRepairSettings define the default repair settings the bus is
+	// configured with on startup. Repairs aren't prioritised by default,
+	// setting the access boost boosts slabs of objects that were read ten
+	// times or more and were last read in the past week.
+	DefaultRepairSettings = RepairSettings{
+		AccessBoost:  0,
+		AccessWindow: DurationMS(7 * 24 * time.Hour),
+		HotReadCount: 10,
+		BucketBoosts: map[string]float64{},
+	}
+
 	// DefaultS3Settings defines the 3 settings the bus is configured with on
 	// startup.
 	DefaultS3Settings = S3Settings{
@@ -81,6 +105,45 @@ func DefaultUploadSettings(network string) UploadSettings {
 }
 
 type (
+	// BandwidthSettings contain the bandwidth limits that apply to the
+	// uploads and downloads of individual buckets. The limits are enforced by
+	// every worker separately.
+	BandwidthSettings struct {
+		Buckets map[string]BandwidthLimits `json:"buckets"`
+	}
+
+	// BandwidthLimits contain an upload and download limit in bytes per
+	// second, a limit of 0 means the bandwidth is not limited.
+	BandwidthLimits struct {
+		MaxDownload uint64 `json:"maxDownload"`
+		MaxUpload   uint64 `json:"maxUpload"`
+	}
+
+	// PlacementSettings contain named groups of hosts and the group every
+	// bucket is pinned to. Shards of objects in a pinned bucket are only ever
+	// uploaded to hosts in the bucket's group, buckets that aren't pinned can
+	// use any host. The diversity rules apply to the shards of all slabs.
+	PlacementSettings struct {
+		Groups    map[string][]types.PublicKey `json:"groups"`
+		Buckets   map[string]string            `json:"buckets"`
+		Diversity DiversityRules               `json:"diversity"`
+
+		// ContractSets assigns buckets to named contract sets, objects in a
+		// bucket are only uploaded to contracts in the bucket's set. Buckets
+		// that aren't assigned use the default set.
+		ContractSets map[string]string `json:"contractSets,omitempty"`
+	}
+
+	// DiversityRules limit the number of shards of a slab that are stored on
+	// hosts in the same country, autonomous system or subnet. Subnets are /16
+	// for IPv4 and /32 for IPv6, a limit of 0 means the number of shards is
+	// not limited. Hosts with an unknown location aren't restricted.
+	DiversityRules struct {
+		MaxShardsPerCountry uint64 `json:"maxShardsPerCountry"`
+		MaxShardsPerASN     uint64 `json:"maxShardsPerASN"`
+		MaxShardsPerSubnet  uint64 `json:"maxShardsPerSubnet"`
+	}
+
 	// GougingSettings contain some price settings used in price gouging.
 	GougingSettings struct {
 		// MaxRPCPrice is the maximum allowed base price for RPCs
@@ -130,6 +193,10 @@ type (
 		// GougingSettingsPins contains the pinned settings for the gouging
 		// settings.
 		GougingSettingsPins GougingSettingsPins `json:"gougingSettingsPins"`
+
+		// Budget pins the spending budget of the contracts config, it is
+		// expressed in the external currency per period.
+		Budget Pin `json:"budget"`
 	}
 
 	// UploadSettings contains various settings related to uploads.
@@ -162,6 +229,27 @@ type (
 		Value  float64 `json:"value"`
 	}
 
+	// RepairSettings contain settings that affect the order in which the
+	// migrator repairs unhealthy slabs. Slabs are repaired in order of their
+	// health minus a boost, the boost is the sum of an access boost, based on
+	// how hot the data in the slab is, and the boost of the bucket the slab
+	// belongs to. If a slab is shared by multiple objects the highest boost
+	// applies.
+	RepairSettings struct {
+		// AccessBoost is the boost for slabs of objects that were read at
+		// least 'HotReadCount' times and last read within the access window.
+		// Objects that were read less often get a proportional boost. The
+		// read count of an object is not windowed, it counts all reads since
+		// the object was created. Only reads that start at the beginning of
+		// the object are counted.
+		AccessBoost  float64    `json:"accessBoost"`
+		AccessWindow DurationMS `json:"accessWindow"`
+		HotReadCount uint64     `json:"hotReadCount"`
+
+		// BucketBoosts contains the boost for slabs of objects in a bucket.
+		BucketBoosts map[string]float64 `json:"bucketBoosts"`
+	}
+
 	// S3Settings contains various settings related to the S3 API.
 	S3Settings struct {
 		Authentication S3AuthenticationSettings `json:"authentication"`
@@ -182,7 +270,8 @@ func (p Pin) IsPinned() bool {
 func (ps PinnedSettings) Enabled() bool {
 	if ps.GougingSettingsPins.MaxDownload.Pinned ||
 		ps.GougingSettingsPins.MaxStorage.Pinned ||
-		ps.GougingSettingsPins.MaxUpload.Pinned {
+		ps.GougingSettingsPins.MaxUpload.Pinned ||
+		ps.Budget.Pinned {
 		return true
 	}
 	return false
@@ -202,6 +291,76 @@ func (ps PinnedSettings) Validate() error {
 	return nil
 }
 
+// Validate returns an error if the bandwidth settings are not considered
+// valid.
+func (bs BandwidthSettings) Validate() error {
+	for bucket := range bs.Buckets {
+		if bucket == "" {
+			return errors.New("bucket name can't be empty")
+		}
+	}
+	return nil
+}
+
+// PinnedHosts returns the set of hosts the given bucket is pinned to and
+// whether the bucket is pinned at all.
+func (ps PlacementSettings) PinnedHosts(bucket string) (map[types.PublicKey]struct{}, bool) {
+	group, ok := ps.Buckets[bucket]
+	if !ok {
+		return nil, false
+	}
+	hosts := make(map[types.PublicKey]struct{})
+	for _, hk := range ps.Groups[group] {
+		hosts[hk] = struct{}{}
+	}
+	return hosts, true
+}
+
+// ContractSet returns the contract set the given bucket is assigned to, the
+// empty string refers to the default set.
+func (ps PlacementSettings) ContractSet(bucket string) string {
+	return ps.ContractSets[bucket]
+}
+
+// Enabled returns true if any of the diversity rules limit the number of
+// shards.
+func (dr DiversityRules) Enabled() bool {
+	return dr.MaxShardsPerCountry > 0 || dr.MaxShardsPerASN > 0 || dr.MaxShardsPerSubnet > 0
+}
+
+// RequiresGeoIP returns true if the rules can only be enforced using a GeoIP
+// database.
+func (dr DiversityRules) RequiresGeoIP() bool {
+	return dr.MaxShardsPerCountry > 0 || dr.MaxShardsPerASN > 0
+}
+
+// Validate returns an error if the placement settings are not considered
+// valid.
+func (ps PlacementSettings) Validate() error {
+	for name, hosts := range ps.Groups {
+		if name == "" {
+			return errors.New("group name can't be empty")
+		} else if len(hosts) == 0 {
+			return fmt.Errorf("group %q has no hosts", name)
+		}
+	}
+	for bucket, group := range ps.Buckets {
+		if bucket == "" {
+			return errors.New("bucket name can't be empty")
+		} else if _, ok := ps.Groups[group]; !ok {
+			return fmt.Errorf("bucket %q is pinned to unknown group %q", bucket, group)
+		}
+	}
+	for bucket, set := range ps.ContractSets {
+		if bucket == "" {
+			return errors.New("bucket name can't be empty")
+		} else if set == "" {
+			return fmt.Errorf("bucket %q is assigned to a contract set without a name", bucket)
+		}
+	}
+	return nil
+}
+
 // Validate returns an error if the gouging settings are not considered valid.
 func (gs GougingSettings) Validate() error {
 	if gs.HostBlockHeightLeeway < 3 {
@@ -219,6 +378,25 @@ func (gs GougingSettings) Validate() error {
 	return nil
 }
 
+// Validate returns an error if the repair settings are not considered valid.
+func (rs RepairSettings) Validate() error {
+	if rs.AccessBoost < 0 || rs.AccessBoost > 1 {
+		return errors.New("AccessBoost must be between 0 and 1")
+	} else if rs.AccessBoost > 0 && rs.AccessWindow <= 0 {
+		return errors.New("AccessWindow must be greater than zero when AccessBoost is set")
+	} else if rs.AccessBoost > 0 && rs.HotReadCount == 0 {
+		return errors.New("HotReadCount must be greater than zero when AccessBoost is set")
+	}
+	for bucket, boost := range rs.BucketBoosts {
+		if bucket == "" {
+			return errors.New("bucket name can't be empty")
+		} else if boost < 0 || boost > 1 {
+			return fmt.Errorf("boost for bucket '%s' must be between 0 and 1", bucket)
+		}
+	}
+	return nil
+}
+
 // Validate returns an error if the upload settings are not considered valid.
 func (us UploadSettings) Validate() error {
 	if us.Packing.Enabled && us.Packing.SlabBufferMaxSizeSoft <= 0 {
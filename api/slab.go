@@ -20,6 +20,13 @@ type (
 		Locked   bool   `json:"locked"`   // whether the slab buffer is locked for uploading
 	}
 
+	// SlabWithBuckets is a slab together with the names of the buckets of the
+	// objects referencing it.
+	SlabWithBuckets struct {
+		Slab    object.Slab `json:"slab"`
+		Buckets []string    `json:"buckets"`
+	}
+
 	UnhealthySlab struct {
 		EncryptionKey object.EncryptionKey `json:"encryptionKey"`
 		Health        float64              `json:"health"`
@@ -45,9 +52,15 @@ type (
 	// MigrationSlabsRequest is the request type for the /slabs/migration endpoint.
 	MigrationSlabsRequest struct {
 		HealthCutoff float64 `json:"healthCutoff"`
+		Offset       int     `json:"offset"`
 		Limit        int     `json:"limit"`
 	}
 
+	// SlabsRequest is the request type for the /slabs endpoint.
+	SlabsRequest struct {
+		Keys []object.EncryptionKey `json:"keys"`
+	}
+
 	PackedSlabsRequestGET struct {
 		LockingDuration DurationMS `json:"lockingDuration"`
 		MinShards       uint8      `json:"minShards"`
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"go.sia.tech/core/types"
+)
+
+const (
+	SpendingReportFormatCSV  = "csv"
+	SpendingReportFormatJSON = "json"
+)
+
+var (
+	// ErrInvalidSpendingReportFormat is returned when a spending report is
+	// requested in a format that isn't supported.
+	ErrInvalidSpendingReportFormat = errors.New("invalid spending report format, must be 'json' or 'csv'")
+)
+
+type (
+	// SpendingBreakdown breaks down spending by category.
+	SpendingBreakdown struct {
+		ContractSpending
+		ContractPrice types.Currency `json:"contractPrice"`
+	}
+
+	// SpendingReportItem contains the spending on a host within a period of
+	// a spending report.
+	SpendingReportItem struct {
+		Period  TimeRFC3339     `json:"period"`
+		HostKey types.PublicKey `json:"hostKey"`
+		SpendingBreakdown
+	}
+
+	// SpendingSummary contains the spending of a subset of the items of a
+	// spending report.
+	SpendingSummary struct {
+		SpendingBreakdown
+		Total     types.Currency `json:"total"`
+		TotalFiat float64        `json:"totalFiat,omitempty"`
+	}
+
+	// HostSpendingSummary contains the spending on a host over all periods of
+	// a spending report.
+	HostSpendingSummary struct {
+		HostKey types.PublicKey `json:"hostKey"`
+		SpendingSummary
+	}
+
+	// PeriodSpendingSummary contains the spending on all hosts within a
+	// period of a spending report.
+	PeriodSpendingSummary struct {
+		Period TimeRFC3339 `json:"period"`
+		SpendingSummary
+	}
+
+	// SpendingReport aggregates the spending across all contracts, including
+	// archived ones, in n periods of the given interval.
+	SpendingReport struct {
+		Start    TimeRFC3339 `json:"start"`
+		Interval DurationMS  `json:"interval"`
+
+		// Currency and ExchangeRate are only set if the report contains a
+		// fiat valuation.
+		Currency     string  `json:"currency,omitempty"`
+		ExchangeRate float64 `json:"exchangeRate,omitempty"`
+
+		Total   SpendingSummary         `json:"total"`
+		Hosts   []HostSpendingSummary   `json:"hosts"`
+		Periods []PeriodSpendingSummary `json:"periods"`
+		Items   []SpendingReportItem    `json:"items"`
+	}
+
+	// SpendingReportOpts contains the options for fetching a spending report.
+	SpendingReportOpts struct {
+		Currency string
+	}
+)
+
+// Add returns the sum of the current and given spending breakdown.
+func (x SpendingBreakdown) Add(y SpendingBreakdown) (z SpendingBreakdown) {
+	z.ContractSpending = x.ContractSpending.Add(y.ContractSpending)
+	z.ContractPrice = x.ContractPrice.Add(y.ContractPrice)
+	return
+}
+
+// Total returns the total of all spending categories.
+func (x SpendingBreakdown) Total() types.Currency {
+	return x.ContractSpending.Total().Add(x.ContractPrice)
+}
+
+// NewSpendingReport aggregates the given items into a spending report. If
+// both a currency and an exchange rate are given, the report contains a fiat
+// valuation of the totals.
+func NewSpendingReport(start time.Time, n uint64, interval time.Duration, items []SpendingReportItem, currency string, rate float64) SpendingReport {
+	if currency == "" || rate <= 0 {
+		currency, rate = "", 0
+	}
+	summarize := func(b SpendingBreakdown) SpendingSummary {
+		return SpendingSummary{
+			SpendingBreakdown: b,
+			Total:             b.Total(),
+			TotalFiat:         ConvertCurrencyToFiat(b.Total(), rate),
+		}
+	}
+
+	// aggregate the items
+	var total SpendingBreakdown
+	hosts := make(map[types.PublicKey]SpendingBreakdown)
+	periods := make([]SpendingBreakdown, n)
+	for _, item := range items {
+		total = total.Add(item.SpendingBreakdown)
+		hosts[item.HostKey] = hosts[item.HostKey].Add(item.SpendingBreakdown)
+		if p := time.Time(item.Period).Sub(start) / interval; p >= 0 && uint64(p) < n {
+			periods[p] = periods[p].Add(item.SpendingBreakdown)
+		}
+	}
+
+	report := SpendingReport{
+		Start:        TimeRFC3339(start),
+		Interval:     DurationMS(interval),
+		Currency:     currency,
+		ExchangeRate: rate,
+		Total:        summarize(total),
+		Hosts:        make([]HostSpendingSummary, 0, len(hosts)),
+		Periods:      make([]PeriodSpendingSummary, 0, n),
+		Items:        items,
+	}
+	if report.Items == nil {
+		report.Items = []SpendingReportItem{}
+	}
+	for hk, b := range hosts {
+		report.Hosts = append(report.Hosts, HostSpendingSummary{HostKey: hk, SpendingSummary: summarize(b)})
+	}
+	sort.Slice(report.Hosts, func(i, j int) bool {
+		return report.Hosts[i].HostKey.String() < report.Hosts[j].HostKey.String()
+	})
+	for i, b := range periods {
+		report.Periods = append(report.Periods, PeriodSpendingSummary{
+			Period:          TimeRFC3339(start.Add(time.Duration(i) * interval)),
+			SpendingSummary: summarize(b),
+		})
+	}
+	return report
+}
+
+// ConvertCurrencyToFiat converts the given amount of hastings to fiat using
+// the given exchange rate in fiat per SC.
+func ConvertCurrencyToFiat(c types.Currency, rate float64) float64 {
+	if rate <= 0 {
+		return 0
+	}
+	sc := new(big.Float).Quo(new(big.Float).SetInt(c.Big()), new(big.Float).SetInt(types.Siacoins(1).Big()))
+	fiat, _ := sc.Mul(sc, big.NewFloat(rate)).Float64()
+	return fiat
+}
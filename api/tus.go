@@ -0,0 +1,79 @@
+package api
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// TusVersion is the version of the tus resumable upload protocol that is
+	// supported by the worker.
+	TusVersion = "1.0.0"
+
+	// TusExtensions are the extensions of the tus protocol that are supported
+	// by the worker.
+	TusExtensions = "creation,termination"
+
+	// TusCompletedUploadExpiry is the amount of time a completed tus upload
+	// is kept around, allowing clients that lost the response of the final
+	// chunk to verify the upload was completed.
+	TusCompletedUploadExpiry = 24 * time.Hour
+)
+
+var (
+	// ErrTusUploadNotFound is returned if the specified tus upload wasn't
+	// found.
+	ErrTusUploadNotFound = errors.New("tus upload not found")
+
+	// ErrTusUploadOffsetMismatch is returned if a chunk is added to a tus
+	// upload at an offset that doesn't match the upload's current offset.
+	ErrTusUploadOffsetMismatch = errors.New("tus upload offset mismatch")
+
+	// ErrTusUploadLengthExceeded is returned if a chunk is added to a tus
+	// upload that would exceed the upload's length.
+	ErrTusUploadLengthExceeded = errors.New("tus upload length exceeded")
+)
+
+type (
+	// TusUpload describes a resumable upload. Every chunk of a tus upload is
+	// stored as a part of the multipart upload with the same id, once the
+	// offset reaches the length of the upload the multipart upload is
+	// completed. Completed uploads have no bucket or key.
+	TusUpload struct {
+		ID        string      `json:"id"`
+		Bucket    string      `json:"bucket"`
+		Key       string      `json:"key"`
+		Length    uint64      `json:"length"`
+		Offset    uint64      `json:"offset"`
+		Parts     int         `json:"parts"`
+		CreatedAt TimeRFC3339 `json:"createdAt"`
+	}
+
+	CreateTusUploadOptions struct {
+		MimeType string
+		Metadata ObjectUserMetadata
+	}
+)
+
+type (
+	TusCreateRequest struct {
+		Bucket   string             `json:"bucket"`
+		Key      string             `json:"key"`
+		Length   uint64             `json:"length"`
+		MimeType string             `json:"mimeType"`
+		Metadata ObjectUserMetadata `json:"metadata"`
+	}
+
+	// TusUpdateOffsetRequest is the request to advance the offset of a tus
+	// upload after a chunk of the given length was added to the multipart
+	// upload as part number 'Parts+1'.
+	TusUpdateOffsetRequest struct {
+		Offset uint64 `json:"offset"`
+		Length uint64 `json:"length"`
+	}
+)
+
+// Complete returns true if all of the upload's data was received.
+func (u TusUpload) Complete() bool {
+	return u.Offset == u.Length
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -27,8 +28,13 @@ var (
 	// be scanned since it is on a private network.
 	ErrHostOnPrivateNetwork = errors.New("host is on a private network")
 
-	// ErrMultiRangeNotSupported is returned by the worker API when a request
-	// tries to download multiple ranges at once.
+	// ErrUploadNotFound is returned by the worker API when trying to cancel
+	// an upload that isn't in progress.
+	ErrUploadNotFound = errors.New("upload not found")
+
+	// ErrMultiRangeNotSupported is returned by ParseDownloadRange when the
+	// request contains multiple ranges, only GET requests for objects support
+	// multiple ranges.
 	ErrMultiRangeNotSupported = errors.New("multipart ranges are not supported")
 )
 
@@ -89,6 +95,7 @@ type (
 		AvgOverdrivePct      float64           `json:"avgOverdrivePct"`
 		HealthyDownloaders   uint64            `json:"healthyDownloaders"`
 		NumDownloaders       uint64            `json:"numDownloaders"`
+		NumPrefetchedSlabs   uint64            `json:"numPrefetchedSlabs"`
 		DownloadersStats     []DownloaderStats `json:"downloadersStats"`
 	}
 	DownloaderStats struct {
@@ -109,6 +116,24 @@ type (
 		AvgSectorUploadSpeedMBPS float64         `json:"avgSectorUploadSpeedMbps"`
 	}
 
+	// UploadProgress describes an object upload that is in progress, it is
+	// returned by the /worker/uploads endpoint.
+	UploadProgress struct {
+		ID                UploadID             `json:"id"`
+		Bucket            string               `json:"bucket"`
+		Key               string               `json:"key"`
+		MultipartUploadID string               `json:"multipartUploadID,omitempty"`
+		PartNumber        int                  `json:"partNumber,omitempty"`
+		StartedAt         TimeRFC3339          `json:"startedAt"`
+		BytesReceived     uint64               `json:"bytesReceived"`
+		SlabsUploaded     uint64               `json:"slabsUploaded"`
+		Hosts             []HostUploadProgress `json:"hosts"`
+	}
+	HostUploadProgress struct {
+		HostKey         types.PublicKey `json:"hostKey"`
+		SectorsUploaded uint64          `json:"sectorsUploaded"`
+	}
+
 	// WorkerStateResponse is the response type for the /worker/state endpoint.
 	WorkerStateResponse struct {
 		ID        string      `json:"id"`
@@ -199,6 +224,41 @@ func ParseDownloadRange(req *http.Request) (DownloadRange, error) {
 	return dr, nil
 }
 
+// ParseDownloadRanges parses all ranges of the given "Range" header for an
+// object of given size. Overlapping and adjacent ranges are coalesced and the
+// resulting ranges are sorted by offset.
+func ParseDownloadRanges(header string, size int64) ([]DownloadRange, error) {
+	ranges, err := http_range.ParseRange(header, size)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+
+	var drs []DownloadRange
+	for _, r := range ranges {
+		if r.Length == 0 {
+			continue
+		} else if n := len(drs); n > 0 && r.Start <= drs[n-1].Offset+drs[n-1].Length {
+			drs[n-1].Length = max(drs[n-1].Offset+drs[n-1].Length, r.Start+r.Length) - drs[n-1].Offset
+			continue
+		}
+		drs = append(drs, DownloadRange{Offset: r.Start, Length: r.Length})
+	}
+	if len(drs) == 0 && len(ranges) > 0 {
+		return nil, http_range.ErrNoOverlap
+	}
+	return drs, nil
+}
+
+func (r HostBenchmarkResponse) Error() error {
+	if r.BenchmarkError != "" {
+		return errors.New(r.BenchmarkError)
+	}
+	return nil
+}
+
 func (r HostScanResponse) Error() error {
 	if r.ScanError != "" {
 		return errors.New(r.ScanError)
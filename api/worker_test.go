@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gotd/contrib/http_range"
+)
+
+func TestParseDownloadRanges(t *testing.T) {
+	tests := []struct {
+		header string
+		ranges []DownloadRange
+		err    error
+		desc   string
+	}{
+		{
+			header: "",
+			ranges: nil,
+			desc:   "no header",
+		},
+		{
+			header: "bytes=0-9,20-29",
+			ranges: []DownloadRange{{Offset: 0, Length: 10}, {Offset: 20, Length: 10}},
+			desc:   "disjoint ranges",
+		},
+		{
+			header: "bytes=20-29,0-9",
+			ranges: []DownloadRange{{Offset: 0, Length: 10}, {Offset: 20, Length: 10}},
+			desc:   "unsorted ranges",
+		},
+		{
+			header: "bytes=0-9,5-14,12-19",
+			ranges: []DownloadRange{{Offset: 0, Length: 20}},
+			desc:   "overlapping ranges",
+		},
+		{
+			header: "bytes=0-9,10-19,30-39",
+			ranges: []DownloadRange{{Offset: 0, Length: 20}, {Offset: 30, Length: 10}},
+			desc:   "adjacent ranges",
+		},
+		{
+			header: "bytes=0-19,5-9",
+			ranges: []DownloadRange{{Offset: 0, Length: 20}},
+			desc:   "contained range",
+		},
+		{
+			header: "bytes=0-9,-10",
+			ranges: []DownloadRange{{Offset: 0, Length: 10}, {Offset: 90, Length: 10}},
+			desc:   "suffix range",
+		},
+		{
+			header: "bytes=90-,95-",
+			ranges: []DownloadRange{{Offset: 90, Length: 10}},
+			desc:   "open ranges",
+		},
+		{
+			header: "bytes=100-,200-",
+			err:    http_range.ErrNoOverlap,
+			desc:   "no overlap",
+		},
+		{
+			header: "bytes=10-0",
+			err:    http_range.ErrInvalid,
+			desc:   "invalid range",
+		},
+	}
+	for _, test := range tests {
+		ranges, err := ParseDownloadRanges(test.header, 100)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("%v: expected error %v, got %v", test.desc, test.err, err)
+		} else if !reflect.DeepEqual(ranges, test.ranges) {
+			t.Fatalf("%v: expected ranges %v, got %v", test.desc, test.ranges, ranges)
+		}
+	}
+}
@@ -26,7 +26,9 @@ var (
 type (
 	Bus interface {
 		AutopilotConfig(ctx context.Context) (api.AutopilotConfig, error)
+		BenchmarkHost(ctx context.Context, hostKey types.PublicKey, timeout time.Duration) (api.HostBenchmarkResponse, error)
 		ConsensusState(ctx context.Context) (api.ConsensusState, error)
+		FileContractTax(ctx context.Context, payout types.Currency) (types.Currency, error)
 		GougingSettings(ctx context.Context) (gs api.GougingSettings, err error)
 		Hosts(ctx context.Context, opts api.HostOptions) ([]api.Host, error)
 		RecommendedFee(ctx context.Context) (types.Currency, error)
@@ -38,9 +40,11 @@ type (
 
 	Contractor interface {
 		PerformContractMaintenance(context.Context, *contractor.MaintenanceState) (bool, error)
+		SimulateContractMaintenance(context.Context, *contractor.MaintenanceState) (api.MaintenanceSimulationResponse, error)
 	}
 
 	Migrator interface {
+		EstimateMigrations(ctx context.Context) (api.MigrationEstimateResponse, error)
 		Migrate(ctx context.Context)
 		SignalMaintenanceFinished()
 		Shutdown(ctx context.Context) error
@@ -49,11 +53,14 @@ type (
 
 	Pruner interface {
 		PerformContractPruning(context.Context)
+		PerformContractReconciliation(context.Context)
+		ReconcileContracts(ctx context.Context) (api.ContractsReconcileResponse, error)
 		Shutdown(ctx context.Context) error
 		Status() (bool, time.Time)
 	}
 
 	Scanner interface {
+		Benchmark(ctx context.Context, hs scanner.HostScanner)
 		Scan(ctx context.Context, hs scanner.HostScanner, force bool)
 		Shutdown(ctx context.Context) error
 		Status() (bool, time.Time)
@@ -109,9 +116,13 @@ func New(ctx context.Context, cancel context.CancelCauseFunc, b Bus, c Contracto
 // Handler returns an HTTP handler that serves the autopilot api.
 func (ap *Autopilot) Handler() http.Handler {
 	return jape.Mux(map[string]jape.Handler{
-		"POST   /config/evaluate": ap.configEvaluateHandlerPOST,
-		"GET    /state":           ap.stateHandlerGET,
-		"POST   /trigger":         ap.triggerHandlerPOST,
+		"POST   /config/evaluate":      ap.configEvaluateHandlerPOST,
+		"POST   /contracts/reconcile":  ap.contractsReconcileHandlerPOST,
+		"POST   /forecast":             ap.forecastHandlerPOST,
+		"POST   /maintenance/simulate": ap.maintenanceSimulateHandlerPOST,
+		"GET    /migrations/estimate":  ap.migrationsEstimateHandlerGET,
+		"GET    /state":                ap.stateHandlerGET,
+		"POST   /trigger":              ap.triggerHandlerPOST,
 	})
 }
 
@@ -139,6 +150,12 @@ func (ap *Autopilot) configEvaluateHandlerPOST(jc jape.Context) {
 		return
 	}
 
+	// fetch the current config to compare the host selection against
+	curCfg, err := ap.bus.AutopilotConfig(ctx)
+	if jc.Check("failed to get autopilot config", err) != nil {
+		return
+	}
+
 	// evaluate the config
 	res, err := contractor.EvaluateConfig(reqCfg, cs, rs, gs, hosts)
 	if errors.Is(err, contractor.ErrMissingRequiredFields) {
@@ -148,6 +165,121 @@ func (ap *Autopilot) configEvaluateHandlerPOST(jc jape.Context) {
 		jc.Error(err, http.StatusInternalServerError)
 		return
 	}
+	res.Selection = contractor.EvaluateSelection(curCfg, reqCfg, cs, rs, gs, hosts)
+	jc.Encode(res)
+}
+
+func (ap *Autopilot) contractsReconcileHandlerPOST(jc jape.Context) {
+	res, err := ap.pruner.ReconcileContracts(jc.Request.Context())
+	if jc.Check("failed to reconcile contracts", err) != nil {
+		return
+	}
+	jc.Encode(res)
+}
+
+func (ap *Autopilot) forecastHandlerPOST(jc jape.Context) {
+	ctx := jc.Request.Context()
+
+	// decode request
+	var req api.CostForecastRequest
+	if jc.Decode(&req) != nil {
+		return
+	}
+
+	// fetch necessary information
+	cfg, err := ap.bus.AutopilotConfig(ctx)
+	if jc.Check("failed to get autopilot config", err) != nil {
+		return
+	}
+	gs, err := ap.bus.GougingSettings(ctx)
+	if jc.Check("failed to get gouging settings", err) != nil {
+		return
+	}
+	us, err := ap.bus.UploadSettings(ctx)
+	if jc.Check("failed to get upload settings", err) != nil {
+		return
+	}
+	cs, err := ap.bus.ConsensusState(ctx)
+	if jc.Check("failed to get consensus state", err) != nil {
+		return
+	}
+	hosts, err := ap.bus.Hosts(ctx, api.HostOptions{})
+	if jc.Check("failed to get hosts", err) != nil {
+		return
+	}
+
+	// apply defaults
+	if req.Period == 0 {
+		req.Period = cfg.Contracts.Period
+	}
+	if req.Redundancy == (api.RedundancySettings{}) {
+		req.Redundancy = us.Redundancy
+	}
+
+	// forecast the costs
+	res, err := contractor.ForecastCosts(req, cfg, cs, gs, hosts, func(payout types.Currency) (types.Currency, error) {
+		return ap.bus.FileContractTax(ctx, payout)
+	})
+	if errors.Is(err, api.ErrInvalidCostForecastRequest) || errors.Is(err, api.ErrNoUsableHosts) {
+		jc.Error(err, http.StatusBadRequest)
+		return
+	} else if jc.Check("failed to forecast costs", err) != nil {
+		return
+	}
+	jc.Encode(res)
+}
+
+func (ap *Autopilot) maintenanceSimulateHandlerPOST(jc jape.Context) {
+	ctx := jc.Request.Context()
+
+	// decode request
+	var req api.MaintenanceSimulationRequest
+	if jc.Decode(&req) != nil {
+		return
+	}
+
+	// build the maintenance state
+	state, err := ap.buildState(ctx)
+	if jc.Check("failed to build maintenance state", err) != nil {
+		return
+	}
+
+	// apply the proposed config and gouging settings
+	if req.AutopilotConfig != nil {
+		cfg := *req.AutopilotConfig
+		if err := cfg.Contracts.Validate(); err != nil {
+			jc.Error(fmt.Errorf("contracts config is invalid: %w", err), http.StatusBadRequest)
+			return
+		} else if err := cfg.Hosts.Validate(); err != nil {
+			jc.Error(fmt.Errorf("hosts config is invalid: %w", err), http.StatusBadRequest)
+			return
+		} else if err := api.ValidateContractSets(cfg.ContractSets); err != nil {
+			jc.Error(fmt.Errorf("contract sets are invalid: %w", err), http.StatusBadRequest)
+			return
+		}
+		state.AP = cfg
+	}
+	if req.GougingSettings != nil {
+		if err := req.GougingSettings.Validate(); err != nil {
+			jc.Error(fmt.Errorf("gouging settings are invalid: %w", err), http.StatusBadRequest)
+			return
+		}
+		state.GS = *req.GougingSettings
+	}
+
+	// simulate the maintenance
+	res, err := ap.contractor.SimulateContractMaintenance(ctx, state)
+	if jc.Check("failed to simulate contract maintenance", err) != nil {
+		return
+	}
+	jc.Encode(res)
+}
+
+func (ap *Autopilot) migrationsEstimateHandlerGET(jc jape.Context) {
+	res, err := ap.migrator.EstimateMigrations(jc.Request.Context())
+	if jc.Check("failed to estimate migrations", err) != nil {
+		return
+	}
 	jc.Encode(res)
 }
 
@@ -383,6 +515,9 @@ func (ap *Autopilot) performMaintenance(forceScan bool, tickerFired chan struct{
 	// update the scanner with the hosts config
 	ap.scanner.UpdateHostsConfig(apCfg.Hosts)
 
+	// benchmark the hosts we have contracts with
+	ap.scanner.Benchmark(ap.shutdownCtx, ap.bus)
+
 	// perform wallet maintenance
 	err = ap.maintainer.PerformWalletMaintenance(ap.shutdownCtx, apCfg)
 	if err != nil && utils.IsErr(err, context.Canceled) {
@@ -416,9 +551,10 @@ func (ap *Autopilot) performMaintenance(forceScan bool, tickerFired chan struct{
 	// migration
 	ap.migrator.Migrate(ap.shutdownCtx)
 
-	// pruning
+	// pruning and reconciliation
 	if apCfg.Contracts.Prune {
 		ap.pruner.PerformContractPruning(ap.shutdownCtx)
+		ap.pruner.PerformContractReconciliation(ap.shutdownCtx)
 	} else {
 		ap.logger.Info("pruning disabled")
 	}
@@ -20,6 +20,37 @@ func NewClient(addr, password string) *Client {
 	}}
 }
 
+// EstimateMigrations returns an estimate of the data that needs to be
+// transferred to migrate all unhealthy slabs and what it would cost, without
+// performing any migrations.
+func (c *Client) EstimateMigrations(ctx context.Context) (resp api.MigrationEstimateResponse, err error) {
+	err = c.c.GET(ctx, "/migrations/estimate", &resp)
+	return
+}
+
+// ForecastCosts forecasts the cost of the given workload over the requested
+// number of periods at current host prices.
+func (c *Client) ForecastCosts(ctx context.Context, req api.CostForecastRequest) (resp api.CostForecastResponse, err error) {
+	err = c.c.POST(ctx, "/forecast", req, &resp)
+	return
+}
+
+// SimulateMaintenance simulates a round of contract maintenance using the
+// given config and gouging settings, without forming, renewing or refreshing
+// any contracts or updating any state.
+func (c *Client) SimulateMaintenance(ctx context.Context, req api.MaintenanceSimulationRequest) (resp api.MaintenanceSimulationResponse, err error) {
+	err = c.c.POST(ctx, "/maintenance/simulate", req, &resp)
+	return
+}
+
+// ReconcileContracts compares the sector roots of all good contracts on the
+// hosts to the ones in the database. Sectors missing on the host are marked as
+// lost, sectors that aren't referenced are reported as prunable.
+func (c *Client) ReconcileContracts(ctx context.Context) (resp api.ContractsReconcileResponse, err error) {
+	err = c.c.POST(ctx, "/contracts/reconcile", nil, &resp)
+	return
+}
+
 // State returns the current state of the autopilot.
 func (c *Client) State(ctx context.Context) (state api.AutopilotStateResponse, err error) {
 	err = c.c.GET(ctx, "/state", &state)
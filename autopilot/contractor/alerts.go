@@ -21,6 +21,7 @@ var (
 	alertContractUsabilityUpdated     = alerts.RandomAlertID() // constant until restarted
 	alertLostSectorsID                = alerts.RandomAlertID() // constant until restarted
 	alertRenewalFailedID              = alerts.RandomAlertID() // constant until restarted
+	alertSpendingBudgetID             = alerts.RandomAlertID() // constant until restarted
 )
 
 func newContractRenewalFailedAlert(contract api.ContractMetadata, ourFault bool, err error) alerts.Alert {
@@ -71,6 +72,29 @@ func newContractMaintenanceSkippedAlert(reason string) alerts.Alert {
 	}
 }
 
+func newSpendingBudgetAlert(budget *periodBudget) alerts.Alert {
+	severity := alerts.SeverityWarning
+	message := "Spending budget is almost exhausted"
+	hint := "The funds committed to contracts in the current period are close to the spending budget. Once the budget is exhausted, no new contracts are formed and contracts are only renewed if they contain data."
+	if budget.Exhausted() {
+		severity = alerts.SeverityCritical
+		message = "Spending budget is exhausted"
+		hint = "The funds committed to contracts in the current period exceed the spending budget. No new contracts are formed, contracts are not refreshed and only renewed if they contain data, and accounts are no longer funded. Consider increasing the budget."
+	}
+
+	return alerts.Alert{
+		ID:       alertSpendingBudgetID,
+		Severity: severity,
+		Message:  message,
+		Data: map[string]interface{}{
+			"budget": budget.limit.String(),
+			"spent":  budget.spent.String(),
+			"hint":   hint,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
 func registerLostSectorsAlert(dataLost, dataStored uint64) bool {
 	return dataLost > 0 && float64(dataLost) >= float64(dataStored)*alertLostSectorsThresholdPct
 }
@@ -0,0 +1,87 @@
+package contractor
+
+import (
+	"math/big"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+)
+
+const (
+	// alertBudgetThresholdPct is the percentage of the period's spending
+	// budget at which we register an alert warning that the budget is about
+	// to be exhausted.
+	alertBudgetThresholdPct = 90
+)
+
+// A periodBudget keeps track of the spending budget of the current period
+// during a maintenance iteration. A nil budget allows all spending.
+type periodBudget struct {
+	limit types.Currency
+	spent types.Currency
+}
+
+// newPeriodBudget returns the budget of the period ending at the given block
+// height, it returns nil if the contracts config doesn't have a budget.
+func newPeriodBudget(cfg api.ContractsConfig, contracts []api.ContractMetadata, bh uint64) *periodBudget {
+	if cfg.Budget.IsZero() {
+		return nil
+	}
+	return &periodBudget{
+		limit: cfg.Budget,
+		spent: api.PeriodSpending(contracts, bh, cfg.Period),
+	}
+}
+
+// Allows returns true if the given amount can be spent without exceeding the
+// budget.
+func (b *periodBudget) Allows(amount types.Currency) bool {
+	if b == nil {
+		return true
+	}
+	total, overflow := b.spent.AddWithOverflow(amount)
+	return !overflow && total.Cmp(b.limit) <= 0
+}
+
+// Exhausted returns true if the budget is used up.
+func (b *periodBudget) Exhausted() bool {
+	return b != nil && b.spent.Cmp(b.limit) >= 0
+}
+
+// NearLimit returns true if the spending crossed the alert threshold.
+func (b *periodBudget) NearLimit() bool {
+	if b == nil {
+		return false
+	}
+	spent := new(big.Int).Mul(b.spent.Big(), big.NewInt(100))
+	limit := new(big.Int).Mul(b.limit.Big(), big.NewInt(alertBudgetThresholdPct))
+	return spent.Cmp(limit) >= 0
+}
+
+// Spend records that the given contract was formed, renewed or refreshed.
+func (b *periodBudget) Spend(c api.ContractMetadata) {
+	if b == nil {
+		return
+	}
+	b.spent = b.spent.Add(c.RenterCost)
+}
+
+// refreshFunds returns the funds that are added to a contract with the given
+// remaining funds when refreshing it to the given renter funds, the renter
+// is required to add something.
+func refreshFunds(renterFunds, remaining types.Currency) types.Currency {
+	if renterFunds.Cmp(remaining) <= 0 {
+		return types.Siacoins(1)
+	}
+	return renterFunds.Sub(remaining)
+}
+
+// renewalFunds returns the funds that are added when renewing a contract with
+// the given remaining funds, the remaining funds are rolled over into the
+// renewal.
+func renewalFunds(renterFunds, remaining types.Currency) types.Currency {
+	if renterFunds.Cmp(remaining) <= 0 {
+		return types.ZeroCurrency
+	}
+	return renterFunds.Sub(remaining)
+}
@@ -0,0 +1,54 @@
+package contractor
+
+import (
+	"testing"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+)
+
+func TestPeriodBudget(t *testing.T) {
+	contracts := []api.ContractMetadata{
+		{StartHeight: 50, InitialRenterFunds: types.Siacoins(5), RenterCost: types.Siacoins(6)},   // previous period
+		{StartHeight: 51, InitialRenterFunds: types.Siacoins(5), RenterCost: types.Siacoins(6)},   // current period
+		{StartHeight: 150, InitialRenterFunds: types.Siacoins(10), RenterCost: types.Siacoins(3)}, // current period, renewal with rolled over funds
+	}
+
+	// assert no budget allows all spending
+	var b *periodBudget
+	if b = newPeriodBudget(api.ContractsConfig{Period: 100}, contracts, 150); b != nil {
+		t.Fatal("expected nil budget")
+	} else if !b.Allows(types.MaxCurrency) || b.Exhausted() || b.NearLimit() {
+		t.Fatal("unexpected nil budget behaviour")
+	}
+	b.Spend(contracts[0]) // no-op
+
+	// assert only spending of the current period is considered
+	b = newPeriodBudget(api.ContractsConfig{Period: 100, Budget: types.Siacoins(10)}, contracts, 150)
+	if !b.spent.Equals(types.Siacoins(9)) {
+		t.Fatalf("unexpected spending %v", b.spent)
+	} else if !b.Allows(types.Siacoins(1)) || b.Allows(types.Siacoins(2)) {
+		t.Fatal("unexpected allowance")
+	} else if !b.NearLimit() || b.Exhausted() {
+		t.Fatal("expected budget to be near its limit but not exhausted")
+	} else if b.Allows(types.MaxCurrency) {
+		t.Fatal("expected overflow to be disallowed")
+	}
+
+	// spend the remainder and assert the budget is exhausted
+	b.Spend(api.ContractMetadata{InitialRenterFunds: types.Siacoins(5), RenterCost: types.Siacoins(1)})
+	if !b.Exhausted() || b.Allows(types.NewCurrency64(1)) {
+		t.Fatal("expected budget to be exhausted")
+	}
+
+	// assert only the funds that are added count towards the budget
+	if f := refreshFunds(types.Siacoins(5), types.Siacoins(3)); !f.Equals(types.Siacoins(2)) {
+		t.Fatalf("unexpected refresh funds %v", f)
+	} else if f := refreshFunds(types.Siacoins(3), types.Siacoins(5)); !f.Equals(types.Siacoins(1)) {
+		t.Fatalf("unexpected refresh funds %v", f)
+	} else if f := renewalFunds(types.Siacoins(5), types.Siacoins(3)); !f.Equals(types.Siacoins(2)) {
+		t.Fatalf("unexpected renewal funds %v", f)
+	} else if f := renewalFunds(types.Siacoins(3), types.Siacoins(5)); !f.IsZero() {
+		t.Fatalf("unexpected renewal funds %v", f)
+	}
+}
@@ -91,7 +91,7 @@ type ConsensusStore interface {
 type ContractManager interface {
 	BroadcastContract(ctx context.Context, fcid types.FileContractID) (types.TransactionID, error)
 	ContractRevision(ctx context.Context, fcid types.FileContractID) (api.Revision, error)
-	FormContract(ctx context.Context, renterAddress types.Address, renterFunds types.Currency, hostKey types.PublicKey, hostCollateral types.Currency, endHeight uint64) (api.ContractMetadata, error)
+	FormContractInSet(ctx context.Context, renterAddress types.Address, renterFunds types.Currency, hostKey types.PublicKey, hostCollateral types.Currency, endHeight uint64, contractSet string) (api.ContractMetadata, error)
 	RenewContract(ctx context.Context, fcid types.FileContractID, endHeight uint64, renterFunds, minNewCollateral types.Currency) (api.ContractMetadata, error)
 }
 
@@ -205,8 +205,13 @@ func (c *Contractor) formContract(ctx *mCtx, hs HostScanner, host api.Host, logg
 
 	renterFunds, hostCollateral := contractFunding(scan.V2Settings.HostSettings, 0, minRenterAllowance, minHostCollateral, duration)
 
+	// check the budget
+	if !ctx.Budget().Allows(renterFunds) {
+		return api.ContractMetadata{}, true, api.ErrSpendingBudgetExceeded
+	}
+
 	// form contract
-	contract, err := c.cm.FormContract(ctx, ctx.state.Address, renterFunds, hk, hostCollateral, endHeight)
+	contract, err := c.cm.FormContractInSet(ctx, ctx.state.Address, renterFunds, hk, hostCollateral, endHeight, ctx.ContractSet())
 	if err != nil {
 		logger.Debugw("formation failed",
 			zap.Uint64("endHeight", endHeight),
@@ -217,6 +222,7 @@ func (c *Contractor) formContract(ctx *mCtx, hs HostScanner, host api.Host, logg
 		return api.ContractMetadata{}, ourFault, err
 	}
 
+	ctx.Budget().Spend(contract)
 	logger.Infow("formation succeeded",
 		"fcid", contract.ID,
 		"renterFunds", renterFunds.String(),
@@ -254,6 +260,12 @@ func (c *Contractor) refreshContract(ctx *mCtx, contract contract, host api.Host
 	duration := contract.EndHeight() - cs.BlockHeight
 	renterFunds, hostCollateral := contractFunding(host.V2Settings.HostSettings, contract.Size, minRenterAllowance, minHostCollateral, duration)
 
+	// check the budget, refreshes are never essential since the contract
+	// remains usable until it runs out of funds
+	if !ctx.Budget().Allows(refreshFunds(renterFunds, contract.RenterFunds())) {
+		return api.ContractMetadata{}, true, api.ErrSpendingBudgetExceeded
+	}
+
 	// renew the contract
 	renewal, err := c.cm.RenewContract(ctx, contract.ID, contract.EndHeight(), renterFunds, hostCollateral)
 	if err != nil {
@@ -268,6 +280,7 @@ func (c *Contractor) refreshContract(ctx *mCtx, contract contract, host api.Host
 	}
 
 	// add to renewed set
+	ctx.Budget().Spend(renewal)
 	logger.Infow("refresh succeeded",
 		"fcid", renewal.ID,
 		"renewedFrom", renewal.RenewedFrom,
@@ -296,6 +309,9 @@ func (c *Contractor) renewContract(ctx *mCtx, contract contract, host api.Host,
 		return api.ContractMetadata{}, true, err
 	}
 	endHeight := ctx.EndHeight(cs.BlockHeight)
+	if contract.Renewal != nil {
+		endHeight = cs.BlockHeight + contract.Renewal.Period + contract.Renewal.RenewWindow
+	}
 	// sanity check the endheight is not the same on renewals
 	if endHeight <= contract.ProofHeight {
 		logger.Infow("invalid renewal endheight", "oldEndheight", contract.EndHeight(), "newEndHeight", endHeight, "bh", cs.BlockHeight)
@@ -306,6 +322,18 @@ func (c *Contractor) renewContract(ctx *mCtx, contract contract, host api.Host,
 	// calculate the renter funds for the renewal a.k.a. the funds the renter will
 	// be able to spend
 	renterFunds, hostCollateral := contractFunding(host.V2Settings.HostSettings, 0, minRenterAllowance, minHostCollateral, duration)
+	if contract.Renewal != nil {
+		renterFunds, hostCollateral = contract.Renewal.RenterFunds, contract.Renewal.MinNewCollateral
+	}
+
+	// check the budget, renewals of contracts that contain data are essential
+	// since we would lose the data otherwise
+	if !ctx.Budget().Allows(renewalFunds(renterFunds, contract.RenterFunds())) {
+		if contract.Size == 0 {
+			return api.ContractMetadata{}, true, api.ErrSpendingBudgetExceeded
+		}
+		logger.Warnw("renewing contract despite exceeding the spending budget to avoid losing data", "renterFunds", renterFunds)
+	}
 
 	// renew the contract
 	renewal, err := c.cm.RenewContract(ctx, fcid, endHeight, renterFunds, hostCollateral)
@@ -320,6 +348,7 @@ func (c *Contractor) renewContract(ctx *mCtx, contract contract, host api.Host,
 		return api.ContractMetadata{}, ourFault, err
 	}
 
+	ctx.Budget().Spend(renewal)
 	logger.Infow(
 		"renewal succeeded",
 		"fcid", renewal.ID,
@@ -602,7 +631,14 @@ func performContractChecks(ctx *mCtx, alerter alerts.Alerter, s Database, churn
 	// necessary and filtering out contracts that should no longer be used
 	logger.With("contracts", len(contracts)).Info("checking existing contracts")
 
-	var renewed, refreshed, wasGood uint64
+	// check the contracts that end first first, so they are prioritised when
+	// the number of renewals per cycle is capped
+	sort.SliceStable(contracts, func(i, j int) bool {
+		return contracts[i].EndHeight() < contracts[j].EndHeight()
+	})
+	maxRenewals := ctx.AutopilotConfig().Contracts.MaxRenewalsPerCycle
+
+	var renewals, renewed, refreshed, deferred, wasGood uint64
 	for _, c := range contracts {
 		cm := c.ContractMetadata
 		if cm.IsGood() {
@@ -627,7 +663,8 @@ func performContractChecks(ctx *mCtx, alerter alerts.Alerter, s Database, churn
 			With("blockHeight", cs.BlockHeight)
 		logger.Debug("checking contract")
 
-		// check if contract is ready to be archived.
+		// check if contract is ready to be archived, this applies to manual
+		// contracts as well since it only depends on the state of the chain
 		if reason := cc.shouldArchive(c, cs.BlockHeight, network); reason != nil {
 			if err := s.ArchiveContracts(ctx, map[types.FileContractID]string{c.ID: reason.Error()}); err != nil {
 				logger.With(zap.Error(err)).Error("failed to archive contract")
@@ -637,6 +674,32 @@ func performContractChecks(ctx *mCtx, alerter alerts.Alerter, s Database, churn
 			continue
 		}
 
+		// manual contracts are not maintained by the contractor, their
+		// usability is never updated and they are only renewed if they have
+		// renewal settings
+		if c.Manual {
+			if !isManualRenewalDue(c, cs.BlockHeight) {
+				logger.Debug("ignoring manual contract")
+				continue
+			}
+			host, err := s.Host(ctx, c.HostKey)
+			if err != nil {
+				logger.With(zap.Error(err)).Warn("missing host for manual contract")
+				continue
+			}
+			if _, ourFault, err := cr.renewContract(ctx, c, host, logger); err != nil {
+				logger.Debugw("failed to renew manual contract", zap.Bool("ourFault", ourFault), zap.Error(err))
+				if !isErrHostOutOfFunds(err) && !errors.Is(err, api.ErrSpendingBudgetExceeded) {
+					alerter.RegisterAlert(ctx, newContractRenewalFailedAlert(cm, ourFault, err))
+				}
+			} else {
+				logger.Info("successfully renewed manual contract")
+				alerter.DismissAlerts(ctx, alerts.IDForContract(alertRenewalFailedID, cm.ID))
+				renewed++
+			}
+			continue
+		}
+
 		// fetch host
 		host, err := s.Host(ctx, c.HostKey)
 		if err != nil {
@@ -662,12 +725,28 @@ func performContractChecks(ctx *mCtx, alerter alerts.Alerter, s Database, churn
 			continue
 		}
 
+		// check if the contract set still exists
+		sctx, ok := ctx.WithContractSet(c.ContractSet)
+		if !ok {
+			logger.With("contractSet", c.ContractSet).Info("contract set not found")
+			updateUsability(ctx, host, cm, api.ContractUsabilityBad, api.ErrContractSetNotFound.Error())
+			continue
+		}
+
+		// check if the host is allowed by the contract set
+		if set, _ := sctx.AutopilotConfig().ContractSet(c.ContractSet); !set.Hosts.Allows(host) {
+			logger.With("contractSet", c.ContractSet).Info("host is not allowed by the contract set")
+			updateUsability(ctx, host, cm, api.ContractUsabilityBad, api.ErrContractSetHostNotAllowed.Error())
+			continue
+		}
+
 		// get check
 		if host.Checks == (api.HostChecks{}) {
 			logger.Warn("missing host check")
 			updateUsability(ctx, host, cm, api.ContractUsabilityBad, api.ErrUsabilityHostCheckNotFound.Error())
 			continue
 		}
+		host.Checks = contractSetHostChecks(sctx, host)
 
 		// NOTE: if we have a contract with a host that is not scanned, we
 		// either added the host and contract manually or reset the host scans.
@@ -693,7 +772,7 @@ func performContractChecks(ctx *mCtx, alerter alerts.Alerter, s Database, churn
 		}
 
 		// check if contract is usable
-		usable, needsRefresh, needsRenew, reasons := cc.isUsableContract(ctx.AutopilotConfig(), c, cs.BlockHeight)
+		usable, needsRefresh, needsRenew, reasons := cc.isUsableContract(sctx.AutopilotConfig(), c, cs.BlockHeight)
 
 		// extend logger
 		logger = logger.With("usable", usable).
@@ -701,14 +780,23 @@ func performContractChecks(ctx *mCtx, alerter alerts.Alerter, s Database, churn
 			With("needsRenew", needsRenew).
 			With("reasons", reasons)
 
+		// defer the renewal if we've reached the max number of renewals for
+		// this cycle, unless the contract is no longer usable
+		if needsRenew && usable && maxRenewals > 0 && renewals >= maxRenewals {
+			logger.Debug("deferring renewal, max renewals per cycle reached")
+			needsRenew = false
+			deferred++
+		}
+
 		// renew/refresh as necessary
 		var ourFault bool
 		if needsRenew {
+			renewals++
 			var renewedContract api.ContractMetadata
-			renewedContract, ourFault, err = cr.renewContract(ctx, c, host, logger)
+			renewedContract, ourFault, err = cr.renewContract(sctx, c, host, logger)
 			if err != nil {
 				logger.Debugw("failed to renew contract", zap.Bool("ourFault", ourFault), zap.Error(err))
-				if !isErrHostOutOfFunds(err) { // don't register if host ran out of funds, nothing we can do
+				if !isErrHostOutOfFunds(err) && !errors.Is(err, api.ErrSpendingBudgetExceeded) { // don't register if host ran out of funds or the budget is exceeded, the latter has its own alert
 					alerter.RegisterAlert(ctx, newContractRenewalFailedAlert(cm, ourFault, err))
 				}
 			} else {
@@ -720,10 +808,10 @@ func performContractChecks(ctx *mCtx, alerter alerts.Alerter, s Database, churn
 			}
 		} else if needsRefresh {
 			var refreshedContract api.ContractMetadata
-			refreshedContract, ourFault, err = cr.refreshContract(ctx, c, host, logger)
+			refreshedContract, ourFault, err = cr.refreshContract(sctx, c, host, logger)
 			if err != nil {
 				logger.Debugw("failed to refresh contract", zap.Bool("ourFault", ourFault), zap.Error(err))
-				if !isErrHostOutOfFunds(err) { // don't register if host ran out of funds, nothing we can do
+				if !isErrHostOutOfFunds(err) && !errors.Is(err, api.ErrSpendingBudgetExceeded) { // don't register if host ran out of funds or the budget is exceeded, the latter has its own alert
 					alerter.RegisterAlert(ctx, newContractRenewalFailedAlert(cm, ourFault, err))
 				}
 			} else {
@@ -762,16 +850,17 @@ func performContractChecks(ctx *mCtx, alerter alerts.Alerter, s Database, churn
 	logger.
 		With("refreshed", refreshed).
 		With("renewed", renewed).
+		With("deferred", deferred).
 		With("updated", len(updates)).
 		Info("contract checks done")
 	return uint64(len(updates)), nil
 }
 
-// performContractFormations forms up to 'wanted' new contracts with hosts. The
-// 'ipFilter' and 'remainingFunds' are updated with every new contract.
+// performContractFormations forms new contracts for the default contract set
+// and all named contract sets, each set is topped up to its wanted amount of
+// contracts. The 'ipFilter' and 'remainingFunds' are updated with every new
+// contract.
 func performContractFormations(ctx *mCtx, bus Database, cr contractReviser, hf hostFilter, hs HostScanner, logger *zap.SugaredLogger) (uint64, error) {
-	wanted := int(ctx.WantedContracts())
-
 	// fetch all active contracts
 	contracts, err := bus.Contracts(ctx, api.ContractsOpts{
 		FilterMode: api.ContractFilterModeActive,
@@ -780,21 +869,12 @@ func performContractFormations(ctx *mCtx, bus Database, cr contractReviser, hf h
 		return 0, fmt.Errorf("failed to fetch contracts: %w", err)
 	}
 
-	// collect all hosts
+	// collect all hosts, a host is never used by more than one set
 	usedHosts := make(map[types.PublicKey]struct{})
 	for _, c := range contracts {
-		if c.IsGood() {
-			wanted--
-		}
 		usedHosts[c.HostKey] = struct{}{}
 	}
 
-	// return early if no more contracts are needed
-	if wanted <= 0 {
-		logger.Info("already have enough contracts, no need to form new ones")
-		return 0, nil
-	}
-
 	// fetch all good hosts
 	allHosts, err := bus.Hosts(ctx, api.HostOptions{
 		FilterMode:    api.HostFilterModeAllowed,
@@ -804,7 +884,64 @@ func performContractFormations(ctx *mCtx, bus Database, cr contractReviser, hf h
 		return 0, fmt.Errorf("failed to fetch good hosts: %w", err)
 	}
 
+	// maintain the sets that filter hosts by their labels first, they can
+	// only use a subset of the hosts, followed by the default set and the
+	// remaining named sets
+	var labelled, unlabelled []string
+	for name, set := range ctx.AutopilotConfig().ContractSets {
+		if len(set.Hosts.Labels) > 0 {
+			labelled = append(labelled, name)
+		} else {
+			unlabelled = append(unlabelled, name)
+		}
+	}
+	sort.Strings(labelled)
+	sort.Strings(unlabelled)
+	sets := append(append(labelled, ""), unlabelled...)
+
+	var nFormed uint64
+	for _, name := range sets {
+		sctx, _ := ctx.WithContractSet(name)
+		logger := logger
+		if name != "" {
+			logger = logger.With("contractSet", name)
+		}
+
+		formed, done, err := performContractSetFormations(sctx, contracts, allHosts, usedHosts, cr, hf, hs, logger)
+		nFormed += formed
+		if err != nil {
+			return nFormed, err
+		} else if done {
+			break
+		}
+	}
+	return nFormed, nil
+}
+
+// performContractSetFormations forms up to 'wanted' new contracts with hosts
+// for the contract set of the given context. The returned boolean indicates
+// whether formations should be stopped altogether, e.g. because the spending
+// budget was exceeded.
+func performContractSetFormations(ctx *mCtx, contracts []api.ContractMetadata, allHosts []api.Host, usedHosts map[types.PublicKey]struct{}, cr contractReviser, hf hostFilter, hs HostScanner, logger *zap.SugaredLogger) (uint64, bool, error) {
+	wanted := int(ctx.WantedContracts())
+
+	// manual contracts don't count towards the wanted contracts since the
+	// autopilot doesn't maintain them
+	for _, c := range contracts {
+		if c.IsGood() && !c.Manual && c.ContractSet == ctx.ContractSet() {
+			wanted--
+		}
+	}
+
+	// return early if no more contracts are needed
+	if wanted <= 0 {
+		logger.Info("already have enough contracts, no need to form new ones")
+		return 0, false, nil
+	}
+
 	// filter them
+	labelRules := ctx.AutopilotConfig().Hosts.LabelRules
+	set, _ := ctx.AutopilotConfig().ContractSet(ctx.ContractSet())
 	var candidates scoredHosts
 	for _, host := range allHosts {
 		logger := logger.With("hostKey", host.PublicKey)
@@ -818,6 +955,15 @@ func performContractFormations(ctx *mCtx, bus Database, cr contractReviser, hf h
 		} else if score := host.Checks.ScoreBreakdown.Score(); score == 0 {
 			logger.Error("host has a score of 0")
 			continue
+		} else if !labelRules.AllowsNewContracts(host.Labels) {
+			logger.Debugw("host labels don't allow new contracts", "labels", host.Labels)
+			continue
+		} else if !set.Hosts.Allows(host) {
+			logger.Debug("host is filtered out by the contract set")
+			continue
+		} else if hc := contractSetHostChecks(ctx, host); hc.UsabilityBreakdown.LowMaxDuration {
+			logger.Debug("host's max contract duration is lower than the contract set's period")
+			continue
 		}
 		candidates = append(candidates, newScoredHost(host, host.Checks.ScoreBreakdown))
 	}
@@ -832,15 +978,16 @@ func performContractFormations(ctx *mCtx, bus Database, cr contractReviser, hf h
 
 	// form contracts until the new set has the desired size
 	var nFormed uint64
+	var done bool
 	for _, candidate := range candidates {
 		if wanted == 0 {
-			return nFormed, nil // done
+			break
 		}
 
 		// break if the autopilot is stopped
 		select {
 		case <-ctx.Done():
-			return 0, context.Cause(ctx)
+			return 0, true, context.Cause(ctx)
 		default:
 		}
 
@@ -855,9 +1002,14 @@ func performContractFormations(ctx *mCtx, bus Database, cr contractReviser, hf h
 
 		// form the contract
 		_, ourFault, err := cr.formContract(ctx, hs, candidate.host, logger)
-		if err != nil {
+		if errors.Is(err, api.ErrSpendingBudgetExceeded) {
+			logger.Info("spending budget exceeded, skipping remaining contract formations")
+			done = true
+			break
+		} else if err != nil {
 			if ourFault {
 				logger.Warn("failed to form contract, skipping remaining contract formations", zap.Error(err))
+				done = true
 				break
 			}
 			logger.Debugw("failed to form contract", zap.Error(err))
@@ -866,11 +1018,12 @@ func performContractFormations(ctx *mCtx, bus Database, cr contractReviser, hf h
 
 		// add new contract and host
 		hf.Add(ctx, candidate.host)
+		usedHosts[candidate.host.PublicKey] = struct{}{}
 		nFormed++
 		wanted--
 	}
 	logger.With("formedContracts", nFormed).Info("done forming contracts")
-	return nFormed, nil
+	return nFormed, done, nil
 }
 
 // performHostChecks performs scoring and usability checks on all hosts,
@@ -983,6 +1136,11 @@ func performContractMaintenance(ctx *mCtx, alerter alerts.Alerter, s Database, c
 
 	logger.Infow("performing contract maintenance")
 
+	// initialise the spending budget of the current period
+	if err := initPeriodBudget(ctx, s, cs); err != nil {
+		return false, err
+	}
+
 	// STEP 1: perform host checks
 	if err := performHostChecks(ctx, s, cs, logger); err != nil {
 		return false, err
@@ -1001,10 +1159,39 @@ func performContractMaintenance(ctx *mCtx, alerter alerts.Alerter, s Database, c
 		return false, err
 	}
 
+	// update the spending budget alert
+	if budget := ctx.Budget(); budget.NearLimit() {
+		if err := alerter.RegisterAlert(ctx, newSpendingBudgetAlert(budget)); err != nil {
+			logger.With(zap.Error(err)).Error("failed to register spending budget alert")
+		}
+	} else if err := alerter.DismissAlerts(ctx, alertSpendingBudgetID); err != nil {
+		logger.With(zap.Error(err)).Error("failed to dismiss spending budget alert")
+	}
+
 	// STEP 4: perform post maintenance tasks
 	return (nUpdated + nFormed) > 0, performPostMaintenanceTasks(ctx, s, alerter, cc, rb, logger)
 }
 
+// initPeriodBudget initialises the spending budget of the maintenance context
+// using the funds that were committed to contracts in the current period.
+func initPeriodBudget(ctx *mCtx, s Database, cs ConsensusStore) error {
+	if ctx.ContractsConfig().Budget.IsZero() {
+		ctx.budget = nil
+		return nil
+	}
+
+	state, err := cs.ConsensusState(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch consensus state: %w", err)
+	}
+	contracts, err := s.Contracts(ctx, api.ContractsOpts{FilterMode: api.ContractFilterModeAll})
+	if err != nil {
+		return fmt.Errorf("failed to fetch contracts: %w", err)
+	}
+	ctx.budget = newPeriodBudget(ctx.ContractsConfig(), contracts, state.BlockHeight)
+	return nil
+}
+
 // contractFunding is a helper that calculates the funding and collateral
 // that go into forming, refreshing or renewing a contract.
 func contractFunding(settings rhpv4.HostSettings, existingData uint64, minAllowance, minCollateral types.Currency, duration uint64) (allowance, collateral types.Currency) {
@@ -1,6 +1,7 @@
 package contractor
 
 import (
+	"context"
 	"math"
 	"testing"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"go.sia.tech/core/consensus"
 	rhpv4 "go.sia.tech/core/rhp/v4"
 	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/alerts"
 	"go.sia.tech/renterd/v2/api"
 	"go.uber.org/zap"
 	"lukechampine.com/frand"
@@ -247,3 +249,401 @@ func TestShouldForgiveFailedRenewal(t *testing.T) {
 		t.Fatal("expected no failures")
 	}
 }
+
+func TestStaggeredRenewals(t *testing.T) {
+	var cfg api.AutopilotConfig
+	cfg.Contracts.RenewWindow = 100
+	const endHeight = 1000
+
+	// without staggering every contract is renewed at the start of the window
+	var fcid types.FileContractID
+	frand.Read(fcid[:])
+	if renew, _ := isUpForRenewal(cfg, fcid, endHeight, endHeight-101); renew {
+		t.Fatal("contract shouldn't be renewed before the window")
+	} else if renew, secondHalf := isUpForRenewal(cfg, fcid, endHeight, endHeight-100); !renew || secondHalf {
+		t.Fatal("contract should be renewed at the start of the window", renew, secondHalf)
+	}
+
+	// with staggering the renewals are spread over the first half of the
+	// window and every contract is renewed in the second half
+	cfg.Contracts.StaggerRenewals = true
+	heights := make(map[uint64]struct{})
+	for i := 0; i < 100; i++ {
+		frand.Read(fcid[:])
+		var renewHeight uint64
+		for bh := uint64(endHeight - 100); bh <= endHeight; bh++ {
+			if renew, _ := isUpForRenewal(cfg, fcid, endHeight, bh); renew {
+				renewHeight = bh
+				break
+			}
+		}
+		if renewHeight < endHeight-100 || renewHeight > endHeight-50 {
+			t.Fatalf("unexpected renew height %d", renewHeight)
+		} else if renew, secondHalf := isUpForRenewal(cfg, fcid, endHeight, endHeight-50); !renew || !secondHalf {
+			t.Fatal("contract should be renewed in the second half of the window", renew, secondHalf)
+		}
+		heights[renewHeight] = struct{}{}
+	}
+	if len(heights) < 10 {
+		t.Fatalf("expected renewals to be spread out, got %d distinct heights", len(heights))
+	}
+}
+
+func TestIsManualRenewalDue(t *testing.T) {
+	c := contract{
+		ContractMetadata: api.ContractMetadata{
+			Manual:      true,
+			WindowStart: 100,
+		},
+		Revision: &api.Revision{RevisionNumber: 1},
+	}
+
+	// without renewal settings the contract is never renewed
+	if isManualRenewalDue(c, 95) {
+		t.Fatal("contract without renewal settings shouldn't be renewed")
+	}
+
+	// with renewal settings it's renewed within its renew window
+	c.Renewal = &api.ContractRenewalSettings{Period: 50, RenewWindow: 10, RenterFunds: types.Siacoins(1)}
+	if isManualRenewalDue(c, 89) {
+		t.Fatal("contract shouldn't be renewed before the renew window")
+	} else if !isManualRenewalDue(c, 90) {
+		t.Fatal("contract should be renewed at the start of the renew window")
+	} else if !isManualRenewalDue(c, 100) {
+		t.Fatal("contract should be renewed at the end of the renew window")
+	} else if isManualRenewalDue(c, 101) {
+		t.Fatal("expired contract shouldn't be renewed")
+	}
+
+	// renewed contracts are not renewed again
+	c.Revision.RevisionNumber = math.MaxUint64
+	if isManualRenewalDue(c, 95) {
+		t.Fatal("renewed contract shouldn't be renewed")
+	}
+}
+
+func TestContractSetContext(t *testing.T) {
+	state := &MaintenanceState{
+		AP: api.AutopilotConfig{
+			Contracts: api.ContractsConfig{Amount: 3, Period: 100, RenewWindow: 10, Budget: types.Siacoins(10), MaxRenewalsPerCycle: 5},
+			ContractSets: map[string]api.ContractSetConfig{
+				"archive": {
+					Contracts: api.ContractsConfig{Amount: 5, Period: 1000, RenewWindow: 100},
+					Hosts:     api.ContractSetHostFilter{Labels: []string{"archive"}, MaxStoragePrice: types.NewCurrency64(10)},
+				},
+			},
+		},
+	}
+	ctx := newMaintenanceCtx(context.Background(), state)
+
+	// assert unknown sets are reported
+	if _, ok := ctx.WithContractSet("unknown"); ok {
+		t.Fatal("expected unknown set to not be found")
+	}
+
+	// assert the default set uses the default config
+	if dctx, ok := ctx.WithContractSet(""); !ok || dctx.WantedContracts() != 3 || dctx.EndHeight(0) != 110 {
+		t.Fatal("unexpected default set context")
+	}
+
+	// assert the archive set uses its own config but shares the budget and
+	// the renewal cap with the default set
+	sctx, ok := ctx.WithContractSet("archive")
+	if !ok {
+		t.Fatal("expected archive set to be found")
+	} else if sctx.ContractSet() != "archive" {
+		t.Fatal("unexpected contract set", sctx.ContractSet())
+	} else if sctx.WantedContracts() != 5 || sctx.EndHeight(0) != 1100 {
+		t.Fatal("unexpected archive set config", sctx.ContractsConfig())
+	} else if !sctx.ContractsConfig().Budget.Equals(types.Siacoins(10)) || sctx.ContractsConfig().MaxRenewalsPerCycle != 5 {
+		t.Fatal("expected budget and renewal cap to be shared", sctx.ContractsConfig())
+	} else if ctx.WantedContracts() != 3 {
+		t.Fatal("default context shouldn't be modified")
+	}
+
+	// assert the set's host filter is applied
+	var h api.Host
+	h.Labels = []string{"archive"}
+	h.V2Settings.Prices.StoragePrice = types.NewCurrency64(10)
+	filter := state.AP.ContractSets["archive"].Hosts
+	if !filter.Allows(h) {
+		t.Fatal("expected host to be allowed")
+	}
+	h.V2Settings.Prices.StoragePrice = types.NewCurrency64(11)
+	if filter.Allows(h) {
+		t.Fatal("expected host exceeding the max storage price to be filtered")
+	}
+	h.V2Settings.Prices.StoragePrice = types.NewCurrency64(10)
+	h.Labels = []string{"other"}
+	if filter.Allows(h) {
+		t.Fatal("expected host without label to be filtered")
+	}
+}
+
+type (
+	mockConsensusStore struct{ cs api.ConsensusState }
+
+	mockContractChecker struct {
+		expired  map[types.FileContractID]struct{}
+		unusable map[types.FileContractID]struct{}
+	}
+
+	mockContractManager struct{}
+
+	mockContractReviser struct {
+		formed  []string
+		renewed []types.FileContractID
+	}
+
+	mockDatabase struct {
+		archived  map[types.FileContractID]string
+		contracts []api.ContractMetadata
+		hosts     []api.Host
+		usability map[types.FileContractID]string
+	}
+
+	mockHostFilter struct{}
+)
+
+func (s *mockConsensusStore) ConsensusState(context.Context) (api.ConsensusState, error) {
+	return s.cs, nil
+}
+
+func (s *mockConsensusStore) ConsensusNetwork(context.Context) (consensus.Network, error) {
+	return consensus.Network{}, nil
+}
+
+func (cc *mockContractChecker) isUsableContract(_ api.AutopilotConfig, c contract, _ uint64) (usable, refresh, renew bool, reasons []string) {
+	_, unusable := cc.unusable[c.ID]
+	return !unusable, false, true, nil
+}
+
+func (cc *mockContractChecker) pruneContractRefreshFailures([]api.ContractMetadata) {}
+
+func (cc *mockContractChecker) shouldArchive(c contract, _ uint64, _ consensus.Network) error {
+	if _, expired := cc.expired[c.ID]; expired {
+		return errContractExpired
+	}
+	return nil
+}
+
+func (cm *mockContractManager) BroadcastContract(context.Context, types.FileContractID) (types.TransactionID, error) {
+	return types.TransactionID{}, nil
+}
+
+func (cm *mockContractManager) ContractRevision(_ context.Context, fcid types.FileContractID) (api.Revision, error) {
+	return api.Revision{ContractID: fcid}, nil
+}
+
+func (cm *mockContractManager) FormContractInSet(context.Context, types.Address, types.Currency, types.PublicKey, types.Currency, uint64, string) (api.ContractMetadata, error) {
+	return api.ContractMetadata{}, nil
+}
+
+func (cm *mockContractManager) RenewContract(context.Context, types.FileContractID, uint64, types.Currency, types.Currency) (api.ContractMetadata, error) {
+	return api.ContractMetadata{}, nil
+}
+
+func (cr *mockContractReviser) formContract(ctx *mCtx, _ HostScanner, h api.Host, _ *zap.SugaredLogger) (api.ContractMetadata, bool, error) {
+	cr.formed = append(cr.formed, ctx.ContractSet())
+	return api.ContractMetadata{HostKey: h.PublicKey, ContractSet: ctx.ContractSet()}, false, nil
+}
+
+func (cr *mockContractReviser) renewContract(_ *mCtx, c contract, _ api.Host, _ *zap.SugaredLogger) (api.ContractMetadata, bool, error) {
+	cr.renewed = append(cr.renewed, c.ID)
+	return c.ContractMetadata, false, nil
+}
+
+func (cr *mockContractReviser) refreshContract(_ *mCtx, c contract, _ api.Host, _ *zap.SugaredLogger) (api.ContractMetadata, bool, error) {
+	return c.ContractMetadata, false, nil
+}
+
+func (db *mockDatabase) ArchiveContracts(_ context.Context, toArchive map[types.FileContractID]string) error {
+	if db.archived == nil {
+		db.archived = make(map[types.FileContractID]string)
+	}
+	for fcid, reason := range toArchive {
+		db.archived[fcid] = reason
+	}
+	return nil
+}
+
+func (db *mockDatabase) Contracts(context.Context, api.ContractsOpts) ([]api.ContractMetadata, error) {
+	return db.contracts, nil
+}
+
+func (db *mockDatabase) Host(_ context.Context, hk types.PublicKey) (api.Host, error) {
+	for _, h := range db.hosts {
+		if h.PublicKey == hk {
+			return h, nil
+		}
+	}
+	return api.Host{
+		PublicKey: hk,
+		Checks:    api.HostChecks{ScoreBreakdown: api.HostScoreBreakdown{Age: 1}},
+	}, nil
+}
+
+func (db *mockDatabase) Hosts(context.Context, api.HostOptions) ([]api.Host, error) {
+	return db.hosts, nil
+}
+
+func (db *mockDatabase) UpdateContractUsability(_ context.Context, fcid types.FileContractID, usability string) error {
+	if db.usability == nil {
+		db.usability = make(map[types.FileContractID]string)
+	}
+	db.usability[fcid] = usability
+	return nil
+}
+
+func (db *mockDatabase) UpdateHostCheck(context.Context, types.PublicKey, api.HostChecks) error {
+	return nil
+}
+
+func (hf mockHostFilter) Add(context.Context, api.Host) {}
+
+func (hf mockHostFilter) HasRedundantIP(context.Context, api.Host) bool { return false }
+
+func TestRenewalsPerCycle(t *testing.T) {
+	// create contracts that are all up for renewal, the contracts are not
+	// ordered by their end height and the last one is no longer usable
+	db := &mockDatabase{}
+	for i, endHeight := range []uint64{400, 100, 300, 200, 500} {
+		db.contracts = append(db.contracts, api.ContractMetadata{
+			ID:          types.FileContractID{byte(i + 1)},
+			HostKey:     types.PublicKey{byte(i + 1)},
+			Usability:   api.ContractUsabilityGood,
+			WindowStart: endHeight,
+		})
+	}
+	cc := &mockContractChecker{unusable: map[types.FileContractID]struct{}{db.contracts[4].ID: {}}}
+
+	// perform the contract checks with a cap of 2 renewals per cycle
+	cr := &mockContractReviser{}
+	ctx := newMaintenanceCtx(context.Background(), &MaintenanceState{
+		AP: api.AutopilotConfig{Contracts: api.ContractsConfig{MaxRenewalsPerCycle: 2}},
+	})
+	cs := &mockConsensusStore{cs: api.ConsensusState{BlockHeight: 50}}
+	_, err := performContractChecks(ctx, alerts.NewManager(), db, make(accumulatedChurn), cc, &mockContractManager{}, cr, cs, mockHostFilter{}, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// assert the contracts that end first are renewed first, the others are
+	// deferred except for the unusable contract which is renewed regardless
+	expected := []types.FileContractID{db.contracts[1].ID, db.contracts[3].ID, db.contracts[4].ID}
+	if len(cr.renewed) != len(expected) {
+		t.Fatalf("expected %v renewals, got %v", len(expected), len(cr.renewed))
+	}
+	for i := range expected {
+		if cr.renewed[i] != expected[i] {
+			t.Fatalf("unexpected renewal %d: %v != %v", i, cr.renewed[i], expected[i])
+		}
+	}
+
+	// without a cap every contract is renewed
+	cr.renewed = nil
+	ctx.state.AP.Contracts.MaxRenewalsPerCycle = 0
+	_, err = performContractChecks(ctx, alerts.NewManager(), db, make(accumulatedChurn), cc, &mockContractManager{}, cr, cs, mockHostFilter{}, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatal(err)
+	} else if len(cr.renewed) != len(db.contracts) {
+		t.Fatalf("expected %v renewals, got %v", len(db.contracts), len(cr.renewed))
+	}
+}
+
+func TestManualContractChecks(t *testing.T) {
+	// create an expired and an active manual contract
+	db := &mockDatabase{}
+	for i := 0; i < 2; i++ {
+		db.contracts = append(db.contracts, api.ContractMetadata{
+			ID:          types.FileContractID{byte(i + 1)},
+			HostKey:     types.PublicKey{byte(i + 1)},
+			Manual:      true,
+			Usability:   api.ContractUsabilityBad,
+			WindowStart: 100,
+		})
+	}
+	cc := &mockContractChecker{expired: map[types.FileContractID]struct{}{db.contracts[0].ID: {}}}
+
+	// perform the contract checks
+	cr := &mockContractReviser{}
+	ctx := newMaintenanceCtx(context.Background(), &MaintenanceState{})
+	cs := &mockConsensusStore{cs: api.ConsensusState{BlockHeight: 50}}
+	updates, err := performContractChecks(ctx, alerts.NewManager(), db, make(accumulatedChurn), cc, &mockContractManager{}, cr, cs, mockHostFilter{}, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// assert the expired contract was archived but the usability of the
+	// manual contracts wasn't touched and neither was renewed
+	if len(db.archived) != 1 || db.archived[db.contracts[0].ID] != errContractExpired.Error() {
+		t.Fatalf("unexpected archived contracts %v", db.archived)
+	} else if updates != 0 {
+		t.Fatalf("expected no usability updates, got %v", updates)
+	} else if len(cr.renewed) != 0 {
+		t.Fatalf("expected no renewals, got %v", len(cr.renewed))
+	}
+}
+
+func TestContractSetChecks(t *testing.T) {
+	newHost := func(i byte, maxDuration uint64, labels ...string) api.Host {
+		h := api.Host{
+			PublicKey:        types.PublicKey{i},
+			Labels:           labels,
+			LastAnnouncement: time.Now(),
+			Scanned:          true,
+			Checks:           api.HostChecks{ScoreBreakdown: api.HostScoreBreakdown{Age: 1, Collateral: 1, Interactions: 1, Latency: 1, Prices: 1, StorageRemaining: 1, Throughput: 1, Uptime: 1, Version: 1}},
+		}
+		h.V2Settings.MaxContractDuration = maxDuration
+		h.V2Settings.AcceptingContracts = true
+		return h
+	}
+
+	state := &MaintenanceState{
+		AP: api.AutopilotConfig{
+			Contracts: api.ContractsConfig{Amount: 1, Period: 100},
+			ContractSets: map[string]api.ContractSetConfig{
+				"archive": {
+					Contracts: api.ContractsConfig{Amount: 1, Period: 1000},
+					Hosts:     api.ContractSetHostFilter{Labels: []string{"archive"}},
+				},
+			},
+		},
+	}
+	ctx := newMaintenanceCtx(context.Background(), state)
+	sctx, _ := ctx.WithContractSet("archive")
+
+	// assert the max duration check is repeated using the set's period
+	h := newHost(1, 500, "archive")
+	if hc := contractSetHostChecks(ctx, h); hc.UsabilityBreakdown.LowMaxDuration {
+		t.Fatal("host should be usable for the default set")
+	} else if hc := contractSetHostChecks(sctx, h); !hc.UsabilityBreakdown.LowMaxDuration {
+		t.Fatal("host should have a low max duration for the archive set")
+	}
+
+	// assert contracts with hosts that aren't allowed by their set are bad
+	db := &mockDatabase{
+		contracts: []api.ContractMetadata{
+			{ID: types.FileContractID{1}, HostKey: types.PublicKey{1}, ContractSet: "archive", Usability: api.ContractUsabilityGood, WindowStart: 2000},
+			{ID: types.FileContractID{2}, HostKey: types.PublicKey{2}, ContractSet: "archive", Usability: api.ContractUsabilityGood, WindowStart: 2000},
+		},
+		hosts: []api.Host{newHost(1, 2000), newHost(2, 2000, "archive")},
+	}
+	cs := &mockConsensusStore{cs: api.ConsensusState{BlockHeight: 50}}
+	_, err := performContractChecks(ctx, alerts.NewManager(), db, make(accumulatedChurn), &mockContractChecker{}, &mockContractManager{}, &mockContractReviser{}, cs, mockHostFilter{}, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatal(err)
+	} else if len(db.usability) != 1 || db.usability[types.FileContractID{1}] != api.ContractUsabilityBad {
+		t.Fatalf("unexpected usability updates %v", db.usability)
+	}
+
+	// assert the labelled set is filled before the default set, the host with
+	// a low max duration for the archive set is only used by the default set
+	db = &mockDatabase{hosts: []api.Host{newHost(1, 2000, "archive"), newHost(2, 2000), newHost(3, 500, "archive")}}
+	cr := &mockContractReviser{}
+	if _, err := performContractFormations(ctx, db, cr, mockHostFilter{}, nil, zap.NewNop().Sugar()); err != nil {
+		t.Fatal(err)
+	} else if len(cr.formed) != 2 || cr.formed[0] != "archive" || cr.formed[1] != "" {
+		t.Fatalf("unexpected formations %v", cr.formed)
+	}
+}
@@ -1,7 +1,10 @@
 package contractor
 
 import (
+	"bytes"
+	"cmp"
 	"errors"
+	"slices"
 
 	"go.sia.tech/core/types"
 	"go.sia.tech/renterd/v2/api"
@@ -21,6 +24,69 @@ func countUsableHosts(cfg api.AutopilotConfig, cs api.ConsensusState, period uin
 	return
 }
 
+// usableHosts returns the hosts that are usable under the given config and
+// settings, ignoring the block height of their prices.
+func usableHosts(cfg api.AutopilotConfig, cs api.ConsensusState, period uint64, rs api.RedundancySettings, gs api.GougingSettings, hosts []api.Host) (usable []scoredHost) {
+	gc := gouging.NewChecker(gs, cs)
+	for _, h := range hosts {
+		h.V2Settings.Prices.TipHeight = cs.BlockHeight
+		sh := scoreHost(h, cfg, gs, rs.Redundancy())
+		if hc := checkHost(gc, sh, minValidScore, period); hc.UsabilityBreakdown.IsUsable() {
+			usable = append(usable, sh)
+		}
+	}
+	return
+}
+
+// EvaluateSelection compares the hosts that are preferred under the current
+// config to the hosts that are preferred under the evaluated config. The
+// preferred hosts are the usable hosts with the highest score, as many as the
+// config's contract amount.
+func EvaluateSelection(current, cfg api.AutopilotConfig, cs api.ConsensusState, rs api.RedundancySettings, gs api.GougingSettings, hosts []api.Host) (diff api.ConfigSelectionDiff) {
+	before := preferredHosts(current, cs, rs, gs, hosts)
+	after := preferredHosts(cfg, cs, rs, gs, hosts)
+
+	for hk := range after {
+		if _, ok := before[hk]; ok {
+			diff.Unchanged++
+		} else {
+			diff.Added = append(diff.Added, hk)
+		}
+	}
+	for hk := range before {
+		if _, ok := after[hk]; !ok {
+			diff.Removed = append(diff.Removed, hk)
+		}
+	}
+
+	// sort for a stable response
+	sortKeys := func(a, b types.PublicKey) int { return bytes.Compare(a[:], b[:]) }
+	slices.SortFunc(diff.Added, sortKeys)
+	slices.SortFunc(diff.Removed, sortKeys)
+	return
+}
+
+func preferredHosts(cfg api.AutopilotConfig, cs api.ConsensusState, rs api.RedundancySettings, gs api.GougingSettings, hosts []api.Host) map[types.PublicKey]struct{} {
+	usable := usableHosts(cfg, cs, cfg.Contracts.Period, rs, gs, hosts)
+
+	// sort by score, break ties using the host key
+	slices.SortFunc(usable, func(a, b scoredHost) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.host.PublicKey[:], b.host.PublicKey[:])
+	})
+	if uint64(len(usable)) > cfg.Contracts.Amount {
+		usable = usable[:cfg.Contracts.Amount]
+	}
+
+	preferred := make(map[types.PublicKey]struct{}, len(usable))
+	for _, sh := range usable {
+		preferred[sh.host.PublicKey] = struct{}{}
+	}
+	return preferred
+}
+
 // EvaluateConfig evaluates the given configuration and if the gouging settings
 // are too strict for the number of contracts required by 'cfg', it will provide
 // a recommendation on how to loosen it.
@@ -108,3 +108,83 @@ func TestOptimiseGougingSetting(t *testing.T) {
 		t.Fatal("unexpected storage price", gs.MaxStoragePrice.ExactString())
 	}
 }
+
+func TestEvaluateSelection(t *testing.T) {
+	// create 10 usable hosts, the first half of which is slow to respond
+	var hosts []api.Host
+	for i := 0; i < 10; i++ {
+		latency := 100 * time.Millisecond
+		if i < 5 {
+			latency = time.Second
+		}
+		hosts = append(hosts, api.Host{
+			KnownSince: time.Unix(0, 0),
+			PublicKey:  types.PublicKey{byte(i)},
+			V2Settings: rhp.HostSettings{
+				HostSettings: rhpv4.HostSettings{
+					AcceptingContracts: true,
+					MaxCollateral:      types.Siacoins(1000),
+					Prices: rhpv4.HostPrices{
+						Collateral: types.Siacoins(1),
+					},
+					ProtocolVersion: [3]uint8{2, 0, 0},
+				},
+			},
+			Interactions: api.HostInteractions{
+				Uptime:                  time.Hour * 1000,
+				LastScan:                time.Now(),
+				LastScanSuccess:         true,
+				SecondToLastScanSuccess: true,
+				ScanLatency:             latency,
+				TotalScans:              100,
+			},
+			LastAnnouncement: time.Unix(0, 0),
+			Scanned:          true,
+		})
+	}
+
+	cfg := api.AutopilotConfig{
+		Contracts: api.ContractsConfig{
+			Amount: 5,
+		},
+	}
+	cs := api.ConsensusState{
+		BlockHeight:   100,
+		LastBlockTime: api.TimeNow(),
+		Synced:        true,
+	}
+	rs := api.RedundancySettings{MinShards: 10, TotalShards: 30}
+	gs := api.GougingSettings{
+		MaxRPCPrice:           types.Siacoins(1),
+		MaxContractPrice:      types.Siacoins(1),
+		MaxDownloadPrice:      types.Siacoins(1),
+		MaxUploadPrice:        types.Siacoins(1),
+		MaxStoragePrice:       types.Siacoins(1),
+		HostBlockHeightLeeway: math.MaxInt32,
+	}
+
+	// assert evaluating the same config doesn't change the selection
+	diff := EvaluateSelection(cfg, cfg, cs, rs, gs, hosts)
+	if len(diff.Added) != 0 || len(diff.Removed) != 0 || diff.Unchanged != 5 {
+		t.Fatalf("unexpected diff %+v", diff)
+	}
+
+	// assert weighing the latency prefers the fast hosts, without weights all
+	// hosts score the same and the ones with the lowest keys are preferred
+	weights := api.DefaultHostScoreWeights
+	weights.Latency.Weight = 1
+	weighted := cfg
+	weighted.Hosts.ScoreWeights = &weights
+
+	diff = EvaluateSelection(cfg, weighted, cs, rs, gs, hosts)
+	if diff.Unchanged != 0 || len(diff.Added) != 5 || len(diff.Removed) != 5 {
+		t.Fatalf("unexpected diff %+v", diff)
+	}
+	for i := 0; i < 5; i++ {
+		if diff.Removed[i] != hosts[i].PublicKey {
+			t.Fatalf("unexpected removed host %v", diff.Removed[i])
+		} else if diff.Added[i] != hosts[i+5].PublicKey {
+			t.Fatalf("unexpected added host %v", diff.Added[i])
+		}
+	}
+}
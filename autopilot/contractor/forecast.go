@@ -0,0 +1,142 @@
+package contractor
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	rhpv4 "go.sia.tech/core/rhp/v4"
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+)
+
+const (
+	// blocksPerMonth is the number of blocks in a month, assuming a block
+	// time of 10 minutes.
+	blocksPerMonth = 144 * 30
+)
+
+var errForecastOverflow = errors.New("forecasted cost overflows")
+
+// ForecastCosts forecasts the cost of the given workload over the requested
+// number of periods. The forecast is based on the median prices of the hosts
+// that are usable under the given config and gouging settings. The tax
+// function is used to compute the siafund fee of a contract with a given
+// payout.
+func ForecastCosts(req api.CostForecastRequest, cfg api.AutopilotConfig, cs api.ConsensusState, gs api.GougingSettings, hosts []api.Host, tax func(payout types.Currency) (types.Currency, error)) (api.CostForecastResponse, error) {
+	if err := req.Validate(); err != nil {
+		return api.CostForecastResponse{}, err
+	}
+
+	// fetch the prices of the usable hosts
+	usable := usableHosts(cfg, cs, req.Period, req.Redundancy, gs, hosts)
+	if len(usable) == 0 {
+		return api.CostForecastResponse{}, api.ErrNoUsableHosts
+	}
+	prices := make([]rhpv4.HostPrices, 0, len(usable))
+	for _, sh := range usable {
+		prices = append(prices, sh.host.V2Settings.Prices)
+	}
+
+	resp := api.CostForecastResponse{
+		Hosts:     uint64(len(hosts)),
+		Usable:    uint64(len(usable)),
+		Contracts: max(cfg.Contracts.Amount, uint64(req.Redundancy.TotalShards)),
+		Prices: api.HostPriceDistribution{
+			ContractPrice: priceDistribution(prices, func(p rhpv4.HostPrices) types.Currency { return p.ContractPrice }),
+			Collateral:    priceDistribution(prices, func(p rhpv4.HostPrices) types.Currency { return p.Collateral }),
+			StoragePrice:  priceDistribution(prices, func(p rhpv4.HostPrices) types.Currency { return p.StoragePrice }),
+			IngressPrice:  priceDistribution(prices, func(p rhpv4.HostPrices) types.Currency { return p.IngressPrice }),
+			EgressPrice:   priceDistribution(prices, func(p rhpv4.HostPrices) types.Currency { return p.EgressPrice }),
+		},
+	}
+
+	// forecast the cost of every period, the data stored grows by the data
+	// uploaded in every preceding period
+	for i := uint64(0); i < req.Periods; i++ {
+		forecast, err := forecastPeriod(req, resp.Prices, resp.Contracts, i, tax)
+		if err != nil {
+			return api.CostForecastResponse{}, err
+		}
+		start := cs.BlockHeight + i*req.Period
+		resp.Periods = append(resp.Periods, api.CostForecastPeriod{
+			StartHeight:  start,
+			EndHeight:    start + req.Period,
+			CostForecast: forecast,
+		})
+		resp.Total = resp.Total.Add(forecast.Total)
+	}
+	return resp, nil
+}
+
+// forecastPeriod forecasts the cost of the i-th period of the forecast.
+func forecastPeriod(req api.CostForecastRequest, prices api.HostPriceDistribution, contracts, i uint64, tax func(types.Currency) (types.Currency, error)) (f api.CostForecast, err error) {
+	// mul multiplies the given price with the given factors and sets err if
+	// the result overflows
+	mul := func(price types.Currency, factors ...uint64) types.Currency {
+		var overflow bool
+		for _, factor := range factors {
+			if price, overflow = price.Mul64WithOverflow(factor); overflow {
+				err = errForecastOverflow
+				return types.ZeroCurrency
+			}
+		}
+		return price
+	}
+
+	// compute the cost of the data stored on and transferred to and from
+	// hosts within a period, uploads are redundant while downloads are not,
+	// prices are multiplied before dividing to avoid losing precision
+	rs := req.Redundancy
+	minShards, totalShards := uint64(rs.MinShards), uint64(rs.TotalShards)
+	storageCost := func(price types.Currency) types.Currency {
+		stored := mul(price, req.Storage, totalShards, req.Period).Div64(minShards)
+		growth := mul(price, req.Upload, req.Period, i, totalShards, req.Period).Div64(blocksPerMonth).Div64(minShards)
+		return stored.Add(growth)
+	}
+
+	f.ContractFees = mul(prices.ContractPrice.Median, contracts)
+	f.Storage = storageCost(prices.StoragePrice.Median)
+	f.Upload = mul(prices.IngressPrice.Median, req.Upload, req.Period, totalShards).Div64(blocksPerMonth).Div64(minShards)
+	f.Download = mul(prices.EgressPrice.Median, req.Download, req.Period).Div64(blocksPerMonth)
+	collateral := storageCost(prices.Collateral.Median)
+	if err != nil {
+		return api.CostForecast{}, err
+	}
+
+	// the siafund fee is paid on the payout of every contract, which covers
+	// the renter's funds, the contract price and the host's collateral
+	renterFunds := f.Storage.Add(f.Upload).Add(f.Download)
+	payout := renterFunds.Add(collateral).Div64(contracts).Add(prices.ContractPrice.Median)
+	fee, err := tax(payout)
+	if err != nil {
+		return api.CostForecast{}, fmt.Errorf("failed to compute siafund fee: %w", err)
+	}
+	f.SiafundFees = mul(fee, contracts)
+	if err != nil {
+		return api.CostForecast{}, err
+	}
+
+	f.Total = f.ContractFees.Add(f.SiafundFees).Add(renterFunds)
+	return f, nil
+}
+
+func priceDistribution(prices []rhpv4.HostPrices, price func(rhpv4.HostPrices) types.Currency) (d api.PriceDistribution) {
+	if len(prices) == 0 {
+		return
+	}
+	values := make([]types.Currency, 0, len(prices))
+	for _, p := range prices {
+		values = append(values, price(p))
+	}
+	slices.SortFunc(values, func(a, b types.Currency) int { return a.Cmp(b) })
+
+	d.Min = values[0]
+	d.Max = values[len(values)-1]
+	if mid := len(values) / 2; len(values)%2 == 1 {
+		d.Median = values[mid]
+	} else {
+		d.Median = values[mid-1].Add(values[mid]).Div64(2)
+	}
+	return
+}
@@ -0,0 +1,147 @@
+package contractor
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	rhpv4 "go.sia.tech/core/rhp/v4"
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/rhp/v4"
+)
+
+func TestForecastCosts(t *testing.T) {
+	// create 3 hosts with different storage prices
+	var hosts []api.Host
+	for i := 0; i < 3; i++ {
+		hosts = append(hosts, api.Host{
+			KnownSince: time.Unix(0, 0),
+			PublicKey:  types.PublicKey{byte(i)},
+			V2Settings: rhp.HostSettings{
+				HostSettings: rhpv4.HostSettings{
+					AcceptingContracts:  true,
+					MaxCollateral:       types.Siacoins(1000),
+					MaxContractDuration: 1000,
+					Prices: rhpv4.HostPrices{
+						ContractPrice: types.NewCurrency64(100),
+						Collateral:    types.NewCurrency64(2),
+						StoragePrice:  types.NewCurrency64(uint64(i + 1)),
+						IngressPrice:  types.NewCurrency64(10),
+						EgressPrice:   types.NewCurrency64(20),
+					},
+					ProtocolVersion: [3]uint8{2, 0, 0},
+				},
+			},
+			Interactions: api.HostInteractions{
+				Uptime:                  time.Hour * 1000,
+				LastScan:                time.Now(),
+				LastScanSuccess:         true,
+				SecondToLastScanSuccess: true,
+				TotalScans:              100,
+			},
+			LastAnnouncement: time.Unix(0, 0),
+			Scanned:          true,
+		})
+	}
+
+	cfg := api.AutopilotConfig{Contracts: api.ContractsConfig{Amount: 3}}
+	cs := api.ConsensusState{
+		BlockHeight:   100,
+		LastBlockTime: api.TimeNow(),
+		Synced:        true,
+	}
+	gs := api.GougingSettings{
+		MaxRPCPrice:           types.Siacoins(1),
+		MaxContractPrice:      types.Siacoins(1),
+		MaxDownloadPrice:      types.Siacoins(1),
+		MaxUploadPrice:        types.Siacoins(1),
+		MaxStoragePrice:       types.Siacoins(1),
+		HostBlockHeightLeeway: math.MaxInt32,
+	}
+	req := api.CostForecastRequest{
+		Storage:    1000,
+		Upload:     10 * blocksPerMonth,
+		Download:   5 * blocksPerMonth,
+		Period:     100,
+		Periods:    3,
+		Redundancy: api.RedundancySettings{MinShards: 1, TotalShards: 2},
+	}
+	tax := func(payout types.Currency) (types.Currency, error) { return payout.Div64(10), nil }
+
+	// assert invalid requests are rejected
+	invalid := req
+	invalid.Periods = 0
+	if _, err := ForecastCosts(invalid, cfg, cs, gs, hosts, tax); !errors.Is(err, api.ErrInvalidCostForecastRequest) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// assert we need usable hosts
+	if _, err := ForecastCosts(req, cfg, cs, gs, nil, tax); !errors.Is(err, api.ErrNoUsableHosts) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// forecast the costs
+	res, err := ForecastCosts(req, cfg, cs, gs, hosts, tax)
+	if err != nil {
+		t.Fatal(err)
+	} else if res.Usable != 3 || res.Contracts != 3 {
+		t.Fatalf("unexpected number of usable hosts or contracts, %v %v", res.Usable, res.Contracts)
+	} else if res.Prices.StoragePrice != (api.PriceDistribution{Min: types.NewCurrency64(1), Median: types.NewCurrency64(2), Max: types.NewCurrency64(3)}) {
+		t.Fatalf("unexpected storage price distribution %+v", res.Prices.StoragePrice)
+	}
+
+	// 2000 bytes are stored initially and 2000 bytes are uploaded per period,
+	// the data stored grows by the uploaded data in every period, 500 bytes
+	// are downloaded per period
+	expected := []api.CostForecast{
+		{
+			ContractFees: types.NewCurrency64(300),    // 3 contracts * 100
+			Storage:      types.NewCurrency64(400000), // 2 * 2000 bytes * 100 blocks
+			Upload:       types.NewCurrency64(20000),  // 10 * 2000 bytes
+			Download:     types.NewCurrency64(10000),  // 20 * 500 bytes
+			SiafundFees:  types.NewCurrency64(83028),  // 3 * ((430000 + 400000 collateral) / 3 + 100) / 10
+			Total:        types.NewCurrency64(513328),
+		},
+		{
+			ContractFees: types.NewCurrency64(300),
+			Storage:      types.NewCurrency64(800000), // 2 * 4000 bytes * 100 blocks
+			Upload:       types.NewCurrency64(20000),
+			Download:     types.NewCurrency64(10000),
+			SiafundFees:  types.NewCurrency64(163029), // 3 * ((830000 + 800000 collateral) / 3 + 100) / 10
+			Total:        types.NewCurrency64(993329),
+		},
+		{
+			ContractFees: types.NewCurrency64(300),
+			Storage:      types.NewCurrency64(1200000), // 2 * 6000 bytes * 100 blocks
+			Upload:       types.NewCurrency64(20000),
+			Download:     types.NewCurrency64(10000),
+			SiafundFees:  types.NewCurrency64(243030), // 3 * ((1230000 + 1200000 collateral) / 3 + 100) / 10
+			Total:        types.NewCurrency64(1473330),
+		},
+	}
+	if len(res.Periods) != 3 {
+		t.Fatalf("unexpected number of periods %v", len(res.Periods))
+	}
+	for i, p := range res.Periods {
+		if p.CostForecast != expected[i] {
+			t.Fatalf("unexpected forecast %+v", p.CostForecast)
+		} else if p.StartHeight != 100+uint64(i)*100 || p.EndHeight != p.StartHeight+100 {
+			t.Fatalf("unexpected period %v-%v", p.StartHeight, p.EndHeight)
+		}
+	}
+	if !res.Total.Equals(types.NewCurrency64(2979987)) {
+		t.Fatalf("unexpected total %v", res.Total)
+	}
+
+	// assert uploads smaller than a block's worth per month aren't rounded
+	// down to zero
+	req.Upload = blocksPerMonth - 1
+	res, err = ForecastCosts(req, cfg, cs, gs, hosts, tax)
+	if err != nil {
+		t.Fatal(err)
+	} else if !res.Periods[0].Upload.Equals(types.NewCurrency64(1999)) { // 10 * 4319 * 100 * 2 / 4320
+		t.Fatalf("unexpected upload cost %v", res.Periods[0].Upload)
+	}
+}
@@ -1,6 +1,7 @@
 package contractor
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"math"
@@ -113,7 +114,7 @@ func (c *Contractor) isUsableContract(cfg api.AutopilotConfig, contract contract
 			refresh = true
 			renew = false
 		}
-		if shouldRenew, secondHalf := isUpForRenewal(cfg, contract.EndHeight(), bh); shouldRenew {
+		if shouldRenew, secondHalf := isUpForRenewal(cfg, contract.ID, contract.EndHeight(), bh); shouldRenew {
 			reasons = append(reasons, fmt.Errorf("%w; second half: %t", errContractUpForRenewal, secondHalf).Error())
 			usable = usable && !secondHalf // only unusable if in second half of renew window
 			refresh = false
@@ -142,12 +143,50 @@ func (c contract) IsOutOfCollateral() bool {
 	return c.RemainingCollateral().Cmp(minRefreshCollateral) <= 0
 }
 
-func isUpForRenewal(cfg api.AutopilotConfig, endHeight, blockHeight uint64) (shouldRenew, secondHalf bool) {
-	shouldRenew = blockHeight+cfg.Contracts.RenewWindow >= endHeight
+// isUpForRenewal returns whether the contract should be renewed and whether
+// it's in the second half of the renew window. If renewals are staggered, the
+// contract is only renewed after an offset into the renew window that is
+// derived from its id, that offset never exceeds half the window.
+func isUpForRenewal(cfg api.AutopilotConfig, fcid types.FileContractID, endHeight, blockHeight uint64) (shouldRenew, secondHalf bool) {
+	renewWindow := cfg.Contracts.RenewWindow
+	if cfg.Contracts.StaggerRenewals {
+		renewWindow -= renewalOffset(fcid, cfg.Contracts.RenewWindow)
+	}
+	shouldRenew = blockHeight+renewWindow >= endHeight
 	secondHalf = blockHeight+cfg.Contracts.RenewWindow/2 >= endHeight
 	return
 }
 
+// isManualRenewalDue returns whether the given manual contract should be
+// renewed according to its renewal settings.
+func isManualRenewalDue(c contract, bh uint64) bool {
+	if c.Renewal == nil || c.Revision == nil {
+		return false
+	} else if bh > c.EndHeight() || c.Revision.RevisionNumber == math.MaxUint64 || c.RenewedTo != (types.FileContractID{}) {
+		return false
+	}
+	return bh+c.Renewal.RenewWindow >= c.EndHeight()
+}
+
+// renewalOffset returns the number of blocks into the renew window after which
+// the contract with given id is renewed. Contract ids are hashes, so the
+// offsets are evenly spread over the first half of the window.
+func renewalOffset(fcid types.FileContractID, renewWindow uint64) uint64 {
+	return binary.LittleEndian.Uint64(fcid[:8]) % (renewWindow/2 + 1)
+}
+
+// contractSetHostChecks returns the host's checks for the contract set of the
+// given context. The host checks in the database are performed using the
+// period of the default set, so the max duration check is repeated using the
+// set's period.
+func contractSetHostChecks(ctx *mCtx, h api.Host) api.HostChecks {
+	hc := h.Checks
+	if h.IsAnnounced() && h.Scanned {
+		hc.UsabilityBreakdown.LowMaxDuration = ctx.Period() > h.V2Settings.MaxContractDuration
+	}
+	return hc
+}
+
 // checkHost performs a series of checks on the host.
 func checkHost(gc gouging.Checker, sh scoredHost, minScore float64, period uint64) *api.HostChecks {
 	h := sh.host
@@ -20,6 +20,20 @@ const (
 	// price score but is otherwise perfect can at most be 90% less likely to be
 	// picked than a host that has a perfect score.
 	minSubScore = 0.1
+
+	// latencyThreshold is the scan latency up to which a host receives a
+	// perfect latency score.
+	latencyThreshold = 250 * time.Millisecond
+
+	// throughputThreshold is the benchmarked throughput in bytes per second
+	// from which on a host receives a perfect throughput score, it equals one
+	// sector per second.
+	throughputThreshold = rhpv4.SectorSize
+
+	// unbenchmarkedThroughputScore is the throughput score of hosts that
+	// haven't been benchmarked successfully yet. It's neutral, placing them
+	// between hosts that proved to be fast and hosts that proved to be slow.
+	unbenchmarkedThroughputScore = 0.5
 )
 
 // clampScore makes sure that a score can not be smaller than 'minSubScore'.
@@ -67,13 +81,18 @@ func hostScore(cfg api.AutopilotConfig, gs api.GougingSettings, h api.Host, expe
 	remainingStorage := h.V2Settings.RemainingStorage * rhpv4.SectorSize
 	version := 1.0 // v2 only has one version
 
+	// weigh the sub-scores, the weights are applied after clamping to allow
+	// for lowering the impact of a sub-score below the minimum sub-score
+	w := cfg.Hosts.Weights()
 	return api.HostScoreBreakdown{
-		Age:              ageScore(h), // not clamped since values are hardcoded
-		Collateral:       clampScore(collateralScore(uploadSectorCost, maxCollateral, collateral, uint64(allocationPerHost), cCfg.Period)),
-		Interactions:     clampScore(interactionScore(h)),
-		Prices:           clampScore(priceAdjustmentScore(egressPrice, ingressPrice, storagePrice, gs)),
-		StorageRemaining: clampScore(storageRemainingScore(remainingStorage, h.StoredData, allocationPerHost)),
-		Uptime:           clampScore(uptimeScore(h)),
+		Age:              w.Age.Apply(ageScore(h)), // not clamped since values are hardcoded
+		Collateral:       w.Collateral.Apply(clampScore(collateralScore(uploadSectorCost, maxCollateral, collateral, uint64(allocationPerHost), cCfg.Period))),
+		Interactions:     w.Interactions.Apply(clampScore(interactionScore(h))),
+		Latency:          w.Latency.Apply(clampScore(latencyScore(h))),
+		Prices:           w.Prices.Apply(clampScore(priceAdjustmentScore(egressPrice, ingressPrice, storagePrice, gs))),
+		StorageRemaining: w.StorageRemaining.Apply(clampScore(storageRemainingScore(remainingStorage, h.StoredData, allocationPerHost))),
+		Throughput:       w.Throughput.Apply(clampScore(throughputScore(h))),
+		Uptime:           w.Uptime.Apply(clampScore(uptimeScore(h))),
 		Version:          version,
 	}
 }
@@ -242,6 +261,34 @@ func interactionScore(h api.Host) float64 {
 	return math.Pow(success/(success+fail), 10)
 }
 
+// latencyScore computes a score between 0 and 1 for a host given the latency
+// measured during its last successful scan. Hosts that respond within
+// 'latencyThreshold' get a full score, beyond that the score is inversely
+// proportional to the latency. Hosts that haven't been scanned successfully
+// yet aren't penalised.
+func latencyScore(h api.Host) float64 {
+	latency := h.Interactions.ScanLatency
+	if latency <= latencyThreshold {
+		return 1
+	}
+	return float64(latencyThreshold) / float64(latency)
+}
+
+// throughputScore computes a score between 0 and 1 for a host given the
+// slower of the upload and download throughput measured during its last
+// successful benchmark. Hosts that reach 'throughputThreshold' get a full
+// score, below that the score is proportional to the throughput. Hosts that
+// haven't been benchmarked successfully yet get a neutral score.
+func throughputScore(h api.Host) float64 {
+	throughput := min(h.Benchmark.UploadThroughput, h.Benchmark.DownloadThroughput)
+	if throughput == 0 {
+		return unbenchmarkedThroughputScore
+	} else if throughput >= throughputThreshold {
+		return 1
+	}
+	return float64(throughput) / float64(throughputThreshold)
+}
+
 func uptimeScore(h api.Host) float64 {
 	secondToLastScanSuccess := h.Interactions.SecondToLastScanSuccess
 	lastScanSuccess := h.Interactions.LastScanSuccess
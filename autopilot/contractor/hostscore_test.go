@@ -138,6 +138,80 @@ func TestHostScore(t *testing.T) {
 	}
 }
 
+func TestHostScoreWeights(t *testing.T) {
+	h1 := test.NewHost(test.RandomHostKey(), test.NewHostSettings())
+	h2 := test.NewHost(test.RandomHostKey(), test.NewHostSettings())
+	gs := api.GougingSettings{
+		MaxUploadPrice:   types.NewCurrency64(1000000000000),
+		MaxStoragePrice:  types.NewCurrency64(3000000000),
+		MaxDownloadPrice: types.NewCurrency64(100000000000000),
+	}
+	redundancy := 3.0
+
+	// assert latency doesn't affect the score by default
+	h2.Interactions.ScanLatency = time.Second
+	if hostScore(cfg, gs, h1, redundancy).Score() != hostScore(cfg, gs, h2, redundancy).Score() {
+		t.Fatal("unexpected")
+	}
+
+	// assert latency affects the score once it's weighted
+	weights := api.DefaultHostScoreWeights
+	weights.Latency = api.ScoreWeight{Weight: 1, Exponent: 1}
+	wCfg := cfg
+	wCfg.Hosts.ScoreWeights = &weights
+	if sb := hostScore(wCfg, gs, h2, redundancy); sb.Latency != 0.25 {
+		t.Fatal("unexpected latency score", sb.Latency)
+	} else if sb.Score() >= hostScore(wCfg, gs, h1, redundancy).Score() {
+		t.Fatal("unexpected")
+	}
+
+	// assert throughput only affects the score once it's weighted and that
+	// hosts that weren't benchmarked get a neutral score
+	h2 = test.NewHost(test.RandomHostKey(), test.NewHostSettings())
+	h2.Benchmark.UploadThroughput = throughputThreshold
+	h2.Benchmark.DownloadThroughput = throughputThreshold / 4
+	if hostScore(cfg, gs, h2, redundancy).Throughput != 1 {
+		t.Fatal("unexpected")
+	}
+	weights.Throughput = api.ScoreWeight{Weight: 1, Exponent: 1}
+	h3 := test.NewHost(test.RandomHostKey(), test.NewHostSettings())
+	h3.Benchmark.UploadThroughput = throughputThreshold
+	h3.Benchmark.DownloadThroughput = throughputThreshold
+	if sb := hostScore(wCfg, gs, h2, redundancy); sb.Throughput != 0.25 {
+		t.Fatal("unexpected throughput score", sb.Throughput)
+	} else if sb := hostScore(wCfg, gs, h1, redundancy); sb.Throughput != unbenchmarkedThroughputScore {
+		t.Fatal("unexpected throughput score", sb.Throughput)
+	} else if sb := hostScore(wCfg, gs, h3, redundancy); sb.Throughput != 1 {
+		t.Fatal("unexpected throughput score", sb.Throughput)
+	}
+
+	// assert a lower weight reduces the impact of a sub-score
+	h2 = test.NewHost(test.RandomHostKey(), test.NewHostSettings())
+	h2.Interactions.FailedInteractions = 10
+	full := hostScore(wCfg, gs, h2, redundancy).Interactions
+	weights.Interactions.Weight = 0.5
+	half := hostScore(wCfg, gs, h2, redundancy).Interactions
+	if half <= full || half != 0.5+0.5*full {
+		t.Fatal("unexpected", full, half)
+	}
+
+	// assert a higher exponent increases the impact of a sub-score
+	weights.Interactions = api.ScoreWeight{Weight: 1, Exponent: 2}
+	if sb := hostScore(wCfg, gs, h2, redundancy); sb.Interactions != full*full {
+		t.Fatal("unexpected", sb.Interactions)
+	}
+
+	// assert a weight of 0 disables a sub-score unless it's 0
+	weights.Interactions = api.ScoreWeight{Weight: 0, Exponent: 1}
+	weights.Collateral = api.ScoreWeight{Weight: 0, Exponent: 1}
+	h2.V2Settings.MaxCollateral = types.ZeroCurrency
+	if sb := hostScore(wCfg, gs, h2, redundancy); sb.Interactions != 1 {
+		t.Fatal("unexpected", sb.Interactions)
+	} else if sb.Collateral != 0 {
+		t.Fatal("unexpected", sb.Collateral)
+	}
+}
+
 func TestPriceAdjustmentScore(t *testing.T) {
 	score := func(mdp, mup, msp uint64) float64 {
 		t.Helper()
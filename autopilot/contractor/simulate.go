@@ -0,0 +1,409 @@
+package contractor
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/alerts"
+	"go.sia.tech/renterd/v2/api"
+	"go.uber.org/zap"
+)
+
+type (
+	// simulation implements the dependencies of the contract maintenance
+	// without performing any RPCs or writes. Reads are passed through to the
+	// database, writes are kept in memory so that later steps of the
+	// maintenance see them and contract formations, renewals and refreshes
+	// are recorded instead of being performed.
+	simulation struct {
+		c  *Contractor
+		db Database
+
+		mu          sync.Mutex
+		archived    map[types.FileContractID]string
+		hostChecks  map[types.PublicKey]api.HostChecks
+		usabilities map[types.FileContractID]string
+		fundings    map[types.FileContractID]api.SimulatedContractFunding
+		churn       accumulatedChurn
+		contracts   map[types.FileContractID]api.ContractMetadata
+
+		res api.MaintenanceSimulationResponse
+	}
+)
+
+var (
+	_ alerts.Alerter      = (*simulation)(nil)
+	_ ContractManager     = (*simulation)(nil)
+	_ Database            = (*simulation)(nil)
+	_ HostScanner         = (*simulation)(nil)
+	_ contractReviser     = (*simulation)(nil)
+	_ revisionBroadcaster = (*simulation)(nil)
+)
+
+// SimulateContractMaintenance performs a round of contract maintenance using
+// the given state without performing any RPCs or updating any state. It
+// returns the contracts that would be archived, marked as bad, renewed or
+// refreshed and the hosts contracts would be formed with. Since no revisions
+// are fetched, the remaining funds of a contract are estimated from its
+// recorded spending and contracts are assumed to have enough collateral left.
+func (c *Contractor) SimulateContractMaintenance(ctx context.Context, state *MaintenanceState) (api.MaintenanceSimulationResponse, error) {
+	if reason, skip := canSkipContractMaintenance(ctx, state.ContractsConfig()); skip {
+		return api.MaintenanceSimulationResponse{Skipped: reason}, nil
+	}
+
+	sim := &simulation{
+		db: c.db,
+
+		archived:    make(map[types.FileContractID]string),
+		hostChecks:  make(map[types.PublicKey]api.HostChecks),
+		usabilities: make(map[types.FileContractID]string),
+		fundings:    make(map[types.FileContractID]api.SimulatedContractFunding),
+		contracts:   make(map[types.FileContractID]api.ContractMetadata),
+	}
+	sim.c = &Contractor{
+		alerter: sim,
+		cm:      sim,
+		cs:      c.cs,
+		db:      sim,
+		hs:      sim,
+		churn:   make(accumulatedChurn),
+		logger:  c.logger.Named("simulation"),
+
+		allowRedundantHostIPs: c.allowRedundantHostIPs,
+
+		revisionBroadcastInterval: c.revisionBroadcastInterval,
+		revisionLastBroadcast:     make(map[types.FileContractID]time.Time),
+		revisionSubmissionBuffer:  c.revisionSubmissionBuffer,
+
+		firstRefreshFailure: make(map[types.FileContractID]time.Time),
+	}
+
+	_, err := performContractMaintenance(newMaintenanceCtx(ctx, state), sim, sim, sim.c.churn, sim.c, sim, sim, c.cs, sim, sim, c.allowRedundantHostIPs, sim.c.logger)
+	if err != nil {
+		return api.MaintenanceSimulationResponse{}, err
+	}
+	return sim.result(), nil
+}
+
+// result returns the actions recorded by the simulation.
+func (s *simulation) result() api.MaintenanceSimulationResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := s.res
+	for fcid, reason := range s.archived {
+		c := s.contracts[fcid]
+		res.Archived = append(res.Archived, api.SimulatedContractUpdate{
+			ContractID:  fcid,
+			HostKey:     c.HostKey,
+			ContractSet: c.ContractSet,
+			Reason:      reason,
+		})
+	}
+	for fcid, updates := range s.churn {
+		if last := updates[len(updates)-1]; last.To == api.ContractUsabilityBad {
+			res.MarkedBad = append(res.MarkedBad, api.SimulatedContractUpdate{
+				ContractID:  fcid,
+				HostKey:     last.HostKey,
+				ContractSet: s.contracts[fcid].ContractSet,
+				Reason:      last.Reason,
+			})
+		}
+	}
+	return res
+}
+
+// Alerts implements the alerts.Alerter interface, the simulation has no
+// alerts.
+func (s *simulation) Alerts(_ context.Context, _ alerts.AlertsOpts) (alerts.AlertsResponse, error) {
+	return alerts.AlertsResponse{}, nil
+}
+
+// RegisterAlert implements the alerts.Alerter interface, alerts aren't
+// registered but the usability updates are taken from the churn alert.
+func (s *simulation) RegisterAlert(_ context.Context, a alerts.Alert) error {
+	if a.ID != alertContractUsabilityUpdated {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.churn, _ = a.Data["churn"].(accumulatedChurn)
+	return nil
+}
+
+// DismissAlerts implements the alerts.Alerter interface.
+func (s *simulation) DismissAlerts(_ context.Context, _ ...types.Hash256) error {
+	return nil
+}
+
+// BroadcastContract implements the ContractManager interface.
+func (s *simulation) BroadcastContract(_ context.Context, _ types.FileContractID) (types.TransactionID, error) {
+	return types.TransactionID{}, nil
+}
+
+// ContractRevision implements the ContractManager interface. Instead of
+// fetching the revision from the host, it is estimated using the contract's
+// metadata.
+func (s *simulation) ContractRevision(_ context.Context, fcid types.FileContractID) (api.Revision, error) {
+	s.mu.Lock()
+	c, ok := s.contracts[fcid]
+	s.mu.Unlock()
+	if !ok {
+		return api.Revision{}, api.ErrContractNotFound
+	}
+
+	var renterFunds types.Currency
+	if spent := c.Spending.Total(); spent.Cmp(c.InitialRenterFunds) < 0 {
+		renterFunds = c.InitialRenterFunds.Sub(spent)
+	}
+	return api.Revision{
+		ContractID:      fcid,
+		MissedHostValue: types.MaxCurrency,
+		RenterFunds:     renterFunds,
+		RevisionNumber:  c.RevisionNumber,
+		Size:            c.Size,
+	}, nil
+}
+
+// FormContractInSet implements the ContractManager interface, it records the
+// formation and returns the metadata of a contract that is never persisted.
+// The contract's renter cost is its renter funds plus the host's contract
+// price so that the simulation spends the budget like a real formation.
+func (s *simulation) FormContractInSet(ctx context.Context, _ types.Address, renterFunds types.Currency, hostKey types.PublicKey, hostCollateral types.Currency, endHeight uint64, contractSet string) (api.ContractMetadata, error) {
+	host, err := s.db.Host(ctx, hostKey)
+	if err != nil {
+		return api.ContractMetadata{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.res.Formed = append(s.res.Formed, api.SimulatedContractFunding{
+		HostKey:        hostKey,
+		ContractSet:    contractSet,
+		EndHeight:      endHeight,
+		RenterFunds:    renterFunds,
+		HostCollateral: hostCollateral,
+	})
+	contractPrice := host.V2Settings.Prices.ContractPrice
+	return api.ContractMetadata{
+		HostKey:            hostKey,
+		State:              api.ContractStatePending,
+		Usability:          api.ContractUsabilityGood,
+		WindowStart:        endHeight,
+		ContractPrice:      contractPrice,
+		InitialRenterFunds: renterFunds,
+		RenterCost:         renterFunds.Add(contractPrice),
+		ContractSet:        contractSet,
+	}, nil
+}
+
+// RenewContract implements the ContractManager interface, it keeps track of
+// the renewal's funding and returns the metadata of a renewal that is never
+// persisted. The renewal's renter cost is the host's contract price plus the
+// renter funds that aren't rolled over from the estimated remaining funds of
+// the renewed contract.
+func (s *simulation) RenewContract(ctx context.Context, fcid types.FileContractID, endHeight uint64, renterFunds, minNewCollateral types.Currency) (api.ContractMetadata, error) {
+	rev, err := s.ContractRevision(ctx, fcid)
+	if err != nil {
+		return api.ContractMetadata{}, err
+	}
+
+	s.mu.Lock()
+	c := s.contracts[fcid]
+	s.mu.Unlock()
+
+	host, err := s.db.Host(ctx, c.HostKey)
+	if err != nil {
+		return api.ContractMetadata{}, err
+	}
+	contractPrice := host.V2Settings.Prices.ContractPrice
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fundings[fcid] = api.SimulatedContractFunding{
+		ContractID:     fcid,
+		HostKey:        c.HostKey,
+		ContractSet:    c.ContractSet,
+		EndHeight:      endHeight,
+		RenterFunds:    renterFunds,
+		HostCollateral: minNewCollateral,
+	}
+	s.usabilities[fcid] = api.ContractUsabilityGood
+
+	renewal := c
+	renewal.ID = types.FileContractID{}
+	renewal.RenewedFrom = fcid
+	renewal.Usability = api.ContractUsabilityGood
+	renewal.WindowStart = endHeight
+	renewal.ContractPrice = contractPrice
+	renewal.InitialRenterFunds = renterFunds
+	renewal.RenterCost = renewalFunds(renterFunds, rev.RenterFunds).Add(contractPrice)
+	renewal.Spending = api.ContractSpending{}
+	return renewal, nil
+}
+
+// ArchiveContracts implements the Database interface.
+func (s *simulation) ArchiveContracts(_ context.Context, toArchive map[types.FileContractID]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for fcid, reason := range toArchive {
+		s.archived[fcid] = reason
+	}
+	return nil
+}
+
+// Contracts implements the Database interface, it applies the archivals and
+// usability updates of the simulation to the contracts in the database.
+func (s *simulation) Contracts(ctx context.Context, opts api.ContractsOpts) ([]api.ContractMetadata, error) {
+	filterGood := opts.FilterMode == api.ContractFilterModeGood
+	if filterGood {
+		opts.FilterMode = api.ContractFilterModeActive
+	}
+	contracts, err := s.db.Contracts(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	filtered := contracts[:0]
+	for _, c := range contracts {
+		if c.ArchivalReason == "" {
+			s.contracts[c.ID] = c
+		}
+		if _, archived := s.archived[c.ID]; archived && opts.FilterMode == api.ContractFilterModeActive {
+			continue
+		} else if usability, ok := s.usabilities[c.ID]; ok {
+			c.Usability = usability
+		}
+		if filterGood && !c.IsGood() {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	return filtered, nil
+}
+
+// Host implements the Database interface, it applies the host checks of the
+// simulation to the host in the database.
+func (s *simulation) Host(ctx context.Context, hostKey types.PublicKey) (api.Host, error) {
+	h, err := s.db.Host(ctx, hostKey)
+	if err != nil {
+		return api.Host{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if hc, ok := s.hostChecks[hostKey]; ok {
+		h.Checks = hc
+	}
+	return h, nil
+}
+
+// Hosts implements the Database interface, it applies the host checks of the
+// simulation to the hosts in the database before filtering them by usability.
+func (s *simulation) Hosts(ctx context.Context, opts api.HostOptions) ([]api.Host, error) {
+	usabilityMode := opts.UsabilityMode
+	opts.UsabilityMode = api.UsabilityFilterModeAll
+	hosts, err := s.db.Hosts(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	filtered := hosts[:0]
+	for _, h := range hosts {
+		if hc, ok := s.hostChecks[h.PublicKey]; ok {
+			h.Checks = hc
+		}
+		switch usabilityMode {
+		case api.UsabilityFilterModeUsable:
+			if h.Checks == (api.HostChecks{}) || !h.Checks.UsabilityBreakdown.IsUsable() {
+				continue
+			}
+		case api.UsabilityFilterModeUnusable:
+			if h.Checks == (api.HostChecks{}) || h.Checks.UsabilityBreakdown.IsUsable() {
+				continue
+			}
+		}
+		filtered = append(filtered, h)
+	}
+	return filtered, nil
+}
+
+// UpdateContractUsability implements the Database interface.
+func (s *simulation) UpdateContractUsability(_ context.Context, contractID types.FileContractID, usability string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usabilities[contractID] = usability
+	return nil
+}
+
+// UpdateHostCheck implements the Database interface.
+func (s *simulation) UpdateHostCheck(_ context.Context, hostKey types.PublicKey, hostCheck api.HostChecks) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hostChecks[hostKey] = hostCheck
+	return nil
+}
+
+// ScanHost implements the HostScanner interface, instead of scanning the host
+// it returns the settings of the host's last scan.
+func (s *simulation) ScanHost(ctx context.Context, hostKey types.PublicKey, _ time.Duration) (api.HostScanResponse, error) {
+	h, err := s.db.Host(ctx, hostKey)
+	if err != nil {
+		return api.HostScanResponse{}, err
+	}
+	return api.HostScanResponse{V2Settings: h.V2Settings}, nil
+}
+
+func (s *simulation) broadcastRevisions(_ context.Context, _ []api.ContractMetadata, _ *zap.SugaredLogger) {
+	// revisions aren't broadcast in a simulation
+}
+
+func (s *simulation) formContract(ctx *mCtx, hs HostScanner, host api.Host, logger *zap.SugaredLogger) (api.ContractMetadata, bool, error) {
+	return s.c.formContract(ctx, hs, host, logger)
+}
+
+func (s *simulation) renewContract(ctx *mCtx, c contract, h api.Host, logger *zap.SugaredLogger) (api.ContractMetadata, bool, error) {
+	renewal, ourFault, err := s.c.renewContract(ctx, c, h, logger)
+	if err == nil {
+		reason := "contract is up for renewal"
+		if c.Manual {
+			reason = "manual contract is up for renewal"
+		}
+		s.recordFunding(&s.res.Renewed, c.ID, reason)
+	}
+	return renewal, ourFault, err
+}
+
+func (s *simulation) refreshContract(ctx *mCtx, c contract, h api.Host, logger *zap.SugaredLogger) (api.ContractMetadata, bool, error) {
+	renewal, ourFault, err := s.c.refreshContract(ctx, c, h, logger)
+	if err == nil {
+		var reasons []string
+		if c.IsOutOfCollateral() {
+			reasons = append(reasons, errContractOutOfCollateral.Error())
+		}
+		if c.IsOutOfFunds() {
+			reasons = append(reasons, errContractOutOfFunds.Error())
+		}
+		s.recordFunding(&s.res.Refreshed, c.ID, strings.Join(reasons, ","))
+	}
+	return renewal, ourFault, err
+}
+
+// recordFunding moves the funding of the renewal of the given contract to the
+// given list of simulated actions.
+func (s *simulation) recordFunding(fundings *[]api.SimulatedContractFunding, fcid types.FileContractID, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f, ok := s.fundings[fcid]; ok {
+		f.Reason = reason
+		*fundings = append(*fundings, f)
+		delete(s.fundings, fcid)
+	}
+}
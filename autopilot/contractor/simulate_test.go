@@ -0,0 +1,177 @@
+package contractor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+	"go.uber.org/zap"
+)
+
+type simulationTestDB struct {
+	Database
+
+	contracts []api.ContractMetadata
+	hosts     []api.Host
+}
+
+func (db *simulationTestDB) Contracts(_ context.Context, opts api.ContractsOpts) ([]api.ContractMetadata, error) {
+	var contracts []api.ContractMetadata
+	for _, c := range db.contracts {
+		if opts.FilterMode != api.ContractFilterModeGood || c.IsGood() {
+			contracts = append(contracts, c)
+		}
+	}
+	return contracts, nil
+}
+
+func (db *simulationTestDB) Host(_ context.Context, hk types.PublicKey) (api.Host, error) {
+	for _, h := range db.hosts {
+		if h.PublicKey == hk {
+			return h, nil
+		}
+	}
+	return api.Host{}, api.ErrHostNotFound
+}
+
+func (db *simulationTestDB) Hosts(_ context.Context, opts api.HostOptions) ([]api.Host, error) {
+	if opts.UsabilityMode != api.UsabilityFilterModeAll {
+		panic("simulation should filter by usability")
+	}
+	return append([]api.Host(nil), db.hosts...), nil
+}
+
+func TestSimulation(t *testing.T) {
+	db := &simulationTestDB{
+		contracts: []api.ContractMetadata{
+			{ID: types.FileContractID{1}, Usability: api.ContractUsabilityGood, InitialRenterFunds: types.Siacoins(10), Spending: api.ContractSpending{Uploads: types.Siacoins(4)}},
+			{ID: types.FileContractID{2}, Usability: api.ContractUsabilityGood, InitialRenterFunds: types.Siacoins(10), Spending: api.ContractSpending{Uploads: types.Siacoins(11)}},
+			{ID: types.FileContractID{3}, HostKey: types.PublicKey{1}, Usability: api.ContractUsabilityGood},
+		},
+		hosts: []api.Host{
+			{PublicKey: types.PublicKey{1}},
+			{PublicKey: types.PublicKey{2}},
+		},
+	}
+	sim := &simulation{
+		db:          db,
+		archived:    make(map[types.FileContractID]string),
+		hostChecks:  make(map[types.PublicKey]api.HostChecks),
+		usabilities: make(map[types.FileContractID]string),
+		fundings:    make(map[types.FileContractID]api.SimulatedContractFunding),
+		contracts:   make(map[types.FileContractID]api.ContractMetadata),
+	}
+	db.hosts[0].V2Settings.Prices.ContractPrice = types.Siacoins(1)
+	ctx := context.Background()
+
+	// archive a contract and mark another one as bad
+	sim.ArchiveContracts(ctx, map[types.FileContractID]string{{1}: "expired"})
+	sim.UpdateContractUsability(ctx, types.FileContractID{2}, api.ContractUsabilityBad)
+
+	// assert the updates are applied to the contracts in the database
+	if contracts, _ := sim.Contracts(ctx, api.ContractsOpts{FilterMode: api.ContractFilterModeActive}); len(contracts) != 2 {
+		t.Fatal("expected archived contract to be filtered", len(contracts))
+	} else if contracts, _ := sim.Contracts(ctx, api.ContractsOpts{FilterMode: api.ContractFilterModeGood}); len(contracts) != 1 || contracts[0].ID != (types.FileContractID{3}) {
+		t.Fatal("expected bad contract to be filtered", contracts)
+	} else if db.contracts[1].Usability != api.ContractUsabilityGood {
+		t.Fatal("database shouldn't be updated")
+	}
+
+	// assert revisions are estimated from the spending
+	if _, err := sim.ContractRevision(ctx, types.FileContractID{4}); err == nil {
+		t.Fatal("expected error for unknown contract")
+	} else if rev, err := sim.ContractRevision(ctx, types.FileContractID{1}); err != nil {
+		t.Fatal(err)
+	} else if !rev.RenterFunds.Equals(types.Siacoins(6)) {
+		t.Fatal("unexpected renter funds", rev.RenterFunds)
+	} else if rev, err := sim.ContractRevision(ctx, types.FileContractID{2}); err != nil {
+		t.Fatal(err)
+	} else if !rev.RenterFunds.IsZero() {
+		t.Fatal("expected overspent contract to have no funds", rev.RenterFunds)
+	}
+
+	// assert host checks are applied before filtering by usability
+	sim.UpdateHostCheck(ctx, types.PublicKey{1}, api.HostChecks{ScoreBreakdown: api.HostScoreBreakdown{Age: 1}})
+	sim.UpdateHostCheck(ctx, types.PublicKey{2}, api.HostChecks{UsabilityBreakdown: api.HostUsabilityBreakdown{Gouging: true}})
+	if hosts, _ := sim.Hosts(ctx, api.HostOptions{UsabilityMode: api.UsabilityFilterModeUsable}); len(hosts) != 1 || hosts[0].PublicKey != (types.PublicKey{1}) {
+		t.Fatal("unexpected usable hosts", hosts)
+	} else if hosts, _ := sim.Hosts(ctx, api.HostOptions{UsabilityMode: api.UsabilityFilterModeUnusable}); len(hosts) != 1 || hosts[0].PublicKey != (types.PublicKey{2}) {
+		t.Fatal("unexpected unusable hosts", hosts)
+	}
+
+	// assert renewals are recorded with their funding and cost the contract
+	// price on top of the renter funds
+	if renewal, err := sim.RenewContract(ctx, types.FileContractID{3}, 100, types.Siacoins(1), types.Siacoins(2)); err != nil {
+		t.Fatal(err)
+	} else if !renewal.RenterCost.Equals(types.Siacoins(2)) {
+		t.Fatal("unexpected renter cost", renewal.RenterCost)
+	}
+	sim.recordFunding(&sim.res.Renewed, types.FileContractID{3}, "contract is up for renewal")
+	if res := sim.result(); len(res.Renewed) != 1 || res.Renewed[0].EndHeight != 100 || !res.Renewed[0].RenterFunds.Equals(types.Siacoins(1)) || !res.Renewed[0].HostCollateral.Equals(types.Siacoins(2)) {
+		t.Fatalf("unexpected renewals %+v", res.Renewed)
+	} else if len(res.Archived) != 1 || res.Archived[0].Reason != "expired" {
+		t.Fatalf("unexpected archivals %+v", res.Archived)
+	}
+}
+
+func TestSimulationBudget(t *testing.T) {
+	db := &simulationTestDB{
+		contracts: []api.ContractMetadata{
+			{ID: types.FileContractID{1}, HostKey: types.PublicKey{1}, Usability: api.ContractUsabilityGood, InitialRenterFunds: types.Siacoins(10), Spending: api.ContractSpending{Uploads: types.Siacoins(4)}},
+		},
+	}
+	for i := 1; i <= 3; i++ {
+		h := api.Host{PublicKey: types.PublicKey{byte(i)}}
+		h.V2Settings.Prices.ContractPrice = types.Siacoins(1)
+		db.hosts = append(db.hosts, h)
+	}
+	sim := &simulation{
+		db:          db,
+		archived:    make(map[types.FileContractID]string),
+		hostChecks:  make(map[types.PublicKey]api.HostChecks),
+		usabilities: make(map[types.FileContractID]string),
+		fundings:    make(map[types.FileContractID]api.SimulatedContractFunding),
+		contracts:   make(map[types.FileContractID]api.ContractMetadata),
+	}
+	sim.c = &Contractor{
+		cm:     sim,
+		cs:     &mockConsensusStore{cs: api.ConsensusState{BlockHeight: 100}},
+		db:     sim,
+		hs:     sim,
+		logger: zap.NewNop().Sugar(),
+	}
+
+	// configure a budget that allows for a renewal and a single formation
+	cfg := api.ContractsConfig{Period: 100, RenewWindow: 10, Budget: types.Siacoins(20)}
+	ctx := newMaintenanceCtx(context.Background(), &MaintenanceState{AP: api.AutopilotConfig{Contracts: cfg}})
+	ctx.budget = newPeriodBudget(cfg, nil, 100)
+
+	// renew the contract, the remaining 6SC are rolled over so the renewal
+	// costs the contract price plus the 4SC that are added
+	if _, err := sim.Contracts(ctx, api.ContractsOpts{}); err != nil {
+		t.Fatal(err)
+	}
+	c := contract{ContractMetadata: db.contracts[0], Revision: &api.Revision{RenterFunds: types.Siacoins(6)}}
+	if _, _, err := sim.renewContract(ctx, c, db.hosts[0], zap.NewNop().Sugar()); err != nil {
+		t.Fatal(err)
+	} else if !ctx.Budget().spent.Equals(types.Siacoins(5)) {
+		t.Fatal("unexpected spending", ctx.Budget().spent)
+	}
+
+	// form contracts until the budget is exhausted, the minimum allowance of
+	// 10SC plus the contract price fit once
+	if _, _, err := sim.formContract(ctx, sim, db.hosts[1], zap.NewNop().Sugar()); err != nil {
+		t.Fatal(err)
+	} else if !ctx.Budget().spent.Equals(types.Siacoins(16)) {
+		t.Fatal("unexpected spending", ctx.Budget().spent)
+	} else if _, _, err := sim.formContract(ctx, sim, db.hosts[2], zap.NewNop().Sugar()); !errors.Is(err, api.ErrSpendingBudgetExceeded) {
+		t.Fatal("expected budget to be exceeded", err)
+	}
+
+	// assert only the affordable actions were recorded
+	if res := sim.result(); len(res.Renewed) != 1 || len(res.Formed) != 1 || res.Formed[0].HostKey != db.hosts[1].PublicKey {
+		t.Fatalf("unexpected result %+v", res)
+	}
+}
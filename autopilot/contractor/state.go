@@ -25,8 +25,10 @@ type (
 	}
 
 	mCtx struct {
-		ctx   context.Context
-		state *MaintenanceState
+		ctx         context.Context
+		state       *MaintenanceState
+		budget      *periodBudget
+		contractSet string
 	}
 )
 
@@ -41,10 +43,20 @@ func (ctx *mCtx) AutopilotConfig() api.AutopilotConfig {
 	return ctx.state.AP
 }
 
+func (ctx *mCtx) Budget() *periodBudget {
+	return ctx.budget
+}
+
 func (ctx *mCtx) ContractsConfig() api.ContractsConfig {
 	return ctx.state.ContractsConfig()
 }
 
+// ContractSet returns the name of the contract set the context maintains, the
+// empty string refers to the default set.
+func (ctx *mCtx) ContractSet() string {
+	return ctx.contractSet
+}
+
 func (ctx *mCtx) Deadline() (deadline time.Time, ok bool) {
 	return ctx.ctx.Deadline()
 }
@@ -94,11 +106,38 @@ func (ctx *mCtx) WantedContracts() uint64 {
 func (ctx *mCtx) WithTimeout(t time.Duration) (*mCtx, context.CancelFunc) {
 	tCtx, cancel := context.WithTimeout(ctx.ctx, t)
 	return &mCtx{
-		ctx:   tCtx,
-		state: ctx.state,
+		ctx:         tCtx,
+		state:       ctx.state,
+		budget:      ctx.budget,
+		contractSet: ctx.contractSet,
 	}, cancel
 }
 
+// WithContractSet returns a context that maintains the contract set with given
+// name, it uses the set's contracts config but shares the spending budget and
+// the renewal cap with the default set. The boolean indicates whether the set
+// exists.
+func (ctx *mCtx) WithContractSet(name string) (*mCtx, bool) {
+	if name == ctx.contractSet {
+		return ctx, true
+	}
+	set, ok := ctx.state.AP.ContractSet(name)
+	if !ok {
+		return nil, false
+	}
+
+	state := *ctx.state
+	state.AP.Contracts = set.Contracts
+	state.AP.Contracts.Budget = ctx.state.AP.Contracts.Budget
+	state.AP.Contracts.MaxRenewalsPerCycle = ctx.state.AP.Contracts.MaxRenewalsPerCycle
+	return &mCtx{
+		ctx:         ctx.ctx,
+		state:       &state,
+		budget:      ctx.budget,
+		contractSet: name,
+	}, true
+}
+
 func (state *MaintenanceState) ContractsConfig() api.ContractsConfig {
 	return state.AP.Contracts
 }
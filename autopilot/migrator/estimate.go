@@ -0,0 +1,260 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	rhpv4 "go.sia.tech/core/rhp/v4"
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/prices"
+	rhp4 "go.sia.tech/renterd/v2/internal/rhp/v4"
+	"go.sia.tech/renterd/v2/internal/upload"
+	"go.sia.tech/renterd/v2/object"
+	"go.uber.org/zap"
+)
+
+const (
+	// estimatePricesTimeout is the maximum amount of time we wait for a host
+	// to return its prices when estimating the cost of migrations
+	estimatePricesTimeout = 10 * time.Second
+
+	// estimateBatchSize is the number of slabs that are fetched per request
+	// when estimating the cost of migrations
+	estimateBatchSize = 1000
+)
+
+var (
+	_ prices.PricesFetcher = (*hostPricesFetcher)(nil)
+)
+
+type (
+	hostPricesFetcher struct {
+		hi   api.HostInfo
+		rhp4 *rhp4.Client
+	}
+
+	// sectorCosts contains the average cost of downloading and uploading a
+	// sector from and to the hosts used for migrations.
+	sectorCosts struct {
+		download types.Currency
+		upload   types.Currency
+	}
+)
+
+func (f *hostPricesFetcher) PublicKey() types.PublicKey { return f.hi.PublicKey }
+
+func (f *hostPricesFetcher) Prices(ctx context.Context) (rhpv4.HostPrices, error) {
+	settings, err := f.rhp4.Settings(ctx, f.hi.PublicKey, f.hi.SiamuxAddr())
+	if err != nil {
+		return rhpv4.HostPrices{}, err
+	}
+	return settings.Prices, nil
+}
+
+// EstimateMigrations walks the slabs that are in need of migration and
+// estimates the amount of data that needs to be downloaded and uploaded to
+// repair them, what that would cost at current host prices and how long it
+// would take. No slabs are migrated.
+func (m *Migrator) EstimateMigrations(ctx context.Context) (api.MigrationEstimateResponse, error) {
+	resp := api.MigrationEstimateResponse{
+		Buckets: make(map[string]api.MigrationEstimate),
+	}
+
+	// fetch the first batch of slabs for migration, we don't refresh the
+	// health here and use the health that was last computed by the bus
+	toMigrate, err := m.ss.SlabsForMigrationPage(ctx, m.healthCutoff, 0, estimateBatchSize)
+	if err != nil {
+		return api.MigrationEstimateResponse{}, fmt.Errorf("failed to fetch slabs for migration: %w", err)
+	} else if len(toMigrate) == 0 {
+		return resp, nil
+	}
+
+	// fetch hosts
+	dlHosts, ulHosts, err := m.migrationHosts(ctx)
+	if err != nil {
+		return api.MigrationEstimateResponse{}, err
+	}
+
+	// fetch the consensus state to compute the remaining contract durations
+	cs, err := m.bus.ConsensusState(ctx)
+	if err != nil {
+		return api.MigrationEstimateResponse{}, fmt.Errorf("couldn't fetch consensus state from bus: %w", err)
+	}
+
+	// compute the average cost of transferring a sector
+	costs, err := m.sectorCosts(ctx, dlHosts, ulHosts, cs.BlockHeight)
+	if err != nil {
+		return api.MigrationEstimateResponse{}, err
+	}
+
+	// fetch placement settings
+	ps, err := m.bus.PlacementSettings(ctx)
+	if err != nil {
+		return api.MigrationEstimateResponse{}, fmt.Errorf("couldn't fetch placement settings from bus: %w", err)
+	}
+
+	// estimate the slabs batch by batch
+	for offset := 0; len(toMigrate) > 0; {
+		// fetch the slabs and the buckets referencing them
+		keys := make([]object.EncryptionKey, 0, len(toMigrate))
+		for _, us := range toMigrate {
+			keys = append(keys, us.EncryptionKey)
+		}
+		slabs, err := m.ss.Slabs(ctx, keys)
+		if err != nil {
+			return api.MigrationEstimateResponse{}, fmt.Errorf("couldn't fetch slabs from bus: %w", err)
+		}
+
+		for _, s := range slabs {
+			slab := s.Slab
+			objects := make([]api.ObjectMetadata, 0, len(s.Buckets))
+			for _, bucket := range s.Buckets {
+				objects = append(objects, api.ObjectMetadata{Bucket: bucket})
+			}
+
+			// estimate the migration of the slab
+			var estimate api.MigrationEstimate
+			if shardIndices, _, err := shardsToMigrate(slab, dlHosts, contractSetHosts(ps, objects, pinnedHosts(ps, objects, ulHosts))); err != nil {
+				estimate.Unrepairable = 1
+			} else if len(shardIndices) == 0 {
+				continue // slab doesn't need to be migrated
+			} else {
+				estimate.Slabs = 1
+				estimate.DownloadBytes = uint64(slab.MinShards) * rhpv4.SectorSize
+				estimate.UploadBytes = uint64(len(shardIndices)) * rhpv4.SectorSize
+				estimate.DownloadCost = costs.download.Mul64(uint64(slab.MinShards))
+				estimate.UploadCost = costs.upload.Mul64(uint64(len(shardIndices)))
+			}
+			addSlabEstimate(&resp, estimate, s.Buckets)
+		}
+
+		// fetch the next batch
+		if len(toMigrate) < estimateBatchSize {
+			break
+		}
+		offset += len(toMigrate)
+		toMigrate, err = m.ss.SlabsForMigrationPage(ctx, m.healthCutoff, offset, estimateBatchSize)
+		if err != nil {
+			return api.MigrationEstimateResponse{}, fmt.Errorf("failed to fetch slabs for migration: %w", err)
+		}
+	}
+
+	// estimate the duration using past migrations, if the migrator hasn't
+	// migrated any slabs yet we fall back to the host benchmarks
+	duration := m.slabMigrationEstimate(int(resp.Slabs))
+	if duration == 0 && resp.Slabs > 0 {
+		hosts, err := m.bus.Hosts(ctx, api.HostOptions{UsabilityMode: api.UsabilityFilterModeUsable})
+		if err != nil {
+			return api.MigrationEstimateResponse{}, fmt.Errorf("couldn't fetch hosts from bus: %w", err)
+		}
+		duration = benchmarkMigrationEstimate(hosts, resp.Slabs, m.numThreads)
+	}
+	resp.DurationMS = api.DurationMS(duration)
+	return resp, nil
+}
+
+// addSlabEstimate adds the estimate of a slab to the total and to every bucket
+// with objects referencing the slab. A slab that is shared by multiple
+// buckets, e.g. a packed slab, is counted once in the total but in full for
+// every one of those buckets.
+func addSlabEstimate(resp *api.MigrationEstimateResponse, estimate api.MigrationEstimate, buckets []string) {
+	resp.MigrationEstimate = resp.MigrationEstimate.Add(estimate)
+	for i, bucket := range buckets {
+		if slices.Contains(buckets[:i], bucket) {
+			continue
+		}
+		resp.Buckets[bucket] = resp.Buckets[bucket].Add(estimate)
+	}
+}
+
+// benchmarkMigrationEstimate estimates how long it takes to migrate the given
+// number of slabs using the average throughput of the benchmarked hosts. The
+// shards of a slab are transferred in parallel, so migrating a slab takes
+// about as long as downloading and uploading a single sector. It returns 0 if
+// none of the hosts were benchmarked.
+func benchmarkMigrationEstimate(hosts []api.Host, slabs, numThreads uint64) time.Duration {
+	var dl, ul, n uint64
+	for _, h := range hosts {
+		if h.Benchmark.DownloadThroughput == 0 || h.Benchmark.UploadThroughput == 0 {
+			continue
+		}
+		dl += h.Benchmark.DownloadThroughput
+		ul += h.Benchmark.UploadThroughput
+		n++
+	}
+	if n == 0 || numThreads == 0 {
+		return 0
+	}
+	dl /= n
+	ul /= n
+
+	perSlab := float64(rhpv4.SectorSize)/float64(dl) + float64(rhpv4.SectorSize)/float64(ul)
+	total := perSlab * float64(slabs) / float64(numThreads)
+	return time.Duration(total * float64(time.Second))
+}
+
+// sectorCosts fetches the prices of the given hosts and returns the average
+// cost of downloading a sector from the download hosts and uploading a sector
+// to the upload hosts. Hosts that fail to return their prices are ignored.
+func (m *Migrator) sectorCosts(ctx context.Context, dlHosts []api.HostInfo, ulHosts []upload.HostInfo, bh uint64) (sectorCosts, error) {
+	// fetch prices in parallel
+	var mu sync.Mutex
+	hostPrices := make(map[types.PublicKey]rhpv4.HostPrices)
+	var wg sync.WaitGroup
+	for _, h := range dlHosts {
+		wg.Add(1)
+		go func(h api.HostInfo) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, estimatePricesTimeout)
+			defer cancel()
+
+			hp, err := m.pricesCache.Fetch(ctx, &hostPricesFetcher{hi: h, rhp4: m.rhp4Client})
+			if err != nil {
+				m.logger.Debugw("failed to fetch host prices", zap.Stringer("host", h.PublicKey), zap.Error(err))
+				return
+			}
+			mu.Lock()
+			hostPrices[h.PublicKey] = hp
+			mu.Unlock()
+		}(h)
+	}
+	wg.Wait()
+
+	// compute average download cost
+	var costs sectorCosts
+	var n uint64
+	for _, hp := range hostPrices {
+		costs.download = costs.download.Add(hp.RPCReadSectorCost(rhpv4.SectorSize).RenterCost())
+		n++
+	}
+	if n == 0 {
+		return sectorCosts{}, fmt.Errorf("failed to fetch prices from any of the %d usable hosts", len(dlHosts))
+	}
+	costs.download = costs.download.Div64(n)
+
+	// compute average upload cost, storage is paid until the end of the
+	// contract so the cost depends on the remaining duration
+	n = 0
+	for _, h := range ulHosts {
+		hp, ok := hostPrices[h.PublicKey]
+		if !ok {
+			continue
+		}
+		var duration uint64
+		if h.ContractEndHeight > bh {
+			duration = h.ContractEndHeight - bh
+		}
+		usage := hp.RPCWriteSectorCost(rhpv4.SectorSize).Add(hp.RPCAppendSectorsCost(1, duration))
+		costs.upload = costs.upload.Add(usage.RenterCost())
+		n++
+	}
+	if n > 0 {
+		costs.upload = costs.upload.Div64(n)
+	}
+	return costs, nil
+}
@@ -0,0 +1,59 @@
+package migrator
+
+import (
+	"testing"
+	"time"
+
+	rhpv4 "go.sia.tech/core/rhp/v4"
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+)
+
+func TestBenchmarkMigrationEstimate(t *testing.T) {
+	host := func(dl, ul uint64) api.Host {
+		return api.Host{Benchmark: api.HostBenchmark{DownloadThroughput: dl, UploadThroughput: ul}}
+	}
+
+	// no benchmarked hosts
+	if d := benchmarkMigrationEstimate([]api.Host{host(0, 0)}, 10, 1); d != 0 {
+		t.Fatal("expected 0, got", d)
+	}
+
+	// a sector per second in both directions, unbenchmarked hosts are ignored
+	hosts := []api.Host{
+		host(rhpv4.SectorSize/2, rhpv4.SectorSize/2),
+		host(3*rhpv4.SectorSize/2, 3*rhpv4.SectorSize/2),
+		host(0, 0),
+	}
+	if d := benchmarkMigrationEstimate(hosts, 10, 1); d != 20*time.Second {
+		t.Fatal("unexpected duration", d)
+	} else if d := benchmarkMigrationEstimate(hosts, 10, 4); d != 5*time.Second {
+		t.Fatal("unexpected duration", d)
+	}
+}
+
+func TestAddSlabEstimate(t *testing.T) {
+	resp := api.MigrationEstimateResponse{Buckets: make(map[string]api.MigrationEstimate)}
+	estimate := api.MigrationEstimate{
+		Slabs:         1,
+		DownloadBytes: rhpv4.SectorSize,
+		UploadBytes:   rhpv4.SectorSize,
+		DownloadCost:  types.NewCurrency64(1),
+		UploadCost:    types.NewCurrency64(2),
+	}
+
+	// add a slab that is only referenced by the first bucket and a slab that
+	// is shared by both buckets, e.g. a packed slab
+	addSlabEstimate(&resp, estimate, []string{"b1"})
+	addSlabEstimate(&resp, estimate, []string{"b1", "b2", "b1"})
+
+	// assert the shared slab is counted once in the total but in full for
+	// both buckets
+	if resp.Slabs != 2 || !resp.UploadCost.Equals(types.NewCurrency64(4)) {
+		t.Fatalf("unexpected total %+v", resp.MigrationEstimate)
+	} else if b1 := resp.Buckets["b1"]; b1.Slabs != 2 || b1.DownloadBytes != 2*rhpv4.SectorSize {
+		t.Fatalf("unexpected estimate for b1 %+v", b1)
+	} else if b2 := resp.Buckets["b2"]; b2 != estimate {
+		t.Fatalf("unexpected estimate for b2 %+v", b2)
+	}
+}
@@ -0,0 +1,57 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.sia.tech/renterd/v2/api"
+)
+
+const (
+	// healthMetricInterval is the interval at which we record the health
+	// distribution of the slabs
+	healthMetricInterval = time.Hour
+
+	// healthMetricTimeout is the maximum amount of time we allow for
+	// recording a health metric
+	healthMetricTimeout = 5 * time.Minute
+)
+
+func (m *Migrator) recordHealthMetrics(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-m.shutdownCtx.Done():
+			return
+		case <-t.C:
+		}
+
+		if err := m.recordHealthMetric(); err != nil {
+			m.logger.Errorf("failed to record health metric: %v", err)
+		}
+	}
+}
+
+func (m *Migrator) recordHealthMetric() error {
+	ctx, cancel := context.WithTimeout(m.shutdownCtx, healthMetricTimeout)
+	defer cancel()
+
+	// recompute health
+	if err := m.ss.RefreshHealth(ctx); err != nil {
+		return fmt.Errorf("failed to refresh health: %w", err)
+	}
+
+	// fetch the health distribution
+	stats, err := m.ss.SlabHealthStats(ctx, m.healthCutoff)
+	if err != nil {
+		return fmt.Errorf("failed to fetch slab health stats: %w", err)
+	}
+
+	return m.bus.RecordHealthMetric(ctx, api.HealthMetric{
+		Timestamp:       api.TimeNow(),
+		SlabHealthStats: stats,
+	})
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"math"
 	"net"
-	"sort"
 	"sync"
 	"time"
 
@@ -13,12 +12,15 @@ import (
 	"go.sia.tech/renterd/v2/alerts"
 	"go.sia.tech/renterd/v2/api"
 	"go.sia.tech/renterd/v2/internal/accounts"
+	"go.sia.tech/renterd/v2/internal/bandwidth"
 	"go.sia.tech/renterd/v2/internal/contracts"
 	"go.sia.tech/renterd/v2/internal/download"
 	"go.sia.tech/renterd/v2/internal/hosts"
 	"go.sia.tech/renterd/v2/internal/memory"
+	"go.sia.tech/renterd/v2/internal/prices"
 	"go.sia.tech/renterd/v2/internal/rhp"
 	rhp4 "go.sia.tech/renterd/v2/internal/rhp/v4"
+	"go.sia.tech/renterd/v2/internal/schedule"
 	"go.sia.tech/renterd/v2/internal/upload"
 	"go.sia.tech/renterd/v2/internal/utils"
 	"go.sia.tech/renterd/v2/object"
@@ -51,10 +53,13 @@ type (
 		FundAccount(ctx context.Context, account rhpv4.Account, fcid types.FileContractID, amount types.Currency) (types.Currency, error)
 		GougingParams(ctx context.Context) (api.GougingParams, error)
 		Host(ctx context.Context, hostKey types.PublicKey) (api.Host, error)
+		Hosts(ctx context.Context, opts api.HostOptions) ([]api.Host, error)
 		KeepaliveContract(ctx context.Context, fcid types.FileContractID, lockID uint64, d time.Duration) (err error)
 		MarkPackedSlabsUploaded(ctx context.Context, slabs []api.UploadedPackedSlab) error
 		Objects(ctx context.Context, prefix string, opts api.ListObjectOptions) (resp api.ObjectsResponse, err error)
+		PlacementSettings(ctx context.Context) (api.PlacementSettings, error)
 		RecordContractSpending(ctx context.Context, records []api.ContractSpendingRecord) error
+		RecordHealthMetric(ctx context.Context, metrics ...api.HealthMetric) error
 		ReleaseContract(ctx context.Context, fcid types.FileContractID, lockID uint64) (err error)
 		RenewedContract(ctx context.Context, renewedFrom types.FileContractID) (api.ContractMetadata, error)
 		Slab(ctx context.Context, key object.EncryptionKey) (object.Slab, error)
@@ -68,7 +73,10 @@ type (
 	SlabStore interface {
 		RefreshHealth(ctx context.Context) error
 		Slab(ctx context.Context, key object.EncryptionKey) (object.Slab, error)
+		SlabHealthStats(ctx context.Context, healthCutoff float64) (api.SlabHealthStats, error)
+		Slabs(ctx context.Context, keys []object.EncryptionKey) ([]api.SlabWithBuckets, error)
 		SlabsForMigration(ctx context.Context, healthCutoff float64, limit int) ([]api.UnhealthySlab, error)
+		SlabsForMigrationPage(ctx context.Context, healthCutoff float64, offset, limit int) ([]api.UnhealthySlab, error)
 	}
 )
 
@@ -80,13 +88,16 @@ type (
 
 		healthCutoff float64
 		numThreads   uint64
+		schedule     schedule.Schedule
+		limiter      *bandwidth.Limiter
 
 		accounts        *accounts.Manager
 		downloadManager *download.Manager
 		uploadManager   *upload.Manager
 		hostManager     hosts.Manager
 
-		rhp4Client *rhp4.Client
+		pricesCache *prices.PricesCache
+		rhp4Client  *rhp4.Client
 
 		signalConsensusNotSynced  chan struct{}
 		signalMaintenanceFinished chan struct{}
@@ -104,7 +115,7 @@ type (
 	}
 )
 
-func New(ctx context.Context, masterKey [32]byte, alerts alerts.Alerter, ss SlabStore, b Bus, healthCutoff float64, numThreads, downloadMaxOverdrive, uploadMaxOverdrive uint64, downloadOverdriveTimeout, uploadOverdriveTimeout, accountsRefillInterval time.Duration, logger *zap.Logger) (*Migrator, error) {
+func New(ctx context.Context, masterKey [32]byte, alerts alerts.Alerter, ss SlabStore, b Bus, healthCutoff float64, numThreads, downloadMaxOverdrive, uploadMaxOverdrive uint64, downloadOverdriveTimeout, uploadOverdriveTimeout, accountsRefillInterval time.Duration, sched schedule.Schedule, maxBandwidth uint64, logger *zap.Logger) (*Migrator, error) {
 	logger = logger.Named("migrator")
 	m := &Migrator{
 		alerts: alerts,
@@ -113,6 +124,8 @@ func New(ctx context.Context, masterKey [32]byte, alerts alerts.Alerter, ss Slab
 
 		healthCutoff: healthCutoff,
 		numThreads:   numThreads,
+		schedule:     sched,
+		limiter:      bandwidth.NewLimiter(maxBandwidth),
 
 		signalConsensusNotSynced:  make(chan struct{}, 1),
 		signalMaintenanceFinished: make(chan struct{}, 1),
@@ -140,6 +153,7 @@ func New(ctx context.Context, masterKey [32]byte, alerts alerts.Alerter, ss Slab
 	dialer := rhp.NewFallbackDialer(b, net.Dialer{}, logger)
 	csr := contracts.NewSpendingRecorder(ctx, b, 5*time.Second, logger)
 	m.hostManager = hosts.NewManager(masterKey, am, csr, dialer, logger)
+	m.pricesCache = prices.NewPricesCache()
 	m.rhp4Client = rhp4.New(dialer)
 
 	// create upload & download manager
@@ -147,6 +161,13 @@ func New(ctx context.Context, masterKey [32]byte, alerts alerts.Alerter, ss Slab
 	m.downloadManager = download.NewManager(ctx, &uk, m.hostManager, mm, b, downloadMaxOverdrive, downloadOverdriveTimeout, logger)
 	m.uploadManager = upload.NewManager(ctx, &uk, m.hostManager, mm, b, b, b, uploadMaxOverdrive, uploadOverdriveTimeout, logger)
 
+	// periodically record the health of the slabs
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.recordHealthMetrics(healthMetricInterval)
+	}()
+
 	return m, nil
 }
 
@@ -209,6 +230,11 @@ func (m *Migrator) slabMigrationEstimate(remaining int) time.Duration {
 }
 
 func (m *Migrator) performMigrations(ctx context.Context) {
+	// only migrate within the maintenance window
+	if now := time.Now(); !m.schedule.Active(now) {
+		m.logger.Infof("skipping migrations, outside of the maintenance window until %v", m.schedule.Next(now))
+		return
+	}
 	m.logger.Info("performing migrations")
 
 	// prepare jobs channel
@@ -284,15 +310,15 @@ func (m *Migrator) performMigrations(ctx context.Context) {
 			}
 		}
 		toMigrate = toMigrate[:len(toMigrate)-removed]
-		for _, slab := range migrateNewMap {
-			toMigrate = append(toMigrate, *slab)
-		}
 
-		// sort the newly added slabs by health
-		newSlabs := toMigrate[len(toMigrate)-len(migrateNewMap):]
-		sort.Slice(newSlabs, func(i, j int) bool {
-			return newSlabs[i].Health < newSlabs[j].Health
-		})
+		// append the newly added slabs in the order returned by the bus, which
+		// takes into account the health as well as the repair priority of the
+		// objects the slabs belong to
+		for _, slab := range toMigrateNew {
+			if _, exists := migrateNewMap[slab.EncryptionKey]; exists {
+				toMigrate = append(toMigrate, slab)
+			}
+		}
 	}
 
 	// unregister the ongoing migrations alert when we're done
@@ -347,6 +373,10 @@ OUTER:
 				}
 				lastRegister = time.Now()
 			}
+			if !m.schedule.Active(time.Now()) {
+				m.logger.Info("migrations interrupted - maintenance window closed")
+				return
+			}
 			select {
 			case <-ctx.Done():
 				return
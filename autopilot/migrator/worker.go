@@ -43,35 +43,30 @@ func (m *Migrator) migrateSlab(ctx context.Context, key object.EncryptionKey) er
 	ctx = gouging.WithChecker(ctx, m.bus, up.GougingParams)
 
 	// fetch hosts
-	dlHosts, err := m.bus.UsableHosts(ctx)
+	dlHosts, ulHosts, err := m.migrationHosts(ctx)
 	if err != nil {
-		return fmt.Errorf("couldn't fetch hosts from bus: %w", err)
-	}
-
-	hmap := make(map[types.PublicKey]api.HostInfo)
-	for _, h := range dlHosts {
-		hmap[h.PublicKey] = h
+		return err
 	}
 
-	contracts, err := m.bus.Contracts(ctx, api.ContractsOpts{FilterMode: api.ContractFilterModeGood})
+	// fetch placement settings
+	ps, err := m.bus.PlacementSettings(ctx)
 	if err != nil {
-		return fmt.Errorf("couldn't fetch contracts from bus: %v", err)
+		return fmt.Errorf("couldn't fetch placement settings from bus: %w", err)
 	}
 
-	var ulHosts []upload.HostInfo
-	for _, c := range contracts {
-		if h, ok := hmap[c.HostKey]; ok {
-			ulHosts = append(ulHosts, upload.HostInfo{
-				HostInfo:            h,
-				ContractEndHeight:   c.WindowEnd,
-				ContractID:          c.ID,
-				ContractRenewedFrom: c.RenewedFrom,
-			})
+	// filter upload hosts to the ones the slab is allowed to be stored on
+	var objects []api.ObjectMetadata
+	if len(ps.Buckets) > 0 || len(ps.ContractSets) > 0 {
+		res, err := m.bus.Objects(ctx, "", api.ListObjectOptions{SlabEncryptionKey: slab.EncryptionKey})
+		if err != nil {
+			return fmt.Errorf("failed to list objects for slab: %w", err)
 		}
+		objects = res.Objects
 	}
+	ulHosts = contractSetHosts(ps, objects, pinnedHosts(ps, objects, ulHosts))
 
 	// migrate the slab and handle alerts
-	err = m.migrate(ctx, slab, dlHosts, ulHosts, up.CurrentHeight)
+	err = m.migrate(ctx, slab, dlHosts, ulHosts, up.CurrentHeight, ps.Diversity)
 	if err != nil && !utils.IsErr(err, api.ErrSlabNotFound) {
 		var objects []api.ObjectMetadata
 		if res, err := m.bus.Objects(ctx, "", api.ListObjectOptions{SlabEncryptionKey: slab.EncryptionKey}); err != nil {
@@ -94,7 +89,85 @@ func (m *Migrator) migrateSlab(ctx context.Context, key object.EncryptionKey) er
 	return nil
 }
 
-func (m *Migrator) migrate(ctx context.Context, s object.Slab, dlHosts []api.HostInfo, ulHosts []upload.HostInfo, bh uint64) error {
+// migrationHosts returns the hosts we can download from and the hosts we can
+// upload to when migrating slabs.
+func (m *Migrator) migrationHosts(ctx context.Context) ([]api.HostInfo, []upload.HostInfo, error) {
+	dlHosts, err := m.bus.UsableHosts(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't fetch hosts from bus: %w", err)
+	}
+
+	hmap := make(map[types.PublicKey]api.HostInfo)
+	for _, h := range dlHosts {
+		hmap[h.PublicKey] = h
+	}
+
+	contracts, err := m.bus.Contracts(ctx, api.ContractsOpts{FilterMode: api.ContractFilterModeGood})
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't fetch contracts from bus: %v", err)
+	}
+
+	var ulHosts []upload.HostInfo
+	for _, c := range contracts {
+		if h, ok := hmap[c.HostKey]; ok {
+			ulHosts = append(ulHosts, upload.HostInfo{
+				HostInfo:            h,
+				ContractEndHeight:   c.WindowEnd,
+				ContractID:          c.ID,
+				ContractRenewedFrom: c.RenewedFrom,
+				ContractSet:         c.ContractSet,
+			})
+		}
+	}
+	return dlHosts, ulHosts, nil
+}
+
+// pinnedHosts filters the given upload hosts down to the hosts that all buckets
+// of the given objects are pinned to, buckets that aren't pinned don't restrict
+// the set of hosts.
+func pinnedHosts(ps api.PlacementSettings, objects []api.ObjectMetadata, ulHosts []upload.HostInfo) []upload.HostInfo {
+	filtered := ulHosts
+	for _, obj := range objects {
+		hosts, pinned := ps.PinnedHosts(obj.Bucket)
+		if !pinned {
+			continue
+		}
+		var allowed []upload.HostInfo
+		for _, h := range filtered {
+			if _, ok := hosts[h.PublicKey]; ok {
+				allowed = append(allowed, h)
+			}
+		}
+		filtered = allowed
+	}
+	return filtered
+}
+
+// contractSetHosts filters the given upload hosts down to the hosts with a
+// contract in the contract set of any of the buckets of the given objects. If
+// there are no objects, only contracts in the default set are returned.
+func contractSetHosts(ps api.PlacementSettings, objects []api.ObjectMetadata, ulHosts []upload.HostInfo) []upload.HostInfo {
+	sets := make(map[string]struct{})
+	for _, obj := range objects {
+		sets[ps.ContractSet(obj.Bucket)] = struct{}{}
+	}
+	if len(sets) == 0 {
+		sets[""] = struct{}{}
+	}
+
+	var filtered []upload.HostInfo
+	for _, h := range ulHosts {
+		if _, ok := sets[h.ContractSet]; ok {
+			filtered = append(filtered, h)
+		}
+	}
+	return filtered
+}
+
+// shardsToMigrate returns the indices of the shards of the given slab that
+// need to be migrated and the set of hosts that store the shards that don't.
+// It returns an error if the slab can't be migrated using the given hosts.
+func shardsToMigrate(s object.Slab, dlHosts []api.HostInfo, ulHosts []upload.HostInfo) ([]int, map[types.PublicKey]struct{}, error) {
 	// map usable hosts
 	usableHosts := make(map[types.PublicKey]struct{})
 	for _, h := range dlHosts {
@@ -136,7 +209,7 @@ SHARDS:
 
 	// if all shards are on good hosts, we're done
 	if len(shardIndices) == 0 {
-		return nil
+		return nil, seen, nil
 	}
 
 	// calculate the number of missing shards and take into account hosts for
@@ -153,10 +226,21 @@ SHARDS:
 
 	// perform some sanity checks
 	if len(ulHosts) < int(s.MinShards) {
-		return fmt.Errorf("not enough hosts to repair unhealthy shard to minimum redundancy, %d<%d", len(ulHosts), int(s.MinShards))
+		return nil, nil, fmt.Errorf("not enough hosts to repair unhealthy shard to minimum redundancy, %d<%d", len(ulHosts), int(s.MinShards))
 	}
 	if len(s.Shards)-missingShards < int(s.MinShards) {
-		return fmt.Errorf("not enough hosts to download unhealthy shard, %d<%d", len(s.Shards)-missingShards, int(s.MinShards))
+		return nil, nil, fmt.Errorf("not enough hosts to download unhealthy shard, %d<%d", len(s.Shards)-missingShards, int(s.MinShards))
+	}
+	return shardIndices, seen, nil
+}
+
+func (m *Migrator) migrate(ctx context.Context, s object.Slab, dlHosts []api.HostInfo, ulHosts []upload.HostInfo, bh uint64, dr api.DiversityRules) error {
+	// collect indices of shards that need to be migrated
+	shardIndices, seen, err := shardsToMigrate(s, dlHosts, ulHosts)
+	if err != nil {
+		return err
+	} else if len(shardIndices) == 0 {
+		return nil // all shards are on good hosts
 	}
 
 	// acquire memory for the migration
@@ -166,6 +250,11 @@ SHARDS:
 	}
 	defer mem.Release()
 
+	// throttle the download
+	if err := m.limiter.Wait(ctx, int(s.MinShards)*rhpv4.SectorSize); err != nil {
+		return fmt.Errorf("failed to wait for bandwidth: %w", err)
+	}
+
 	// download the slab
 	shards, err := m.downloadManager.DownloadSlab(ctx, s, dlHosts)
 	if err != nil {
@@ -184,6 +273,15 @@ SHARDS:
 	}
 	shards = shards[:len(shardIndices)]
 
+	// collect the locations of the hosts that store the shards we keep, they
+	// count towards the limits of the diversity rules
+	var existing []api.HostLocation
+	for _, h := range dlHosts {
+		if _, used := seen[h.PublicKey]; used && h.Location != nil {
+			existing = append(existing, *h.Location)
+		}
+	}
+
 	// filter upload contracts to the ones we haven't used yet
 	var allowed []upload.HostInfo
 	for _, h := range ulHosts {
@@ -193,8 +291,13 @@ SHARDS:
 		}
 	}
 
+	// throttle the upload
+	if err := m.limiter.Wait(ctx, len(shards)*rhpv4.SectorSize); err != nil {
+		return fmt.Errorf("failed to wait for bandwidth: %w", err)
+	}
+
 	// migrate the shards
-	err = m.uploadManager.UploadShards(ctx, s, shards, allowed, bh, mem)
+	err = m.uploadManager.UploadShards(ctx, s, shards, allowed, bh, mem, dr, existing)
 	if err != nil {
 		m.logger.Debugw("slab migration failed",
 			zap.Error(err),
@@ -0,0 +1,65 @@
+package migrator
+
+import (
+	"testing"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/upload"
+)
+
+func TestPinnedHosts(t *testing.T) {
+	hk1, hk2, hk3 := types.PublicKey{1}, types.PublicKey{2}, types.PublicKey{3}
+	ulHosts := []upload.HostInfo{
+		{HostInfo: api.HostInfo{PublicKey: hk1}},
+		{HostInfo: api.HostInfo{PublicKey: hk2}},
+		{HostInfo: api.HostInfo{PublicKey: hk3}},
+	}
+	ps := api.PlacementSettings{
+		Groups: map[string][]types.PublicKey{
+			"eu": {hk1, hk2},
+			"us": {hk2, hk3},
+		},
+		Buckets: map[string]string{
+			"eu": "eu",
+			"us": "us",
+		},
+	}
+	objects := func(buckets ...string) (objs []api.ObjectMetadata) {
+		for _, bucket := range buckets {
+			objs = append(objs, api.ObjectMetadata{Bucket: bucket})
+		}
+		return
+	}
+
+	tests := []struct {
+		name     string
+		objects  []api.ObjectMetadata
+		expected []types.PublicKey
+	}{
+		{"no objects", nil, []types.PublicKey{hk1, hk2, hk3}},
+		{"unpinned", objects("default"), []types.PublicKey{hk1, hk2, hk3}},
+		{"pinned", objects("eu"), []types.PublicKey{hk1, hk2}},
+		{"pinned and unpinned", objects("default", "us"), []types.PublicKey{hk2, hk3}},
+		{"intersection", objects("eu", "us"), []types.PublicKey{hk2}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filtered := pinnedHosts(ps, test.objects, ulHosts)
+			if len(filtered) != len(test.expected) {
+				t.Fatalf("expected %d hosts, got %d", len(test.expected), len(filtered))
+			}
+			for i, h := range filtered {
+				if h.PublicKey != test.expected[i] {
+					t.Fatalf("expected host %v at index %d, got %v", test.expected[i], i, h.PublicKey)
+				}
+			}
+		})
+	}
+
+	// pinning to a group without usable hosts leaves no hosts
+	ps.Groups["eu"] = []types.PublicKey{{4}}
+	if filtered := pinnedHosts(ps, objects("eu"), ulHosts); len(filtered) != 0 {
+		t.Fatal("expected no hosts", filtered)
+	}
+}
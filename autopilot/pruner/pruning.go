@@ -9,6 +9,7 @@ import (
 
 	"go.sia.tech/core/types"
 	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/schedule"
 	"go.sia.tech/renterd/v2/internal/utils"
 	"go.uber.org/zap"
 )
@@ -17,6 +18,11 @@ const (
 	// timeoutPruneContract defines the maximum amount of time we lock a
 	// contract for pruning
 	timeoutPruneContract = 10 * time.Minute
+
+	// reconcileInterval is the interval at which the contracts are reconciled
+	// in the background, fetching the roots costs money so we don't do it on
+	// every iteration
+	reconcileInterval = 24 * time.Hour
 )
 
 type (
@@ -26,25 +32,31 @@ type (
 		Host(ctx context.Context, hostKey types.PublicKey) (api.Host, error)
 		PrunableData(ctx context.Context) (prunableData api.ContractsPrunableDataResponse, err error)
 		PruneContract(ctx context.Context, id types.FileContractID, timeout time.Duration) (api.ContractPruneResponse, error)
+		ReconcileContract(ctx context.Context, id types.FileContractID, timeout time.Duration) (api.ContractReconcileResponse, error)
 		RecordContractPruneMetric(ctx context.Context, metrics ...api.ContractPruneMetric) error
+		RefreshHealth(ctx context.Context) error
 	}
 )
 
 type Pruner struct {
-	bus    Bus
-	logger *zap.SugaredLogger
+	bus      Bus
+	schedule schedule.Schedule
+	logger   *zap.SugaredLogger
 
 	wg sync.WaitGroup
 
 	mu               sync.Mutex
 	pruning          bool
 	pruningLastStart time.Time
+	reconciling      bool
+	lastReconcile    time.Time
 }
 
-func New(bus Bus, logger *zap.Logger) *Pruner {
+func New(bus Bus, sched schedule.Schedule, logger *zap.Logger) *Pruner {
 	return &Pruner{
-		bus:    bus,
-		logger: logger.Named("pruner").Sugar(),
+		bus:      bus,
+		schedule: sched,
+		logger:   logger.Named("pruner").Sugar(),
 	}
 }
 
@@ -131,6 +143,12 @@ func (p *Pruner) fetchHostContract(ctx context.Context, fcid types.FileContractI
 
 func (p *Pruner) performContractPruning(ctx context.Context) {
 	log := p.logger.Named("performContractPruning")
+
+	// only prune within the maintenance window
+	if now := time.Now(); !p.schedule.Active(now) {
+		log.Infof("skipping contract pruning, outside of the maintenance window until %v", p.schedule.Next(now))
+		return
+	}
 	log.Info("performing contract pruning")
 
 	// fetch prunable contracts
@@ -144,6 +162,12 @@ func (p *Pruner) performContractPruning(ctx context.Context) {
 	// loop prunable contracts
 	var total uint64
 	for _, contract := range prunable {
+		// stop if the maintenance window closed
+		if !p.schedule.Active(time.Now()) {
+			log.Info("contract pruning interrupted - maintenance window closed")
+			break
+		}
+
 		// fetch host
 		h, _, err := p.fetchHostContract(ctx, contract.ID)
 		if utils.IsErr(err, api.ErrContractNotFound) {
@@ -0,0 +1,107 @@
+package pruner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/utils"
+	"go.uber.org/zap"
+)
+
+// PerformContractReconciliation reconciles all good contracts in the
+// background if they weren't reconciled within the last reconcileInterval.
+func (p *Pruner) PerformContractReconciliation(ctx context.Context) {
+	p.mu.Lock()
+	if p.reconciling || time.Since(p.lastReconcile) < reconcileInterval {
+		p.mu.Unlock()
+		return
+	}
+	p.reconciling = true
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		reconciled := p.performContractReconciliation(ctx)
+		p.mu.Lock()
+		p.reconciling = false
+		if reconciled {
+			p.lastReconcile = time.Now()
+		}
+		p.mu.Unlock()
+	}()
+}
+
+func (p *Pruner) performContractReconciliation(ctx context.Context) bool {
+	log := p.logger.Named("performContractReconciliation")
+
+	// only reconcile within the maintenance window
+	if now := time.Now(); !p.schedule.Active(now) {
+		log.Infof("skipping contract reconciliation, outside of the maintenance window until %v", p.schedule.Next(now))
+		return false
+	}
+	log.Info("performing contract reconciliation")
+
+	res, err := p.ReconcileContracts(ctx)
+	if err != nil {
+		log.Errorw("failed to reconcile contracts", zap.Error(err))
+		return false
+	}
+	log.Infow("reconciled contracts",
+		"contracts", len(res.Contracts),
+		"missing", utils.HumanReadableSize(int(res.Missing)),
+		"prunable", utils.HumanReadableSize(int(res.Prunable)))
+	return true
+}
+
+// ReconcileContracts compares the sector roots of all good contracts on the
+// hosts to the ones in the database. Sectors that are missing on the host are
+// marked as lost by the bus and the health of the affected slabs is refreshed
+// once all contracts are reconciled, sectors that aren't referenced are
+// reported as prunable. Contracts that fail to reconcile are reported but don't interrupt
+// the reconciliation of the other contracts.
+func (p *Pruner) ReconcileContracts(ctx context.Context) (api.ContractsReconcileResponse, error) {
+	log := p.logger.Named("reconcileContracts")
+
+	// fetch good contracts
+	contracts, err := p.bus.Contracts(ctx, api.ContractsOpts{FilterMode: api.ContractFilterModeGood})
+	if err != nil {
+		return api.ContractsReconcileResponse{}, fmt.Errorf("failed to fetch contracts: %w", err)
+	}
+
+	resp := api.ContractsReconcileResponse{
+		Contracts: make([]api.ContractReconcileResult, 0, len(contracts)),
+	}
+	for _, c := range contracts {
+		res := api.ContractReconcileResult{
+			ContractID: c.ID,
+			HostKey:    c.HostKey,
+		}
+
+		reconciled, err := p.bus.ReconcileContract(ctx, c.ID, timeoutPruneContract)
+		if utils.IsErr(err, context.Canceled) {
+			return api.ContractsReconcileResponse{}, err
+		} else if err != nil {
+			log.Errorw("failed to reconcile contract", zap.Error(err), "contract", c.ID)
+			res.Error = err.Error()
+		} else {
+			res.ContractReconcileResponse = reconciled
+			resp.Missing += reconciled.Missing
+			resp.Prunable += reconciled.Prunable
+		}
+		if reconciled.Missing > 0 {
+			log.Warnw("found sectors missing on host", "contract", c.ID, "host", c.HostKey, "missing", utils.HumanReadableSize(int(reconciled.Missing)))
+		}
+		resp.Contracts = append(resp.Contracts, res)
+	}
+
+	// update the health of the affected slabs
+	if resp.Missing > 0 {
+		if err := p.bus.RefreshHealth(ctx); err != nil {
+			return api.ContractsReconcileResponse{}, fmt.Errorf("failed to refresh health: %w", err)
+		}
+	}
+	return resp, nil
+}
@@ -15,6 +15,10 @@ import (
 
 const (
 	DefaultScanTimeout = 10 * time.Second
+
+	// DefaultBenchmarkTimeout is the timeout for benchmarking a single host,
+	// it has to be long enough to upload and download a sector.
+	DefaultBenchmarkTimeout = 2 * time.Minute
 )
 
 var (
@@ -24,10 +28,12 @@ var (
 
 type (
 	HostScanner interface {
+		BenchmarkHost(ctx context.Context, hostKey types.PublicKey, timeout time.Duration) (api.HostBenchmarkResponse, error)
 		ScanHost(ctx context.Context, hostKey types.PublicKey, timeout time.Duration) (api.HostScanResponse, error)
 	}
 
 	HostStore interface {
+		Contracts(ctx context.Context, opts api.ContractsOpts) ([]api.ContractMetadata, error)
 		Hosts(ctx context.Context, opts api.HostOptions) ([]api.Host, error)
 		RemoveOfflineHosts(ctx context.Context, maxConsecutiveScanFailures uint64, maxDowntime time.Duration) (uint64, error)
 	}
@@ -41,6 +47,9 @@ type (
 		scanThreads   int
 		scanInterval  time.Duration
 
+		benchmarkInterval time.Duration
+		benchmarkThreads  int
+
 		statsHostPingMS *utils.DataPoints
 
 		wg sync.WaitGroup
@@ -53,6 +62,9 @@ type (
 		scanning          bool
 		scanningLastStart time.Time
 		scanningCtxCancel context.CancelCauseFunc
+
+		benchmarking          bool
+		benchmarkingCtxCancel context.CancelCauseFunc
 	}
 
 	scanJob struct {
@@ -61,7 +73,10 @@ type (
 	}
 )
 
-func New(hs HostStore, scanBatchSize, scanThreads uint64, scanMinInterval time.Duration, logger *zap.Logger) (*Scanner, error) {
+// New returns a new scanner. A zero benchmark interval disables host
+// benchmarks, otherwise the hosts we have contracts with are benchmarked once
+// per interval using the given number of threads.
+func New(hs HostStore, scanBatchSize, scanThreads uint64, scanMinInterval, benchmarkInterval time.Duration, benchmarkThreads uint64, logger *zap.Logger) (*Scanner, error) {
 	logger = logger.Named("scanner")
 	if scanBatchSize == 0 {
 		return nil, errors.New("scanner batch size has to be greater than zero")
@@ -69,6 +84,9 @@ func New(hs HostStore, scanBatchSize, scanThreads uint64, scanMinInterval time.D
 	if scanThreads == 0 {
 		return nil, errors.New("scanner threads has to be greater than zero")
 	}
+	if benchmarkInterval > 0 && benchmarkThreads == 0 {
+		return nil, errors.New("benchmark threads has to be greater than zero")
+	}
 	return &Scanner{
 		hs: hs,
 
@@ -76,6 +94,9 @@ func New(hs HostStore, scanBatchSize, scanThreads uint64, scanMinInterval time.D
 		scanThreads:   int(scanThreads),
 		scanInterval:  scanMinInterval,
 
+		benchmarkInterval: benchmarkInterval,
+		benchmarkThreads:  int(benchmarkThreads),
+
 		statsHostPingMS: utils.NewDataPoints(0),
 		logger:          logger.Sugar(),
 	}, nil
@@ -138,13 +159,56 @@ func (s *Scanner) Scan(ctx context.Context, hs HostScanner, force bool) {
 	}()
 }
 
+// Benchmark benchmarks the hosts we have good contracts with in the
+// background, unless a benchmark is already in progress. Benchmarks run
+// independently of scans so slow hosts don't hold up scanning.
+func (s *Scanner) Benchmark(ctx context.Context, hs HostScanner) {
+	if s.benchmarkInterval == 0 {
+		s.logger.Debug("host benchmarks disabled")
+		return
+	}
+
+	s.mu.Lock()
+	if s.benchmarking {
+		s.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	s.benchmarkingCtxCancel = cancel
+	s.benchmarking = true
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			s.benchmarking = false
+			s.mu.Unlock()
+
+			s.wg.Done()
+			cancel(nil)
+		}()
+		start := time.Now()
+		if benchmarked := s.benchmarkHosts(ctx, hs); benchmarked > 0 {
+			s.logger.Infow("benchmarks finished",
+				"duration", time.Since(start),
+				"benchmarked", benchmarked)
+		}
+	}()
+}
+
 func (s *Scanner) Shutdown(ctx context.Context) error {
 	s.mu.Lock()
-	if !s.scanning {
+	if !s.scanning && !s.benchmarking {
 		s.mu.Unlock()
 		return nil
 	}
-	s.scanningCtxCancel(ErrShuttingDown)
+	if s.scanning {
+		s.scanningCtxCancel(ErrShuttingDown)
+	}
+	if s.benchmarking {
+		s.benchmarkingCtxCancel(ErrShuttingDown)
+	}
 	s.mu.Unlock()
 
 	waitChan := make(chan struct{})
@@ -254,6 +318,85 @@ func (s *Scanner) scanHosts(ctx context.Context, hs HostScanner, cutoff time.Tim
 	return
 }
 
+// benchmarkHosts benchmarks the hosts we have good contracts with, unless they
+// were benchmarked within the benchmark interval. Benchmarks are only
+// performed once the scanner received a hosts config, which indicates the
+// autopilot is enabled, since they are paid for by the contracts.
+func (s *Scanner) benchmarkHosts(ctx context.Context, hs HostScanner) (benchmarked uint64) {
+	s.mu.Lock()
+	configured := s.hostsCfg != nil
+	s.mu.Unlock()
+	if !configured {
+		s.logger.Debug("no hosts config set, skipping host benchmarks")
+		return
+	}
+
+	// fetch the hosts we have good contracts with
+	contracts, err := s.hs.Contracts(ctx, api.ContractsOpts{FilterMode: api.ContractFilterModeGood})
+	if err != nil {
+		s.logger.Errorw("could not get contracts for benchmarking", zap.Error(err))
+		return
+	}
+	seen := make(map[types.PublicKey]struct{})
+	var hks []types.PublicKey
+	for _, c := range contracts {
+		if _, ok := seen[c.HostKey]; !ok {
+			seen[c.HostKey] = struct{}{}
+			hks = append(hks, c.HostKey)
+		}
+	}
+	if len(hks) == 0 {
+		return
+	}
+	hosts, err := s.hs.Hosts(ctx, api.HostOptions{KeyIn: hks, Limit: -1})
+	if err != nil {
+		s.logger.Errorw("could not get hosts for benchmarking", zap.Error(err))
+		return
+	}
+
+	// filter out hosts that were benchmarked recently
+	cutoff := time.Now().Add(-s.benchmarkInterval)
+	var toBenchmark []api.Host
+	for _, h := range hosts {
+		if !h.Benchmark.Timestamp.After(cutoff) {
+			toBenchmark = append(toBenchmark, h)
+		}
+	}
+
+	// benchmark the hosts using a bounded number of threads
+	jobs := make(chan types.PublicKey)
+	var wg sync.WaitGroup
+	for i := 0; i < s.benchmarkThreads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for hk := range jobs {
+				resp, err := hs.BenchmarkHost(ctx, hk, DefaultBenchmarkTimeout)
+				if errors.Is(err, ErrShuttingDown) || errors.Is(err, ErrScanInterrupted) || errors.Is(err, context.Canceled) {
+					continue
+				} else if err != nil {
+					s.logger.Debugw("could not benchmark host", zap.Error(err), "hk", hk)
+				} else if err := resp.Error(); err != nil {
+					s.logger.Debugw("host benchmark failed", zap.Error(err), "hk", hk)
+				} else {
+					atomic.AddUint64(&benchmarked, 1)
+				}
+			}
+		}()
+	}
+loop:
+	for _, h := range toBenchmark {
+		select {
+		case <-ctx.Done():
+			break loop
+		case jobs <- h.PublicKey:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return
+}
+
 func (s *Scanner) removeOfflineHosts(ctx context.Context) (removed uint64) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -19,22 +19,34 @@ const (
 )
 
 type mockHostStore struct {
-	hosts []api.Host
+	contracts []api.ContractMetadata
+	hosts     []api.Host
 
 	mu       sync.Mutex
 	scans    []string
 	removals []string
 }
 
+func (hs *mockHostStore) Contracts(ctx context.Context, opts api.ContractsOpts) ([]api.ContractMetadata, error) {
+	return hs.contracts, nil
+}
+
 func (hs *mockHostStore) Hosts(ctx context.Context, opts api.HostOptions) ([]api.Host, error) {
 	hs.mu.Lock()
 	defer hs.mu.Unlock()
 	hs.scans = append(hs.scans, fmt.Sprintf("%d-%d", opts.Offset, opts.Offset+opts.Limit))
 
+	keyIn := make(map[types.PublicKey]struct{})
+	for _, hk := range opts.KeyIn {
+		keyIn[hk] = struct{}{}
+	}
+
 	var hosts []api.Host
 	for _, host := range hs.hosts {
 		if !opts.MaxLastScan.IsZero() && opts.MaxLastScan.Std().Before(host.Interactions.LastScan) {
 			continue
+		} else if _, ok := keyIn[host.PublicKey]; len(keyIn) > 0 && !ok {
+			continue
 		}
 		hosts = append(hosts, host)
 	}
@@ -45,7 +57,7 @@ func (hs *mockHostStore) Hosts(ctx context.Context, opts api.HostOptions) ([]api
 	}
 
 	end := opts.Offset + opts.Limit
-	if end > len(hosts) {
+	if opts.Limit == -1 || end > len(hosts) {
 		end = len(hosts)
 	}
 
@@ -81,8 +93,16 @@ type mockHostScanner struct {
 	blockChan chan struct{}
 	hs        *mockHostStore
 
-	mu        sync.Mutex
-	scanCount int
+	mu          sync.Mutex
+	benchmarked []types.PublicKey
+	scanCount   int
+}
+
+func (w *mockHostScanner) BenchmarkHost(ctx context.Context, hostKey types.PublicKey, _ time.Duration) (api.HostBenchmarkResponse, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.benchmarked = append(w.benchmarked, hostKey)
+	return api.HostBenchmarkResponse{}, nil
 }
 
 func (w *mockHostScanner) ScanHost(ctx context.Context, hostKey types.PublicKey, _ time.Duration) (api.HostScanResponse, error) {
@@ -107,7 +127,7 @@ func TestScanner(t *testing.T) {
 	hs := &mockHostStore{hosts: test.NewHosts(100)}
 
 	// create test scanner
-	s, err := New(hs, testBatchSize, testNumThreads, time.Minute, zap.NewNop())
+	s, err := New(hs, testBatchSize, testNumThreads, time.Minute, time.Hour, testNumThreads, zap.NewNop())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -217,3 +237,74 @@ func TestScanner(t *testing.T) {
 		t.Fatal("unexpected")
 	}
 }
+
+func TestScannerBenchmarks(t *testing.T) {
+	// create mock store with contracts with two hosts, one of which was
+	// benchmarked recently
+	hosts := test.NewHosts(3)
+	hosts[1].Benchmark.Timestamp = time.Now()
+	hs := &mockHostStore{
+		hosts: hosts,
+		contracts: []api.ContractMetadata{
+			{ID: types.FileContractID{1}, HostKey: hosts[0].PublicKey},
+			{ID: types.FileContractID{2}, HostKey: hosts[0].PublicKey},
+			{ID: types.FileContractID{3}, HostKey: hosts[1].PublicKey},
+		},
+	}
+
+	// create test scanner
+	s, err := New(hs, testBatchSize, testNumThreads, time.Minute, time.Hour, testNumThreads, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown(context.Background())
+
+	benchmarked := func(b *mockHostScanner) []types.PublicKey {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return b.benchmarked
+	}
+
+	// assert hosts aren't benchmarked when scanning
+	b := &mockHostScanner{hs: hs}
+	s.UpdateHostsConfig(api.HostsConfig{})
+	s.Scan(context.Background(), b, true)
+	time.Sleep(time.Second)
+	if len(benchmarked(b)) != 0 {
+		t.Fatal("unexpected benchmarks", benchmarked(b))
+	}
+
+	// assert hosts aren't benchmarked without a hosts config
+	s.mu.Lock()
+	s.hostsCfg = nil
+	s.mu.Unlock()
+	s.Benchmark(context.Background(), b)
+	time.Sleep(time.Second)
+	if len(benchmarked(b)) != 0 {
+		t.Fatal("unexpected benchmarks", benchmarked(b))
+	}
+
+	// assert only the host that wasn't benchmarked recently is benchmarked
+	s.UpdateHostsConfig(api.HostsConfig{})
+	s.Benchmark(context.Background(), b)
+	time.Sleep(time.Second)
+	if bm := benchmarked(b); len(bm) != 1 || bm[0] != hosts[0].PublicKey {
+		t.Fatal("unexpected benchmarks", bm)
+	}
+
+	// assert a zero interval disables benchmarks
+	b = &mockHostScanner{hs: hs}
+	s.benchmarkInterval = 0
+	s.Benchmark(context.Background(), b)
+	time.Sleep(time.Second)
+	if len(benchmarked(b)) != 0 {
+		t.Fatal("unexpected benchmarks", benchmarked(b))
+	}
+
+	// assert benchmark threads are required unless benchmarks are disabled
+	if _, err := New(hs, testBatchSize, testNumThreads, time.Minute, time.Hour, 0, zap.NewNop()); err == nil {
+		t.Fatal("expected error")
+	} else if _, err := New(hs, testBatchSize, testNumThreads, time.Minute, 0, 0, zap.NewNop()); err != nil {
+		t.Fatal(err)
+	}
+}
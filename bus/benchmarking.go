@@ -0,0 +1,160 @@
+package bus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	rhpv4 "go.sia.tech/core/rhp/v4"
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/gouging"
+	"go.uber.org/zap"
+	"lukechampine.com/frand"
+)
+
+const (
+	// benchmarkAccountsID is the identifier used to derive the keys of the
+	// accounts that pay for benchmarks, they are separate from the worker
+	// accounts to avoid interfering with their balances.
+	benchmarkAccountsID = "benchmark"
+
+	// benchmarkFundingMultiplier is the number of benchmarks a benchmark
+	// account is funded for at once to avoid revising the contract before
+	// every benchmark.
+	benchmarkFundingMultiplier = 10
+)
+
+var (
+	errNoBenchmarkContract = errors.New("no good contract with host to fund the benchmark")
+
+	// errBenchmarkAborted is returned when a benchmark couldn't be performed
+	// for a reason that isn't the host's fault, such failures aren't recorded
+	// since they say nothing about the host's performance.
+	errBenchmarkAborted = errors.New("benchmark aborted")
+)
+
+// ttfbWriter is a writer that keeps track of the time it took for the first
+// byte to be written since it was created.
+type ttfbWriter struct {
+	w     io.Writer
+	start time.Time
+	ttfb  time.Duration
+}
+
+func newTTFBWriter(w io.Writer) *ttfbWriter {
+	return &ttfbWriter{w: w, start: time.Now()}
+}
+
+func (w *ttfbWriter) Write(p []byte) (int, error) {
+	if w.ttfb == 0 && len(p) > 0 {
+		w.ttfb = time.Since(w.start)
+	}
+	return w.w.Write(p)
+}
+
+// benchmarkContract returns a good contract with the host that can be used to
+// fund the benchmark account.
+func (b *Bus) benchmarkContract(ctx context.Context, hk types.PublicKey) (api.ContractMetadata, error) {
+	contracts, err := b.store.Contracts(ctx, api.ContractsOpts{FilterMode: api.ContractFilterModeGood})
+	if err != nil {
+		return api.ContractMetadata{}, fmt.Errorf("failed to fetch contracts: %w", err)
+	}
+	for _, c := range contracts {
+		if c.HostKey == hk {
+			return c, nil
+		}
+	}
+	return api.ContractMetadata{}, errNoBenchmarkContract
+}
+
+// benchmarkHost uploads a sector to the host's temporary storage and
+// downloads it again to measure the host's upload and download throughput as
+// well as its time to first byte. The sector is written using an account
+// that's funded by the given contract, it's not appended to the contract.
+func (b *Bus) benchmarkHost(ctx context.Context, timeout time.Duration, h api.Host, fcid types.FileContractID, gc gouging.Checker) (api.HostBenchmark, error) {
+	hostIP := h.SiamuxAddr()
+
+	// apply the timeout
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// get prices
+	settings, err := b.rhp4Client.Settings(ctx, h.PublicKey, hostIP)
+	if err != nil {
+		return api.HostBenchmark{}, fmt.Errorf("failed to fetch prices for benchmark: %w", err)
+	}
+	prices := settings.Prices
+
+	// make sure they are sane
+	if gb := gc.Check(settings); gb.Gouging() {
+		return api.HostBenchmark{}, fmt.Errorf("%w: host for benchmark is gouging: %v", errBenchmarkAborted, gb.String())
+	}
+
+	// make sure the benchmark account can pay for the benchmark
+	accountKey := b.benchmarkAccountKey(h.PublicKey)
+	account := rhpv4.Account(accountKey.PublicKey())
+	cost := prices.RPCWriteSectorCost(rhpv4.SectorSize).RenterCost().
+		Add(prices.RPCReadSectorCost(rhpv4.SectorSize).RenterCost())
+	balance, err := b.rhp4Client.AccountBalance(ctx, h.PublicKey, hostIP, account)
+	if err != nil {
+		return api.HostBenchmark{}, fmt.Errorf("failed to fetch benchmark account balance: %w", err)
+	} else if balance.Cmp(cost) < 0 {
+		if _, err := b.fundAccount(ctx, fcid, account, cost.Mul64(benchmarkFundingMultiplier)); err != nil {
+			return api.HostBenchmark{}, fmt.Errorf("%w: failed to fund benchmark account: %v", errBenchmarkAborted, err)
+		}
+	}
+	token := rhpv4.NewAccountToken(accountKey, h.PublicKey)
+
+	// upload a sector of random data
+	data := frand.Bytes(rhpv4.SectorSize)
+	start := time.Now()
+	res, err := b.rhp4Client.WriteSector(ctx, h.PublicKey, hostIP, prices, token, bytes.NewReader(data), rhpv4.SectorSize)
+	if err != nil {
+		return api.HostBenchmark{}, fmt.Errorf("failed to upload sector: %w", err)
+	}
+	uploadElapsed := time.Since(start)
+
+	// download it again
+	buf := bytes.NewBuffer(make([]byte, 0, rhpv4.SectorSize))
+	w := newTTFBWriter(buf)
+	if _, err := b.rhp4Client.ReadSector(ctx, h.PublicKey, hostIP, prices, token, w, res.Root, 0, rhpv4.SectorSize); err != nil {
+		return api.HostBenchmark{}, fmt.Errorf("failed to download sector: %w", err)
+	} else if !bytes.Equal(buf.Bytes(), data) {
+		return api.HostBenchmark{}, errors.New("downloaded sector doesn't match uploaded sector")
+	}
+	downloadElapsed := time.Since(w.start)
+
+	return api.HostBenchmark{
+		Timestamp:          time.Now(),
+		Success:            true,
+		UploadThroughput:   throughput(rhpv4.SectorSize, uploadElapsed),
+		DownloadThroughput: throughput(rhpv4.SectorSize, downloadElapsed),
+		TimeToFirstByte:    w.ttfb,
+	}, nil
+}
+
+func (b *Bus) benchmarkAccountKey(hk types.PublicKey) types.PrivateKey {
+	key := b.masterKey.DeriveAccountsKey(benchmarkAccountsID)
+	return key.DeriveAccountKey(hk)
+}
+
+func (b *Bus) recordHostBenchmark(ctx context.Context, hk types.PublicKey, hb api.HostBenchmark) {
+	if err := b.store.RecordHostBenchmark(ctx, hk, hb); err != nil {
+		b.logger.Errorw("failed to record host benchmark", zap.Error(err), "hk", hk)
+	}
+}
+
+// throughput returns the throughput in bytes per second.
+func throughput(n uint64, elapsed time.Duration) uint64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return uint64(float64(n) / elapsed.Seconds())
+}
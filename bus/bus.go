@@ -28,6 +28,7 @@ import (
 	"go.sia.tech/renterd/v2/config"
 	ibus "go.sia.tech/renterd/v2/internal/bus"
 	"go.sia.tech/renterd/v2/internal/contracts"
+	"go.sia.tech/renterd/v2/internal/geoip"
 	"go.sia.tech/renterd/v2/internal/gouging"
 	"go.sia.tech/renterd/v2/internal/rhp"
 	rhp4 "go.sia.tech/renterd/v2/internal/rhp/v4"
@@ -41,6 +42,8 @@ const (
 	defaultWalletRecordMetricInterval = 5 * time.Minute
 	defaultPinUpdateInterval          = 5 * time.Minute
 	defaultPinRateWindow              = 6 * time.Hour
+	defaultHostLocationExpiry         = time.Hour
+	defaultHostRecordMetricInterval   = time.Hour
 
 	lockingPriorityPruning   = 20
 	lockingPriorityFunding   = 40
@@ -50,6 +53,8 @@ const (
 	stdTxnSize = 1200 // bytes
 )
 
+var errContractOutOfFunds = errors.New("contract is out of funds")
+
 // Client re-exports the client from the client package.
 type Client struct {
 	*client.Client
@@ -114,6 +119,7 @@ type (
 	}
 
 	PinManager interface {
+		ExchangeRate(ctx context.Context, currency string) (float64, error)
 		Shutdown(context.Context) error
 		TriggerUpdate()
 	}
@@ -190,12 +196,14 @@ type (
 		HostAllowlist(ctx context.Context) ([]types.PublicKey, error)
 		HostBlocklist(ctx context.Context) ([]string, error)
 		Hosts(ctx context.Context, opts api.HostOptions) ([]api.Host, error)
+		RecordHostBenchmark(ctx context.Context, hk types.PublicKey, b api.HostBenchmark) error
 		RecordHostScans(ctx context.Context, scans []api.HostScan) error
 		RemoveOfflineHosts(ctx context.Context, maxConsecutiveScanFailures uint64, maxDowntime time.Duration) (uint64, error)
 		ResetLostSectors(ctx context.Context, hk types.PublicKey) error
 		UpdateHostAllowlistEntries(ctx context.Context, add, remove []types.PublicKey, clear bool) error
 		UpdateHostBlocklistEntries(ctx context.Context, add, remove []string, clear bool) error
 		UpdateHostCheck(ctx context.Context, hk types.PublicKey, check api.HostChecks) error
+		UpdateHostLabels(ctx context.Context, hk types.PublicKey, labels []string, notes string) error
 		UsableHosts(ctx context.Context) ([]sql.HostInfo, error)
 	}
 
@@ -208,9 +216,11 @@ type (
 		ArchiveAllContracts(ctx context.Context, reason string) error
 		Contract(ctx context.Context, id types.FileContractID) (api.ContractMetadata, error)
 		Contracts(ctx context.Context, opts api.ContractsOpts) ([]api.ContractMetadata, error)
+		ContractsRenterCost(ctx context.Context, minStartHeight uint64) (types.Currency, error)
 		RecordContractSpending(ctx context.Context, records []api.ContractSpendingRecord) error
 		PutContract(ctx context.Context, c api.ContractMetadata) error
 		RenewedContract(ctx context.Context, renewedFrom types.FileContractID) (api.ContractMetadata, error)
+		UpdateContractManual(ctx context.Context, id types.FileContractID, manual bool, renewal *api.ContractRenewalSettings) error
 		UpdateContractUsability(ctx context.Context, id types.FileContractID, usability string) error
 
 		ContractRoots(ctx context.Context, id types.FileContractID) ([]types.Hash256, error)
@@ -218,6 +228,7 @@ type (
 		ContractSize(ctx context.Context, id types.FileContractID) (api.ContractSize, error)
 		PrunableContractRoots(ctx context.Context, id types.FileContractID, roots []types.Hash256) ([]uint64, error)
 
+		DeleteContractSectors(ctx context.Context, fcid types.FileContractID, roots []types.Hash256) (int, error)
 		DeleteHostSector(ctx context.Context, hk types.PublicKey, root types.Hash256) (int, error)
 
 		Bucket(_ context.Context, bucketName string) (api.Bucket, error)
@@ -231,6 +242,7 @@ type (
 		Objects(ctx context.Context, bucketName, prefix, substring, delim, sortBy, sortDir, marker string, limit int, slabEncryptionKey object.EncryptionKey) (api.ObjectsResponse, error)
 		ObjectMetadata(ctx context.Context, bucketName, key string) (api.Object, error)
 		ObjectsStats(ctx context.Context, opts api.ObjectsStatsOpts) (api.ObjectsStatsResponse, error)
+		RecordObjectAccesses(ctx context.Context, records []api.ObjectAccessRecord) error
 		RemoveObject(ctx context.Context, bucketName, key string) error
 		RemoveObjects(ctx context.Context, bucketName, prefix string) error
 		RenameObject(ctx context.Context, bucketName, from, to string, force bool) error
@@ -245,6 +257,10 @@ type (
 		MultipartUploads(ctx context.Context, bucketName, prefix, keyMarker, uploadIDMarker string, maxUploads int) (resp api.MultipartListUploadsResponse, _ error)
 		MultipartUploadParts(ctx context.Context, bucketName, object string, uploadID string, marker int, limit int64) (resp api.MultipartListPartsResponse, _ error)
 
+		CreateTusUpload(ctx context.Context, bucketName, key string, ec object.EncryptionKey, mimeType string, metadata api.ObjectUserMetadata, length uint64) (api.TusUpload, error)
+		TusUpload(ctx context.Context, uploadID string) (api.TusUpload, error)
+		UpdateTusUploadOffset(ctx context.Context, uploadID string, offset, length uint64) (api.TusUpload, error)
+
 		MarkPackedSlabsUploaded(ctx context.Context, slabs []api.UploadedPackedSlab) error
 		PackedSlabsForUpload(ctx context.Context, lockingDuration time.Duration, minShards, totalShards uint8, limit int) ([]api.PackedSlab, error)
 		SlabBuffers(ctx context.Context) ([]api.SlabBuffer, error)
@@ -252,8 +268,10 @@ type (
 		AddPartialSlab(ctx context.Context, data []byte, minShards, totalShards uint8) (slabs []object.SlabSlice, bufferSize int64, err error)
 		FetchPartialSlab(ctx context.Context, key object.EncryptionKey, offset, length uint32) ([]byte, error)
 		Slab(ctx context.Context, key object.EncryptionKey) (object.Slab, error)
-		SlabsForMigration(ctx context.Context, healthCutoff float64, limit int) ([]api.UnhealthySlab, error)
+		Slabs(ctx context.Context, keys []object.EncryptionKey) ([]api.SlabWithBuckets, error)
+		SlabsForMigration(ctx context.Context, healthCutoff float64, offset, limit int, rs api.RepairSettings) ([]api.UnhealthySlab, error)
 		RefreshHealth(ctx context.Context) error
+		SlabHealthStats(ctx context.Context, healthCutoff float64) (api.SlabHealthStats, error)
 		UpdateSlab(ctx context.Context, key object.EncryptionKey, sectors []api.UploadedSector) error
 	}
 
@@ -265,20 +283,38 @@ type (
 		ContractMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.ContractMetricsQueryOpts) ([]api.ContractMetric, error)
 		RecordContractMetric(ctx context.Context, metrics ...api.ContractMetric) error
 
+		HealthMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HealthMetricsQueryOpts) ([]api.HealthMetric, error)
+		RecordHealthMetric(ctx context.Context, metrics ...api.HealthMetric) error
+
+		HostMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HostMetricsQueryOpts) ([]api.HostMetric, error)
+		HostNetworkMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HostNetworkMetricsQueryOpts) ([]api.HostNetworkMetric, error)
+		RecordHostMetric(ctx context.Context, metrics ...api.HostMetric) error
+
 		WalletMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.WalletMetricsQueryOpts) ([]api.WalletMetric, error)
 		RecordWalletMetric(ctx context.Context, metrics ...api.WalletMetric) error
 
 		PruneMetrics(ctx context.Context, metric string, cutoff time.Time) error
+
+		SpendingReport(ctx context.Context, start time.Time, n uint64, interval time.Duration) ([]api.SpendingReportItem, error)
 	}
 
 	// A SettingStore stores settings.
 	SettingStore interface {
+		BandwidthSettings(ctx context.Context) (api.BandwidthSettings, error)
+		UpdateBandwidthSettings(ctx context.Context, bs api.BandwidthSettings) error
+
 		GougingSettings(ctx context.Context) (api.GougingSettings, error)
 		UpdateGougingSettings(ctx context.Context, gs api.GougingSettings) error
 
 		PinnedSettings(ctx context.Context) (api.PinnedSettings, error)
 		UpdatePinnedSettings(ctx context.Context, ps api.PinnedSettings) error
 
+		PlacementSettings(ctx context.Context) (api.PlacementSettings, error)
+		UpdatePlacementSettings(ctx context.Context, ps api.PlacementSettings) error
+
+		RepairSettings(ctx context.Context) (api.RepairSettings, error)
+		UpdateRepairSettings(ctx context.Context, rs api.RepairSettings) error
+
 		UploadSettings(ctx context.Context) (api.UploadSettings, error)
 		UpdateUploadSettings(ctx context.Context, us api.UploadSettings) error
 
@@ -309,6 +345,8 @@ type Bus struct {
 
 	contractLocker        ContractLocker
 	explorer              *ibus.Explorer
+	hostMetricsRecorder   *ibus.HostMetricsRecorder
+	locator               *ibus.HostLocator
 	sectors               UploadingSectorsCache
 	walletMetricsRecorder WalletMetricsRecorder
 
@@ -316,7 +354,7 @@ type Bus struct {
 }
 
 // New returns a new Bus
-func New(cfg config.Bus, masterKey [32]byte, am AlertManager, cm ChainManager, s Syncer, w Wallet, store Store, explorerURL string, l *zap.Logger) (_ *Bus, err error) {
+func New(cfg config.Bus, masterKey [32]byte, am AlertManager, cm ChainManager, s Syncer, w Wallet, store Store, geoIP *geoip.Database, explorerURL string, l *zap.Logger) (_ *Bus, err error) {
 	l = l.Named("bus")
 	dialer := rhp.NewFallbackDialer(store, net.Dialer{}, l)
 
@@ -349,6 +387,9 @@ func New(cfg config.Bus, masterKey [32]byte, am AlertManager, cm ChainManager, s
 	// create contract locker
 	b.contractLocker = ibus.NewContractLocker()
 
+	// create host locator
+	b.locator = ibus.NewHostLocator(geoIP, defaultHostLocationExpiry)
+
 	// create sectors cache
 	b.sectors = ibus.NewSectorsCache()
 
@@ -359,6 +400,9 @@ func New(cfg config.Bus, masterKey [32]byte, am AlertManager, cm ChainManager, s
 	announcementMaxAge := time.Duration(cfg.AnnouncementMaxAgeHours) * time.Hour
 	b.cs = ibus.NewChainSubscriber(cm, store, w, announcementMaxAge, l)
 
+	// create host metrics recorder
+	b.hostMetricsRecorder = ibus.NewHostMetricsRecorder(store, defaultHostRecordMetricInterval)
+
 	// create wallet metrics recorder
 	b.walletMetricsRecorder = ibus.NewWalletMetricRecorder(store, w, defaultWalletRecordMetricInterval, l)
 
@@ -405,8 +449,10 @@ func (b *Bus) Handler() http.Handler {
 		"GET    /contract/:id/ancestors": b.contractIDAncestorsHandler,
 		"POST   /contract/:id/broadcast": b.contractIDBroadcastHandler,
 		"POST   /contract/:id/keepalive": b.contractKeepaliveHandlerPOST,
+		"PUT    /contract/:id/manual":    b.contractManualHandlerPUT,
 		"GET    /contract/:id/revision":  b.contractLatestRevisionHandlerGET,
 		"POST   /contract/:id/prune":     b.contractPruneHandlerPOST,
+		"POST   /contract/:id/reconcile": b.contractReconcileHandlerPOST,
 		"POST   /contract/:id/renew":     b.contractIDRenewHandlerPOST,
 		"POST   /contract/:id/release":   b.contractReleaseHandlerPOST,
 		"GET    /contract/:id/roots":     b.contractIDRootsHandlerGET,
@@ -423,6 +469,8 @@ func (b *Bus) Handler() http.Handler {
 
 		"GET    /host/:hostkey":                  b.hostsPubkeyHandlerGET,
 		"PUT    /host/:hostkey/check":            b.hostsCheckHandlerPUT,
+		"POST   /host/:hostkey/benchmark":        b.hostsBenchmarkHandlerPOST,
+		"PUT    /host/:hostkey/labels":           b.hostsLabelsHandlerPUT,
 		"POST   /host/:hostkey/resetlostsectors": b.hostsResetLostSectorsPOST,
 		"POST   /host/:hostkey/scan":             b.hostsScanHandlerPOST,
 
@@ -439,6 +487,7 @@ func (b *Bus) Handler() http.Handler {
 		"POST   /multipart/listparts":   b.multipartHandlerListPartsPOST,
 
 		"GET    /objects/*prefix": b.objectsHandlerGET,
+		"POST   /objects/access":  b.objectsAccessHandlerPOST,
 		"POST   /objects/copy":    b.objectsCopyHandlerPOST,
 		"POST   /objects/remove":  b.objectsRemoveHandlerPOST,
 		"POST   /objects/rename":  b.objectsRenameHandlerPOST,
@@ -452,19 +501,26 @@ func (b *Bus) Handler() http.Handler {
 
 		"DELETE /sectors/:hostkey/:root": b.sectorsHostRootHandlerDELETE,
 
-		"GET    /settings/gouging": b.settingsGougingHandlerGET,
-		"PUT    /settings/gouging": b.settingsGougingHandlerPUT,
-		"GET    /settings/pinned":  b.settingsPinnedHandlerGET,
-		"PUT    /settings/pinned":  b.settingsPinnedHandlerPUT,
-		"GET    /settings/s3":      b.settingsS3HandlerGET,
-		"PUT    /settings/s3":      b.settingsS3HandlerPUT,
-		"GET    /settings/upload":  b.settingsUploadHandlerGET,
-		"PUT    /settings/upload":  b.settingsUploadHandlerPUT,
+		"GET    /settings/bandwidth": b.settingsBandwidthHandlerGET,
+		"PUT    /settings/bandwidth": b.settingsBandwidthHandlerPUT,
+		"GET    /settings/gouging":   b.settingsGougingHandlerGET,
+		"PUT    /settings/gouging":   b.settingsGougingHandlerPUT,
+		"GET    /settings/pinned":    b.settingsPinnedHandlerGET,
+		"PUT    /settings/pinned":    b.settingsPinnedHandlerPUT,
+		"GET    /settings/placement": b.settingsPlacementHandlerGET,
+		"PUT    /settings/placement": b.settingsPlacementHandlerPUT,
+		"GET    /settings/repair":    b.settingsRepairHandlerGET,
+		"PUT    /settings/repair":    b.settingsRepairHandlerPUT,
+		"GET    /settings/s3":        b.settingsS3HandlerGET,
+		"PUT    /settings/s3":        b.settingsS3HandlerPUT,
+		"GET    /settings/upload":    b.settingsUploadHandlerGET,
+		"PUT    /settings/upload":    b.settingsUploadHandlerPUT,
 
 		"GET    /slabbuffers":      b.slabbuffersHandlerGET,
 		"POST   /slabbuffer/done":  b.packedSlabsHandlerDonePOST,
 		"POST   /slabbuffer/fetch": b.packedSlabsHandlerFetchPOST,
 
+		"POST   /slabs":               b.slabsHandlerPOST,
 		"POST   /slabs/migration":     b.slabsMigrationHandlerPOST,
 		"GET    /slabs/partial/:key":  b.slabsPartialHandlerGET,
 		"POST   /slabs/partial":       b.slabsPartialHandlerPOST,
@@ -472,9 +528,12 @@ func (b *Bus) Handler() http.Handler {
 		"GET    /slab/:key":           b.slabHandlerGET,
 		"PUT    /slab/:key":           b.slabHandlerPUT,
 
+		"GET    /spending": b.spendingHandlerGET,
+
 		"GET    /state": b.stateHandlerGET,
 
 		"GET    /stats/objects": b.objectsStatshandlerGET,
+		"GET    /stats/slabs":   b.slabsStatsHandlerGET,
 
 		"GET    /syncer/address": b.syncerAddrHandler,
 		"POST   /syncer/connect": b.syncerConnectHandler,
@@ -486,6 +545,10 @@ func (b *Bus) Handler() http.Handler {
 		"GET    /txpool/transactions":   b.txpoolTransactionsHandler,
 		"POST   /txpool/broadcast":      b.txpoolBroadcastHandler,
 
+		"POST   /tus/create":            b.tusHandlerCreatePOST,
+		"GET    /tus/upload/:id":        b.tusHandlerUploadGET,
+		"POST   /tus/upload/:id/offset": b.tusHandlerOffsetPOST,
+
 		"POST   /upload/:id":        b.uploadTrackHandlerPOST,
 		"DELETE /upload/:id":        b.uploadFinishedHandlerDELETE,
 		"POST   /upload/:id/sector": b.uploadAddSectorHandlerPOST,
@@ -596,7 +659,111 @@ func (b *Bus) broadcastContract(ctx context.Context, fcid types.FileContractID)
 	return txn.ID(), nil
 }
 
+// checkSpendingBudget returns api.ErrSpendingBudgetExceeded if adding the
+// given amount to a contract that's formed, renewed or refreshed would exceed
+// the spending budget of the current period or if the budget is exhausted.
+func (b *Bus) checkSpendingBudget(ctx context.Context, amount types.Currency) error {
+	ap, err := b.store.AutopilotConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch autopilot config: %w", err)
+	} else if ap.Contracts.Budget.IsZero() {
+		return nil
+	}
+
+	spent, err := b.store.ContractsRenterCost(ctx, api.PeriodStartHeight(b.cm.Tip().Height, ap.Contracts.Period))
+	if err != nil {
+		return fmt.Errorf("failed to fetch period spending: %w", err)
+	}
+	if total, overflow := spent.AddWithOverflow(amount); overflow || total.Cmp(ap.Contracts.Budget) > 0 || spent.Cmp(ap.Contracts.Budget) >= 0 {
+		return fmt.Errorf("%w: can't spend %v, %v spent of %v", api.ErrSpendingBudgetExceeded, amount, spent, ap.Contracts.Budget)
+	}
+	return nil
+}
+
+// fundAccount deposits the given amount into the account using the given
+// contract, the amount is capped by what's left in the contract. It returns
+// the amount that was deposited.
+func (b *Bus) fundAccount(ctx context.Context, fcid types.FileContractID, account rhpv4.Account, amount types.Currency) (types.Currency, error) {
+	// funding accounts is not essential, so we stop doing so once the
+	// spending budget is exhausted
+	if err := b.checkSpendingBudget(ctx, types.ZeroCurrency); err != nil {
+		return types.ZeroCurrency, err
+	}
+
+	// fetch contract
+	cm, err := b.store.Contract(ctx, fcid)
+	if err != nil {
+		return types.ZeroCurrency, fmt.Errorf("failed to fetch contract metadata: %w", err)
+	}
+
+	// fetch host
+	host, err := b.store.Host(ctx, cm.HostKey)
+	if err != nil {
+		return types.ZeroCurrency, fmt.Errorf("failed to fetch host for contract: %w", err)
+	}
+
+	rk := b.masterKey.DeriveContractKey(cm.HostKey)
+
+	// acquire contract
+	lockID, err := b.contractLocker.Acquire(ctx, lockingPriorityFunding, fcid, math.MaxInt64)
+	if err != nil {
+		return types.ZeroCurrency, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer b.contractLocker.Release(fcid, lockID)
+
+	// latest revision
+	rev, err := b.rhp4Client.LatestRevision(ctx, cm.HostKey, host.SiamuxAddr(), fcid)
+	if err != nil {
+		return types.ZeroCurrency, fmt.Errorf("failed to fetch contract revision: %w", err)
+	}
+
+	// cap the deposit by what's left in the contract
+	renterFunds := rev.RenterOutput.Value
+	if renterFunds.IsZero() {
+		return types.ZeroCurrency, errContractOutOfFunds
+	} else if amount.Cmp(renterFunds) > 0 {
+		amount = renterFunds
+	}
+
+	// fund the account
+	signer := ibus.NewFormContractSigner(b.w, rk)
+	res, err := b.rhp4Client.FundAccounts(ctx, host.PublicKey, host.SiamuxAddr(), b.cm.TipState(), signer, cRhp4.ContractRevision{ID: fcid, Revision: rev}, []rhpv4.AccountDeposit{
+		{
+			Account: account,
+			Amount:  amount,
+		},
+	})
+	if err != nil {
+		return types.ZeroCurrency, err
+	}
+
+	// record spending
+	rev = res.Revision
+	err = b.store.RecordContractSpending(ctx, []api.ContractSpendingRecord{
+		{
+			ContractSpending: api.ContractSpending{
+				FundAccount: amount,
+			},
+			ContractID:     fcid,
+			RevisionNumber: rev.RevisionNumber,
+			Size:           rev.Filesize,
+
+			MissedHostPayout:  rev.MissedHostValue,
+			ValidRenterPayout: rev.RenterOutput.Value,
+		},
+	})
+	if err != nil {
+		b.logger.Errorw("failed to record contract spending", zap.Error(err))
+	}
+	return amount, nil
+}
+
 func (b *Bus) formContract(ctx context.Context, hk types.PublicKey, hostIP string, hostAddr, renterAddr types.Address, prices rhpv4.HostPrices, renterFunds types.Currency, collateral types.Currency, endHeight uint64) (api.ContractMetadata, error) {
+	// check the budget
+	if err := b.checkSpendingBudget(ctx, renterFunds.Add(prices.ContractPrice)); err != nil {
+		return api.ContractMetadata{}, err
+	}
+
 	cs := b.cm.TipState()
 	key := b.masterKey.DeriveContractKey(hk)
 	signer := ibus.NewFormContractSigner(b.w, key)
@@ -625,6 +792,7 @@ func (b *Bus) formContract(ctx context.Context, hk types.PublicKey, hostIP strin
 		WindowEnd:          contract.Revision.ProofHeight + rhpv4.ProofWindow,
 		ContractPrice:      res.Usage.RenterCost(),
 		InitialRenterFunds: renterFunds,
+		RenterCost:         res.Cost,
 		Usability:          api.ContractUsabilityGood,
 	}, nil
 }
@@ -658,6 +826,11 @@ func (b *Bus) refreshContract(ctx context.Context, cs consensus.State, h api.Hos
 		additionalRenterFunds = renterFunds.Sub(rev.RenterOutput.Value)
 	}
 
+	// check the budget, only the additional funds count towards it
+	if err := b.checkSpendingBudget(ctx, additionalRenterFunds.Add(settings.Prices.ContractPrice)); err != nil {
+		return api.ContractMetadata{}, err
+	}
+
 	var res cRhp4.RPCRefreshContractResult
 	res, err = b.rhp4Client.RefreshContract(ctx, h.PublicKey, h.SiamuxAddr(), b.cm, signer, cs, settings.Prices, rev, rhpv4.RPCRefreshContractParams{
 		ContractID: c.ID,
@@ -681,6 +854,7 @@ func (b *Bus) refreshContract(ctx context.Context, cs consensus.State, h api.Hos
 		WindowEnd:          contract.Revision.ExpirationHeight,
 		ContractPrice:      settings.Prices.ContractPrice,
 		InitialRenterFunds: contract.Revision.RenterOutput.Value,
+		RenterCost:         res.Cost,
 		Usability:          api.ContractUsabilityGood,
 	}, nil
 }
@@ -706,6 +880,19 @@ func (b *Bus) renewContract(ctx context.Context, cs consensus.State, h api.Host,
 		return api.ContractMetadata{}, errors.New(gb.String())
 	}
 
+	// check the budget, the remaining funds are rolled over so only the
+	// difference counts towards it, renewals of contracts that contain data
+	// are always allowed since we would lose the data otherwise
+	if rev.Filesize == 0 {
+		newFunds := types.ZeroCurrency
+		if renterFunds.Cmp(rev.RenterOutput.Value) > 0 {
+			newFunds = renterFunds.Sub(rev.RenterOutput.Value)
+		}
+		if err := b.checkSpendingBudget(ctx, newFunds.Add(settings.Prices.ContractPrice)); err != nil {
+			return api.ContractMetadata{}, err
+		}
+	}
+
 	var res cRhp4.RPCRenewContractResult
 	res, err = b.rhp4Client.RenewContract(ctx, h.PublicKey, h.SiamuxAddr(), b.cm, signer, cs, settings.Prices, rev, rhpv4.RPCRenewContractParams{
 		ContractID:  c.ID,
@@ -730,6 +917,7 @@ func (b *Bus) renewContract(ctx context.Context, cs consensus.State, h api.Host,
 		WindowEnd:          contract.Revision.ExpirationHeight,
 		ContractPrice:      settings.Prices.ContractPrice,
 		InitialRenterFunds: contract.Revision.RenterOutput.Value,
+		RenterCost:         res.Cost,
 		Usability:          api.ContractUsabilityGood,
 	}, nil
 }
@@ -23,6 +23,11 @@ func WithHostsConfig(cfg api.HostsConfig) UpdateAutopilotOption {
 		req.Hosts = &cfg
 	}
 }
+func WithContractSets(sets map[string]api.ContractSetConfig) UpdateAutopilotOption {
+	return func(req *api.UpdateAutopilotRequest) {
+		req.ContractSets = &sets
+	}
+}
 
 // Autopilot returns the autopilot configuration.
 func (c *Client) AutopilotConfig(ctx context.Context) (ap api.AutopilotConfig, err error) {
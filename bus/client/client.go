@@ -31,6 +31,15 @@ func (c *Client) Backup(ctx context.Context, database, dstPath string) (err erro
 	return
 }
 
+// BenchmarkHost benchmarks a host by uploading and downloading a sector,
+// returning its throughput and time to first byte.
+func (c *Client) BenchmarkHost(ctx context.Context, hostKey types.PublicKey, timeout time.Duration) (resp api.HostBenchmarkResponse, err error) {
+	err = c.c.POST(ctx, fmt.Sprintf("/host/%s/benchmark", hostKey), api.HostBenchmarkRequest{
+		Timeout: api.DurationMS(timeout),
+	}, &resp)
+	return
+}
+
 // ScanHost scans a host, returning its current settings and prices.
 func (c *Client) ScanHost(ctx context.Context, hostKey types.PublicKey, timeout time.Duration) (resp api.HostScanResponse, err error) {
 	err = c.c.POST(ctx, fmt.Sprintf("/host/%s/scan", hostKey), api.HostScanRequest{
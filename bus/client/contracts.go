@@ -103,12 +103,19 @@ func (c *Client) DeleteAllContracts(ctx context.Context) (err error) {
 
 // FormContract forms a contract with a host and adds it to the bus.
 func (c *Client) FormContract(ctx context.Context, renterAddress types.Address, renterFunds types.Currency, hostKey types.PublicKey, hostCollateral types.Currency, endHeight uint64) (contract api.ContractMetadata, err error) {
+	return c.FormContractInSet(ctx, renterAddress, renterFunds, hostKey, hostCollateral, endHeight, "")
+}
+
+// FormContractInSet forms a contract with a host and adds it to the bus, the
+// contract is added to the contract set with given name.
+func (c *Client) FormContractInSet(ctx context.Context, renterAddress types.Address, renterFunds types.Currency, hostKey types.PublicKey, hostCollateral types.Currency, endHeight uint64, contractSet string) (contract api.ContractMetadata, err error) {
 	err = c.c.POST(ctx, "/contracts/form", api.ContractFormRequest{
 		EndHeight:      endHeight,
 		HostCollateral: hostCollateral,
 		HostKey:        hostKey,
 		RenterFunds:    renterFunds,
 		RenterAddress:  renterAddress,
+		ContractSet:    contractSet,
 	}, &contract)
 	return
 }
@@ -143,6 +150,13 @@ func (c *Client) PruneContract(ctx context.Context, contractID types.FileContrac
 	return
 }
 
+// ReconcileContract compares the sector roots of the given contract on the host
+// to the ones in the database, sectors missing on the host are marked as lost.
+func (c *Client) ReconcileContract(ctx context.Context, contractID types.FileContractID, timeout time.Duration) (res api.ContractReconcileResponse, err error) {
+	err = c.c.POST(ctx, fmt.Sprintf("/contract/%s/reconcile", contractID), api.ContractReconcileRequest{Timeout: api.DurationMS(timeout)}, &res)
+	return
+}
+
 // RenewContract renews an existing contract with a host and adds it to the bus.
 func (c *Client) RenewContract(ctx context.Context, contractID types.FileContractID, endHeight uint64, renterFunds, minNewCollateral types.Currency) (renewal api.ContractMetadata, err error) {
 	req := api.ContractRenewRequest{
@@ -174,6 +188,17 @@ func (c *Client) ReleaseContract(ctx context.Context, contractID types.FileContr
 	return
 }
 
+// UpdateContractManual marks the given contract as manual, manual contracts
+// are not maintained by the autopilot and only renewed if renewal settings are
+// provided.
+func (c *Client) UpdateContractManual(ctx context.Context, contractID types.FileContractID, manual bool, renewal *api.ContractRenewalSettings) (err error) {
+	err = c.c.PUT(ctx, fmt.Sprintf("/contract/%s/manual", contractID), api.ContractManualRequest{
+		Manual:  manual,
+		Renewal: renewal,
+	})
+	return
+}
+
 // UpdateContractUsability updates the usability of the given contract.
 func (c *Client) UpdateContractUsability(ctx context.Context, contractID types.FileContractID, usability string) (err error) {
 	err = c.c.PUT(ctx, fmt.Sprintf("/contract/%s/usability", contractID), usability)
@@ -24,6 +24,7 @@ func (c *Client) Hosts(ctx context.Context, opts api.HostOptions) (hosts []api.H
 		UsabilityMode:   opts.UsabilityMode,
 		AddressContains: opts.AddressContains,
 		KeyIn:           opts.KeyIn,
+		Labels:          opts.Labels,
 		MaxLastScan:     opts.MaxLastScan,
 	}, &hosts)
 	return
@@ -75,6 +76,15 @@ func (c *Client) UpdateHostCheck(ctx context.Context, hostKey types.PublicKey, h
 	return
 }
 
+// UpdateHostLabels replaces the labels and notes of a host.
+func (c *Client) UpdateHostLabels(ctx context.Context, hostKey types.PublicKey, labels []string, notes string) (err error) {
+	err = c.c.PUT(ctx, fmt.Sprintf("/host/%s/labels", hostKey), api.UpdateHostLabelsRequest{
+		Labels: labels,
+		Notes:  notes,
+	})
+	return
+}
+
 // UsableHosts returns a list of hosts that are ready to be used. That means
 // they are deemed usable by the autopilot, they are not gouging, not blocked,
 // not offline, etc.
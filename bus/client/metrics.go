@@ -55,6 +55,46 @@ func (c *Client) ContractPruneMetrics(ctx context.Context, start time.Time, n ui
 	return resp, nil
 }
 
+func (c *Client) HealthMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HealthMetricsQueryOpts) ([]api.HealthMetric, error) {
+	values := url.Values{}
+	values.Set("start", api.TimeRFC3339(start).String())
+	values.Set("n", fmt.Sprint(n))
+	values.Set("interval", api.DurationMS(interval).String())
+
+	var resp []api.HealthMetric
+	if err := c.metric(ctx, api.MetricHealth, values, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) HostMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HostMetricsQueryOpts) ([]api.HostMetric, error) {
+	values := url.Values{}
+	values.Set("start", api.TimeRFC3339(start).String())
+	values.Set("n", fmt.Sprint(n))
+	values.Set("interval", api.DurationMS(interval).String())
+	values.Set("hostkey", opts.HostKey.String())
+
+	var resp []api.HostMetric
+	if err := c.metric(ctx, api.MetricHost, values, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) HostNetworkMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HostNetworkMetricsQueryOpts) ([]api.HostNetworkMetric, error) {
+	values := url.Values{}
+	values.Set("start", api.TimeRFC3339(start).String())
+	values.Set("n", fmt.Sprint(n))
+	values.Set("interval", api.DurationMS(interval).String())
+
+	var resp []api.HostNetworkMetric
+	if err := c.metric(ctx, api.MetricHostNetwork, values, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 func (c *Client) WalletMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.WalletMetricsQueryOpts) ([]api.WalletMetric, error) {
 	values := url.Values{}
 	values.Set("start", api.TimeRFC3339(start).String())
@@ -72,6 +112,10 @@ func (c *Client) RecordContractPruneMetric(ctx context.Context, metrics ...api.C
 	return c.recordMetric(ctx, api.MetricContractPrune, api.ContractPruneMetricRequestPUT{Metrics: metrics})
 }
 
+func (c *Client) RecordHealthMetric(ctx context.Context, metrics ...api.HealthMetric) error {
+	return c.recordMetric(ctx, api.MetricHealth, api.HealthMetricRequestPUT{Metrics: metrics})
+}
+
 func (c *Client) PruneMetrics(ctx context.Context, metric string, cutoff time.Time) error {
 	values := url.Values{}
 	values.Set("cutoff", api.TimeRFC3339(cutoff).String())
@@ -46,6 +46,13 @@ func (c *Client) DeleteObject(ctx context.Context, bucket, key string) (err erro
 	return
 }
 
+// RecordObjectAccesses records reads of objects, updating their access
+// statistics.
+func (c *Client) RecordObjectAccesses(ctx context.Context, records []api.ObjectAccessRecord) (err error) {
+	err = c.c.POST(ctx, "/objects/access", records, nil)
+	return
+}
+
 // RemoveObjects removes objects with given prefix.
 func (c *Client) RemoveObjects(ctx context.Context, bucket, prefix string) (err error) {
 	err = c.c.POST(ctx, "/objects/remove", api.ObjectsRemoveRequest{
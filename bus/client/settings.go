@@ -6,6 +6,17 @@ import (
 	"go.sia.tech/renterd/v2/api"
 )
 
+// BandwidthSettings returns the bandwidth settings.
+func (c *Client) BandwidthSettings(ctx context.Context) (bs api.BandwidthSettings, err error) {
+	err = c.c.GET(ctx, "/settings/bandwidth", &bs)
+	return
+}
+
+// UpdateBandwidthSettings updates the given setting.
+func (c *Client) UpdateBandwidthSettings(ctx context.Context, bs api.BandwidthSettings) error {
+	return c.c.PUT(ctx, "/settings/bandwidth", bs)
+}
+
 // GougingSettings returns the gouging settings.
 func (c *Client) GougingSettings(ctx context.Context) (gs api.GougingSettings, err error) {
 	err = c.c.GET(ctx, "/settings/gouging", &gs)
@@ -28,6 +39,28 @@ func (c *Client) UpdatePinnedSettings(ctx context.Context, ps api.PinnedSettings
 	return c.c.PUT(ctx, "/settings/pinned", ps)
 }
 
+// PlacementSettings returns the placement settings.
+func (c *Client) PlacementSettings(ctx context.Context) (ps api.PlacementSettings, err error) {
+	err = c.c.GET(ctx, "/settings/placement", &ps)
+	return
+}
+
+// UpdatePlacementSettings updates the given setting.
+func (c *Client) UpdatePlacementSettings(ctx context.Context, ps api.PlacementSettings) error {
+	return c.c.PUT(ctx, "/settings/placement", ps)
+}
+
+// RepairSettings returns the repair settings.
+func (c *Client) RepairSettings(ctx context.Context) (rs api.RepairSettings, err error) {
+	err = c.c.GET(ctx, "/settings/repair", &rs)
+	return
+}
+
+// UpdateRepairSettings updates the given setting.
+func (c *Client) UpdateRepairSettings(ctx context.Context, rs api.RepairSettings) error {
+	return c.c.PUT(ctx, "/settings/repair", rs)
+}
+
 // S3Settings returns the S3 settings.
 func (c *Client) S3Settings(ctx context.Context) (as api.S3Settings, err error) {
 	err = c.c.GET(ctx, "/settings/s3", &as)
@@ -100,18 +100,40 @@ func (c *Client) Slab(ctx context.Context, key object.EncryptionKey) (slab objec
 	return
 }
 
+// Slabs returns the slabs with the given keys together with the buckets of the
+// objects referencing them. Slabs that don't exist are omitted.
+func (c *Client) Slabs(ctx context.Context, keys []object.EncryptionKey) (slabs []api.SlabWithBuckets, err error) {
+	err = c.c.POST(ctx, "/slabs", api.SlabsRequest{Keys: keys}, &slabs)
+	return
+}
+
 // SlabBuffers returns information about the number of objects and their size.
 func (c *Client) SlabBuffers(ctx context.Context) (buffers []api.SlabBuffer, err error) {
 	err = c.c.GET(ctx, "/slabbuffers", &buffers)
 	return
 }
 
+// SlabHealthStats returns the health distribution of all slabs, the amount of
+// data below the given health cutoff is included in the stats.
+func (c *Client) SlabHealthStats(ctx context.Context, healthCutoff float64) (stats api.SlabHealthStats, err error) {
+	values := url.Values{}
+	values.Set("healthcutoff", fmt.Sprint(healthCutoff))
+	err = c.c.GET(ctx, "/stats/slabs?"+values.Encode(), &stats)
+	return
+}
+
 // SlabsForMigration returns up to 'limit' slabs which require migration. A slab
 // needs to be migrated if it has sectors on contracts that are not part of the
 // given 'set'.
 func (c *Client) SlabsForMigration(ctx context.Context, healthCutoff float64, limit int) (slabs []api.UnhealthySlab, err error) {
+	return c.SlabsForMigrationPage(ctx, healthCutoff, 0, limit)
+}
+
+// SlabsForMigrationPage returns up to 'limit' slabs which require migration,
+// skipping the first 'offset' slabs.
+func (c *Client) SlabsForMigrationPage(ctx context.Context, healthCutoff float64, offset, limit int) (slabs []api.UnhealthySlab, err error) {
 	var usr api.SlabsForMigrationResponse
-	err = c.c.POST(ctx, "/slabs/migration", api.MigrationSlabsRequest{HealthCutoff: healthCutoff, Limit: limit}, &usr)
+	err = c.c.POST(ctx, "/slabs/migration", api.MigrationSlabsRequest{HealthCutoff: healthCutoff, Offset: offset, Limit: limit}, &usr)
 	if err != nil {
 		return
 	}
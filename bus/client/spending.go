@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.sia.tech/renterd/v2/api"
+)
+
+// SpendingReport returns the spending across all contracts in n periods of
+// the given interval.
+func (c *Client) SpendingReport(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.SpendingReportOpts) (report api.SpendingReport, err error) {
+	values := spendingReportValues(start, n, interval, opts)
+	values.Set("format", api.SpendingReportFormatJSON)
+	err = c.c.GET(ctx, fmt.Sprintf("/spending?%s", values.Encode()), &report)
+	return
+}
+
+// SpendingReportCSV writes the spending report for n periods of the given
+// interval to w in CSV format.
+func (c *Client) SpendingReportCSV(ctx context.Context, w io.Writer, start time.Time, n uint64, interval time.Duration, opts api.SpendingReportOpts) error {
+	c.c.Custom("GET", "/spending", nil, &[]byte{})
+	values := spendingReportValues(start, n, interval, opts)
+	values.Set("format", api.SpendingReportFormatCSV)
+
+	u, err := url.Parse(fmt.Sprintf("%s/spending", c.c.BaseURL))
+	if err != nil {
+		panic(err)
+	}
+	u.RawQuery = values.Encode()
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), http.NoBody)
+	if err != nil {
+		panic(err)
+	}
+	req.SetBasicAuth("", c.c.Password)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer io.Copy(io.Discard, resp.Body)
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		err, _ := io.ReadAll(resp.Body)
+		return errors.New(string(err))
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+func spendingReportValues(start time.Time, n uint64, interval time.Duration, opts api.SpendingReportOpts) url.Values {
+	values := url.Values{}
+	values.Set("start", api.TimeRFC3339(start).String())
+	values.Set("n", fmt.Sprint(n))
+	values.Set("interval", api.DurationMS(interval).String())
+	if opts.Currency != "" {
+		values.Set("currency", opts.Currency)
+	}
+	return values
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"go.sia.tech/renterd/v2/api"
+)
+
+// CreateTusUpload creates a new resumable upload of given length.
+func (c *Client) CreateTusUpload(ctx context.Context, bucket, key string, length uint64, opts api.CreateTusUploadOptions) (resp api.TusUpload, err error) {
+	err = c.c.POST(ctx, "/tus/create", api.TusCreateRequest{
+		Bucket:   bucket,
+		Key:      key,
+		Length:   length,
+		MimeType: opts.MimeType,
+		Metadata: opts.Metadata,
+	}, &resp)
+	return
+}
+
+// TusUpload returns information about a specific resumable upload.
+func (c *Client) TusUpload(ctx context.Context, uploadID string) (resp api.TusUpload, err error) {
+	err = c.c.GET(ctx, fmt.Sprintf("/tus/upload/%s", uploadID), &resp)
+	return
+}
+
+// UpdateTusUploadOffset advances the offset of a resumable upload after a
+// chunk of given length was added to it.
+func (c *Client) UpdateTusUploadOffset(ctx context.Context, uploadID string, offset, length uint64) (resp api.TusUpload, err error) {
+	err = c.c.POST(ctx, fmt.Sprintf("/tus/upload/%s/offset", uploadID), api.TusUpdateOffsetRequest{
+		Offset: offset,
+		Length: length,
+	}, &resp)
+	return
+}
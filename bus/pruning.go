@@ -37,34 +37,9 @@ func (b *Bus) pruneContract(ctx context.Context, rk types.PrivateKey, cm api.Con
 	}
 
 	// fetch all contract roots
-	numsectors := rev.Filesize / rhpv4.SectorSize
-	sectorRoots := make([]types.Hash256, 0, numsectors)
-	var rootsUsage rhpv4.Usage
-	for offset := uint64(0); offset < numsectors; {
-		// calculate the batch size
-		length := uint64(rhpv4.MaxSectorBatchSize)
-		if offset+length > numsectors {
-			length = numsectors - offset
-		}
-
-		// fetch the batch
-		res, err := b.rhp4Client.SectorRoots(ctx, cm.HostKey, hostIP, b.cm.TipState(), prices, signer, cRHP4.ContractRevision{
-			ID:       cm.ID,
-			Revision: rev,
-		}, offset, length)
-		if err != nil {
-			return api.ContractPruneResponse{}, fmt.Errorf("failed to fetch contract sectors: %w", err)
-		}
-
-		// update revision since it was revised
-		rev = res.Revision
-
-		// collect roots
-		sectorRoots = append(sectorRoots, res.Roots...)
-		offset += uint64(len(res.Roots))
-
-		// update the cost
-		rootsUsage = rootsUsage.Add(res.Usage)
+	sectorRoots, rev, rootsUsage, err := b.fetchContractRoots(ctx, signer, cm, hostIP, prices, rev)
+	if err != nil {
+		return api.ContractPruneResponse{}, err
 	}
 
 	// fetch indices to prune
@@ -127,3 +102,40 @@ func (b *Bus) pruneContract(ctx context.Context, rk types.PrivateKey, cm api.Con
 		Remaining:    (totalToPrune - uint64(len(toPrune))) * rhpv4.SectorSize,
 	}, nil
 }
+
+// fetchContractRoots downloads all sector roots of the contract from the host,
+// it returns the roots, the revised contract revision and the cost of
+// downloading the roots.
+func (b *Bus) fetchContractRoots(ctx context.Context, signer cRHP4.FormContractSigner, cm api.ContractMetadata, hostIP string, prices rhpv4.HostPrices, rev types.V2FileContract) ([]types.Hash256, types.V2FileContract, rhpv4.Usage, error) {
+	numsectors := rev.Filesize / rhpv4.SectorSize
+	sectorRoots := make([]types.Hash256, 0, numsectors)
+	var rootsUsage rhpv4.Usage
+	for offset := uint64(0); offset < numsectors; {
+		// calculate the batch size
+		length := uint64(rhpv4.MaxSectorBatchSize)
+		if offset+length > numsectors {
+			length = numsectors - offset
+		}
+
+		// fetch the batch
+		res, err := b.rhp4Client.SectorRoots(ctx, cm.HostKey, hostIP, b.cm.TipState(), prices, signer, cRHP4.ContractRevision{
+			ID:       cm.ID,
+			Revision: rev,
+		}, offset, length)
+		if err != nil {
+			return nil, types.V2FileContract{}, rhpv4.Usage{}, fmt.Errorf("failed to fetch contract sectors: %w", err)
+		}
+
+		// update revision since it was revised
+		rev = res.Revision
+
+		// collect roots
+		sectorRoots = append(sectorRoots, res.Roots...)
+		offset += uint64(len(res.Roots))
+
+		// update the cost
+		rootsUsage = rootsUsage.Add(res.Usage)
+	}
+
+	return sectorRoots, rev, rootsUsage, nil
+}
@@ -0,0 +1,109 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	rhpv4 "go.sia.tech/core/rhp/v4"
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+	ibus "go.sia.tech/renterd/v2/internal/bus"
+	"go.sia.tech/renterd/v2/internal/gouging"
+)
+
+// reconcileContract downloads the sector roots of the contract from the host
+// and compares them to the sectors stored on the contract according to the
+// database. Sectors that are missing on the host are marked as lost, sectors
+// that are stored on the host but aren't referenced are reported as prunable.
+func (b *Bus) reconcileContract(ctx context.Context, rk types.PrivateKey, cm api.ContractMetadata, hostIP string, gc gouging.Checker, pendingUploads map[types.Hash256]struct{}) (api.ContractReconcileResponse, error) {
+	signer := ibus.NewFormContractSigner(b.w, rk)
+
+	// get latest revision
+	rev, err := b.rhp4Client.LatestRevision(ctx, cm.HostKey, hostIP, cm.ID)
+	if err != nil {
+		return api.ContractReconcileResponse{}, fmt.Errorf("failed to fetch revision for reconciliation: %w", err)
+	} else if rev.RevisionNumber < cm.RevisionNumber {
+		return api.ContractReconcileResponse{}, fmt.Errorf("latest known revision %d is less than contract revision %d", rev.RevisionNumber, cm.RevisionNumber)
+	}
+
+	// get prices
+	settings, err := b.rhp4Client.Settings(ctx, cm.HostKey, hostIP)
+	if err != nil {
+		return api.ContractReconcileResponse{}, fmt.Errorf("failed to fetch prices for reconciliation: %w", err)
+	}
+
+	// make sure they are sane
+	if gb := gc.Check(settings); gb.Gouging() {
+		return api.ContractReconcileResponse{}, fmt.Errorf("host for reconciliation is gouging: %v", gb.String())
+	}
+
+	// fetch all contract roots from the host
+	hostRoots, rev, rootsUsage, err := b.fetchContractRoots(ctx, signer, cm, hostIP, settings.Prices, rev)
+	if err != nil {
+		return api.ContractReconcileResponse{}, err
+	}
+
+	// record spending
+	if !rootsUsage.RenterCost().IsZero() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		err := b.store.RecordContractSpending(ctx, []api.ContractSpendingRecord{
+			{
+				ContractSpending: api.ContractSpending{
+					SectorRoots: rootsUsage.RenterCost(),
+				},
+				ContractID:     cm.ID,
+				RevisionNumber: rev.RevisionNumber,
+				Size:           rev.Filesize,
+
+				MissedHostPayout:  rev.MissedHostOutput().Value,
+				ValidRenterPayout: rev.RenterOutput.Value,
+			},
+		})
+		if err != nil {
+			return api.ContractReconcileResponse{}, fmt.Errorf("failed to record contract spending: %w", err)
+		}
+	}
+
+	// fetch the roots the database expects to be stored on the contract
+	dbRoots, err := b.store.ContractRoots(ctx, cm.ID)
+	if err != nil {
+		return api.ContractReconcileResponse{}, fmt.Errorf("failed to fetch contract roots from the database: %w", err)
+	}
+
+	// mark the sectors that are missing on the host as lost
+	onHost := make(map[types.Hash256]struct{}, len(hostRoots))
+	for _, root := range hostRoots {
+		onHost[root] = struct{}{}
+	}
+	var lost []types.Hash256
+	for _, root := range dbRoots {
+		if _, ok := onHost[root]; !ok {
+			lost = append(lost, root)
+		}
+	}
+	n, err := b.store.DeleteContractSectors(ctx, cm.ID, lost)
+	if err != nil {
+		return api.ContractReconcileResponse{}, fmt.Errorf("failed to mark %d sectors as lost: %w", len(lost), err)
+	}
+	missing := uint64(n)
+
+	// fetch the roots that aren't referenced, ignoring pending uploads
+	indices, err := b.store.PrunableContractRoots(ctx, cm.ID, hostRoots)
+	if err != nil {
+		return api.ContractReconcileResponse{}, fmt.Errorf("failed to fetch prunable roots: %w", err)
+	}
+	var prunable uint64
+	for _, index := range indices {
+		if _, ok := pendingUploads[hostRoots[index]]; !ok {
+			prunable++
+		}
+	}
+
+	return api.ContractReconcileResponse{
+		ContractSize: rev.Filesize,
+		Missing:      missing * rhpv4.SectorSize,
+		Prunable:     prunable * rhpv4.SectorSize,
+	}, nil
+}
@@ -17,8 +17,6 @@ import (
 
 	rhpv4 "go.sia.tech/core/rhp/v4"
 
-	rhp4utils "go.sia.tech/coreutils/rhp/v4"
-	ibus "go.sia.tech/renterd/v2/internal/bus"
 	"go.sia.tech/renterd/v2/internal/prometheus"
 	"go.sia.tech/renterd/v2/internal/utils"
 	"go.sia.tech/renterd/v2/stores/sql"
@@ -42,74 +40,16 @@ func (b *Bus) accountsFundHandler(jc jape.Context) {
 		return
 	}
 
-	// fetch contract
-	cm, err := b.store.Contract(jc.Request.Context(), req.ContractID)
+	deposit, err := b.fundAccount(jc.Request.Context(), req.ContractID, rhpv4.Account(req.AccountID), req.Amount)
 	if errors.Is(err, api.ErrContractNotFound) {
 		jc.Error(err, http.StatusNotFound)
 		return
-	} else if jc.Check("failed to fetch contract metadata", err) != nil {
-		return
-	}
-
-	// fetch host
-	host, err := b.store.Host(jc.Request.Context(), cm.HostKey)
-	if jc.Check("failed to fetch host for contract", err) != nil {
-		return
-	}
-
-	rk := b.masterKey.DeriveContractKey(cm.HostKey)
-
-	// acquire contract
-	lockID, err := b.contractLocker.Acquire(jc.Request.Context(), lockingPriorityFunding, req.ContractID, math.MaxInt64)
-	if jc.Check("failed to acquire lock", err) != nil {
-		return
-	}
-	defer b.contractLocker.Release(req.ContractID, lockID)
-
-	// latest revision
-	rev, err := b.rhp4Client.LatestRevision(jc.Request.Context(), cm.HostKey, host.SiamuxAddr(), req.ContractID)
-	if jc.Check("failed to fetch contract revision", err) != nil {
-		return
-	}
-	// cap the deposit by what's left in the contract
-	renterFunds := rev.RenterOutput.Value
-	if renterFunds.IsZero() {
-		jc.Error(errors.New("contract is out of funds"), http.StatusBadRequest)
+	} else if errors.Is(err, errContractOutOfFunds) || errors.Is(err, api.ErrSpendingBudgetExceeded) {
+		jc.Error(err, http.StatusBadRequest)
 		return
-	} else if req.Amount.Cmp(renterFunds) > 0 {
-		req.Amount = renterFunds
-	}
-	deposit := req.Amount
-	// fund the account
-	signer := ibus.NewFormContractSigner(b.w, rk)
-	res, err := b.rhp4Client.FundAccounts(jc.Request.Context(), host.PublicKey, host.SiamuxAddr(), b.cm.TipState(), signer, rhp4utils.ContractRevision{ID: req.ContractID, Revision: rev}, []rhpv4.AccountDeposit{
-		{
-			Account: rhpv4.Account(req.AccountID),
-			Amount:  deposit,
-		},
-	})
-	if jc.Check("failed to fund account", err) != nil {
+	} else if jc.Check("failed to fund account", err) != nil {
 		return
 	}
-
-	// record spending
-	rev = res.Revision
-	err = b.store.RecordContractSpending(jc.Request.Context(), []api.ContractSpendingRecord{
-		{
-			ContractSpending: api.ContractSpending{
-				FundAccount: deposit,
-			},
-			ContractID:     req.ContractID,
-			RevisionNumber: rev.RevisionNumber,
-			Size:           rev.Filesize,
-
-			MissedHostPayout:  rev.MissedHostValue,
-			ValidRenterPayout: rev.RenterOutput.Value,
-		},
-	})
-	if err != nil {
-		b.logger.Errorw("failed to record contract spending", zap.Error(err))
-	}
 	jc.Encode(api.AccountsFundResponse{
 		Deposit: deposit,
 	})
@@ -455,12 +395,28 @@ func (b *Bus) hostsHandlerGET(jc jape.Context) {
 	gc := gouging.NewChecker(gp.GougingSettings, gp.ConsensusState)
 	bh := b.cm.TipState().Index.Height
 
+	ps, err := b.placementSettings(jc.Request.Context())
+	if jc.Check("could not get placement settings", err) != nil {
+		return
+	}
+
 	var infos []api.HostInfo
 	for _, h := range hosts {
 		// ignore height
 		h.V2HS.HostSettings.Prices.TipHeight = bh
-		if len(h.V2SiamuxAddresses) > 0 && !gc.Check(h.V2HS).Gouging() {
-			infos = append(infos, h.HostInfo)
+		if len(h.V2SiamuxAddresses) == 0 || gc.Check(h.V2HS).Gouging() {
+			continue
+		}
+
+		infos = append(infos, h.HostInfo)
+	}
+
+	// locate the hosts if shard placement is subject to diversity rules,
+	// locations are refreshed when hosts are scanned so this is usually
+	// served from the cache
+	if ps.Diversity.Enabled() {
+		for i, loc := range b.locator.LocateAll(jc.Request.Context(), infos) {
+			infos[i].Location = &loc
 		}
 	}
 	jc.Encode(infos)
@@ -513,6 +469,7 @@ func (b *Bus) hostsHandlerPOST(jc jape.Context) {
 		UsabilityMode:   req.UsabilityMode,
 		AddressContains: req.AddressContains,
 		KeyIn:           req.KeyIn,
+		Labels:          req.Labels,
 		Offset:          req.Offset,
 		Limit:           req.Limit,
 		MaxLastScan:     req.MaxLastScan,
@@ -603,7 +560,13 @@ func (b *Bus) hostsScanHandlerPOST(jc jape.Context) {
 	// record host scan - make sure this is interrupted by the request ctx and
 	// not the context with the timeout used to time out the scan itself.
 	// Otherwise scans that time out won't be recorded.
-	b.recordHostScan(jc.Request.Context(), err, hk, v2Settings)
+	b.recordHostScan(jc.Request.Context(), err, hk, v2Settings, ping)
+
+	// refresh the host's location so it doesn't have to be resolved when the
+	// usable hosts are requested
+	if err == nil {
+		b.locator.Refresh(jc.Request.Context(), api.HostInfo{PublicKey: h.PublicKey, V2SiamuxAddresses: h.V2SiamuxAddresses})
+	}
 
 	// send response
 	var errStr string
@@ -617,6 +580,96 @@ func (b *Bus) hostsScanHandlerPOST(jc jape.Context) {
 	})
 }
 
+func (b *Bus) hostsBenchmarkHandlerPOST(jc jape.Context) {
+	ctx := jc.Request.Context()
+
+	// decode the request
+	var hk types.PublicKey
+	if jc.DecodeParam("hostkey", &hk) != nil {
+		return
+	}
+	var req api.HostBenchmarkRequest
+	if jc.Decode(&req) != nil {
+		return
+	}
+
+	// fetch host
+	h, err := b.store.Host(ctx, hk)
+	if errors.Is(err, api.ErrHostNotFound) {
+		jc.Error(err, http.StatusNotFound)
+		return
+	} else if jc.Check("failed to fetch host", err) != nil {
+		return
+	}
+
+	// fetch a contract to fund the benchmark with
+	c, err := b.benchmarkContract(ctx, hk)
+	if errors.Is(err, errNoBenchmarkContract) {
+		jc.Error(err, http.StatusBadRequest)
+		return
+	} else if jc.Check("failed to fetch contract for benchmark", err) != nil {
+		return
+	}
+
+	// create gouging checker
+	gp, err := b.gougingParams(ctx)
+	if jc.Check("couldn't fetch gouging parameters", err) != nil {
+		return
+	}
+	gc := gouging.NewChecker(gp.GougingSettings, gp.ConsensusState)
+
+	// benchmark host
+	hb, err := b.benchmarkHost(ctx, time.Duration(req.Timeout), h, c.ID, gc)
+	if ctx.Err() != nil {
+		jc.Error(errors.New("benchmark failed due to bus shutting down"), http.StatusServiceUnavailable)
+		return
+	} else if err != nil {
+		hb = api.HostBenchmark{Timestamp: time.Now()}
+	}
+
+	// record host benchmark, unless it failed for a reason that isn't the
+	// host's fault
+	if !errors.Is(err, errBenchmarkAborted) {
+		b.recordHostBenchmark(ctx, hk, hb)
+	}
+
+	// send response
+	var errStr string
+	if err != nil {
+		errStr = err.Error()
+	}
+	jc.Encode(api.HostBenchmarkResponse{
+		UploadThroughput:   hb.UploadThroughput,
+		DownloadThroughput: hb.DownloadThroughput,
+		TimeToFirstByte:    api.DurationMS(hb.TimeToFirstByte),
+		BenchmarkError:     errStr,
+	})
+}
+
+func (b *Bus) hostsLabelsHandlerPUT(jc jape.Context) {
+	var hk types.PublicKey
+	if jc.DecodeParam("hostkey", &hk) != nil {
+		return
+	}
+	var req api.UpdateHostLabelsRequest
+	if jc.Decode(&req) != nil {
+		return
+	}
+
+	labels, err := api.NormalizeHostLabels(req.Labels)
+	if err != nil {
+		jc.Error(err, http.StatusBadRequest)
+		return
+	}
+
+	err = b.store.UpdateHostLabels(jc.Request.Context(), hk, labels, strings.TrimSpace(req.Notes))
+	if errors.Is(err, api.ErrHostNotFound) {
+		jc.Error(err, http.StatusNotFound)
+		return
+	}
+	jc.Check("failed to update host labels", err)
+}
+
 func (b *Bus) hostsResetLostSectorsPOST(jc jape.Context) {
 	var hostKey types.PublicKey
 	if jc.DecodeParam("hostkey", &hostKey) != nil {
@@ -672,9 +725,13 @@ func (b *Bus) hostsBlocklistHandlerPUT(jc jape.Context) {
 		if len(req.Add)+len(req.Remove) > 0 && req.Clear {
 			jc.Error(errors.New("cannot add or remove entries while clearing the blocklist"), http.StatusBadRequest)
 			return
-		} else if jc.Check("couldn't update blocklist entries", b.store.UpdateHostBlocklistEntries(ctx, req.Add, req.Remove, req.Clear)) != nil {
+		}
+		err := b.store.UpdateHostBlocklistEntries(ctx, req.Add, req.Remove, req.Clear)
+		if errors.Is(err, api.ErrInvalidBlocklistEntry) {
+			jc.Error(err, http.StatusBadRequest)
 			return
 		}
+		jc.Check("couldn't update blocklist entries", err)
 	}
 }
 
@@ -859,6 +916,79 @@ func (b *Bus) contractPruneHandlerPOST(jc jape.Context) {
 	jc.Encode(res)
 }
 
+func (b *Bus) contractReconcileHandlerPOST(jc jape.Context) {
+	ctx := jc.Request.Context()
+
+	// decode fcid
+	var fcid types.FileContractID
+	if jc.DecodeParam("id", &fcid) != nil {
+		return
+	}
+
+	// decode timeout
+	var req api.ContractReconcileRequest
+	if jc.Decode(&req) != nil {
+		return
+	}
+
+	// create gouging checker
+	gp, err := b.gougingParams(ctx)
+	if jc.Check("couldn't fetch gouging parameters", err) != nil {
+		return
+	}
+	gc := gouging.NewChecker(gp.GougingSettings, gp.ConsensusState)
+
+	// apply timeout
+	reconcileCtx := ctx
+	if req.Timeout > 0 {
+		var cancel context.CancelFunc
+		reconcileCtx, cancel = context.WithTimeout(ctx, time.Duration(req.Timeout))
+		defer cancel()
+	}
+
+	// acquire contract lock indefinitely and defer the release, this
+	// prevents sectors from being added to the contract while we compare the
+	// roots
+	lockID, err := b.contractLocker.Acquire(reconcileCtx, lockingPriorityPruning, fcid, time.Duration(math.MaxInt64))
+	if jc.Check("couldn't acquire contract lock", err) != nil {
+		return
+	}
+	defer func() {
+		if err := b.contractLocker.Release(fcid, lockID); err != nil {
+			b.logger.Errorw("failed to release contract lock", zap.Error(err))
+		}
+	}()
+
+	// fetch the contract from the bus
+	c, err := b.store.Contract(ctx, fcid)
+	if errors.Is(err, api.ErrContractNotFound) {
+		jc.Error(err, http.StatusNotFound)
+		return
+	} else if jc.Check("couldn't fetch contract", err) != nil {
+		return
+	}
+
+	// fetch the corresponding host
+	host, err := b.store.Host(ctx, c.HostKey)
+	if jc.Check("failed to fetch host for reconciliation", err) != nil {
+		return
+	}
+
+	// build map of uploading sectors
+	pending := make(map[types.Hash256]struct{})
+	for _, root := range b.sectors.Sectors() {
+		pending[root] = struct{}{}
+	}
+
+	// reconcile the contract
+	rk := b.masterKey.DeriveContractKey(c.HostKey)
+	res, err := b.reconcileContract(reconcileCtx, rk, c, host.SiamuxAddr(), gc, pending)
+	if jc.Check("failed to reconcile contract", err) != nil {
+		return
+	}
+	jc.Encode(res)
+}
+
 func (b *Bus) contractsPrunableDataHandlerGET(jc jape.Context) {
 	sizes, err := b.store.ContractSizes(jc.Request.Context())
 	if jc.Check("failed to fetch contract sizes", err) != nil {
@@ -933,6 +1063,28 @@ func (b *Bus) contractUsabilityHandlerPUT(jc jape.Context) {
 	jc.Check("failed to update contract usability", err)
 }
 
+func (b *Bus) contractManualHandlerPUT(jc jape.Context) {
+	var id types.FileContractID
+	if jc.DecodeParam("id", &id) != nil {
+		return
+	}
+
+	var req api.ContractManualRequest
+	if jc.Decode(&req) != nil {
+		return
+	} else if err := req.Validate(); err != nil {
+		jc.Error(err, http.StatusBadRequest)
+		return
+	}
+
+	err := b.store.UpdateContractManual(jc.Request.Context(), id, req.Manual, req.Renewal)
+	if errors.Is(err, api.ErrContractNotFound) {
+		jc.Error(err, http.StatusNotFound)
+		return
+	}
+	jc.Check("failed to update contract", err)
+}
+
 func (b *Bus) contractReleaseHandlerPOST(jc jape.Context) {
 	var id types.FileContractID
 	if jc.DecodeParam("id", &id) != nil {
@@ -1036,7 +1188,10 @@ func (b *Bus) contractIDRenewHandlerPOST(jc jape.Context) {
 	} else {
 		contract, err = b.renewContract(ctx, cs, h, gp, c, rrr.RenterFunds, rrr.MinNewCollateral, rrr.EndHeight)
 	}
-	if jc.Check("couldn't renew/refresh contract", err) != nil {
+	if errors.Is(err, api.ErrSpendingBudgetExceeded) {
+		jc.Error(err, http.StatusBadRequest)
+		return
+	} else if jc.Check("couldn't renew/refresh contract", err) != nil {
 		return
 	}
 
@@ -1154,6 +1309,14 @@ func (b *Bus) objectHandlerPUT(jc jape.Context) {
 	jc.Check("couldn't store object", b.store.UpdateObject(jc.Request.Context(), aor.Bucket, jc.PathParam("key"), aor.ETag, aor.MimeType, aor.Metadata, aor.Object))
 }
 
+func (b *Bus) objectsAccessHandlerPOST(jc jape.Context) {
+	var records []api.ObjectAccessRecord
+	if jc.Decode(&records) != nil {
+		return
+	}
+	jc.Check("failed to record object accesses", b.store.RecordObjectAccesses(jc.Request.Context(), records))
+}
+
 func (b *Bus) objectsCopyHandlerPOST(jc jape.Context) {
 	var orr api.CopyObjectsRequest
 	if jc.Decode(&orr) != nil {
@@ -1281,6 +1444,28 @@ func (b *Bus) packedSlabsHandlerDonePOST(jc jape.Context) {
 	jc.Check("failed to mark packed slab(s) as uploaded", b.store.MarkPackedSlabsUploaded(jc.Request.Context(), psrp.Slabs))
 }
 
+func (b *Bus) settingsBandwidthHandlerGET(jc jape.Context) {
+	bs, err := b.bandwidthSettings(jc.Request.Context())
+	if err != nil {
+		jc.Error(err, http.StatusInternalServerError)
+		return
+	}
+	jc.Encode(bs)
+}
+
+func (b *Bus) settingsBandwidthHandlerPUT(jc jape.Context) {
+	var bs api.BandwidthSettings
+	if jc.Decode(&bs) != nil {
+		return
+	}
+	if err := bs.Validate(); err != nil {
+		jc.Error(fmt.Errorf("couldn't update bandwidth settings, error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	jc.Check("failed to update bandwidth settings", b.store.UpdateBandwidthSettings(jc.Request.Context(), bs))
+}
+
 func (b *Bus) settingsGougingHandlerGET(jc jape.Context) {
 	gs, err := b.gougingSettings(jc.Request.Context())
 	if err != nil {
@@ -1332,6 +1517,67 @@ func (b *Bus) settingsPinnedHandlerPUT(jc jape.Context) {
 	}
 }
 
+func (b *Bus) settingsPlacementHandlerGET(jc jape.Context) {
+	ps, err := b.placementSettings(jc.Request.Context())
+	if err != nil {
+		jc.Error(err, http.StatusInternalServerError)
+		return
+	}
+	jc.Encode(ps)
+}
+
+func (b *Bus) settingsPlacementHandlerPUT(jc jape.Context) {
+	var ps api.PlacementSettings
+	if jc.Decode(&ps) != nil {
+		return
+	}
+	if err := ps.Validate(); err != nil {
+		jc.Error(fmt.Errorf("couldn't update placement settings, error: %v", err), http.StatusBadRequest)
+		return
+	} else if ps.Diversity.RequiresGeoIP() && !b.locator.GeoIPEnabled() {
+		jc.Error(fmt.Errorf("can't limit shards per country or ASN, %w", api.ErrGeoIPDisabled), http.StatusBadRequest)
+		return
+	}
+
+	// make sure the contract sets buckets are assigned to exist
+	if len(ps.ContractSets) > 0 {
+		ap, err := b.store.AutopilotConfig(jc.Request.Context())
+		if jc.Check("failed to fetch autopilot config", err) != nil {
+			return
+		}
+		for bucket, set := range ps.ContractSets {
+			if _, ok := ap.ContractSet(set); !ok {
+				jc.Error(fmt.Errorf("couldn't update placement settings, bucket %q is assigned to unknown contract set %q: %w", bucket, set, api.ErrContractSetNotFound), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	jc.Check("failed to update placement settings", b.store.UpdatePlacementSettings(jc.Request.Context(), ps))
+}
+
+func (b *Bus) settingsRepairHandlerGET(jc jape.Context) {
+	rs, err := b.repairSettings(jc.Request.Context())
+	if err != nil {
+		jc.Error(err, http.StatusInternalServerError)
+		return
+	}
+	jc.Encode(rs)
+}
+
+func (b *Bus) settingsRepairHandlerPUT(jc jape.Context) {
+	var rs api.RepairSettings
+	if jc.Decode(&rs) != nil {
+		return
+	}
+	if err := rs.Validate(); err != nil {
+		jc.Error(fmt.Errorf("couldn't update repair settings, error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	jc.Check("failed to update repair settings", b.store.UpdateRepairSettings(jc.Request.Context(), rs))
+}
+
 func (b *Bus) settingsUploadHandlerGET(jc jape.Context) {
 	us, err := b.uploadSettings(jc.Request.Context())
 	if err != nil {
@@ -1446,17 +1692,46 @@ func (b *Bus) slabHandlerPUT(jc jape.Context) {
 	}
 }
 
+func (b *Bus) slabsHandlerPOST(jc jape.Context) {
+	var req api.SlabsRequest
+	if jc.Decode(&req) != nil {
+		return
+	}
+	slabs, err := b.store.Slabs(jc.Request.Context(), req.Keys)
+	if jc.Check("couldn't fetch slabs", err) != nil {
+		return
+	}
+	jc.Encode(slabs)
+}
+
 func (b *Bus) slabsRefreshHealthHandlerPOST(jc jape.Context) {
 	jc.Check("failed to recompute health", b.store.RefreshHealth(jc.Request.Context()))
 }
 
+func (b *Bus) slabsStatsHandlerGET(jc jape.Context) {
+	var healthCutoff float64
+	if jc.DecodeForm("healthcutoff", &healthCutoff) != nil {
+		return
+	}
+	stats, err := b.store.SlabHealthStats(jc.Request.Context(), healthCutoff)
+	if jc.Check("couldn't get slab health stats", err) != nil {
+		return
+	}
+	jc.Encode(stats)
+}
+
 func (b *Bus) slabsMigrationHandlerPOST(jc jape.Context) {
 	var msr api.MigrationSlabsRequest
 	if jc.Decode(&msr) != nil {
 		return
 	}
 
-	slabs, err := b.store.SlabsForMigration(jc.Request.Context(), msr.HealthCutoff, msr.Limit)
+	rs, err := b.repairSettings(jc.Request.Context())
+	if jc.Check("couldn't fetch repair settings", err) != nil {
+		return
+	}
+
+	slabs, err := b.store.SlabsForMigration(jc.Request.Context(), msr.HealthCutoff, msr.Offset, msr.Limit, rs)
 	if jc.Check("couldn't fetch slabs for migration", err) != nil {
 		return
 	}
@@ -1574,9 +1849,38 @@ func (b *Bus) autopilotHandlerPUT(jc jape.Context) {
 			jc.Error(fmt.Errorf("failed to update autopilot, hosts config is invalid: %w", err), http.StatusBadRequest)
 			return
 		}
+		if req.Hosts.LabelRules != nil {
+			req.Hosts.LabelRules.NoNewContracts, _ = api.NormalizeHostLabels(req.Hosts.LabelRules.NoNewContracts)
+		}
 		cfg.Hosts = *req.Hosts
 	}
 
+	// update the contract sets
+	if req.ContractSets != nil {
+		sets := *req.ContractSets
+		if err := api.ValidateContractSets(sets); err != nil {
+			jc.Error(fmt.Errorf("failed to update autopilot, contract sets are invalid: %w", err), http.StatusBadRequest)
+			return
+		}
+		for name, set := range sets {
+			set.Hosts.Labels, _ = api.NormalizeHostLabels(set.Hosts.Labels)
+			sets[name] = set
+		}
+
+		// make sure we don't remove sets that buckets are assigned to
+		ps, err := b.placementSettings(jc.Request.Context())
+		if jc.Check("failed to fetch placement settings", err) != nil {
+			return
+		}
+		for bucket, set := range ps.ContractSets {
+			if _, ok := sets[set]; !ok {
+				jc.Error(fmt.Errorf("failed to update autopilot, contract set %q is still assigned to bucket %q", set, bucket), http.StatusBadRequest)
+				return
+			}
+		}
+		cfg.ContractSets = sets
+	}
+
 	// enable/disable the autopilot
 	if req.Enabled != nil {
 		cfg.Enabled = *req.Enabled
@@ -1624,10 +1928,16 @@ func (b *Bus) paramsHandlerUploadGET(jc jape.Context) {
 		return
 	}
 
+	ps, err := b.placementSettings(jc.Request.Context())
+	if jc.Check("could not get placement settings", err) != nil {
+		return
+	}
+
 	api.WriteResponse(jc, api.UploadParams{
-		CurrentHeight: b.cm.TipState().Index.Height,
-		GougingParams: gp,
-		UploadPacking: us.Packing.Enabled,
+		CurrentHeight:     b.cm.TipState().Index.Height,
+		GougingParams:     gp,
+		PlacementSettings: ps,
+		UploadPacking:     us.Packing.Enabled,
 	})
 }
 
@@ -1769,6 +2079,11 @@ func (b *Bus) hostsCheckHandlerPUT(jc jape.Context) {
 	if jc.Check("failed to update host check", err) != nil {
 		return
 	}
+
+	// record host metric
+	if err := b.hostMetricsRecorder.Record(jc.Request.Context(), hk, hc); err != nil {
+		b.logger.Warnw("failed to record host metric", "hostKey", hk, zap.Error(err))
+	}
 }
 
 func (b *Bus) consensusPayoutContractTaxHandlerGET(jc jape.Context) {
@@ -1780,6 +2095,63 @@ func (b *Bus) consensusPayoutContractTaxHandlerGET(jc jape.Context) {
 	jc.Encode(cs.FileContractTax(types.FileContract{Payout: payout}))
 }
 
+func (b *Bus) spendingHandlerGET(jc jape.Context) {
+	// parse mandatory query parameters
+	var start time.Time
+	if jc.DecodeForm("start", (*api.TimeRFC3339)(&start)) != nil {
+		return
+	} else if start.IsZero() {
+		jc.Error(errors.New("parameter 'start' is required"), http.StatusBadRequest)
+		return
+	}
+
+	var n uint64
+	if jc.DecodeForm("n", &n) != nil {
+		return
+	} else if n == 0 {
+		jc.Error(errors.New("'n' has to be greater than zero"), http.StatusBadRequest)
+		return
+	}
+
+	var interval time.Duration
+	if jc.DecodeForm("interval", (*api.DurationMS)(&interval)) != nil {
+		return
+	} else if interval <= 0 {
+		jc.Error(errors.New("parameter 'interval' is required"), http.StatusBadRequest)
+		return
+	}
+
+	// parse optional query parameters
+	format := api.SpendingReportFormatJSON
+	var currency string
+	if jc.DecodeForm("format", &format) != nil {
+		return
+	} else if format != api.SpendingReportFormatJSON && format != api.SpendingReportFormatCSV {
+		jc.Error(api.ErrInvalidSpendingReportFormat, http.StatusBadRequest)
+		return
+	} else if jc.DecodeForm("currency", &currency) != nil {
+		return
+	}
+
+	report, err := b.spendingReport(jc.Request.Context(), start, n, interval, strings.ToLower(currency))
+	if errors.Is(err, api.ErrMaxIntervalsExceeded) || errors.Is(err, api.ErrExplorerDisabled) {
+		jc.Error(err, http.StatusBadRequest)
+		return
+	} else if jc.Check("failed to build spending report", err) != nil {
+		return
+	}
+
+	if format == api.SpendingReportFormatCSV {
+		jc.ResponseWriter.Header().Set("Content-Type", "text/csv")
+		jc.ResponseWriter.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"spending-%s.csv\"", start.UTC().Format("2006-01-02")))
+		if err := writeSpendingReportCSV(jc.ResponseWriter, report); err != nil {
+			b.logger.Errorw("failed to write spending report", zap.Error(err))
+		}
+		return
+	}
+	jc.Encode(report)
+}
+
 func (b *Bus) stateHandlerGET(jc jape.Context) {
 	api.WriteResponse(jc, api.BusStateResponse{
 		StartTime: api.TimeRFC3339(b.startTime),
@@ -1838,20 +2210,26 @@ func (b *Bus) metricsHandlerDELETE(jc jape.Context) {
 func (b *Bus) metricsHandlerPUT(jc jape.Context) {
 	jc.Custom((*interface{})(nil), nil)
 
+	// TODO: jape hack - remove once jape can handle decoding multiple different request types
 	key := jc.PathParam("key")
-	if key != api.MetricContractPrune {
+	switch key {
+	case api.MetricContractPrune:
+		var req api.ContractPruneMetricRequestPUT
+		if err := json.NewDecoder(jc.Request.Body).Decode(&req); err != nil {
+			jc.Error(fmt.Errorf("couldn't decode request type (%T): %w", req, err), http.StatusBadRequest)
+			return
+		}
+		jc.Check("failed to record contract prune metric", b.store.RecordContractPruneMetric(jc.Request.Context(), req.Metrics...))
+	case api.MetricHealth:
+		var req api.HealthMetricRequestPUT
+		if err := json.NewDecoder(jc.Request.Body).Decode(&req); err != nil {
+			jc.Error(fmt.Errorf("couldn't decode request type (%T): %w", req, err), http.StatusBadRequest)
+			return
+		}
+		jc.Check("failed to record health metric", b.store.RecordHealthMetric(jc.Request.Context(), req.Metrics...))
+	default:
 		jc.Error(fmt.Errorf("unknown metric '%s'", key), http.StatusBadRequest)
-		return
-	}
-
-	// TODO: jape hack - remove once jape can handle decoding multiple different request types
-	var req api.ContractPruneMetricRequestPUT
-	if err := json.NewDecoder(jc.Request.Body).Decode(&req); err != nil {
-		jc.Error(fmt.Errorf("couldn't decode request type (%T): %w", req, err), http.StatusBadRequest)
-		return
 	}
-
-	jc.Check("failed to record contract prune metric", b.store.RecordContractPruneMetric(jc.Request.Context(), req.Metrics...))
 }
 
 func (b *Bus) metricsHandlerGET(jc jape.Context) {
@@ -1907,6 +2285,21 @@ func (b *Bus) metricsHandlerGET(jc jape.Context) {
 			return
 		}
 		metrics, err = b.metrics(jc.Request.Context(), key, start, n, interval, opts)
+	case api.MetricHealth:
+		var opts api.HealthMetricsQueryOpts
+		metrics, err = b.metrics(jc.Request.Context(), key, start, n, interval, opts)
+	case api.MetricHost:
+		var opts api.HostMetricsQueryOpts
+		if jc.DecodeForm("hostkey", &opts.HostKey) != nil {
+			return
+		} else if opts.HostKey == (types.PublicKey{}) {
+			jc.Error(errors.New("parameter 'hostkey' is required"), http.StatusBadRequest)
+			return
+		}
+		metrics, err = b.metrics(jc.Request.Context(), key, start, n, interval, opts)
+	case api.MetricHostNetwork:
+		var opts api.HostNetworkMetricsQueryOpts
+		metrics, err = b.metrics(jc.Request.Context(), key, start, n, interval, opts)
 	case api.MetricWallet:
 		var opts api.WalletMetricsQueryOpts
 		metrics, err = b.metrics(jc.Request.Context(), key, start, n, interval, opts)
@@ -1929,6 +2322,12 @@ func (b *Bus) metrics(ctx context.Context, key string, start time.Time, n uint64
 		return b.store.ContractMetrics(ctx, start, n, interval, opts.(api.ContractMetricsQueryOpts))
 	case api.MetricContractPrune:
 		return b.store.ContractPruneMetrics(ctx, start, n, interval, opts.(api.ContractPruneMetricsQueryOpts))
+	case api.MetricHealth:
+		return b.store.HealthMetrics(ctx, start, n, interval, opts.(api.HealthMetricsQueryOpts))
+	case api.MetricHost:
+		return b.store.HostMetrics(ctx, start, n, interval, opts.(api.HostMetricsQueryOpts))
+	case api.MetricHostNetwork:
+		return b.store.HostNetworkMetrics(ctx, start, n, interval, opts.(api.HostNetworkMetricsQueryOpts))
 	case api.MetricWallet:
 		return b.store.WalletMetrics(ctx, start, n, interval, opts.(api.WalletMetricsQueryOpts))
 	}
@@ -2043,6 +2442,69 @@ func (b *Bus) multipartHandlerListPartsPOST(jc jape.Context) {
 	jc.Encode(resp)
 }
 
+func (b *Bus) tusHandlerCreatePOST(jc jape.Context) {
+	var req api.TusCreateRequest
+	if jc.Decode(&req) != nil {
+		return
+	} else if req.Bucket == "" {
+		jc.Error(api.ErrBucketMissing, http.StatusBadRequest)
+		return
+	} else if req.Key == "" {
+		jc.Error(errors.New("key must be non-empty"), http.StatusBadRequest)
+		return
+	}
+
+	if req.MimeType == "" {
+		req.MimeType = mime.TypeByExtension(filepath.Ext(req.Key))
+		if req.MimeType == "" {
+			req.MimeType = "application/octet-stream"
+		}
+	}
+
+	key := object.GenerateEncryptionKey(object.EncryptionKeyTypeSalted)
+	upload, err := b.store.CreateTusUpload(jc.Request.Context(), req.Bucket, req.Key, key, req.MimeType, req.Metadata, req.Length)
+	if errors.Is(err, api.ErrBucketNotFound) {
+		jc.Error(err, http.StatusNotFound)
+		return
+	} else if jc.Check("failed to create tus upload", err) != nil {
+		return
+	}
+	jc.Encode(upload)
+}
+
+func (b *Bus) tusHandlerUploadGET(jc jape.Context) {
+	upload, err := b.store.TusUpload(jc.Request.Context(), jc.PathParam("id"))
+	if errors.Is(err, api.ErrTusUploadNotFound) {
+		jc.Error(err, http.StatusNotFound)
+		return
+	} else if jc.Check("failed to fetch tus upload", err) != nil {
+		return
+	}
+	jc.Encode(upload)
+}
+
+func (b *Bus) tusHandlerOffsetPOST(jc jape.Context) {
+	var req api.TusUpdateOffsetRequest
+	if jc.Decode(&req) != nil {
+		return
+	}
+
+	upload, err := b.store.UpdateTusUploadOffset(jc.Request.Context(), jc.PathParam("id"), req.Offset, req.Length)
+	if errors.Is(err, api.ErrTusUploadNotFound) {
+		jc.Error(err, http.StatusNotFound)
+		return
+	} else if errors.Is(err, api.ErrTusUploadOffsetMismatch) {
+		jc.Error(err, http.StatusConflict)
+		return
+	} else if errors.Is(err, api.ErrTusUploadLengthExceeded) {
+		jc.Error(err, http.StatusBadRequest)
+		return
+	} else if jc.Check("failed to update tus upload offset", err) != nil {
+		return
+	}
+	jc.Encode(upload)
+}
+
 func (b *Bus) contractsFormHandler(jc jape.Context) {
 	// apply pessimistic timeout
 	ctx, cancel := context.WithTimeout(jc.Request.Context(), 15*time.Minute)
@@ -2070,6 +2532,20 @@ func (b *Bus) contractsFormHandler(jc jape.Context) {
 	} else if rfr.RenterAddress == (types.Address{}) {
 		http.Error(jc.ResponseWriter, "RenterAddress must be provided", http.StatusBadRequest)
 		return
+	} else if err := (api.ContractManualRequest{Manual: rfr.Manual, Renewal: rfr.Renewal}).Validate(); err != nil {
+		jc.Error(err, http.StatusBadRequest)
+		return
+	}
+
+	// make sure the contract set exists
+	if rfr.ContractSet != "" {
+		ap, err := b.store.AutopilotConfig(ctx)
+		if jc.Check("failed to fetch autopilot config", err) != nil {
+			return
+		} else if _, ok := ap.ContractSet(rfr.ContractSet); !ok {
+			jc.Error(fmt.Errorf("%w: %q", api.ErrContractSetNotFound, rfr.ContractSet), http.StatusBadRequest)
+			return
+		}
 	}
 
 	// fetch host to form a contract with to get its netaddress
@@ -2111,11 +2587,17 @@ func (b *Bus) contractsFormHandler(jc jape.Context) {
 		rfr.HostCollateral,
 		rfr.EndHeight,
 	)
-	if jc.Check("couldn't form contract", err) != nil {
+	if errors.Is(err, api.ErrSpendingBudgetExceeded) {
+		jc.Error(err, http.StatusBadRequest)
+		return
+	} else if jc.Check("couldn't form contract", err) != nil {
 		return
 	}
 
 	// add the contract
+	contract.ContractSet = rfr.ContractSet
+	contract.Manual = rfr.Manual
+	contract.Renewal = rfr.Renewal
 	metadata, err := b.addContract(ctx, contract)
 	if jc.Check("couldn't add contract", err) != nil {
 		return
@@ -12,7 +12,7 @@ import (
 	"go.uber.org/zap"
 )
 
-func (b *Bus) recordHostScan(ctx context.Context, err error, hostKey types.PublicKey, v2Settings rhp4.HostSettings) {
+func (b *Bus) recordHostScan(ctx context.Context, err error, hostKey types.PublicKey, v2Settings rhp4.HostSettings, latency time.Duration) {
 	// record host scan - make sure this is interrupted by the request ctx and
 	// not the context with the timeout used to time out the scan itself.
 	// Otherwise scans that time out won't be recorded.
@@ -26,6 +26,7 @@ func (b *Bus) recordHostScan(ctx context.Context, err error, hostKey types.Publi
 			// changes, we should adjust this code to account for that.
 			Success:    err == nil,
 			V2Settings: v2Settings,
+			Latency:    latency,
 			Timestamp:  time.Now(),
 		},
 	})
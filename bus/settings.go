@@ -8,6 +8,16 @@ import (
 	"go.sia.tech/renterd/v2/stores/sql"
 )
 
+func (b Bus) bandwidthSettings(ctx context.Context) (api.BandwidthSettings, error) {
+	bs, err := b.store.BandwidthSettings(ctx)
+	if errors.Is(err, sql.ErrSettingNotFound) {
+		bs = api.DefaultBandwidthSettings
+	} else if err != nil {
+		return api.BandwidthSettings{}, err
+	}
+	return bs, nil
+}
+
 func (b Bus) gougingSettings(ctx context.Context) (api.GougingSettings, error) {
 	gs, err := b.store.GougingSettings(ctx)
 	if errors.Is(err, sql.ErrSettingNotFound) {
@@ -28,6 +38,26 @@ func (b Bus) pinnedSettings(ctx context.Context) (api.PinnedSettings, error) {
 	return ps, nil
 }
 
+func (b Bus) placementSettings(ctx context.Context) (api.PlacementSettings, error) {
+	ps, err := b.store.PlacementSettings(ctx)
+	if errors.Is(err, sql.ErrSettingNotFound) {
+		ps = api.DefaultPlacementSettings
+	} else if err != nil {
+		return api.PlacementSettings{}, err
+	}
+	return ps, nil
+}
+
+func (b Bus) repairSettings(ctx context.Context) (api.RepairSettings, error) {
+	rs, err := b.store.RepairSettings(ctx)
+	if errors.Is(err, sql.ErrSettingNotFound) {
+		rs = api.DefaultRepairSettings
+	} else if err != nil {
+		return api.RepairSettings{}, err
+	}
+	return rs, nil
+}
+
 func (b Bus) s3Settings(ctx context.Context) (api.S3Settings, error) {
 	s3s, err := b.store.S3Settings(ctx)
 	if errors.Is(err, sql.ErrSettingNotFound) {
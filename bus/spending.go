@@ -0,0 +1,67 @@
+package bus
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"go.sia.tech/renterd/v2/api"
+)
+
+// spendingReport returns the spending report for n periods of the given
+// interval. If a currency is given, the report contains a fiat valuation
+// using the pin manager's exchange rate.
+func (b *Bus) spendingReport(ctx context.Context, start time.Time, n uint64, interval time.Duration, currency string) (api.SpendingReport, error) {
+	var rate float64
+	if currency != "" {
+		var err error
+		rate, err = b.pinMgr.ExchangeRate(ctx, currency)
+		if err != nil {
+			return api.SpendingReport{}, fmt.Errorf("failed to fetch exchange rate for '%s': %w", currency, err)
+		}
+	}
+
+	items, err := b.store.SpendingReport(ctx, start, n, interval)
+	if err != nil {
+		return api.SpendingReport{}, err
+	}
+	return api.NewSpendingReport(start, n, interval, items, currency, rate), nil
+}
+
+// writeSpendingReportCSV writes the items of the given report as CSV, amounts
+// are in hastings.
+func writeSpendingReportCSV(w io.Writer, report api.SpendingReport) error {
+	header := []string{"period", "hostKey", "contractPrice", "deletions", "fundAccount", "sectorRoots", "uploads", "total"}
+	if report.Currency != "" {
+		header = append(header, "total"+report.Currency)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, item := range report.Items {
+		total := item.Total()
+		record := []string{
+			item.Period.String(),
+			item.HostKey.String(),
+			item.ContractPrice.ExactString(),
+			item.Deletions.ExactString(),
+			item.FundAccount.ExactString(),
+			item.SectorRoots.ExactString(),
+			item.Uploads.ExactString(),
+			total.ExactString(),
+		}
+		if report.Currency != "" {
+			record = append(record, strconv.FormatFloat(api.ConvertCurrencyToFiat(total, report.ExchangeRate), 'f', -1, 64))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -107,6 +107,9 @@ var cfg = config.Config{
 
 		Heartbeat: 30 * time.Minute,
 
+		BenchmarkInterval:   24 * time.Hour,
+		BenchmarkNumThreads: 2, // kept low to avoid hosts competing for bandwidth
+
 		MigratorAccountsRefillInterval:   defaultAccountRefillInterval,
 		MigratorHealthCutoff:             0.75,
 		MigratorNumThreads:               4,
@@ -228,6 +231,7 @@ func parseCLIFlags() {
 	flag.Uint64Var(&cfg.Bus.AnnouncementMaxAgeHours, "bus.announcementMaxAgeHours", cfg.Bus.AnnouncementMaxAgeHours, "Max age for announcements")
 	flag.BoolVar(&cfg.Bus.Bootstrap, "bus.bootstrap", cfg.Bus.Bootstrap, "Bootstraps gateway and consensus modules")
 	flag.StringVar(&cfg.Bus.GatewayAddr, "bus.gatewayAddr", cfg.Bus.GatewayAddr, "Address for Sia peer connections (overrides with RENTERD_BUS_GATEWAY_ADDR)")
+	flag.StringVar(&cfg.Bus.GeoIPDatabase, "bus.geoIPDatabase", cfg.Bus.GeoIPDatabase, "Path to an IP to ASN database in the TSV format of iptoasn.com, used to enforce shard diversity rules and to match ASN blocklist entries")
 	flag.DurationVar(&cfg.Bus.UsedUTXOExpiry, "bus.usedUTXOExpiry", cfg.Bus.UsedUTXOExpiry, "Expiry for used UTXOs in transactions")
 	flag.Int64Var(&cfg.Bus.SlabBufferCompletionThreshold, "bus.slabBufferCompletionThreshold", cfg.Bus.SlabBufferCompletionThreshold, "Threshold for slab buffer upload (overrides with RENTERD_BUS_SLAB_BUFFER_COMPLETION_THRESHOLD)")
 
@@ -235,10 +239,12 @@ func parseCLIFlags() {
 	flag.DurationVar(&cfg.Worker.AccountsRefillInterval, "worker.accountRefillInterval", cfg.Worker.AccountsRefillInterval, "Interval for refilling workers' account balances")
 	flag.DurationVar(&cfg.Worker.BusFlushInterval, "worker.busFlushInterval", cfg.Worker.BusFlushInterval, "Interval for flushing data to bus")
 	flag.Uint64Var(&cfg.Worker.DownloadMaxMemory, "worker.downloadMaxMemory", cfg.Worker.DownloadMaxMemory, "Max amount of RAM the worker allocates for slabs when downloading (overrides with RENTERD_WORKER_DOWNLOAD_MAX_MEMORY)")
+	flag.Uint64Var(&cfg.Worker.DownloadMaxBandwidth, "worker.downloadMaxBandwidth", cfg.Worker.DownloadMaxBandwidth, "Max download bandwidth of the worker in bytes per second, 0 means unlimited (overrides with RENTERD_WORKER_DOWNLOAD_MAX_BANDWIDTH)")
 	flag.Uint64Var(&cfg.Worker.DownloadMaxOverdrive, "worker.downloadMaxOverdrive", cfg.Worker.DownloadMaxOverdrive, "Max overdrive workers for downloads")
 	flag.StringVar(&cfg.Worker.ID, "worker.id", cfg.Worker.ID, "Unique ID for worker (overrides with RENTERD_WORKER_ID)")
 	flag.DurationVar(&cfg.Worker.DownloadOverdriveTimeout, "worker.downloadOverdriveTimeout", cfg.Worker.DownloadOverdriveTimeout, "Timeout for overdriving slab downloads")
 	flag.Uint64Var(&cfg.Worker.UploadMaxMemory, "worker.uploadMaxMemory", cfg.Worker.UploadMaxMemory, "Max amount of RAM the worker allocates for slabs when uploading (overrides with RENTERD_WORKER_UPLOAD_MAX_MEMORY)")
+	flag.Uint64Var(&cfg.Worker.UploadMaxBandwidth, "worker.uploadMaxBandwidth", cfg.Worker.UploadMaxBandwidth, "Max upload bandwidth of the worker in bytes per second, 0 means unlimited (overrides with RENTERD_WORKER_UPLOAD_MAX_BANDWIDTH)")
 	flag.Uint64Var(&cfg.Worker.UploadMaxOverdrive, "worker.uploadMaxOverdrive", cfg.Worker.UploadMaxOverdrive, "Max overdrive workers for uploads")
 	flag.DurationVar(&cfg.Worker.UploadOverdriveTimeout, "worker.uploadOverdriveTimeout", cfg.Worker.UploadOverdriveTimeout, "Timeout for overdriving slab uploads")
 	flag.BoolVar(&cfg.Worker.Enabled, "worker.enabled", cfg.Worker.Enabled, "Enables/disables worker (overrides with RENTERD_WORKER_ENABLED)")
@@ -250,6 +256,8 @@ func parseCLIFlags() {
 	flag.Uint64Var(&cfg.Autopilot.ScannerBatchSize, "autopilot.scannerBatchSize", cfg.Autopilot.ScannerBatchSize, "Batch size for host scanning")
 	flag.DurationVar(&cfg.Autopilot.ScannerInterval, "autopilot.scannerInterval", cfg.Autopilot.ScannerInterval, "Interval for scanning hosts")
 	flag.Uint64Var(&cfg.Autopilot.ScannerNumThreads, "autopilot.scannerNumThreads", cfg.Autopilot.ScannerNumThreads, "Number of threads for scanning hosts")
+	flag.DurationVar(&cfg.Autopilot.BenchmarkInterval, "autopilot.benchmarkInterval", cfg.Autopilot.BenchmarkInterval, "Interval for benchmarking the hosts we have contracts with, 0 disables benchmarks (overrides with RENTERD_AUTOPILOT_BENCHMARK_INTERVAL)")
+	flag.Uint64Var(&cfg.Autopilot.BenchmarkNumThreads, "autopilot.benchmarkNumThreads", cfg.Autopilot.BenchmarkNumThreads, "Number of hosts that are benchmarked in parallel (overrides with RENTERD_AUTOPILOT_BENCHMARK_NUM_THREADS)")
 	flag.BoolVar(&cfg.Autopilot.Enabled, "autopilot.enabled", cfg.Autopilot.Enabled, "Enables/disables autopilot (overrides with RENTERD_AUTOPILOT_ENABLED)")
 	flag.DurationVar(&cfg.ShutdownTimeout, "node.shutdownTimeout", cfg.ShutdownTimeout, "Timeout for node shutdown")
 
@@ -260,6 +268,9 @@ func parseCLIFlags() {
 	flag.DurationVar(&cfg.Autopilot.MigratorDownloadOverdriveTimeout, "autopilot.migratorDownloadOverdriveTimeout", cfg.Autopilot.MigratorDownloadOverdriveTimeout, "Timeout for overdriving migration downloads")
 	flag.Uint64Var(&cfg.Autopilot.MigratorUploadMaxOverdrive, "autopilot.migratorUploadMaxOverdrive", cfg.Autopilot.MigratorUploadMaxOverdrive, "Max overdrive workers for migration uploads")
 	flag.DurationVar(&cfg.Autopilot.MigratorUploadOverdriveTimeout, "autopilot.migratorUploadOverdriveTimeout", cfg.Autopilot.MigratorUploadOverdriveTimeout, "Timeout for overdriving migration uploads")
+	flag.Uint64Var(&cfg.Autopilot.MigratorMaxBandwidth, "autopilot.migratorMaxBandwidth", cfg.Autopilot.MigratorMaxBandwidth, "Max bandwidth in bytes per second used for migrations, 0 means unlimited")
+	flag.StringVar(&cfg.Autopilot.MigratorSchedule, "autopilot.migratorSchedule", cfg.Autopilot.MigratorSchedule, "Maintenance windows in UTC during which slabs are migrated, e.g. 'Mon-Fri 00:00-06:00; Sat,Sun' (overrides with RENTERD_AUTOPILOT_MIGRATOR_SCHEDULE)")
+	flag.StringVar(&cfg.Autopilot.PrunerSchedule, "autopilot.prunerSchedule", cfg.Autopilot.PrunerSchedule, "Maintenance windows in UTC during which contracts are pruned, e.g. 'Sat,Sun' (overrides with RENTERD_AUTOPILOT_PRUNER_SCHEDULE)")
 
 	// s3
 	flag.StringVar(&cfg.S3.Address, "s3.address", cfg.S3.Address, "Address for serving S3 API (overrides with RENTERD_S3_ADDRESS)")
@@ -311,9 +322,15 @@ func parseEnvironmentVariables() {
 	parseEnvVar("RENTERD_WORKER_UNAUTHENTICATED_DOWNLOADS", &cfg.Worker.AllowUnauthenticatedDownloads)
 	parseEnvVar("RENTERD_WORKER_DOWNLOAD_MAX_MEMORY", &cfg.Worker.DownloadMaxMemory)
 	parseEnvVar("RENTERD_WORKER_UPLOAD_MAX_MEMORY", &cfg.Worker.UploadMaxMemory)
+	parseEnvVar("RENTERD_WORKER_DOWNLOAD_MAX_BANDWIDTH", &cfg.Worker.DownloadMaxBandwidth)
+	parseEnvVar("RENTERD_WORKER_UPLOAD_MAX_BANDWIDTH", &cfg.Worker.UploadMaxBandwidth)
 
 	parseEnvVar("RENTERD_AUTOPILOT_ENABLED", &cfg.Autopilot.Enabled)
 	parseEnvVar("RENTERD_AUTOPILOT_REVISION_BROADCAST_INTERVAL", &cfg.Autopilot.RevisionBroadcastInterval)
+	parseEnvVar("RENTERD_AUTOPILOT_MIGRATOR_SCHEDULE", &cfg.Autopilot.MigratorSchedule)
+	parseEnvVar("RENTERD_AUTOPILOT_PRUNER_SCHEDULE", &cfg.Autopilot.PrunerSchedule)
+	parseEnvVar("RENTERD_AUTOPILOT_BENCHMARK_INTERVAL", &cfg.Autopilot.BenchmarkInterval)
+	parseEnvVar("RENTERD_AUTOPILOT_BENCHMARK_NUM_THREADS", &cfg.Autopilot.BenchmarkNumThreads)
 
 	parseEnvVar("RENTERD_S3_ADDRESS", &cfg.S3.Address)
 	parseEnvVar("RENTERD_S3_ENABLED", &cfg.S3.Enabled)
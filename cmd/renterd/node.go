@@ -31,6 +31,8 @@ import (
 	"go.sia.tech/renterd/v2/build"
 	"go.sia.tech/renterd/v2/bus"
 	"go.sia.tech/renterd/v2/config"
+	"go.sia.tech/renterd/v2/internal/geoip"
+	"go.sia.tech/renterd/v2/internal/schedule"
 	"go.sia.tech/renterd/v2/stores"
 	"go.sia.tech/renterd/v2/stores/sql"
 	"go.sia.tech/renterd/v2/stores/sql/mysql"
@@ -256,21 +258,30 @@ func newAutopilot(masterKey [32]byte, cfg config.Autopilot, bus *bus.Client, l *
 	a := alerts.WithOrigin(bus, "autopilot")
 	l = l.Named("autopilot")
 
+	migratorSchedule, err := schedule.Parse(cfg.MigratorSchedule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse migrator schedule: %w", err)
+	}
+	prunerSchedule, err := schedule.Parse(cfg.PrunerSchedule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pruner schedule: %w", err)
+	}
+
 	ctx, cancel := context.WithCancelCause(context.Background())
-	m, err := migrator.New(ctx, masterKey, a, bus, bus, cfg.MigratorHealthCutoff, cfg.MigratorNumThreads, cfg.MigratorDownloadMaxOverdrive, cfg.MigratorUploadMaxOverdrive, cfg.MigratorDownloadOverdriveTimeout, cfg.MigratorUploadOverdriveTimeout, cfg.MigratorAccountsRefillInterval, l)
+	m, err := migrator.New(ctx, masterKey, a, bus, bus, cfg.MigratorHealthCutoff, cfg.MigratorNumThreads, cfg.MigratorDownloadMaxOverdrive, cfg.MigratorUploadMaxOverdrive, cfg.MigratorDownloadOverdriveTimeout, cfg.MigratorUploadOverdriveTimeout, cfg.MigratorAccountsRefillInterval, migratorSchedule, cfg.MigratorMaxBandwidth, l)
 	if err != nil {
 		cancel(nil)
 		return nil, err
 	}
 
-	s, err := scanner.New(bus, cfg.ScannerBatchSize, cfg.ScannerNumThreads, cfg.ScannerInterval, l)
+	s, err := scanner.New(bus, cfg.ScannerBatchSize, cfg.ScannerNumThreads, cfg.ScannerInterval, cfg.BenchmarkInterval, cfg.BenchmarkNumThreads, l)
 	if err != nil {
 		cancel(nil)
 		return nil, err
 	}
 
 	c := contractor.New(bus, bus, bus, bus, bus, cfg.RevisionSubmissionBuffer, cfg.RevisionBroadcastInterval, cfg.AllowRedundantHostIPs, l)
-	p := pruner.New(bus, l)
+	p := pruner.New(bus, prunerSchedule, l)
 	w := walletmaintainer.New(a, bus, l)
 
 	return autopilot.New(ctx, cancel, bus, c, m, p, s, w, cfg.Heartbeat, l), nil
@@ -384,7 +395,7 @@ func newBus(cfg config.Config, pk types.PrivateKey, network *consensus.Network,
 	}
 
 	// create bus
-	b, err := bus.New(cfg.Bus, masterKey, alertsMgr, cm, s, w, sqlStore, explorerURL, logger)
+	b, err := bus.New(cfg.Bus, masterKey, alertsMgr, cm, s, w, sqlStore, storeCfg.GeoIP, explorerURL, logger)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create bus: %w", err)
 	}
@@ -474,6 +485,17 @@ func buildStoreConfig(am alerts.Alerter, cfg config.Config, pk types.PrivateKey,
 		return stores.Config{}, errors.New("Log.Database.SlowThreshold must be greater than 0")
 	}
 
+	// load the GeoIP database, it's shared with the bus which uses it to
+	// locate hosts
+	var geoIPDB *geoip.Database
+	if cfg.Bus.GeoIPDatabase != "" {
+		var err error
+		geoIPDB, err = geoip.Load(cfg.Bus.GeoIPDatabase)
+		if err != nil {
+			return stores.Config{}, err
+		}
+	}
+
 	// create database connections
 	var dbMain sql.Database
 	var dbMetrics sql.MetricsDatabase
@@ -541,6 +563,7 @@ func buildStoreConfig(am alerts.Alerter, cfg config.Config, pk types.PrivateKey,
 		Alerts:                        alerts.WithOrigin(am, "bus"),
 		DB:                            dbMain,
 		DBMetrics:                     dbMetrics,
+		GeoIP:                         geoIPDB,
 		PartialSlabDir:                partialSlabDir,
 		Migrate:                       true,
 		SlabBufferCompletionThreshold: cfg.Bus.SlabBufferCompletionThreshold,
@@ -62,6 +62,7 @@ type (
 		AnnouncementMaxAgeHours       uint64        `yaml:"announcementMaxAgeHours,omitempty"`
 		Bootstrap                     bool          `yaml:"bootstrap,omitempty"`
 		GatewayAddr                   string        `yaml:"gatewayAddr,omitempty"`
+		GeoIPDatabase                 string        `yaml:"geoIPDatabase,omitempty"`
 		RemoteAddr                    string        `yaml:"remoteAddr,omitempty"`
 		RemotePassword                string        `yaml:"remotePassword,omitempty"`
 		UsedUTXOExpiry                time.Duration `yaml:"usedUtxoExpiry,omitempty"`
@@ -127,6 +128,8 @@ type (
 		DownloadMaxMemory             uint64        `yaml:"downloadMaxMemory,omitempty"`
 		UploadMaxMemory               uint64        `yaml:"uploadMaxMemory,omitempty"`
 		UploadMaxOverdrive            uint64        `yaml:"uploadMaxOverdrive,omitempty"`
+		DownloadMaxBandwidth          uint64        `yaml:"downloadMaxBandwidth,omitempty"`
+		UploadMaxBandwidth            uint64        `yaml:"uploadMaxBandwidth,omitempty"`
 		AllowUnauthenticatedDownloads bool          `yaml:"allowUnauthenticatedDownloads,omitempty"`
 		CacheExpiry                   time.Duration `yaml:"cacheExpiry,omitempty"`
 	}
@@ -135,14 +138,19 @@ type (
 	Autopilot struct {
 		Enabled                          bool          `yaml:"enabled,omitempty"`
 		AllowRedundantHostIPs            bool          `yaml:"allowRedundantHostIPs,omitempty"`
+		BenchmarkInterval                time.Duration `yaml:"benchmarkInterval,omitempty"`
+		BenchmarkNumThreads              uint64        `yaml:"benchmarkNumThreads,omitempty"`
 		Heartbeat                        time.Duration `yaml:"heartbeat,omitempty"`
 		MigratorAccountsRefillInterval   time.Duration `yaml:"migratorAccountsRefillInterval,omitempty"`
 		MigratorDownloadMaxOverdrive     uint64        `yaml:"migratorDownloadMaxOverdrive,omitempty"`
 		MigratorDownloadOverdriveTimeout time.Duration `yaml:"migratorDownloadOverdriveTimeout,omitempty"`
 		MigratorHealthCutoff             float64       `yaml:"migratorHealthCutoff,omitempty"`
+		MigratorMaxBandwidth             uint64        `yaml:"migratorMaxBandwidth,omitempty"`
 		MigratorNumThreads               uint64        `yaml:"migratorNumThreads,omitempty"`
+		MigratorSchedule                 string        `yaml:"migratorSchedule,omitempty"`
 		MigratorUploadMaxOverdrive       uint64        `yaml:"migratorUploadMaxOverdrive,omitempty"`
 		MigratorUploadOverdriveTimeout   time.Duration `yaml:"migratorUploadOverdriveTimeout,omitempty"`
+		PrunerSchedule                   string        `yaml:"prunerSchedule,omitempty"`
 		RevisionBroadcastInterval        time.Duration `yaml:"revisionBroadcastInterval,omitempty"`
 		RevisionSubmissionBuffer         uint64        `yaml:"revisionSubmissionBuffer,omitempty"`
 		ScannerInterval                  time.Duration `yaml:"scannerInterval,omitempty"`
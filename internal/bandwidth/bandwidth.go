@@ -0,0 +1,166 @@
+package bandwidth
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// chunkSize is the maximum number of bytes that are read or written before
+// waiting for the limiters. Keeping it small ensures concurrent transfers that
+// share a limiter are interleaved fairly, a single large transfer can't starve
+// the others since every chunk has to reserve its tokens separately.
+const chunkSize = 32 << 10 // 32 KiB
+
+type (
+	// A Limiter limits the throughput of transfers using token buckets. It
+	// has a global limit, which applies to all transfers, and limits per
+	// bucket, which only apply to transfers of objects in that bucket.
+	Limiter struct {
+		global *rate.Limiter
+
+		mu      sync.Mutex
+		buckets map[string]*rate.Limiter
+	}
+
+	limitedReader struct {
+		ctx      context.Context
+		r        io.Reader
+		limiters []*rate.Limiter
+	}
+
+	limitedWriter struct {
+		ctx      context.Context
+		w        io.Writer
+		limiters []*rate.Limiter
+	}
+)
+
+// NewLimiter returns a new limiter with the given global limit in bytes per
+// second, a limit of 0 means the throughput is not limited.
+func NewLimiter(limit uint64) *Limiter {
+	return &Limiter{
+		global:  newRateLimiter(limit),
+		buckets: make(map[string]*rate.Limiter),
+	}
+}
+
+// Reader wraps the given reader, limiting the rate at which it can be read by
+// both the global limit and the given bucket limit.
+func (l *Limiter) Reader(ctx context.Context, r io.Reader, bucket string, bucketLimit uint64) io.Reader {
+	limiters := l.limiters(bucket, bucketLimit)
+	if len(limiters) == 0 {
+		return r
+	}
+	return &limitedReader{ctx: ctx, r: r, limiters: limiters}
+}
+
+// Writer wraps the given writer, limiting the rate at which it can be written
+// to by both the global limit and the given bucket limit.
+func (l *Limiter) Writer(ctx context.Context, w io.Writer, bucket string, bucketLimit uint64) io.Writer {
+	limiters := l.limiters(bucket, bucketLimit)
+	if len(limiters) == 0 {
+		return w
+	}
+	return &limitedWriter{ctx: ctx, w: w, limiters: limiters}
+}
+
+// Wait blocks until the global limit allows for n bytes to be transferred.
+// It's used for transfers that can't be wrapped in a reader or writer, the
+// bytes are reserved in chunks to avoid starving concurrent transfers.
+func (l *Limiter) Wait(ctx context.Context, n int) error {
+	if l.global == nil {
+		return nil
+	}
+	limiters := []*rate.Limiter{l.global}
+	for n > 0 {
+		chunk := min(n, chunkSize)
+		if err := wait(ctx, limiters, chunk); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+// limiters returns the limiters that apply to a transfer for the given bucket,
+// the bucket limiter comes first to avoid a throttled bucket holding on to the
+// global limiter's tokens.
+func (l *Limiter) limiters(bucket string, bucketLimit uint64) (limiters []*rate.Limiter) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if bucketLimit == 0 {
+		delete(l.buckets, bucket)
+	} else if bl, exists := l.buckets[bucket]; !exists {
+		l.buckets[bucket] = newRateLimiter(bucketLimit)
+	} else if bl.Limit() != rate.Limit(bucketLimit) {
+		bl.SetLimit(rate.Limit(bucketLimit))
+		bl.SetBurst(burst(bucketLimit))
+	}
+
+	if bl, exists := l.buckets[bucket]; exists {
+		limiters = append(limiters, bl)
+	}
+	if l.global != nil {
+		limiters = append(limiters, l.global)
+	}
+	return
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if len(p) > chunkSize {
+		p = p[:chunkSize]
+	}
+	n, err := lr.r.Read(p)
+	if n > 0 {
+		if wErr := wait(lr.ctx, lr.limiters, n); wErr != nil {
+			return n, wErr
+		}
+	}
+	return n, err
+}
+
+func (lw *limitedWriter) Write(p []byte) (written int, _ error) {
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > chunkSize {
+			chunk = chunk[:chunkSize]
+		}
+		if err := wait(lw.ctx, lw.limiters, len(chunk)); err != nil {
+			return written, err
+		}
+		n, err := lw.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+func burst(limit uint64) int {
+	if limit < chunkSize {
+		return chunkSize
+	}
+	return int(limit)
+}
+
+func newRateLimiter(limit uint64) *rate.Limiter {
+	if limit == 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(limit), burst(limit))
+}
+
+func wait(ctx context.Context, limiters []*rate.Limiter, n int) error {
+	for _, l := range limiters {
+		if err := l.WaitN(ctx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package bandwidth
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"lukechampine.com/frand"
+)
+
+func TestLimiter(t *testing.T) {
+	const limit = 256 << 10 // 256 KiB/s
+
+	// assert an unlimited limiter doesn't wrap the reader or writer
+	l := NewLimiter(0)
+	r := bytes.NewReader(nil)
+	if l.Reader(context.Background(), r, "bucket", 0) != io.Reader(r) {
+		t.Fatal("expected reader to be returned as is")
+	} else if l.Writer(context.Background(), io.Discard, "bucket", 0) != io.Discard {
+		t.Fatal("expected writer to be returned as is")
+	}
+
+	// assert the bucket limiter is shared and updated
+	limiters := l.limiters("bucket", limit)
+	if len(limiters) != 1 {
+		t.Fatalf("expected 1 limiter, got %d", len(limiters))
+	} else if other := l.limiters("bucket", 2*limit); other[0] != limiters[0] {
+		t.Fatal("expected limiter to be shared")
+	} else if limiters[0].Limit() != 2*limit {
+		t.Fatal("expected limit to be updated", limiters[0].Limit())
+	} else if l.limiters("bucket", 0); len(l.buckets) != 0 {
+		t.Fatal("expected bucket limiter to be removed")
+	}
+
+	// assert the global limiter applies to all buckets
+	l = NewLimiter(limit)
+	if limiters := l.limiters("bucket", limit); len(limiters) != 2 {
+		t.Fatalf("expected 2 limiters, got %d", len(limiters))
+	} else if limiters[1] != l.global {
+		t.Fatal("expected global limiter to come last")
+	}
+
+	// read twice the limit, the first half is covered by the burst so it
+	// should take about a second
+	data := frand.Bytes(2 * limit)
+	start := time.Now()
+	buf, err := io.ReadAll(NewLimiter(0).Reader(context.Background(), bytes.NewReader(data), "bucket", limit))
+	if err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(buf, data) {
+		t.Fatal("data mismatch")
+	} else if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Fatalf("read was not limited, took %v", elapsed)
+	}
+
+	// same for writes
+	var out bytes.Buffer
+	start = time.Now()
+	if n, err := NewLimiter(limit).Writer(context.Background(), &out, "bucket", 0).Write(data); err != nil {
+		t.Fatal(err)
+	} else if n != len(data) || !bytes.Equal(out.Bytes(), data) {
+		t.Fatal("data mismatch")
+	} else if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Fatalf("write was not limited, took %v", elapsed)
+	}
+
+	// same for transfers that only wait for the limiter
+	start = time.Now()
+	if err := NewLimiter(limit).Wait(context.Background(), len(data)); err != nil {
+		t.Fatal(err)
+	} else if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Fatalf("wait was not limited, took %v", elapsed)
+	} else if err := NewLimiter(0).Wait(context.Background(), len(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	// assert a cancelled context interrupts the transfer
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewLimiter(limit).Writer(ctx, io.Discard, "bucket", 0).Write(data); err == nil {
+		t.Fatal("expected error")
+	}
+}
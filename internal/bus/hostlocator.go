@@ -0,0 +1,152 @@
+package bus
+
+import (
+	"context"
+	"net/netip"
+	"slices"
+	"sync"
+	"time"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/geoip"
+	"go.sia.tech/renterd/v2/internal/utils"
+)
+
+const (
+	// locationSubnetIPv4 and locationSubnetIPv6 are the prefix lengths of the
+	// subnets that are considered to be the same network when enforcing
+	// diversity rules.
+	locationSubnetIPv4 = 16
+	locationSubnetIPv6 = 32
+
+	// failedLocationExpiry is the maximum amount of time a failure to resolve
+	// the addresses of a host is cached, failures are retried sooner than
+	// successful lookups are refreshed.
+	failedLocationExpiry = 5 * time.Minute
+
+	// maxConcurrentLocates is the maximum number of hosts that are located in
+	// parallel by LocateAll.
+	maxConcurrentLocates = 10
+)
+
+type (
+	// A HostLocator resolves the network location of hosts, locations are
+	// cached to avoid resolving the addresses of a host on every request.
+	// Failed lookups are cached as well, so unreachable DNS servers don't slow
+	// down every request.
+	HostLocator struct {
+		db     *geoip.Database
+		expiry time.Duration
+
+		mu        sync.Mutex
+		locations map[types.PublicKey]cachedLocation
+	}
+
+	cachedLocation struct {
+		addresses []string
+		expiry    time.Time
+		location  api.HostLocation
+	}
+)
+
+// NewHostLocator returns a new HostLocator, the database is optional and if
+// it's not set, only the subnets of a host are resolved.
+func NewHostLocator(db *geoip.Database, expiry time.Duration) *HostLocator {
+	return &HostLocator{
+		db:        db,
+		expiry:    expiry,
+		locations: make(map[types.PublicKey]cachedLocation),
+	}
+}
+
+// GeoIPEnabled returns true if the locator resolves the country and ASN of
+// hosts.
+func (l *HostLocator) GeoIPEnabled() bool {
+	return l.db != nil
+}
+
+// Locate returns the location of the given host. If the host's addresses
+// can't be resolved an empty location is returned. Locations are only resolved
+// if they aren't cached or the cached location expired.
+func (l *HostLocator) Locate(ctx context.Context, hi api.HostInfo) api.HostLocation {
+	l.mu.Lock()
+	cached, ok := l.locations[hi.PublicKey]
+	l.mu.Unlock()
+	if ok && time.Now().Before(cached.expiry) && slices.Equal(cached.addresses, hi.V2SiamuxAddresses) {
+		return cached.location
+	}
+	return l.Refresh(ctx, hi)
+}
+
+// LocateAll returns the locations of the given hosts, hosts that aren't cached
+// are located in parallel.
+func (l *HostLocator) LocateAll(ctx context.Context, his []api.HostInfo) []api.HostLocation {
+	locs := make([]api.HostLocation, len(his))
+	sem := make(chan struct{}, maxConcurrentLocates)
+	var wg sync.WaitGroup
+	for i, hi := range his {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, hi api.HostInfo) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			locs[i] = l.Locate(ctx, hi)
+		}(i, hi)
+	}
+	wg.Wait()
+	return locs
+}
+
+// Refresh resolves the location of the given host and caches it, regardless
+// of whether a cached location exists. It's called whenever a host is scanned
+// so that requests rarely have to resolve locations themselves.
+func (l *HostLocator) Refresh(ctx context.Context, hi api.HostInfo) api.HostLocation {
+	loc, err := l.resolve(ctx, hi)
+
+	expiry := l.expiry
+	if err != nil && expiry > failedLocationExpiry {
+		expiry = failedLocationExpiry
+	}
+
+	l.mu.Lock()
+	l.locations[hi.PublicKey] = cachedLocation{
+		addresses: slices.Clone(hi.V2SiamuxAddresses),
+		expiry:    time.Now().Add(expiry),
+		location:  loc,
+	}
+	l.mu.Unlock()
+	return loc
+}
+
+func (l *HostLocator) resolve(ctx context.Context, hi api.HostInfo) (api.HostLocation, error) {
+	resolved, err := utils.ResolveHostIPs(ctx, hi.V2SiamuxAddresses)
+	if err != nil {
+		return api.HostLocation{}, err
+	}
+
+	var loc api.HostLocation
+	for _, ip := range resolved {
+		addr, ok := netip.AddrFromSlice(ip.IP)
+		if !ok {
+			continue
+		}
+		addr = addr.Unmap()
+
+		bits := locationSubnetIPv6
+		if addr.Is4() {
+			bits = locationSubnetIPv4
+		}
+		loc.Subnets = append(loc.Subnets, netip.PrefixFrom(addr, bits).Masked().String())
+
+		if l.db != nil && loc.ASN == 0 {
+			if gl, found := l.db.Lookup(addr); found {
+				loc.Country = gl.Country
+				loc.ASN = gl.ASN
+			}
+		}
+	}
+	return loc, nil
+}
@@ -0,0 +1,57 @@
+package bus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+)
+
+func TestHostLocatorCache(t *testing.T) {
+	l := NewHostLocator(nil, time.Hour)
+
+	// locate a host
+	hi := api.HostInfo{PublicKey: types.PublicKey{1}, V2SiamuxAddresses: []string{"1.2.3.4:9984"}}
+	loc := l.Locate(context.Background(), hi)
+	if len(loc.Subnets) != 1 || loc.Subnets[0] != "1.2.0.0/16" {
+		t.Fatal("unexpected location", loc)
+	}
+
+	// the location should be cached
+	cached := l.locations[hi.PublicKey]
+	if until := time.Until(cached.expiry); until < 59*time.Minute {
+		t.Fatal("unexpected expiry", until)
+	} else if loc := l.Locate(context.Background(), hi); len(loc.Subnets) != 1 {
+		t.Fatal("unexpected location", loc)
+	} else if l.locations[hi.PublicKey].expiry != cached.expiry {
+		t.Fatal("expected cache hit")
+	}
+
+	// changing the addresses invalidates the cache
+	hi.V2SiamuxAddresses = []string{"[2001:db8::1]:9984"}
+	if loc := l.Locate(context.Background(), hi); len(loc.Subnets) != 1 || loc.Subnets[0] != "2001:db8::/32" {
+		t.Fatal("unexpected location", loc)
+	}
+
+	// failures are cached with a shorter expiry
+	hi = api.HostInfo{PublicKey: types.PublicKey{2}, V2SiamuxAddresses: []string{"invalid"}}
+	if loc := l.Locate(context.Background(), hi); len(loc.Subnets) != 0 {
+		t.Fatal("unexpected location", loc)
+	} else if cached, ok := l.locations[hi.PublicKey]; !ok {
+		t.Fatal("expected failure to be cached")
+	} else if until := time.Until(cached.expiry); until > failedLocationExpiry {
+		t.Fatal("unexpected expiry", until)
+	}
+
+	// locate multiple hosts at once
+	his := []api.HostInfo{
+		{PublicKey: types.PublicKey{3}, V2SiamuxAddresses: []string{"5.6.7.8:9984"}},
+		hi,
+	}
+	locs := l.LocateAll(context.Background(), his)
+	if len(locs) != 2 || len(locs[0].Subnets) != 1 || locs[0].Subnets[0] != "5.6.0.0/16" || len(locs[1].Subnets) != 0 {
+		t.Fatal("unexpected locations", locs)
+	}
+}
@@ -0,0 +1,94 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+)
+
+type (
+	// A HostMetricsRecorder records a host's prices, remaining storage, uptime
+	// and score whenever its checks are updated. To avoid recording a metric
+	// every time the autopilot checks the hosts, a host's metric is only
+	// recorded once per interval unless its usability changed. Hosts that were
+	// never scanned are not recorded since we don't know their prices. Entries
+	// older than the interval are pruned once per interval, so only the hosts
+	// recorded within the last two intervals are tracked.
+	HostMetricsRecorder struct {
+		store    HostMetricsStore
+		interval time.Duration
+
+		mu        sync.Mutex
+		lastPrune time.Time
+		recorded  map[types.PublicKey]recordedHostMetric
+	}
+
+	HostMetricsStore interface {
+		Host(ctx context.Context, hostKey types.PublicKey) (api.Host, error)
+		RecordHostMetric(ctx context.Context, metrics ...api.HostMetric) error
+	}
+
+	recordedHostMetric struct {
+		timestamp time.Time
+		usable    bool
+	}
+)
+
+// NewHostMetricsRecorder returns a recorder that records a host's metric at
+// most once per interval, unless its usability changes.
+func NewHostMetricsRecorder(store HostMetricsStore, interval time.Duration) *HostMetricsRecorder {
+	return &HostMetricsRecorder{
+		store:    store,
+		interval: interval,
+		recorded: make(map[types.PublicKey]recordedHostMetric),
+	}
+}
+
+// Record records a metric for the host with the given key after its checks
+// were updated to the given checks.
+func (r *HostMetricsRecorder) Record(ctx context.Context, hk types.PublicKey, hc api.HostChecks) error {
+	usable := hc.UsabilityBreakdown.IsUsable()
+
+	r.mu.Lock()
+	last, ok := r.recorded[hk]
+	r.mu.Unlock()
+	if ok && last.usable == usable && time.Since(last.timestamp) < r.interval {
+		return nil
+	}
+
+	h, err := r.store.Host(ctx, hk)
+	if err != nil {
+		return fmt.Errorf("failed to fetch host: %w", err)
+	} else if !h.Scanned {
+		return nil
+	}
+	h.Checks = hc
+
+	now := time.Now()
+	if err := r.store.RecordHostMetric(ctx, api.NewHostMetric(h, now)); err != nil {
+		return fmt.Errorf("failed to record host metric: %w", err)
+	}
+
+	r.mu.Lock()
+	r.recorded[hk] = recordedHostMetric{timestamp: now, usable: usable}
+	if now.Sub(r.lastPrune) >= r.interval {
+		r.pruneExpired(now)
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+// pruneExpired removes the hosts that were last recorded more than an interval
+// ago, they are recorded on their next update anyway.
+func (r *HostMetricsRecorder) pruneExpired(now time.Time) {
+	for hk, recorded := range r.recorded {
+		if now.Sub(recorded.timestamp) >= r.interval {
+			delete(r.recorded, hk)
+		}
+	}
+	r.lastPrune = now
+}
@@ -0,0 +1,108 @@
+package bus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+)
+
+type hostMetricsStoreMock struct {
+	metrics   []api.HostMetric
+	unscanned map[types.PublicKey]struct{}
+}
+
+func (s *hostMetricsStoreMock) Host(_ context.Context, hk types.PublicKey) (api.Host, error) {
+	_, unscanned := s.unscanned[hk]
+	return api.Host{
+		PublicKey: hk,
+		Scanned:   !unscanned,
+		Interactions: api.HostInteractions{
+			Uptime:   3 * time.Hour,
+			Downtime: time.Hour,
+		},
+	}, nil
+}
+
+func (s *hostMetricsStoreMock) RecordHostMetric(_ context.Context, metrics ...api.HostMetric) error {
+	s.metrics = append(s.metrics, metrics...)
+	return nil
+}
+
+func TestHostMetricsRecorder(t *testing.T) {
+	hk1, hk2, hk3 := types.PublicKey{1}, types.PublicKey{2}, types.PublicKey{3}
+	store := &hostMetricsStoreMock{unscanned: map[types.PublicKey]struct{}{hk3: {}}}
+	r := NewHostMetricsRecorder(store, time.Hour)
+
+	usable := api.HostChecks{ScoreBreakdown: api.HostScoreBreakdown{Age: 1, Collateral: 1, Interactions: 1, Latency: 1, StorageRemaining: 1, Throughput: 1, Uptime: 1, Version: 1, Prices: .5}}
+	unusable := usable
+	unusable.UsabilityBreakdown.Gouging = true
+
+	record := func(hk types.PublicKey, hc api.HostChecks) {
+		t.Helper()
+		if err := r.Record(context.Background(), hk, hc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertMetrics := func(n int) {
+		t.Helper()
+		if len(store.metrics) != n {
+			t.Fatalf("expected %d metrics, got %d", n, len(store.metrics))
+		}
+	}
+
+	// assert the first check of every host is recorded
+	record(hk1, usable)
+	record(hk2, usable)
+	assertMetrics(2)
+	if m := store.metrics[0]; m.HostKey != hk1 || m.Score != .5 || !m.Usable || m.Uptime != .75 {
+		t.Fatalf("unexpected metric %+v", m)
+	}
+
+	// assert hosts that were never scanned are not recorded
+	record(hk3, unusable)
+	assertMetrics(2)
+
+	// assert the host is recorded once it was scanned
+	delete(store.unscanned, hk3)
+	record(hk3, unusable)
+	assertMetrics(3)
+	store.metrics = store.metrics[:2]
+
+	// assert subsequent checks are not recorded within the interval
+	record(hk1, usable)
+	assertMetrics(2)
+
+	// assert a change in usability is recorded
+	record(hk1, unusable)
+	assertMetrics(3)
+	if store.metrics[2].Usable {
+		t.Fatal("expected host to be unusable")
+	}
+	record(hk1, unusable)
+	assertMetrics(3)
+
+	// assert checks are recorded again after the interval
+	r.interval = 0
+	record(hk2, usable)
+	assertMetrics(4)
+
+	// assert expired hosts are pruned
+	r.interval = time.Hour
+	r.mu.Lock()
+	for hk, recorded := range r.recorded {
+		recorded.timestamp = recorded.timestamp.Add(-2 * time.Hour)
+		r.recorded[hk] = recorded
+	}
+	r.lastPrune = time.Time{}
+	r.mu.Unlock()
+	record(hk2, unusable)
+	assertMetrics(5)
+	if len(r.recorded) != 1 {
+		t.Fatalf("expected 1 tracked host, got %d", len(r.recorded))
+	} else if _, ok := r.recorded[hk2]; !ok {
+		t.Fatal("expected hk2 to be tracked")
+	}
+}
@@ -31,6 +31,9 @@ type (
 
 		PinnedSettings(ctx context.Context) (api.PinnedSettings, error)
 		UpdatePinnedSettings(ctx context.Context, ps api.PinnedSettings) error
+
+		AutopilotConfig(ctx context.Context) (api.AutopilotConfig, error)
+		UpdateAutopilotConfig(ctx context.Context, cfg api.AutopilotConfig) error
 	}
 )
 
@@ -102,6 +105,21 @@ func (pm *pinManager) Shutdown(ctx context.Context) error {
 	}
 }
 
+// ExchangeRate returns the exchange rate of SC in the given currency. If it's
+// the currency prices are pinned to, the average of the tracked rates is
+// returned, otherwise the current rate is fetched from the explorer.
+func (pm *pinManager) ExchangeRate(ctx context.Context, currency string) (float64, error) {
+	pm.mu.Lock()
+	tracked := pm.ratesCurrency == currency && len(pm.rates) > 0
+	pm.mu.Unlock()
+
+	if tracked {
+		rate, _ := pm.averageRate().Float64()
+		return rate, nil
+	}
+	return pm.e.SiacoinExchangeRate(ctx, currency)
+}
+
 func (pm *pinManager) TriggerUpdate() {
 	select {
 	case pm.triggerChan <- true:
@@ -258,6 +276,33 @@ func (pm *pinManager) updateGougingSettings(ctx context.Context, pins api.Gougin
 	return pm.s.UpdateGougingSettings(ctx, gs)
 }
 
+func (pm *pinManager) updateBudget(ctx context.Context, pin api.Pin, rate decimal.Decimal) error {
+	if !pin.IsPinned() {
+		return nil
+	}
+
+	// convert the budget
+	update, err := convertCurrencyToSC(decimal.NewFromFloat(pin.Value), rate)
+	if err != nil {
+		pm.logger.Warnw("failed to convert spending budget to currency", zap.Error(err))
+		return err
+	}
+
+	// fetch autopilot config
+	cfg, err := pm.s.AutopilotConfig(ctx)
+	if err != nil {
+		return err
+	} else if cfg.Contracts.Budget.Equals(update) {
+		pm.logger.Infow("spending budget did not require update", "rate", rate)
+		return nil
+	}
+
+	// update config
+	pm.logger.Infow("updating spending budget", "old", cfg.Contracts.Budget, "new", update, "rate", rate)
+	cfg.Contracts.Budget = update
+	return pm.s.UpdateAutopilotConfig(ctx, cfg)
+}
+
 func (pm *pinManager) updatePrices(ctx context.Context, forced bool) error {
 	pm.logger.Debugw("updating prices", zap.Bool("forced", forced))
 
@@ -305,6 +350,12 @@ func (pm *pinManager) updatePrices(ctx context.Context, forced bool) error {
 	if err != nil {
 		pm.logger.Warnw("failed to update gouging settings", zap.Error(err))
 	}
+
+	// update spending budget
+	err = pm.updateBudget(ctx, settings.Budget, update)
+	if err != nil {
+		pm.logger.Warnw("failed to update spending budget", zap.Error(err))
+	}
 	return nil
 }
 
@@ -95,6 +95,7 @@ func (e *mockExplorer) setUnreachable(unreachable bool) {
 
 type mockPinStore struct {
 	mu sync.Mutex
+	ap api.AutopilotConfig
 	gs api.GougingSettings
 	ps api.PinnedSettings
 }
@@ -141,6 +142,19 @@ func (ms *mockPinStore) UpdatePinnedSettings(ctx context.Context, ps api.PinnedS
 	return nil
 }
 
+func (ms *mockPinStore) AutopilotConfig(ctx context.Context) (api.AutopilotConfig, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.ap, nil
+}
+
+func (ms *mockPinStore) UpdateAutopilotConfig(ctx context.Context, cfg api.AutopilotConfig) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.ap = cfg
+	return nil
+}
+
 func TestPinManager(t *testing.T) {
 	// mock dependencies
 	a := &mockAlerter{}
@@ -217,6 +231,17 @@ func TestPinManager(t *testing.T) {
 		t.Fatalf("expected gouging settings to be updated, got %v = %v", gss, gs)
 	}
 
+	// pin the spending budget
+	ps.Budget = api.Pin{Value: 3, Pinned: true}
+	s.UpdatePinnedSettings(context.Background(), ps)
+	pm.triggerChan <- true
+	time.Sleep(testUpdateInterval)
+
+	// assert the budget is updated
+	if ap, _ := s.AutopilotConfig(context.Background()); ap.Contracts.Budget.IsZero() {
+		t.Fatal("expected spending budget to be updated")
+	}
+
 	// increase rate so average isn't catching up to us
 	e.setRate(3)
 
@@ -34,6 +34,7 @@ type (
 		Host   *Downloader
 
 		Overdrive   bool
+		Priority    host.Priority
 		SectorIndex int
 		Resps       *SectorResponses
 	}
@@ -67,7 +68,7 @@ type (
 		lastRecompute       time.Time
 
 		numDownloads uint64
-		queue        []*SectorDownloadReq
+		queue        host.Queue[*SectorDownloadReq]
 		stopped      bool
 	}
 )
@@ -81,8 +82,6 @@ func New(ctx context.Context, h host.Downloader) *Downloader {
 
 		signalWorkChan: make(chan struct{}, 1),
 		shutdownCtx:    ctx,
-
-		queue: make([]*SectorDownloadReq, 0),
 	}
 }
 
@@ -106,7 +105,7 @@ func (d *Downloader) Enqueue(download *SectorDownloadReq) {
 	}
 
 	// enqueue the job
-	d.queue = append(d.queue, download)
+	d.queue.Push(download.Priority, download)
 	d.mu.Unlock()
 
 	// signal there's work
@@ -130,7 +129,7 @@ func (d *Downloader) Estimate() float64 {
 		}
 	}
 
-	numSectors := float64(len(d.queue) + 1)
+	numSectors := float64(d.queue.Len() + 1)
 	return numSectors * estimateP90
 }
 
@@ -206,11 +205,8 @@ func (d *Downloader) pop() *SectorDownloadReq {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	if len(d.queue) > 0 {
-		j := d.queue[0]
-		d.queue[0] = nil
-		d.queue = d.queue[1:]
-		return j
+	if req, ok := d.queue.Pop(); ok {
+		return req
 	}
 	return nil
 }
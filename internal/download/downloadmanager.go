@@ -15,6 +15,7 @@ import (
 	"go.sia.tech/core/types"
 	"go.sia.tech/renterd/v2/api"
 	"go.sia.tech/renterd/v2/internal/download/downloader"
+	"go.sia.tech/renterd/v2/internal/host"
 	"go.sia.tech/renterd/v2/internal/hosts"
 	"go.sia.tech/renterd/v2/internal/memory"
 	rhp4 "go.sia.tech/renterd/v2/internal/rhp/v4"
@@ -57,12 +58,15 @@ type (
 
 		shutdownCtx context.Context
 
+		prefetcher *prefetcher
+
 		mu          sync.Mutex
 		downloaders map[types.PublicKey]*downloader.Downloader
 	}
 
 	slabDownload struct {
-		mgr *Manager
+		mgr      *Manager
+		priority host.Priority
 
 		minShards int
 		offset    uint64
@@ -101,6 +105,7 @@ type (
 		AvgOverdrivePct      float64
 		HealthyDownloaders   uint64
 		NumDownloaders       uint64
+		NumPrefetchedSlabs   uint64
 		DownloadSpeedsMBPS   map[types.PublicKey]float64
 	}
 )
@@ -132,6 +137,8 @@ func NewManager(ctx context.Context, uploadKey *utils.UploadKey, hm hosts.Manage
 
 		shutdownCtx: ctx,
 
+		prefetcher: newPrefetcher(mm, logger.Sugar()),
+
 		downloaders: make(map[types.PublicKey]*downloader.Downloader),
 	}
 }
@@ -176,6 +183,23 @@ func (mgr *Manager) DownloadObject(ctx context.Context, w io.Writer, o object.Ob
 		available[h.PublicKey] = struct{}{}
 	}
 
+	// track the read and use prefetched data where possible, if the object is
+	// read sequentially we prefetch the data following the requested range,
+	// the read position only advances if the download succeeds
+	id := streamID(o)
+	sequential, prefetched := mgr.prefetcher.Track(id, offset, length)
+	defer func() {
+		if err == nil {
+			mgr.prefetcher.Advance(id, offset+length)
+		}
+	}()
+	if len(prefetched) > 0 {
+		mgr.usePrefetched(ctx, slabs, offset, prefetched)
+	}
+	if sequential {
+		mgr.prefetchAhead(ctx, id, o, offset+length, available)
+	}
+
 	// create the cipher writer
 	cw, err := o.Key.Decrypt(w, object.EncryptionOptions{
 		Offset: offset,
@@ -228,10 +252,10 @@ func (mgr *Manager) DownloadObject(ctx context.Context, w io.Writer, o object.Ob
 			default:
 			}
 
-			// check if the next slab is a partial slab.
-			if next.PartialSlab {
+			// check if the next slab is a partial slab or was prefetched
+			if next.PartialSlab || next.Prefetched {
 				responseChan <- &slabDownloadResponse{index: slabIndex}
-				continue // handle partial slab separately
+				continue // handle partial and prefetched slabs separately
 			}
 
 			// check if we have enough downloaders
@@ -256,7 +280,7 @@ func (mgr *Manager) DownloadObject(ctx context.Context, w io.Writer, o object.Ob
 			wg.Add(1)
 			go func(index int) {
 				defer wg.Done()
-				shards, err := mgr.downloadSlab(ctx, next.SlabSlice)
+				shards, err := mgr.downloadSlab(ctx, next.SlabSlice, host.PriorityDownload)
 				select {
 				case responseChan <- &slabDownloadResponse{
 					mem:    mem,
@@ -304,8 +328,8 @@ outer:
 			for {
 				if next, exists := responses[respIndex]; exists {
 					s := slabs[respIndex]
-					if s.PartialSlab {
-						// Partial slab.
+					if s.PartialSlab || s.Prefetched {
+						// Partial or prefetched slab.
 						n, err := bw.Write(s.Data)
 						if err != nil {
 							mgr.logger.Errorf("failed to send partial slab", respIndex, err)
@@ -385,7 +409,7 @@ func (mgr *Manager) DownloadSlab(ctx context.Context, slab object.Slab, hosts []
 		Offset: 0,
 		Length: uint32(slab.MinShards) * rhpv4.SectorSize,
 	}
-	shards, err := mgr.downloadSlab(ctx, slice)
+	shards, err := mgr.downloadSlab(ctx, slice, host.PriorityMigration)
 	if err != nil {
 		return nil, err
 	}
@@ -423,11 +447,14 @@ func (mgr *Manager) Stats() Stats {
 		AvgOverdrivePct:      mgr.statsOverdrivePct.Average(),
 		HealthyDownloaders:   numHealthy,
 		NumDownloaders:       uint64(len(mgr.downloaders)),
+		NumPrefetchedSlabs:   mgr.prefetcher.NumPrefetched(),
 		DownloadSpeedsMBPS:   speeds,
 	}
 }
 
 func (mgr *Manager) Stop() {
+	mgr.prefetcher.Close()
+
 	mgr.mu.Lock()
 	defer mgr.mu.Unlock()
 	for _, d := range mgr.downloaders {
@@ -492,7 +519,7 @@ func (mgr *Manager) refreshDownloaders(hosts []api.HostInfo) {
 	}
 }
 
-func (mgr *Manager) newSlabDownload(slice object.SlabSlice) *slabDownload {
+func (mgr *Manager) newSlabDownload(slice object.SlabSlice, priority host.Priority) *slabDownload {
 	// calculate the offset and length
 	offset, length := slice.SectorRegion()
 
@@ -512,7 +539,8 @@ func (mgr *Manager) newSlabDownload(slice object.SlabSlice) *slabDownload {
 
 	// create slab download
 	return &slabDownload{
-		mgr: mgr,
+		mgr:      mgr,
+		priority: priority,
 
 		minShards: int(slice.MinShards),
 		offset:    offset,
@@ -525,9 +553,9 @@ func (mgr *Manager) newSlabDownload(slice object.SlabSlice) *slabDownload {
 	}
 }
 
-func (mgr *Manager) downloadSlab(ctx context.Context, slice object.SlabSlice) ([][]byte, error) {
+func (mgr *Manager) downloadSlab(ctx context.Context, slice object.SlabSlice, priority host.Priority) ([][]byte, error) {
 	// prepare new download
-	slab := mgr.newSlabDownload(slice)
+	slab := mgr.newSlabDownload(slice, priority)
 
 	// execute download
 	return slab.download(ctx)
@@ -649,6 +677,7 @@ func (s *slabDownload) nextRequest(ctx context.Context, resps *downloader.Sector
 			Host:   fastest,
 
 			Overdrive:   overdrive,
+			Priority:    s.priority,
 			SectorIndex: next.index,
 			Resps:       resps,
 		}
@@ -827,9 +856,33 @@ func (mgr *Manager) fastest(hosts []types.PublicKey) (fastest *downloader.Downlo
 	return
 }
 
+// usePrefetched updates the slabs of a download starting at the given offset
+// to use the data of the prefetched slabs that cover them. Slabs for which the
+// prefetch failed are downloaded as usual.
+func (mgr *Manager) usePrefetched(ctx context.Context, slabs []slabSlice, offset uint64, prefetched []*prefetchedSlab) {
+	start := offset
+	for i := range slabs {
+		end := start + uint64(slabs[i].Length)
+		for _, ps := range prefetched {
+			if slabs[i].PartialSlab || !ps.covers(start, end) {
+				continue
+			}
+			data, err := ps.wait(ctx)
+			if err != nil {
+				break
+			}
+			slabs[i].Data = data[start-ps.start : end-ps.start]
+			slabs[i].Prefetched = true
+			break
+		}
+		start = end
+	}
+}
+
 type slabSlice struct {
 	object.SlabSlice
 	PartialSlab bool
+	Prefetched  bool
 	Data        []byte
 }
 
@@ -869,6 +922,16 @@ func slabsForDownload(slabs []slabSlice, offset, length uint64) []slabSlice {
 	return slabs
 }
 
+func isSlabAvailable(s object.Slab, hosts map[types.PublicKey]struct{}) bool {
+	var numAvailable uint8
+	for _, shard := range s.Shards {
+		if isSectorAvailable(shard, hosts) {
+			numAvailable++
+		}
+	}
+	return numAvailable >= s.MinShards
+}
+
 func isSectorAvailable(s object.Sector, hosts map[types.PublicKey]struct{}) bool {
 	// if any of the other hosts that store the sector are
 	// available, the sector is also considered available
@@ -0,0 +1,313 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/internal/host"
+	"go.sia.tech/renterd/v2/internal/memory"
+	"go.sia.tech/renterd/v2/object"
+	"go.uber.org/zap"
+)
+
+const (
+	// prefetchIdleTimeout is the amount of time after which a stream that
+	// hasn't been read from is dropped, releasing its prefetched slabs.
+	prefetchIdleTimeout = time.Minute
+
+	// prefetchMaxStreams is the maximum number of streams that are tracked at
+	// the same time, once reached the least recently used stream is dropped.
+	prefetchMaxStreams = 100
+
+	// prefetchMaxSlabs is the number of slabs that are prefetched ahead of the
+	// read position of a stream.
+	prefetchMaxSlabs = 2
+
+	// prefetchMemoryLimitDenom limits the amount of download memory that can be
+	// used to hold prefetched slabs to 1/6th of the available download memory.
+	prefetchMemoryLimitDenom = 6
+)
+
+type (
+	// prefetcher tracks the read position of objects that are downloaded
+	// through a series of range requests. Once it detects sequential access it
+	// prefetches the data following the read position so the next range
+	// request can be served from memory.
+	prefetcher struct {
+		mm memory.MemoryManager
+
+		mu      sync.Mutex
+		streams map[types.Hash256]*stream
+	}
+
+	// stream represents the sequential read access of an object.
+	stream struct {
+		lastAccess time.Time
+		nextOffset uint64
+		slabs      map[int]*prefetchedSlab
+		timer      *time.Timer
+	}
+
+	// prefetchedSlab holds the recovered data of a slab in the range
+	// [start,end), both offsets are relative to the start of the object.
+	prefetchedSlab struct {
+		start uint64
+		end   uint64
+
+		cancel context.CancelFunc
+		mem    memory.Memory
+
+		done chan struct{}
+		data []byte
+		err  error
+	}
+)
+
+func newPrefetcher(mm memory.MemoryManager, logger *zap.SugaredLogger) *prefetcher {
+	pf := &prefetcher{streams: make(map[types.Hash256]*stream)}
+
+	// limit the amount of memory available for prefetching
+	limited, err := mm.Limit(mm.Status().Total / prefetchMemoryLimitDenom)
+	if err != nil {
+		logger.Errorw("failed to limit prefetch memory, prefetching is disabled", zap.Error(err))
+		return pf
+	}
+	pf.mm = limited
+	return pf
+}
+
+// covers returns true if the prefetched slab contains the given range.
+func (ps *prefetchedSlab) covers(start, end uint64) bool {
+	return ps.start <= start && end <= ps.end
+}
+
+// release cancels the prefetch and releases the memory that was acquired for
+// it.
+func (ps *prefetchedSlab) release() {
+	ps.cancel()
+	ps.mem.Release()
+}
+
+// wait blocks until the slab was prefetched and returns its data.
+func (ps *prefetchedSlab) wait(ctx context.Context) ([]byte, error) {
+	select {
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	case <-ps.done:
+	}
+	return ps.data, ps.err
+}
+
+// Close drops all streams and releases their prefetched slabs.
+func (pf *prefetcher) Close() {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	for id := range pf.streams {
+		pf.removeStream(id)
+	}
+}
+
+// NumPrefetched returns the number of slabs that were prefetched successfully
+// and are currently held in memory.
+func (pf *prefetcher) NumPrefetched() (n uint64) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	for _, s := range pf.streams {
+		for _, ps := range s.slabs {
+			select {
+			case <-ps.done:
+				if ps.err == nil {
+					n++
+				}
+			default:
+			}
+		}
+	}
+	return
+}
+
+// Track registers a read of the given range for the stream with given id. It
+// returns whether the read is sequential as well as the prefetched slabs that
+// overlap with the range. The read position of the stream is only updated
+// once the read succeeded, see Advance.
+func (pf *prefetcher) Track(id types.Hash256, offset, length uint64) (sequential bool, prefetched []*prefetchedSlab) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+
+	s, exists := pf.streams[id]
+	if !exists {
+		return false, nil
+	}
+	s.timer.Reset(prefetchIdleTimeout)
+	s.lastAccess = time.Now()
+
+	// a read is considered sequential if it picks up where the previous one
+	// left off
+	sequential = offset == s.nextOffset
+
+	// release the slabs that are no longer useful, if the read wasn't
+	// sequential that's all of them
+	for i, ps := range s.slabs {
+		if !sequential || ps.end <= offset {
+			ps.release()
+			delete(s.slabs, i)
+		} else if ps.start < offset+length {
+			prefetched = append(prefetched, ps)
+		}
+	}
+	return
+}
+
+// Advance updates the read position of the stream with given id after a read
+// that ended at the given offset succeeded. If the stream isn't tracked yet it
+// is added.
+func (pf *prefetcher) Advance(id types.Hash256, offset uint64) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+
+	s, exists := pf.streams[id]
+	if !exists {
+		// make room for the new stream
+		if len(pf.streams) >= prefetchMaxStreams {
+			var lru types.Hash256
+			var lruAccess time.Time
+			for id, s := range pf.streams {
+				if lruAccess.IsZero() || s.lastAccess.Before(lruAccess) {
+					lru, lruAccess = id, s.lastAccess
+				}
+			}
+			pf.removeStream(lru)
+		}
+
+		s = &stream{slabs: make(map[int]*prefetchedSlab)}
+		s.timer = time.AfterFunc(prefetchIdleTimeout, func() {
+			pf.mu.Lock()
+			defer pf.mu.Unlock()
+			if pf.streams[id] == s {
+				pf.removeStream(id)
+			}
+		})
+		pf.streams[id] = s
+	} else {
+		s.timer.Reset(prefetchIdleTimeout)
+	}
+	s.lastAccess = time.Now()
+	s.nextOffset = offset
+}
+
+// Prefetch registers a prefetch for the given slab of the stream. It returns
+// nil if the slab is already being prefetched, if the stream is no longer
+// tracked or if there's not enough memory available.
+func (pf *prefetcher) Prefetch(ctx context.Context, id types.Hash256, slabIndex int, start, end uint64) (context.Context, *prefetchedSlab) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+
+	s, exists := pf.streams[id]
+	if !exists || pf.mm == nil {
+		return nil, nil
+	} else if _, exists := s.slabs[slabIndex]; exists {
+		return nil, nil
+	}
+
+	// try and acquire the memory, prefetching is best effort so we don't
+	// block if there's not enough memory available
+	mem := pf.mm.TryAcquireMemory(end - start)
+	if mem == nil {
+		return nil, nil
+	}
+
+	// the prefetch outlives the request that triggered it
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	ps := &prefetchedSlab{
+		start:  start,
+		end:    end,
+		cancel: cancel,
+		mem:    mem,
+		done:   make(chan struct{}),
+	}
+	s.slabs[slabIndex] = ps
+	return ctx, ps
+}
+
+func (pf *prefetcher) removeStream(id types.Hash256) {
+	s, exists := pf.streams[id]
+	if !exists {
+		return
+	}
+	s.timer.Stop()
+	for _, ps := range s.slabs {
+		ps.release()
+	}
+	delete(pf.streams, id)
+}
+
+// prefetchAhead prefetches the data that follows the given offset of the
+// object, that is the remainder of the slab that contains the offset as well
+// as the slab after that.
+func (mgr *Manager) prefetchAhead(ctx context.Context, id types.Hash256, o object.Object, offset uint64, available map[types.PublicKey]struct{}) {
+	var start uint64
+	var numSlabs int
+	for i, ss := range o.Slabs {
+		end := start + uint64(ss.Length)
+		if end <= offset {
+			start = end
+			continue
+		} else if numSlabs == prefetchMaxSlabs {
+			break
+		}
+		numSlabs++
+
+		// prefetch the data from the offset onwards, partial slabs are
+		// skipped since they are fetched from the bus
+		from := max(start, offset)
+		if !ss.IsPartial() && isSlabAvailable(ss.Slab, available) {
+			if pctx, ps := mgr.prefetcher.Prefetch(ctx, id, i, from, end); ps != nil {
+				slice := ss
+				slice.Offset += uint32(from - start)
+				slice.Length = uint32(end - from)
+				go mgr.prefetchSlab(pctx, slice, ps)
+			}
+		}
+		start = end
+	}
+}
+
+func (mgr *Manager) prefetchSlab(ctx context.Context, slice object.SlabSlice, ps *prefetchedSlab) {
+	defer close(ps.done)
+
+	shards, err := mgr.downloadSlab(ctx, slice, host.PriorityPrefetch)
+	if err != nil {
+		mgr.logger.Debugw("failed to prefetch slab", zap.Error(err))
+		ps.err = err
+		ps.mem.Release()
+		return
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, slice.Length))
+	slice.Decrypt(shards)
+	if err := slice.Recover(buf, shards); err != nil {
+		mgr.logger.Debugw("failed to recover prefetched slab", zap.Error(err))
+		ps.err = err
+		ps.mem.Release()
+		return
+	}
+	ps.data = buf.Bytes()
+}
+
+// streamID returns an identifier for the given object that is used to track
+// sequential reads of it.
+func streamID(o object.Object) types.Hash256 {
+	h := types.NewHasher()
+	key := o.Key.Entropy()
+	h.E.Write(key[:])
+	for _, ss := range o.Slabs {
+		key := ss.EncryptionKey.Entropy()
+		h.E.Write(key[:])
+		h.E.WriteUint64(uint64(ss.Offset))
+		h.E.WriteUint64(uint64(ss.Length))
+	}
+	return h.Sum()
+}
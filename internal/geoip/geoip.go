@@ -0,0 +1,116 @@
+package geoip
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// A Database maps IP ranges to the country and autonomous system they
+	// belong to.
+	Database struct {
+		ranges []ipRange // sorted by start
+	}
+
+	// A Location is the country and autonomous system an IP belongs to.
+	Location struct {
+		Country string
+		ASN     uint32
+	}
+
+	ipRange struct {
+		start, end netip.Addr
+		Location
+	}
+)
+
+// Load loads a database from the file at the given path, see Parse for the
+// expected format.
+func Load(path string) (*Database, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database: %w", err)
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse parses a database in the tab separated format of the IP to ASN
+// databases published by iptoasn.com. Every line contains the first and last
+// IP of a range, the ASN, the two letter country code and a description of the
+// AS. Ranges that aren't routed are skipped.
+func Parse(r io.Reader) (*Database, error) {
+	var db Database
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		if strings.TrimSpace(s.Text()) == "" {
+			continue
+		}
+		fields := strings.Split(s.Text(), "\t")
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("line %d: expected at least 4 fields, got %d", line, len(fields))
+		}
+
+		start, err := netip.ParseAddr(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid range start: %w", line, err)
+		}
+		end, err := netip.ParseAddr(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid range end: %w", line, err)
+		} else if start.Is4() != end.Is4() || end.Less(start) {
+			return nil, fmt.Errorf("line %d: invalid range %v-%v", line, start, end)
+		}
+		asn, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid ASN: %w", line, err)
+		}
+
+		// ASN 0 is used for ranges that aren't routed
+		if asn == 0 {
+			continue
+		}
+
+		country := strings.ToUpper(fields[3])
+		if country == "NONE" {
+			country = ""
+		}
+		db.ranges = append(db.ranges, ipRange{
+			start: start.Unmap(),
+			end:   end.Unmap(),
+			Location: Location{
+				Country: country,
+				ASN:     uint32(asn),
+			},
+		})
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read GeoIP database: %w", err)
+	}
+
+	sort.Slice(db.ranges, func(i, j int) bool {
+		return db.ranges[i].start.Less(db.ranges[j].start)
+	})
+	return &db, nil
+}
+
+// Lookup returns the location of the given IP, the boolean indicates whether
+// the IP was found in the database.
+func (db *Database) Lookup(addr netip.Addr) (Location, bool) {
+	addr = addr.Unmap()
+
+	// find the last range that starts before or at the given address
+	i := sort.Search(len(db.ranges), func(i int) bool {
+		return addr.Less(db.ranges[i].start)
+	}) - 1
+	if i < 0 || db.ranges[i].end.Less(addr) {
+		return Location{}, false
+	}
+	return db.ranges[i].Location, true
+}
@@ -0,0 +1,54 @@
+package geoip
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestDatabase(t *testing.T) {
+	db, err := Parse(strings.NewReader(strings.Join([]string{
+		"1.0.4.0\t1.0.7.255\t38803\tAU\tGTELECOM-AUSTRALIA Gtelecom-AUSTRALIA",
+		"1.0.0.0\t1.0.0.255\t13335\tUS\tCLOUDFLARENET",
+		"1.0.1.0\t1.0.3.255\t0\tNone\tNot routed",
+		"",
+		"2001:200::\t2001:200:5ff:ffff:ffff:ffff:ffff:ffff\t2500\tJP\tWIDE-BB WIDE Project",
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		addr  string
+		loc   Location
+		found bool
+	}{
+		{"0.255.255.255", Location{}, false},
+		{"1.0.0.0", Location{Country: "US", ASN: 13335}, true},
+		{"1.0.0.255", Location{Country: "US", ASN: 13335}, true},
+		{"::ffff:1.0.0.1", Location{Country: "US", ASN: 13335}, true},
+		{"1.0.2.1", Location{}, false},
+		{"1.0.5.1", Location{Country: "AU", ASN: 38803}, true},
+		{"1.0.8.0", Location{}, false},
+		{"2001:200:1::1", Location{Country: "JP", ASN: 2500}, true},
+		{"2001:200:600::", Location{}, false},
+	}
+	for _, test := range tests {
+		loc, found := db.Lookup(netip.MustParseAddr(test.addr))
+		if found != test.found || loc != test.loc {
+			t.Errorf("%v: unexpected location %+v (%v), expected %+v (%v)", test.addr, loc, found, test.loc, test.found)
+		}
+	}
+
+	// assert invalid databases are rejected
+	for _, invalid := range []string{
+		"1.0.0.0\t1.0.0.255\t13335",
+		"1.0.0.0\t1.0.0.255\tfoo\tUS\tCLOUDFLARENET",
+		"1.0.0.255\t1.0.0.0\t13335\tUS\tCLOUDFLARENET",
+		"1.0.0.0\t2001:200::\t13335\tUS\tCLOUDFLARENET",
+	} {
+		if _, err := Parse(strings.NewReader(invalid)); err == nil {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
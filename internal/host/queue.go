@@ -0,0 +1,114 @@
+package host
+
+import "fmt"
+
+// A Priority is the priority class of a sector request that is sent to a
+// host.
+type Priority uint8
+
+const (
+	// PriorityPackedSlabFlush is used for uploading packed slabs, the data is
+	// already stored in the slab buffers so there's no one waiting on it.
+	PriorityPackedSlabFlush Priority = iota
+
+	// PriorityMigration is used for migrating slabs to new hosts.
+	PriorityMigration
+
+	// PriorityPrefetch is used for reading ahead of sequentially read objects,
+	// the data is speculative so it shouldn't compete with user requests.
+	PriorityPrefetch
+
+	// PriorityUpload is used for user uploads.
+	PriorityUpload
+
+	// PriorityDownload is used for interactive downloads.
+	PriorityDownload
+
+	numPriorities = int(PriorityDownload) + 1
+)
+
+// maxConsecutiveSkips is the number of times in a row a priority class with
+// queued requests can be skipped in favour of a higher priority class. It
+// guarantees background work a minimum share of the host's I/O.
+const maxConsecutiveSkips = 4
+
+// String implements the fmt.Stringer interface.
+func (p Priority) String() string {
+	switch p {
+	case PriorityPackedSlabFlush:
+		return "packedslabflush"
+	case PriorityMigration:
+		return "migration"
+	case PriorityPrefetch:
+		return "prefetch"
+	case PriorityUpload:
+		return "upload"
+	case PriorityDownload:
+		return "download"
+	default:
+		return fmt.Sprintf("unknown priority %d", p)
+	}
+}
+
+// A Queue holds requests to a host by priority class. Requests with a higher
+// priority jump the queue, requests of the same priority are popped in the
+// order they were pushed. To avoid background work from starving, a class
+// that was skipped too often in favour of higher priority requests is served
+// next. A Queue is not safe for concurrent use.
+type Queue[T any] struct {
+	classes [numPriorities][]T
+	skipped [numPriorities]int
+	n       int
+}
+
+// Len returns the number of queued requests.
+func (q *Queue[T]) Len() int {
+	return q.n
+}
+
+// Push adds a request with the given priority to the queue.
+func (q *Queue[T]) Push(p Priority, req T) {
+	if int(p) >= numPriorities {
+		panic(fmt.Sprintf("invalid priority %d", p)) // developer error
+	}
+	q.classes[p] = append(q.classes[p], req)
+	q.n++
+}
+
+// Pop removes and returns the next request, the boolean is false if the queue
+// is empty.
+func (q *Queue[T]) Pop() (req T, _ bool) {
+	if q.n == 0 {
+		return req, false
+	}
+
+	// serve the highest priority class, unless a class that was skipped too
+	// often has requests queued
+	next := -1
+	for p := numPriorities - 1; p >= 0; p-- {
+		if len(q.classes[p]) == 0 {
+			continue
+		} else if next == -1 {
+			next = p
+		} else if q.skipped[p] >= maxConsecutiveSkips {
+			next = p
+			break
+		}
+	}
+
+	// update the skip counters of the classes that are waiting
+	for p := range q.classes {
+		if p == next {
+			q.skipped[p] = 0
+		} else if len(q.classes[p]) > 0 {
+			q.skipped[p]++
+		}
+	}
+
+	var zero T
+	req = q.classes[next][0]
+	q.classes[next][0] = zero
+	q.classes[next] = q.classes[next][1:]
+	q.n--
+	return req, true
+}
@@ -0,0 +1,63 @@
+package host
+
+import "testing"
+
+func TestQueue(t *testing.T) {
+	var q Queue[int]
+	if _, ok := q.Pop(); ok {
+		t.Fatal("expected empty queue")
+	}
+
+	// push requests of every class
+	q.Push(PriorityPackedSlabFlush, 1)
+	q.Push(PriorityMigration, 2)
+	q.Push(PriorityPrefetch, 3)
+	q.Push(PriorityUpload, 4)
+	q.Push(PriorityDownload, 5)
+	if q.Len() != 5 {
+		t.Fatalf("expected 5 requests, got %d", q.Len())
+	}
+
+	// assert requests are popped by priority
+	for _, expected := range []int{5, 4, 3, 2, 1} {
+		if req, ok := q.Pop(); !ok || req != expected {
+			t.Fatalf("expected %d, got %d", expected, req)
+		}
+	}
+	if q.Len() != 0 {
+		t.Fatalf("expected empty queue, got %d", q.Len())
+	}
+
+	// queue a lot of downloads and a migration, assert the downloads are
+	// popped in order and the migration is served after being skipped the max
+	// number of times
+	q.Push(PriorityMigration, -1)
+	for i := 0; i < 10; i++ {
+		q.Push(PriorityDownload, i)
+	}
+	for i := 0; i < maxConsecutiveSkips; i++ {
+		if req, _ := q.Pop(); req != i {
+			t.Fatalf("expected %d, got %d", i, req)
+		}
+	}
+	if req, _ := q.Pop(); req != -1 {
+		t.Fatalf("expected migration, got %d", req)
+	}
+
+	// assert the share of background work is guaranteed while the queue
+	// remains saturated with downloads
+	var q2 Queue[Priority]
+	for i := 0; i < 100; i++ {
+		q2.Push(PriorityDownload, PriorityDownload)
+		q2.Push(PriorityPackedSlabFlush, PriorityPackedSlabFlush)
+	}
+	var flushes int
+	for i := 0; i < 100; i++ {
+		if p, _ := q2.Pop(); p == PriorityPackedSlabFlush {
+			flushes++
+		}
+	}
+	if expected := 100 / (maxConsecutiveSkips + 1); flushes != expected {
+		t.Fatalf("expected %d flushes, got %d", expected, flushes)
+	}
+}
@@ -14,6 +14,7 @@ type (
 	MemoryManager interface {
 		Status() Status
 		AcquireMemory(ctx context.Context, amt uint64) Memory
+		TryAcquireMemory(amt uint64) Memory
 		Limit(amt uint64) (MemoryManager, error)
 	}
 
@@ -113,6 +114,25 @@ func (mm *memoryManager) AcquireMemory(ctx context.Context, amt uint64) Memory {
 	}
 }
 
+// TryAcquireMemory acquires the given amount of memory if it is available
+// right away, if it isn't, nil is returned.
+func (mm *memoryManager) TryAcquireMemory(amt uint64) Memory {
+	if amt == 0 {
+		mm.logger.Errorf("cannot acquire 0 memory")
+		return nil
+	}
+	mm.sigNewMem.L.Lock()
+	defer mm.sigNewMem.L.Unlock()
+	if mm.available < amt {
+		return nil
+	}
+	mm.available -= amt
+	return &acquiredMemory{
+		mm:        mm,
+		remaining: amt,
+	}
+}
+
 // release returns all the remaining memory to the memory manager. Should always
 // be called on every acquiredMemory when done using it.
 func (am *acquiredMemory) Release() {
@@ -169,6 +189,22 @@ func (lmm *limitMemoryManager) AcquireMemory(ctx context.Context, amt uint64) Me
 	}
 }
 
+func (lmm *limitMemoryManager) TryAcquireMemory(amt uint64) Memory {
+	childMem := lmm.child.TryAcquireMemory(amt)
+	if childMem == nil {
+		return nil
+	}
+	parentMem := lmm.parent.TryAcquireMemory(amt)
+	if parentMem == nil {
+		childMem.Release()
+		return nil
+	}
+	return &limitAcquiredMemory{
+		child:  childMem,
+		parent: parentMem,
+	}
+}
+
 func (lmm *limitMemoryManager) Limit(amt uint64) (MemoryManager, error) {
 	return lmm.child.Limit(amt)
 }
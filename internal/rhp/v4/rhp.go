@@ -117,6 +117,16 @@ func (c *Client) AppendSector(ctx context.Context, hk types.PublicKey, hostIP st
 	return revision, usage, err
 }
 
+// WriteSector writes a sector to the host's temporary storage without
+// appending it to a contract.
+func (c *Client) WriteSector(ctx context.Context, hk types.PublicKey, hostIP string, prices rhp4.HostPrices, token rhp4.AccountToken, data io.Reader, length uint64) (res rhp.RPCWriteSectorResult, _ error) {
+	err := c.tpool.withTransport(ctx, hk, hostIP, func(t rhp.TransportClient) (err error) {
+		res, err = rhp.RPCWriteSector(ctx, t, prices, token, data, length)
+		return
+	})
+	return res, err
+}
+
 // FundAccounts funds accounts on the host.
 func (c *Client) FundAccounts(ctx context.Context, hk types.PublicKey, hostIP string, cs consensus.State, signer rhp.ContractSigner, contract rhp.ContractRevision, deposits []rhp4.AccountDeposit) (res rhp.RPCFundAccountResult, _ error) {
 	err := c.tpool.withTransport(ctx, hk, hostIP, func(c rhp.TransportClient) (err error) {
@@ -0,0 +1,175 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type (
+	// A Schedule is a set of recurring weekly time windows in UTC. An empty
+	// schedule is always active.
+	Schedule []Window
+
+	// A Window is active on the given days between the start and end offset
+	// from midnight. If the end is before the start, the window wraps around
+	// midnight and ends on the next day.
+	Window struct {
+		Days  [7]bool
+		Start time.Duration
+		End   time.Duration
+	}
+)
+
+var dayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Parse parses a schedule from a string. The schedule consists of windows
+// separated by a semicolon. Every window consists of an optional set of days
+// and an optional time range in UTC, e.g. "00:00-06:00", "Sat,Sun" or
+// "Mon-Fri 22:00-04:00". Days are comma separated and can be ranges, a window
+// without days is active every day and a window without a time range is
+// active all day.
+func Parse(s string) (Schedule, error) {
+	var schedule Schedule
+	for _, ws := range strings.Split(s, ";") {
+		ws = strings.TrimSpace(ws)
+		if ws == "" {
+			continue
+		}
+		w, err := parseWindow(ws)
+		if err != nil {
+			return nil, fmt.Errorf("invalid window '%s': %w", ws, err)
+		}
+		schedule = append(schedule, w)
+	}
+	return schedule, nil
+}
+
+// Active returns whether the given time falls within any of the schedule's
+// windows.
+func (s Schedule) Active(t time.Time) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, w := range s {
+		if w.active(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the next time at or after the given time at which the schedule
+// is active, with a precision of a minute. If the schedule is never active,
+// the zero time is returned.
+func (s Schedule) Next(t time.Time) time.Time {
+	if s.Active(t) {
+		return t
+	}
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	for i := 0; i < 7*24*60; i++ {
+		if s.Active(t) {
+			return t
+		}
+		t = t.Add(time.Minute)
+	}
+	return time.Time{}
+}
+
+func (w Window) active(t time.Time) bool {
+	t = t.UTC()
+	offset := t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+	day := t.Weekday()
+	if w.Start < w.End {
+		return w.Days[day] && offset >= w.Start && offset < w.End
+	}
+	// the window wraps around midnight, it's either active on the day it
+	// starts or on the morning of the next day
+	return (w.Days[day] && offset >= w.Start) || (w.Days[(day+6)%7] && offset < w.End)
+}
+
+func parseWindow(s string) (w Window, err error) {
+	fields := strings.Fields(s)
+	if len(fields) > 2 {
+		return Window{}, fmt.Errorf("expected at most 2 fields, got %d", len(fields))
+	}
+
+	// default to every day, all day
+	for i := range w.Days {
+		w.Days[i] = true
+	}
+	w.Start, w.End = 0, 24*time.Hour
+
+	for i, field := range fields {
+		if strings.Contains(field, ":") {
+			if w.Start, w.End, err = parseTimeRange(field); err != nil {
+				return Window{}, err
+			}
+		} else if i == 0 {
+			if w.Days, err = parseDays(field); err != nil {
+				return Window{}, err
+			}
+		} else {
+			return Window{}, fmt.Errorf("expected time range, got '%s'", field)
+		}
+	}
+	return w, nil
+}
+
+func parseDays(s string) (days [7]bool, _ error) {
+	for _, part := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		start, ok := dayNames[strings.ToLower(from)]
+		if !ok {
+			return days, fmt.Errorf("unknown day '%s'", from)
+		}
+		end := start
+		if isRange {
+			if end, ok = dayNames[strings.ToLower(to)]; !ok {
+				return days, fmt.Errorf("unknown day '%s'", to)
+			}
+		}
+		for d := start; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == end {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func parseTimeRange(s string) (start, end time.Duration, err error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("expected time range in the format HH:MM-HH:MM, got '%s'", s)
+	}
+	if start, err = parseTime(from); err != nil {
+		return 0, 0, err
+	} else if end, err = parseTime(to); err != nil {
+		return 0, 0, err
+	} else if start == end {
+		return 0, 0, fmt.Errorf("time range '%s' is empty", s)
+	} else if start == 24*time.Hour {
+		return 0, 0, fmt.Errorf("time range '%s' can't start at 24:00", s)
+	}
+	return start, end, nil
+}
+
+func parseTime(s string) (time.Duration, error) {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(s, "%d:%d", &hours, &minutes); err != nil || len(s) != 5 {
+		return 0, fmt.Errorf("expected time in the format HH:MM, got '%s'", s)
+	} else if minutes < 0 || minutes > 59 || hours < 0 || hours > 24 || (hours == 24 && minutes != 0) {
+		return 0, fmt.Errorf("invalid time '%s'", s)
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
@@ -0,0 +1,85 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	// 2024-01-06 is a Saturday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		schedule string
+		active   []time.Time
+		inactive []time.Time
+	}{
+		{
+			schedule: "",
+			active:   []time.Time{at(1, 0, 0), at(6, 12, 0)},
+		},
+		{
+			schedule: "00:00-06:00",
+			active:   []time.Time{at(1, 0, 0), at(6, 5, 59)},
+			inactive: []time.Time{at(1, 6, 0), at(6, 23, 59)},
+		},
+		{
+			schedule: "Sat,Sun",
+			active:   []time.Time{at(6, 0, 0), at(7, 23, 59)},
+			inactive: []time.Time{at(5, 23, 59), at(8, 0, 0)},
+		},
+		{
+			schedule: "Mon-Fri 22:00-04:00",
+			active:   []time.Time{at(1, 22, 0), at(2, 3, 59), at(5, 23, 0), at(6, 3, 0)},
+			inactive: []time.Time{at(1, 3, 0), at(1, 21, 59), at(6, 22, 0), at(7, 3, 0)},
+		},
+		{
+			schedule: "Sat 00:00-24:00; Wed 12:00-13:00",
+			active:   []time.Time{at(6, 0, 0), at(6, 23, 59), at(3, 12, 30)},
+			inactive: []time.Time{at(3, 13, 0), at(7, 0, 0)},
+		},
+		{
+			schedule: "Fri-Mon",
+			active:   []time.Time{at(5, 0, 0), at(8, 23, 59)},
+			inactive: []time.Time{at(2, 12, 0), at(4, 12, 0)},
+		},
+	}
+	for _, test := range tests {
+		s, err := Parse(test.schedule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, ts := range test.active {
+			if !s.Active(ts) {
+				t.Errorf("%q: expected %v to be active", test.schedule, ts)
+			} else if !s.Next(ts).Equal(ts) {
+				t.Errorf("%q: expected next window to start at %v", test.schedule, ts)
+			}
+		}
+		for _, ts := range test.inactive {
+			if s.Active(ts) {
+				t.Errorf("%q: expected %v to be inactive", test.schedule, ts)
+			} else if next := s.Next(ts); !next.After(ts) || !s.Active(next) || s.Active(next.Add(-time.Minute)) {
+				t.Errorf("%q: unexpected next window start %v for %v", test.schedule, next, ts)
+			}
+		}
+	}
+
+	// assert invalid schedules are rejected
+	for _, invalid := range []string{
+		"Foo",
+		"Mon 00:00",
+		"00:00-00:00",
+		"24:00-06:00",
+		"00:00-24:01",
+		"0:00-06:00",
+		"Mon 00:00-06:00 Tue",
+		"00:00-06:00 Mon",
+	} {
+		if _, err := Parse(invalid); err == nil {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
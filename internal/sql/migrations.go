@@ -573,6 +573,60 @@ var (
 					return performMigration(ctx, tx, migrationsFs, dbIdentifier, "00039_host_settings_protocol_version", log)
 				},
 			},
+			{
+				ID: "00040_tus_uploads",
+				Migrate: func(tx Tx) error {
+					return performMigration(ctx, tx, migrationsFs, dbIdentifier, "00040_tus_uploads", log)
+				},
+			},
+			{
+				ID: "00041_object_access_stats",
+				Migrate: func(tx Tx) error {
+					return performMigration(ctx, tx, migrationsFs, dbIdentifier, "00041_object_access_stats", log)
+				},
+			},
+			{
+				ID: "00042_host_score_weights",
+				Migrate: func(tx Tx) error {
+					return performMigration(ctx, tx, migrationsFs, dbIdentifier, "00042_host_score_weights", log)
+				},
+			},
+			{
+				ID: "00043_host_benchmarks",
+				Migrate: func(tx Tx) error {
+					return performMigration(ctx, tx, migrationsFs, dbIdentifier, "00043_host_benchmarks", log)
+				},
+			},
+			{
+				ID: "00044_host_labels",
+				Migrate: func(tx Tx) error {
+					return performMigration(ctx, tx, migrationsFs, dbIdentifier, "00044_host_labels", log)
+				},
+			},
+			{
+				ID: "00045_contracts_budget",
+				Migrate: func(tx Tx) error {
+					return performMigration(ctx, tx, migrationsFs, dbIdentifier, "00045_contracts_budget", log)
+				},
+			},
+			{
+				ID: "00046_contracts_renewals",
+				Migrate: func(tx Tx) error {
+					return performMigration(ctx, tx, migrationsFs, dbIdentifier, "00046_contracts_renewals", log)
+				},
+			},
+			{
+				ID: "00047_contracts_manual",
+				Migrate: func(tx Tx) error {
+					return performMigration(ctx, tx, migrationsFs, dbIdentifier, "00047_contracts_manual", log)
+				},
+			},
+			{
+				ID: "00048_contract_sets",
+				Migrate: func(tx Tx) error {
+					return performMigration(ctx, tx, migrationsFs, dbIdentifier, "00048_contract_sets", log)
+				},
+			},
 		}
 	}
 	MetricsMigrations = func(ctx context.Context, migrationsFs embed.FS, log *zap.SugaredLogger) []Migration {
@@ -612,6 +666,24 @@ var (
 					return performMigration(ctx, tx, migrationsFs, dbIdentifier, "00005_remove_contract_sets", log)
 				},
 			},
+			{
+				ID: "00006_health",
+				Migrate: func(tx Tx) error {
+					return performMigration(ctx, tx, migrationsFs, dbIdentifier, "00006_health", log)
+				},
+			},
+			{
+				ID: "00007_hosts",
+				Migrate: func(tx Tx) error {
+					return performMigration(ctx, tx, migrationsFs, dbIdentifier, "00007_hosts", log)
+				},
+			},
+			{
+				ID: "00008_contract_spending_baselines",
+				Migrate: func(tx Tx) error {
+					return performMigration(ctx, tx, migrationsFs, dbIdentifier, "00008_contract_spending_baselines", log)
+				},
+			},
 		}
 	}
 )
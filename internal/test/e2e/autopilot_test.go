@@ -4,7 +4,9 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
+	"go.sia.tech/core/types"
 	"go.sia.tech/renterd/v2/api"
 	"go.sia.tech/renterd/v2/bus/client"
 	"go.sia.tech/renterd/v2/internal/test"
@@ -45,6 +47,22 @@ func TestAutopilot(t *testing.T) {
 	if err := b.UpdateAutopilotConfig(context.Background(), client.WithHostsConfig(h)); !utils.IsErr(err, api.ErrInvalidReleaseVersion) {
 		t.Fatal("unexpected")
 	}
+	h.MinProtocolVersion = ap.Hosts.MinProtocolVersion
+
+	// assert score weights are validated and persisted
+	weights := api.DefaultHostScoreWeights
+	weights.Latency = api.ScoreWeight{Weight: 1.5, Exponent: 1}
+	h.ScoreWeights = &weights
+	if err := b.UpdateAutopilotConfig(context.Background(), client.WithHostsConfig(h)); err == nil || !strings.Contains(err.Error(), "weight must be between 0 and 1") {
+		t.Fatal("unexpected", err)
+	}
+	weights.Latency = api.ScoreWeight{Weight: 0.5, Exponent: 2}
+	tt.OK(b.UpdateAutopilotConfig(context.Background(), client.WithHostsConfig(h)))
+	ap, err = b.AutopilotConfig(context.Background())
+	tt.OK(err)
+	if ap.Hosts.ScoreWeights == nil || *ap.Hosts.ScoreWeights != weights {
+		t.Fatalf("unexpected score weights %+v", ap.Hosts.ScoreWeights)
+	}
 
 	// assert c config is validated
 	c := ap.Contracts
@@ -58,9 +76,16 @@ func TestAutopilot(t *testing.T) {
 		t.Fatal("unexpected", err)
 	}
 	c.RenewWindow = 1 // valid renew window
+	c.StaggerRenewals = true
+	c.MaxRenewalsPerCycle = 10
 	if err := b.UpdateAutopilotConfig(context.Background(), client.WithContractsConfig(c)); err != nil {
 		t.Fatal(err)
 	}
+	ap, err = b.AutopilotConfig(context.Background())
+	tt.OK(err)
+	if ap.Contracts != c {
+		t.Fatalf("unexpected contracts config %+v", ap.Contracts)
+	}
 
 	// assert we can disable the autopilot
 	tt.OK(b.UpdateAutopilotConfig(context.Background(), client.WithAutopilotEnabled(false)))
@@ -70,3 +95,129 @@ func TestAutopilot(t *testing.T) {
 		t.Fatal("autopilot should be disabled")
 	}
 }
+
+func TestForecastCosts(t *testing.T) {
+	cluster := newTestCluster(t, testClusterOptions{
+		hosts: test.RedundancySettings.TotalShards,
+	})
+	defer cluster.Shutdown()
+	tt := cluster.tt
+
+	// assert invalid requests are rejected
+	_, err := cluster.Autopilot.ForecastCosts(context.Background(), api.CostForecastRequest{Periods: 0})
+	if !utils.IsErr(err, api.ErrInvalidCostForecastRequest) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// forecast storing 1TB for 3 periods
+	res, err := cluster.Autopilot.ForecastCosts(context.Background(), api.CostForecastRequest{
+		Storage:  1e12,
+		Upload:   1e11,
+		Download: 1e11,
+		Periods:  3,
+	})
+	tt.OK(err)
+	if res.Usable != uint64(len(cluster.hosts)) {
+		t.Fatalf("unexpected number of usable hosts %v", res.Usable)
+	} else if len(res.Periods) != 3 {
+		t.Fatalf("unexpected number of periods %v", len(res.Periods))
+	} else if res.Periods[0].EndHeight-res.Periods[0].StartHeight != test.AutopilotConfig.Contracts.Period {
+		t.Fatal("expected the period of the contracts config to be used")
+	} else if res.Periods[0].Storage.IsZero() || res.Periods[0].SiafundFees.IsZero() {
+		t.Fatalf("expected storage and siafund fees to be forecasted, %+v", res.Periods[0])
+	} else if res.Periods[1].Storage.Cmp(res.Periods[0].Storage) <= 0 || res.Periods[2].Storage.Cmp(res.Periods[1].Storage) <= 0 {
+		t.Fatal("expected the forecasted storage to grow every period")
+	} else if !res.Total.Equals(res.Periods[0].Total.Add(res.Periods[1].Total).Add(res.Periods[2].Total)) {
+		t.Fatalf("unexpected total %v", res.Total)
+	}
+}
+
+func TestSimulateMaintenance(t *testing.T) {
+	ctx := context.Background()
+	cluster := newTestCluster(t, testClusterOptions{
+		hosts: test.RedundancySettings.TotalShards,
+	})
+	defer cluster.Shutdown()
+	b := cluster.Bus
+	tt := cluster.tt
+
+	// wait for contracts and disable the autopilot
+	contracts := cluster.WaitForContracts()
+	tt.OK(b.UpdateAutopilotConfig(ctx, client.WithAutopilotEnabled(false)))
+
+	// add a host and scan it
+	h := cluster.NewHost()
+	cluster.AddHost(h)
+	tt.OKAll(b.ScanHost(ctx, h.PublicKey(), 10*time.Second))
+
+	// assert invalid configs are rejected
+	cfg := test.AutopilotConfig
+	cfg.Contracts.Period = 0
+	if _, err := cluster.Autopilot.SimulateMaintenance(ctx, api.MaintenanceSimulationRequest{AutopilotConfig: &cfg}); err == nil || !strings.Contains(err.Error(), "period must be greater than 0") {
+		t.Fatal("unexpected error", err)
+	}
+
+	// assert a config that skips maintenance is reported
+	cfg = test.AutopilotConfig
+	cfg.Contracts.Amount = 0
+	res, err := cluster.Autopilot.SimulateMaintenance(ctx, api.MaintenanceSimulationRequest{AutopilotConfig: &cfg})
+	tt.OK(err)
+	if res.Skipped == "" {
+		t.Fatal("expected maintenance to be skipped")
+	}
+
+	// simulate wanting an additional contract and a renew window that covers
+	// the existing contracts
+	cfg = test.AutopilotConfig
+	cfg.Contracts.Amount++
+	cfg.Contracts.RenewWindow = 10 * cfg.Contracts.Period
+	res, err = cluster.Autopilot.SimulateMaintenance(ctx, api.MaintenanceSimulationRequest{AutopilotConfig: &cfg})
+	tt.OK(err)
+	if len(res.Formed) != 1 || res.Formed[0].HostKey != h.PublicKey() {
+		t.Fatalf("expected a formation with the new host, got %+v", res.Formed)
+	} else if res.Formed[0].RenterFunds.IsZero() || res.Formed[0].HostCollateral.IsZero() {
+		t.Fatalf("expected formation to be funded, got %+v", res.Formed[0])
+	} else if len(res.Renewed) != len(contracts) {
+		t.Fatalf("expected all contracts to be renewed, got %+v", res.Renewed)
+	} else if len(res.MarkedBad) != 0 || len(res.Archived) != 0 {
+		t.Fatalf("unexpected updates %+v %+v", res.MarkedBad, res.Archived)
+	}
+	for _, r := range res.Renewed {
+		if r.RenterFunds.IsZero() || r.Reason == "" {
+			t.Fatalf("unexpected renewal %+v", r)
+		}
+	}
+
+	// simulate gouging settings that all hosts exceed
+	gs := test.GougingSettings
+	gs.MaxStoragePrice = types.NewCurrency64(1)
+	res, err = cluster.Autopilot.SimulateMaintenance(ctx, api.MaintenanceSimulationRequest{GougingSettings: &gs})
+	tt.OK(err)
+	if len(res.MarkedBad) != len(contracts) {
+		t.Fatalf("expected all contracts to be marked bad, got %+v", res.MarkedBad)
+	} else if len(res.Formed) != 0 {
+		t.Fatalf("unexpected formations %+v", res.Formed)
+	}
+	for _, u := range res.MarkedBad {
+		if !strings.Contains(u.Reason, "gouging") {
+			t.Fatalf("unexpected reason %q", u.Reason)
+		}
+	}
+
+	// assert nothing changed
+	updated, err := b.Contracts(ctx, api.ContractsOpts{})
+	tt.OK(err)
+	if len(updated) != len(contracts) {
+		t.Fatalf("unexpected number of contracts, %v != %v", len(updated), len(contracts))
+	}
+	for _, c := range updated {
+		if !c.IsGood() || c.RenewedTo != (types.FileContractID{}) {
+			t.Fatalf("contract %v was updated", c.ID)
+		}
+	}
+	hosts, err := b.Hosts(ctx, api.HostOptions{UsabilityMode: api.UsabilityFilterModeUnusable})
+	tt.OK(err)
+	if len(hosts) != 0 {
+		t.Fatalf("unexpected unusable hosts %v", len(hosts))
+	}
+}
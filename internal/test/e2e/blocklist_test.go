@@ -8,6 +8,7 @@ import (
 
 	"go.sia.tech/core/types"
 	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/utils"
 )
 
 func TestBlocklist(t *testing.T) {
@@ -149,4 +150,25 @@ func TestBlocklist(t *testing.T) {
 	if len(hosts) != 5 {
 		t.Fatal("unexpected number of hosts", len(hosts))
 	}
+
+	// assert invalid rules are rejected
+	if err := b.UpdateHostBlocklist(ctx, []string{"127.0.0.0/33"}, nil, false); !utils.IsErr(err, api.ErrInvalidBlocklistEntry) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// block all hosts using a CIDR range
+	tt.OK(b.UpdateHostBlocklist(ctx, []string{"127.0.0.0/8"}, nil, false))
+	hosts, err = b.Hosts(context.Background(), api.HostOptions{})
+	tt.OK(err)
+	if len(hosts) != 0 {
+		t.Fatal("unexpected number of hosts", len(hosts))
+	}
+
+	// remove the range again
+	tt.OK(b.UpdateHostBlocklist(ctx, nil, []string{"127.0.0.0/8"}, false))
+	hosts, err = b.Hosts(context.Background(), api.HostOptions{})
+	tt.OK(err)
+	if len(hosts) != 5 {
+		t.Fatal("unexpected number of hosts", len(hosts))
+	}
 }
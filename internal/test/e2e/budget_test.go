@@ -0,0 +1,104 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rhpv4 "go.sia.tech/core/rhp/v4"
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/alerts"
+	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/bus/client"
+	"go.sia.tech/renterd/v2/internal/utils"
+)
+
+func TestSpendingBudget(t *testing.T) {
+	ctx := context.Background()
+
+	// create a new test cluster
+	cluster := newTestCluster(t, testClusterOptions{
+		hosts: 1,
+	})
+	defer cluster.Shutdown()
+	b := cluster.Bus
+	tt := cluster.tt
+
+	// fetch the contract
+	contracts, err := b.Contracts(ctx, api.ContractsOpts{FilterMode: api.ContractFilterModeGood})
+	tt.OK(err)
+	if len(contracts) != 1 {
+		t.Fatalf("unexpected number of contracts, %v != 1", len(contracts))
+	}
+
+	// assert the contract's cost was recorded
+	if contracts[0].RenterCost.IsZero() {
+		t.Fatal("expected renter cost to be set")
+	}
+
+	// configure a budget that is already exhausted
+	ap, err := b.AutopilotConfig(ctx)
+	tt.OK(err)
+	cfg := ap.Contracts
+	cfg.Budget = types.Siacoins(1)
+	tt.OK(b.UpdateAutopilotConfig(ctx, client.WithContractsConfig(cfg)))
+
+	// assert the budget was persisted
+	ap, err = b.AutopilotConfig(ctx)
+	tt.OK(err)
+	if !ap.Contracts.Budget.Equals(types.Siacoins(1)) {
+		t.Fatalf("unexpected budget %v", ap.Contracts.Budget)
+	}
+
+	// assert accounts are no longer funded
+	_, err = b.FundAccount(ctx, rhpv4.Account{}, contracts[0].ID, types.NewCurrency64(100))
+	if !utils.IsErr(err, api.ErrSpendingBudgetExceeded) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// assert the autopilot registers a critical alert
+	tt.Retry(100, 100*time.Millisecond, func() error {
+		cluster.MineBlocks(1)
+		ar, err := b.Alerts(ctx, alerts.AlertsOpts{Severity: alerts.SeverityCritical})
+		tt.OK(err)
+		for _, alert := range ar.Alerts {
+			if alert.Message == "Spending budget is exhausted" {
+				return nil
+			}
+		}
+		return errors.New("no spending budget alert")
+	})
+
+	// add a host and assert no contract is formed with it
+	h := cluster.NewHost()
+	cluster.AddHost(h)
+	cluster.MineBlocks(5)
+	time.Sleep(time.Second)
+	contracts, err = b.Contracts(ctx, api.ContractsOpts{FilterMode: api.ContractFilterModeAll})
+	tt.OK(err)
+	for _, c := range contracts {
+		if c.HostKey == h.PublicKey() {
+			t.Fatal("unexpected contract with new host")
+		}
+	}
+
+	// assert the bus refuses to form a contract with it
+	wallet, err := b.Wallet(ctx)
+	tt.OK(err)
+	cs, err := b.ConsensusState(ctx)
+	tt.OK(err)
+	_, err = b.FormContract(ctx, wallet.Address, types.Siacoins(1), h.PublicKey(), types.Siacoins(1), cs.BlockHeight+cfg.Period)
+	if !utils.IsErr(err, api.ErrSpendingBudgetExceeded) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// lift the budget and assert a contract can be formed and accounts are
+	// funded again
+	cfg.Budget = types.ZeroCurrency
+	tt.OK(b.UpdateAutopilotConfig(ctx, client.WithContractsConfig(cfg)))
+	_, err = b.FormContract(ctx, wallet.Address, types.Siacoins(1), h.PublicKey(), types.Siacoins(1), cs.BlockHeight+cfg.Period)
+	tt.OK(err)
+	_, err = b.FundAccount(ctx, rhpv4.Account{}, contracts[0].ID, types.NewCurrency64(100))
+	tt.OK(err)
+}
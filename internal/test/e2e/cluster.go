@@ -542,20 +542,20 @@ func newTestAutopilot(masterKey utils.MasterKey, cfg config.Autopilot, bus *bus.
 	l = l.Named("autopilot")
 
 	ctx, cancel := context.WithCancelCause(context.Background())
-	m, err := migrator.New(ctx, masterKey, a, bus, bus, cfg.MigratorHealthCutoff, cfg.MigratorNumThreads, cfg.MigratorDownloadMaxOverdrive, cfg.MigratorUploadMaxOverdrive, cfg.MigratorDownloadOverdriveTimeout, cfg.MigratorUploadOverdriveTimeout, cfg.MigratorAccountsRefillInterval, l)
+	m, err := migrator.New(ctx, masterKey, a, bus, bus, cfg.MigratorHealthCutoff, cfg.MigratorNumThreads, cfg.MigratorDownloadMaxOverdrive, cfg.MigratorUploadMaxOverdrive, cfg.MigratorDownloadOverdriveTimeout, cfg.MigratorUploadOverdriveTimeout, cfg.MigratorAccountsRefillInterval, nil, 0, l)
 	if err != nil {
 		cancel(nil)
 		return nil, err
 	}
 
-	s, err := scanner.New(bus, cfg.ScannerBatchSize, cfg.ScannerNumThreads, cfg.ScannerInterval, l)
+	s, err := scanner.New(bus, cfg.ScannerBatchSize, cfg.ScannerNumThreads, cfg.ScannerInterval, cfg.BenchmarkInterval, cfg.BenchmarkNumThreads, l)
 	if err != nil {
 		cancel(nil)
 		return nil, err
 	}
 
 	c := contractor.New(bus, bus, bus, bus, bus, cfg.RevisionSubmissionBuffer, cfg.RevisionBroadcastInterval, cfg.AllowRedundantHostIPs, l)
-	p := pruner.New(bus, l)
+	p := pruner.New(bus, nil, l)
 	w := walletmaintainer.New(a, bus, l, walletmaintainer.WithNumOutputs(5, 5))
 
 	return autopilot.New(ctx, cancel, bus, c, m, p, s, w, cfg.Heartbeat, l), nil
@@ -597,7 +597,7 @@ func newTestBus(cm *chain.Manager, genesisBlock types.Block, dir string, cfg con
 	masterKey := blake2b.Sum256(append([]byte("worker"), pk...))
 
 	// create bus
-	b, err := bus.New(cfg, masterKey, alertsMgr, cm, s, w, sqlStore, "", logger)
+	b, err := bus.New(cfg, masterKey, alertsMgr, cm, s, w, sqlStore, storeCfg.GeoIP, "", logger)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
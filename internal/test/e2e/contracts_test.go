@@ -1,6 +1,7 @@
 package e2e
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"testing"
@@ -11,9 +12,11 @@ import (
 	"go.sia.tech/renterd/v2/api"
 	"go.sia.tech/renterd/v2/bus/client"
 	"go.sia.tech/renterd/v2/internal/test"
+	"go.sia.tech/renterd/v2/internal/utils"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
+	"lukechampine.com/frand"
 )
 
 func TestFormContract(t *testing.T) {
@@ -92,6 +95,86 @@ func TestFormContract(t *testing.T) {
 	})
 }
 
+func TestManualContract(t *testing.T) {
+	// configure the autopilot not to form any contracts
+	apCfg := test.AutopilotConfig
+	apCfg.Contracts.Amount = 0
+
+	// create cluster
+	opts := clusterOptsDefault
+	opts.autopilotConfig = &apCfg
+	cluster := newTestCluster(t, opts)
+	defer cluster.Shutdown()
+
+	// convenience variables
+	b := cluster.Bus
+	tt := cluster.tt
+
+	// add a host
+	hosts := cluster.AddHosts(1)
+	h, err := b.Host(context.Background(), hosts[0].PublicKey())
+	tt.OK(err)
+
+	// form a contract using the bus
+	cs, err := b.ConsensusState(context.Background())
+	tt.OK(err)
+	wallet, err := b.Wallet(context.Background())
+	tt.OK(err)
+	endHeight := cs.BlockHeight + apCfg.Contracts.Period + apCfg.Contracts.RenewWindow
+	contract, err := b.FormContract(context.Background(), wallet.Address, types.Siacoins(1), h.PublicKey, types.Siacoins(1), endHeight)
+	tt.OK(err)
+
+	// assert renewal settings can only be configured for manual contracts
+	renewal := api.ContractRenewalSettings{
+		Period:           apCfg.Contracts.Period,
+		RenewWindow:      apCfg.Contracts.RenewWindow,
+		RenterFunds:      types.Siacoins(1),
+		MinNewCollateral: types.Siacoins(1),
+	}
+	if err := b.UpdateContractManual(context.Background(), contract.ID, false, &renewal); !utils.IsErr(err, api.ErrInvalidContractRenewalSettings) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// mark the contract as manual and bad
+	tt.OK(b.UpdateContractManual(context.Background(), contract.ID, true, nil))
+	tt.OK(b.UpdateContractUsability(context.Background(), contract.ID, api.ContractUsabilityBad))
+
+	// mine to the renew window and allow for 1 contract, this won't form a
+	// contract but ensures contract maintenance isn't skipped
+	cluster.MineToRenewWindow()
+	contracts := apCfg.Contracts
+	contracts.Amount = 1
+	tt.OK(b.UpdateAutopilotConfig(context.Background(), client.WithContractsConfig(contracts)))
+	_, err = cluster.Autopilot.Trigger(context.Background(), false)
+	tt.OK(err)
+	time.Sleep(2 * testApCfg().Heartbeat)
+
+	// assert the contract was neither renewed nor marked as good
+	c, err := b.Contract(context.Background(), contract.ID)
+	tt.OK(err)
+	if !c.Manual || c.Renewal != nil {
+		t.Fatalf("unexpected contract %+v", c)
+	} else if c.Usability != api.ContractUsabilityBad {
+		t.Fatalf("expected contract to be bad, got %v", c.Usability)
+	}
+
+	// configure the renewal settings and assert the contract gets renewed
+	tt.OK(b.UpdateContractManual(context.Background(), contract.ID, true, &renewal))
+	tt.Retry(300, 100*time.Millisecond, func() error {
+		contracts, err := b.Contracts(context.Background(), api.ContractsOpts{})
+		if err != nil {
+			return err
+		} else if len(contracts) != 1 {
+			return fmt.Errorf("unexpected number of contracts %d != 1", len(contracts))
+		} else if contracts[0].RenewedFrom != contract.ID {
+			return fmt.Errorf("contract wasn't renewed %v != %v", contracts[0].RenewedFrom, contract.ID)
+		} else if !contracts[0].Manual || contracts[0].Renewal == nil || *contracts[0].Renewal != renewal {
+			return fmt.Errorf("renewal isn't manual %+v", contracts[0])
+		}
+		return nil
+	})
+}
+
 func TestContractUsability(t *testing.T) {
 	// prepare network with no maturity delay
 	network, genesis := testNetwork()
@@ -199,3 +282,104 @@ func TestContractUsability(t *testing.T) {
 		tt.Fatalf("expected contract to be good, got %s", updated.Usability)
 	}
 }
+
+func TestContractSets(t *testing.T) {
+	ctx := context.Background()
+	cluster := newTestCluster(t, testClusterOptions{
+		hosts: test.RedundancySettings.TotalShards,
+	})
+	defer cluster.Shutdown()
+	b := cluster.Bus
+	w := cluster.Worker
+	tt := cluster.tt
+
+	// wait for the contracts of the default set
+	defaultHosts := make(map[types.PublicKey]struct{})
+	for _, c := range cluster.WaitForContracts() {
+		if c.ContractSet != "" {
+			t.Fatalf("expected contract to be in the default set, got %q", c.ContractSet)
+		}
+		defaultHosts[c.HostKey] = struct{}{}
+	}
+
+	// assert assigning a bucket to an unknown set fails
+	if err := b.UpdatePlacementSettings(ctx, api.PlacementSettings{
+		ContractSets: map[string]string{testBucket: "archive"},
+	}); !utils.IsErr(err, api.ErrContractSetNotFound) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// disable the autopilot and add labelled hosts for the archive set
+	tt.OK(b.UpdateAutopilotConfig(ctx, client.WithAutopilotEnabled(false)))
+	archiveHosts := make(map[types.PublicKey]struct{})
+	for i := 0; i < test.RedundancySettings.TotalShards; i++ {
+		h := cluster.NewHost()
+		cluster.AddHost(h)
+		tt.OK(b.UpdateHostLabels(ctx, h.PublicKey(), []string{"archive"}, ""))
+		archiveHosts[h.PublicKey()] = struct{}{}
+	}
+
+	// configure the archive set and enable the autopilot
+	tt.OK(b.UpdateAutopilotConfig(ctx, client.WithAutopilotEnabled(true), client.WithContractSets(map[string]api.ContractSetConfig{
+		"archive": {
+			Contracts: test.AutopilotConfig.Contracts,
+			Hosts:     api.ContractSetHostFilter{Labels: []string{"archive"}},
+		},
+	})))
+
+	// wait for the contracts of the archive set
+	tt.Retry(100, 100*time.Millisecond, func() error {
+		cluster.MineBlocks(1)
+		contracts, err := b.Contracts(ctx, api.ContractsOpts{FilterMode: api.ContractFilterModeGood})
+		tt.OK(err)
+		var n int
+		for _, c := range contracts {
+			if c.ContractSet != "archive" {
+				continue
+			} else if _, ok := archiveHosts[c.HostKey]; !ok {
+				t.Fatalf("contract with unlabelled host %v in archive set", c.HostKey)
+			}
+			n++
+		}
+		if n != test.RedundancySettings.TotalShards {
+			return fmt.Errorf("unexpected number of contracts in archive set, %v != %v", n, test.RedundancySettings.TotalShards)
+		}
+		return nil
+	})
+
+	// assign the test bucket to the archive set
+	tt.OK(b.UpdatePlacementSettings(ctx, api.PlacementSettings{
+		ContractSets: map[string]string{testBucket: "archive"},
+	}))
+	time.Sleep(testWorkerCfg().CacheExpiry) // expire cache
+
+	// assert the archive set can't be removed while a bucket is assigned to it
+	if err := b.UpdateAutopilotConfig(ctx, client.WithContractSets(nil)); err == nil {
+		t.Fatal("expected update to fail")
+	}
+
+	// upload an object to the test bucket and to a bucket that uses the
+	// default set
+	tt.OK(b.CreateBucket(ctx, "other", api.CreateBucketOptions{}))
+	for _, bucket := range []string{testBucket, "other"} {
+		tt.OKAll(w.UploadObject(ctx, bytes.NewReader(frand.Bytes(64)), bucket, "foo", api.UploadObjectOptions{}))
+	}
+
+	// assert the objects are stored on the hosts of their bucket's set
+	for bucket, hosts := range map[string]map[types.PublicKey]struct{}{
+		testBucket: archiveHosts,
+		"other":    defaultHosts,
+	} {
+		res, err := b.Object(ctx, bucket, "foo", api.GetObjectOptions{})
+		tt.OK(err)
+		for _, slab := range res.Object.Slabs {
+			for _, shard := range slab.Shards {
+				for hk := range shard.Contracts {
+					if _, ok := hosts[hk]; !ok {
+						t.Fatalf("shard of object in bucket %q stored on host %v outside of its contract set", bucket, hk)
+					}
+				}
+			}
+		}
+	}
+}
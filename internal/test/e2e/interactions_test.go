@@ -6,6 +6,9 @@ import (
 	"time"
 
 	rhpv4 "go.sia.tech/core/rhp/v4"
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/utils"
 )
 
 func TestInteractions(t *testing.T) {
@@ -54,4 +57,27 @@ func TestInteractions(t *testing.T) {
 	if ts+1 != h.Interactions.TotalScans {
 		t.Fatal("expected one new scan")
 	}
+
+	// benchmark the host manually
+	resp, err := b.BenchmarkHost(context.Background(), h1.PublicKey(), time.Minute)
+	tt.OK(err)
+	tt.OK(resp.Error())
+	if resp.UploadThroughput == 0 || resp.DownloadThroughput == 0 || resp.TimeToFirstByte == 0 {
+		t.Fatalf("unexpected benchmark %+v", resp)
+	}
+
+	// assert the benchmark was recorded
+	h, err = b.Host(context.Background(), h1.PublicKey())
+	tt.OK(err)
+	if !h.Benchmark.Success || time.Since(h.Benchmark.Timestamp) > time.Minute {
+		t.Fatalf("unexpected benchmark %+v", h.Benchmark)
+	} else if h.Benchmark.UploadThroughput != resp.UploadThroughput || h.Benchmark.DownloadThroughput != resp.DownloadThroughput {
+		t.Fatalf("unexpected benchmark %+v", h.Benchmark)
+	}
+
+	// assert benchmarking an unknown host fails
+	_, err = b.BenchmarkHost(context.Background(), types.PublicKey{1}, time.Minute)
+	if !utils.IsErr(err, api.ErrHostNotFound) {
+		t.Fatal("unexpected error", err)
+	}
 }
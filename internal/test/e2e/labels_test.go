@@ -0,0 +1,103 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/bus/client"
+	"go.sia.tech/renterd/v2/internal/utils"
+)
+
+func TestHostLabels(t *testing.T) {
+	ctx := context.Background()
+
+	// create a new test cluster
+	cluster := newTestCluster(t, testClusterOptions{
+		hosts: 1,
+	})
+	defer cluster.Shutdown()
+	b := cluster.Bus
+	tt := cluster.tt
+
+	// fetch the contract
+	contracts, err := b.Contracts(ctx, api.ContractsOpts{FilterMode: api.ContractFilterModeGood})
+	tt.OK(err)
+	if len(contracts) != 1 {
+		t.Fatalf("unexpected number of contracts, %v != 1", len(contracts))
+	}
+	hk1 := contracts[0].HostKey
+
+	// assert invalid labels are rejected
+	if err := b.UpdateHostLabels(ctx, hk1, []string{" "}, ""); !utils.IsErr(err, api.ErrInvalidHostLabel) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// assert labelling an unknown host fails
+	if err := b.UpdateHostLabels(ctx, types.PublicKey{1}, []string{"foo"}, ""); !utils.IsErr(err, api.ErrHostNotFound) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// label the host and assert the labels are normalized
+	tt.OK(b.UpdateHostLabels(ctx, hk1, []string{" do-not-renew", "partner-dc", "partner-dc"}, "operated by a partner"))
+	h, err := b.Host(ctx, hk1)
+	tt.OK(err)
+	if !reflect.DeepEqual(h.Labels, []string{"do-not-renew", "partner-dc"}) {
+		t.Fatal("unexpected labels", h.Labels)
+	} else if h.Notes != "operated by a partner" {
+		t.Fatal("unexpected notes", h.Notes)
+	}
+
+	// disable the autopilot and configure it not to form new contracts with
+	// hosts labelled 'do-not-renew'
+	ap, err := b.AutopilotConfig(ctx)
+	tt.OK(err)
+	hosts := ap.Hosts
+	hosts.LabelRules = &api.HostLabelRules{NoNewContracts: []string{"do-not-renew"}}
+	tt.OK(b.UpdateAutopilotConfig(ctx, client.WithAutopilotEnabled(false), client.WithHostsConfig(hosts)))
+
+	// add two more hosts, label one of them
+	h2 := cluster.NewHost()
+	cluster.AddHost(h2)
+	tt.OK(b.UpdateHostLabels(ctx, h2.PublicKey(), []string{"do-not-renew"}, ""))
+	h3 := cluster.NewHost()
+	cluster.AddHost(h3)
+
+	// assert hosts can be filtered by label
+	labelled, err := b.Hosts(ctx, api.HostOptions{Labels: []string{"do-not-renew"}})
+	tt.OK(err)
+	if len(labelled) != 2 {
+		t.Fatalf("unexpected number of labelled hosts, %v != 2", len(labelled))
+	}
+
+	// enable the autopilot and wait for a contract with the unlabelled host
+	tt.OK(b.UpdateAutopilotConfig(ctx, client.WithAutopilotEnabled(true)))
+	tt.Retry(100, 100*time.Millisecond, func() error {
+		cluster.MineBlocks(1)
+		contracts, err = b.Contracts(ctx, api.ContractsOpts{FilterMode: api.ContractFilterModeGood})
+		tt.OK(err)
+		for _, c := range contracts {
+			if c.HostKey == h3.PublicKey() {
+				return nil
+			}
+		}
+		return errors.New("no contract with unlabelled host")
+	})
+
+	// assert the contract with the labelled host was kept and no contract was
+	// formed with the new labelled host
+	var found bool
+	for _, c := range contracts {
+		if c.HostKey == h2.PublicKey() {
+			t.Fatal("unexpected contract with labelled host")
+		}
+		found = found || c.HostKey == hk1
+	}
+	if !found {
+		t.Fatalf("expected contract with %v to be kept", hk1)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"testing"
 	"time"
@@ -62,6 +63,22 @@ func TestMetrics(t *testing.T) {
 			return errors.New("no contract prune metrics")
 		}
 
+		// check host metrics
+		hnm, err := b.HostNetworkMetrics(context.Background(), start, 10, time.Minute, api.HostNetworkMetricsQueryOpts{})
+		tt.OK(err)
+		if len(hnm) == 0 {
+			return errors.New("no host network metrics")
+		} else if hnm[0].Hosts == 0 || hnm[0].Usable == 0 {
+			return fmt.Errorf("unexpected host network metric %+v", hnm[0])
+		}
+		hm, err := b.HostMetrics(context.Background(), start, 10, time.Minute, api.HostMetricsQueryOpts{HostKey: cluster.hosts[0].PublicKey()})
+		tt.OK(err)
+		if len(hm) == 0 {
+			return errors.New("no host metrics")
+		} else if !hm[0].Usable || hm[0].Score == 0 || hm[0].StoragePrice.IsZero() {
+			return fmt.Errorf("unexpected host metric %+v", hm[0])
+		}
+
 		// check wallet metrics
 		wm, err := b.WalletMetrics(context.Background(), start, 10, time.Minute, api.WalletMetricsQueryOpts{})
 		tt.OK(err)
@@ -15,6 +15,7 @@ import (
 	"go.sia.tech/core/types"
 	"go.sia.tech/renterd/v2/alerts"
 	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/bus/client"
 	"go.sia.tech/renterd/v2/internal/test"
 	"lukechampine.com/frand"
 )
@@ -163,3 +164,78 @@ func TestMigrations(t *testing.T) {
 		t.Fatal("unexpected", cmp.Diff(want, got))
 	}
 }
+
+func TestMigrationsEstimate(t *testing.T) {
+	// create a new test cluster with one extra host
+	rs := test.RedundancySettings
+	cfg := test.AutopilotConfig
+	cfg.Contracts.Amount = uint64(rs.TotalShards) + 1
+	cluster := newTestCluster(t, testClusterOptions{
+		autopilotConfig: &cfg,
+		hosts:           int(cfg.Contracts.Amount),
+	})
+	defer cluster.Shutdown()
+
+	// convenience variables
+	ap := cluster.Autopilot
+	b := cluster.Bus
+	w := cluster.Worker
+	tt := cluster.tt
+
+	// add an object
+	data := make([]byte, rhpv4.SectorSize)
+	frand.Read(data)
+	tt.OKAll(w.UploadObject(context.Background(), bytes.NewReader(data), testBucket, t.Name(), api.UploadObjectOptions{}))
+
+	// assert there's nothing to migrate
+	estimate, err := ap.EstimateMigrations(context.Background())
+	tt.OK(err)
+	if estimate.Slabs != 0 || estimate.Unrepairable != 0 || len(estimate.Buckets) != 0 {
+		t.Fatalf("unexpected estimate %+v", estimate)
+	}
+
+	// disable the autopilot and wait until it's done migrating
+	tt.OK(b.UpdateAutopilotConfig(context.Background(), client.WithAutopilotEnabled(false)))
+	tt.Retry(100, 100*time.Millisecond, func() error {
+		state, err := ap.State(context.Background())
+		if err != nil {
+			return err
+		} else if state.Migrating {
+			return errors.New("autopilot is still migrating")
+		}
+		return nil
+	})
+
+	// mark the contract of one of the hosts storing a shard as bad
+	res, err := b.Object(context.Background(), testBucket, t.Name(), api.GetObjectOptions{})
+	tt.OK(err)
+	var fcid types.FileContractID
+	for _, fcids := range res.Object.Slabs[0].Shards[0].Contracts {
+		fcid = fcids[0]
+	}
+
+	// assert the estimate includes the migration of one shard, the estimate
+	// doesn't refresh the health so we do that ourselves
+	tt.Retry(100, 100*time.Millisecond, func() error {
+		tt.OK(b.UpdateContractUsability(context.Background(), fcid, api.ContractUsabilityBad))
+		tt.OK(b.RefreshHealth(context.Background()))
+		estimate, err = ap.EstimateMigrations(context.Background())
+		if err != nil {
+			return err
+		} else if estimate.Slabs != 1 {
+			return fmt.Errorf("expected 1 slab to migrate, got %d", estimate.Slabs)
+		}
+		return nil
+	})
+	if estimate.Unrepairable != 0 {
+		t.Fatal("unexpected unrepairable slabs", estimate.Unrepairable)
+	} else if estimate.DownloadBytes != uint64(rs.MinShards)*rhpv4.SectorSize {
+		t.Fatal("unexpected download bytes", estimate.DownloadBytes)
+	} else if estimate.UploadBytes != rhpv4.SectorSize {
+		t.Fatal("unexpected upload bytes", estimate.UploadBytes)
+	} else if estimate.DownloadCost.IsZero() || estimate.UploadCost.IsZero() {
+		t.Fatalf("expected non-zero costs, %v %v", estimate.DownloadCost, estimate.UploadCost)
+	} else if len(estimate.Buckets) != 1 || estimate.Buckets[testBucket] != estimate.MigrationEstimate {
+		t.Fatalf("unexpected bucket breakdown %+v", estimate.Buckets)
+	}
+}
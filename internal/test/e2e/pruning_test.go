@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	rhpv4 "go.sia.tech/core/rhp/v4"
 	"go.sia.tech/core/types"
 	"go.sia.tech/renterd/v2/api"
 	"go.sia.tech/renterd/v2/bus/client"
@@ -126,6 +127,93 @@ func TestContractPruning(t *testing.T) {
 	assertPrunableData(false)
 }
 
+func TestContractReconciliation(t *testing.T) {
+	// create a cluster
+	cluster := newTestCluster(t, testClusterOptions{
+		hosts: test.RedundancySettings.TotalShards,
+	})
+	defer cluster.Shutdown()
+
+	// convenience variables
+	w := cluster.Worker
+	b := cluster.Bus
+	a := cluster.Autopilot
+	tt := cluster.tt
+
+	// upload an object
+	tt.OKAll(w.UploadObject(context.Background(), bytes.NewReader([]byte(t.Name())), testBucket, t.Name(), api.UploadObjectOptions{}))
+
+	// assert the database and the hosts are in sync
+	res, err := a.ReconcileContracts(context.Background())
+	tt.OK(err)
+	if len(res.Contracts) != test.RedundancySettings.TotalShards {
+		t.Fatalf("expected %d contracts, got %d", test.RedundancySettings.TotalShards, len(res.Contracts))
+	} else if res.Missing != 0 || res.Prunable != 0 {
+		t.Fatalf("unexpected result %+v", res)
+	}
+
+	// link the first sector to a contract with a host that doesn't store it,
+	// this simulates the database drifting apart from the hosts
+	obj, err := b.Object(context.Background(), testBucket, t.Name(), api.GetObjectOptions{})
+	tt.OK(err)
+	slab := obj.Slabs[0].Slab
+	sector := slab.Shards[0]
+	var contract api.ContractReconcileResult
+	for _, c := range res.Contracts {
+		if _, ok := sector.Contracts[c.HostKey]; !ok {
+			contract = c
+			break
+		}
+	}
+	if contract.ContractID == (types.FileContractID{}) {
+		t.Fatal("no contract found")
+	}
+	tt.OK(b.UpdateSlab(context.Background(), slab.EncryptionKey, []api.UploadedSector{{ContractID: contract.ContractID, Root: sector.Root}}))
+
+	// assert the sector is reported as missing
+	res, err = a.ReconcileContracts(context.Background())
+	tt.OK(err)
+	if res.Missing != rhpv4.SectorSize || res.Prunable != 0 {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	for _, c := range res.Contracts {
+		if c.Error != "" {
+			t.Fatal(c.Error)
+		} else if c.ContractID == contract.ContractID && c.Missing != rhpv4.SectorSize {
+			t.Fatalf("expected contract %v to be missing a sector, got %+v", c.ContractID, c)
+		} else if c.ContractID != contract.ContractID && c.Missing != 0 {
+			t.Fatalf("expected contract %v to be in sync, got %+v", c.ContractID, c)
+		}
+	}
+
+	// assert the sector was marked as lost
+	h, err := b.Host(context.Background(), contract.HostKey)
+	tt.OK(err)
+	if h.Interactions.LostSectors != 1 {
+		t.Fatalf("expected 1 lost sector, got %d", h.Interactions.LostSectors)
+	}
+	roots, err := b.ContractRoots(context.Background(), contract.ContractID)
+	tt.OK(err)
+	for _, root := range roots {
+		if root == sector.Root {
+			t.Fatal("expected sector to be unlinked from the contract")
+		}
+	}
+
+	// delete the object and assert its sectors are reported as prunable
+	tt.OK(b.DeleteObject(context.Background(), testBucket, t.Name()))
+	tt.Retry(100, 100*time.Millisecond, func() error {
+		res, err = a.ReconcileContracts(context.Background())
+		tt.OK(err)
+		if res.Missing != 0 {
+			return fmt.Errorf("unexpected missing data %d", res.Missing)
+		} else if res.Prunable != uint64(test.RedundancySettings.TotalShards)*rhpv4.SectorSize {
+			return fmt.Errorf("unexpected prunable data %d", res.Prunable)
+		}
+		return nil
+	})
+}
+
 func TestSectorPruning(t *testing.T) {
 	// create a cluster
 	opts := clusterOptsDefault
@@ -0,0 +1,82 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/test"
+	"go.sia.tech/renterd/v2/internal/utils"
+)
+
+func TestSpendingReport(t *testing.T) {
+	ctx := context.Background()
+
+	// create a new test cluster
+	cluster := newTestCluster(t, testClusterOptions{
+		hosts: test.RedundancySettings.TotalShards,
+	})
+	defer cluster.Shutdown()
+	b := cluster.Bus
+	tt := cluster.tt
+
+	// upload some data
+	w := cluster.Worker
+	tt.OKAll(w.UploadObject(ctx, bytes.NewReader(make([]byte, 100)), testBucket, "foo", api.UploadObjectOptions{}))
+
+	// sum up the contract prices
+	contracts, err := b.Contracts(ctx, api.ContractsOpts{FilterMode: api.ContractFilterModeAll})
+	tt.OK(err)
+	var contractPrice types.Currency
+	for _, c := range contracts {
+		contractPrice = contractPrice.Add(c.ContractPrice)
+	}
+
+	// fetch the report and assert the contract prices are accounted for
+	start := time.Now().Add(-time.Hour)
+	report, err := b.SpendingReport(ctx, start, 2, time.Hour, api.SpendingReportOpts{})
+	tt.OK(err)
+	if !report.Total.ContractPrice.Equals(contractPrice) {
+		t.Fatalf("unexpected contract price %v != %v", report.Total.ContractPrice, contractPrice)
+	} else if len(report.Hosts) != len(contracts) {
+		t.Fatalf("unexpected number of hosts %v != %v", len(report.Hosts), len(contracts))
+	} else if len(report.Periods) != 2 {
+		t.Fatalf("unexpected number of periods %v", len(report.Periods))
+	} else if report.Currency != "" || report.Total.TotalFiat != 0 {
+		t.Fatal("unexpected fiat valuation")
+	}
+
+	// assert the CSV export contains a row per item, spending might be
+	// recorded in between fetching the report and the export so we retry
+	tt.Retry(10, 100*time.Millisecond, func() error {
+		report, err := b.SpendingReport(ctx, start, 2, time.Hour, api.SpendingReportOpts{})
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		if err := b.SpendingReportCSV(ctx, &buf, start, 2, time.Hour, api.SpendingReportOpts{}); err != nil {
+			return err
+		}
+		records, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			return err
+		} else if len(records) != len(report.Items)+1 {
+			return fmt.Errorf("unexpected number of records %v != %v", len(records), len(report.Items)+1)
+		} else if records[0][0] != "period" || records[1][1] != report.Items[0].HostKey.String() {
+			return fmt.Errorf("unexpected records %v", records[:2])
+		}
+		return nil
+	})
+
+	// assert a fiat valuation requires the explorer
+	_, err = b.SpendingReport(ctx, start, 2, time.Hour, api.SpendingReportOpts{Currency: "usd"})
+	if !utils.IsErr(err, api.ErrExplorerDisabled) {
+		t.Fatal("unexpected error", err)
+	}
+}
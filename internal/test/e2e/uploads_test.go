@@ -3,6 +3,7 @@ package e2e
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"go.sia.tech/core/types"
 	"go.sia.tech/renterd/v2/api"
 	"go.sia.tech/renterd/v2/internal/test"
+	"go.sia.tech/renterd/v2/internal/utils"
 	"lukechampine.com/frand"
 )
 
@@ -44,6 +46,23 @@ func (r *blockedReader) Read(buf []byte) (n int, err error) {
 	return
 }
 
+// failingReader returns an error once the given number of bytes were read,
+// simulating a dropped connection.
+type failingReader struct {
+	*bytes.Reader
+	failAfter int64
+}
+
+func (r *failingReader) Read(buf []byte) (int, error) {
+	read := r.Size() - int64(r.Len())
+	if read >= r.failAfter {
+		return 0, errors.New("connection dropped")
+	} else if remaining := r.failAfter - read; int64(len(buf)) > remaining {
+		buf = buf[:remaining]
+	}
+	return r.Reader.Read(buf)
+}
+
 func TestUploadingSectorsCache(t *testing.T) {
 	cluster := newTestCluster(t, testClusterOptions{
 		hosts: test.RedundancySettings.TotalShards,
@@ -154,3 +173,236 @@ func TestUploadingSectorsCache(t *testing.T) {
 		}
 	}
 }
+
+func TestTusUploads(t *testing.T) {
+	cluster := newTestCluster(t, testClusterOptions{
+		hosts:         test.RedundancySettings.TotalShards,
+		uploadPacking: true,
+	})
+	defer cluster.Shutdown()
+
+	b := cluster.Bus
+	w := cluster.Worker
+	tt := cluster.tt
+
+	// create a resumable upload
+	data := frand.Bytes(3 * 64)
+	id, err := w.CreateTusUpload(context.Background(), testBucket, "/foo", uint64(len(data)), api.CreateTusUploadOptions{
+		Metadata: api.ObjectUserMetadata{"foo": "bar"},
+	})
+	tt.OK(err)
+
+	// upload the first chunk
+	offset, err := w.UploadTusChunk(context.Background(), bytes.NewReader(data[:64]), id, 0)
+	tt.OK(err)
+	if offset != 64 {
+		t.Fatal("unexpected offset", offset)
+	}
+
+	// uploading at the wrong offset should fail
+	_, err = w.UploadTusChunk(context.Background(), bytes.NewReader(data[64:128]), id, 0)
+	if !utils.IsErr(err, api.ErrTusUploadOffsetMismatch) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// resume the upload from the offset reported by the worker
+	offset, length, err := w.TusUploadOffset(context.Background(), id)
+	tt.OK(err)
+	if offset != 64 || length != uint64(len(data)) {
+		t.Fatal("unexpected offset or length", offset, length)
+	}
+	offset, err = w.UploadTusChunk(context.Background(), bytes.NewReader(data[offset:128]), id, offset)
+	tt.OK(err)
+	offset, err = w.UploadTusChunk(context.Background(), bytes.NewReader(data[offset:]), id, offset)
+	tt.OK(err)
+	if offset != uint64(len(data)) {
+		t.Fatal("unexpected offset", offset)
+	}
+
+	// the upload should be completed, the offset is still reported
+	offset, length, err = w.TusUploadOffset(context.Background(), id)
+	tt.OK(err)
+	if offset != length {
+		t.Fatal("unexpected offset", offset, length)
+	}
+	var buf bytes.Buffer
+	tt.OK(w.DownloadObject(context.Background(), &buf, testBucket, "/foo", api.DownloadObjectOptions{}))
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("data mismatch")
+	}
+	obj, err := b.Object(context.Background(), testBucket, "/foo", api.GetObjectOptions{})
+	tt.OK(err)
+	if obj.Metadata["foo"] != "bar" {
+		t.Fatal("unexpected metadata", obj.Metadata)
+	}
+
+	// upload a chunk spanning multiple slabs but drop the connection halfway
+	// through the second slab, the first slab should be committed
+	slabSize := test.RedundancySettings.SlabSizeNoRedundancy()
+	data = frand.Bytes(int(2 * slabSize))
+	id, err = w.CreateTusUpload(context.Background(), testBucket, "/baz", uint64(len(data)), api.CreateTusUploadOptions{})
+	tt.OK(err)
+	_, err = w.UploadTusChunk(context.Background(), &failingReader{Reader: bytes.NewReader(data), failAfter: int64(slabSize + slabSize/2)}, id, 0)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	tt.Retry(100, 100*time.Millisecond, func() error {
+		offset, _, err := w.TusUploadOffset(context.Background(), id)
+		if err != nil {
+			return err
+		} else if offset != slabSize {
+			return fmt.Errorf("unexpected offset %d != %d", offset, slabSize)
+		}
+		return nil
+	})
+
+	// resume the upload
+	offset, err = w.UploadTusChunk(context.Background(), bytes.NewReader(data[slabSize:]), id, slabSize)
+	tt.OK(err)
+	if offset != uint64(len(data)) {
+		t.Fatal("unexpected offset", offset)
+	}
+	buf.Reset()
+	tt.OK(w.DownloadObject(context.Background(), &buf, testBucket, "/baz", api.DownloadObjectOptions{}))
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("data mismatch")
+	}
+
+	// terminate an upload
+	id, err = w.CreateTusUpload(context.Background(), testBucket, "/bar", 10, api.CreateTusUploadOptions{})
+	tt.OK(err)
+	tt.OK(w.TerminateTusUpload(context.Background(), id))
+	if _, err := b.MultipartUpload(context.Background(), id); !utils.IsErr(err, api.ErrMultipartUploadNotFound) {
+		t.Fatal("unexpected error", err)
+	}
+}
+
+func TestUploadPinnedBucket(t *testing.T) {
+	// form contracts with more hosts than we need to upload a slab, that way
+	// the pinned group is a strict subset of the hosts we have contracts with
+	nHosts := test.RedundancySettings.TotalShards + 2
+	cfg := test.AutopilotConfig
+	cfg.Contracts.Amount = uint64(nHosts)
+	cluster := newTestCluster(t, testClusterOptions{
+		autopilotConfig: &cfg,
+		hosts:           nHosts,
+		uploadPacking:   true,
+	})
+	defer cluster.Shutdown()
+
+	b := cluster.Bus
+	w := cluster.Worker
+	tt := cluster.tt
+
+	// fetch contracts
+	contracts := cluster.WaitForContracts()
+	if len(contracts) != nHosts {
+		t.Fatalf("unexpected number of contracts, %v != %v", len(contracts), nHosts)
+	}
+	var hosts []types.PublicKey
+	for _, c := range contracts {
+		hosts = append(hosts, c.HostKey)
+	}
+
+	// pin the test bucket to a group that's too small to upload to
+	tt.OK(b.UpdatePlacementSettings(context.Background(), api.PlacementSettings{
+		Groups:  map[string][]types.PublicKey{"eu": hosts[:test.RedundancySettings.TotalShards-1]},
+		Buckets: map[string]string{testBucket: "eu"},
+	}))
+
+	// assert the upload fails, even though it would fit in a packed slab
+	data := frand.Bytes(64)
+	if _, err := w.UploadObject(context.Background(), bytes.NewReader(data), testBucket, "foo", api.UploadObjectOptions{}); err == nil {
+		t.Fatal("expected upload to fail")
+	}
+
+	// assert other buckets are unaffected
+	tt.OK(b.CreateBucket(context.Background(), "other", api.CreateBucketOptions{}))
+	tt.OKAll(w.UploadObject(context.Background(), bytes.NewReader(data), "other", "foo", api.UploadObjectOptions{}))
+
+	// assert settings referencing unknown groups are rejected
+	if err := b.UpdatePlacementSettings(context.Background(), api.PlacementSettings{
+		Buckets: map[string]string{testBucket: "us"},
+	}); err == nil {
+		t.Fatal("expected update to fail")
+	}
+
+	// pin the test bucket to just enough hosts to upload a slab
+	group := make(map[types.PublicKey]struct{})
+	for _, hk := range hosts[:test.RedundancySettings.TotalShards] {
+		group[hk] = struct{}{}
+	}
+	tt.OK(b.UpdatePlacementSettings(context.Background(), api.PlacementSettings{
+		Groups:  map[string][]types.PublicKey{"eu": hosts[:test.RedundancySettings.TotalShards]},
+		Buckets: map[string]string{testBucket: "eu"},
+	}))
+
+	// assert the upload succeeds, bypasses upload packing and only uses the
+	// hosts in the group
+	tt.OKAll(w.UploadObject(context.Background(), bytes.NewReader(data), testBucket, "foo", api.UploadObjectOptions{}))
+	res, err := b.Object(context.Background(), testBucket, "foo", api.GetObjectOptions{})
+	tt.OK(err)
+	if len(res.Object.Slabs) != 1 {
+		t.Fatal("expected 1 slab", len(res.Object.Slabs))
+	} else if res.Object.Slabs[0].IsPartial() {
+		t.Fatal("expected slab to not be partial")
+	}
+	used := make(map[types.PublicKey]struct{})
+	for _, shard := range res.Object.Slabs[0].Shards {
+		for hk := range shard.Contracts {
+			if _, ok := group[hk]; !ok {
+				t.Fatal("shard stored on host outside of the pinned group", hk)
+			}
+			used[hk] = struct{}{}
+		}
+	}
+	if len(used) != len(group) {
+		t.Fatalf("expected all %d hosts of the group to be used, got %d", len(group), len(used))
+	}
+}
+
+func TestUploadDiversity(t *testing.T) {
+	cluster := newTestCluster(t, testClusterOptions{
+		hosts: test.RedundancySettings.TotalShards,
+	})
+	defer cluster.Shutdown()
+
+	b := cluster.Bus
+	w := cluster.Worker
+	tt := cluster.tt
+
+	// assert rules that require a GeoIP database are rejected
+	if err := b.UpdatePlacementSettings(context.Background(), api.PlacementSettings{
+		Diversity: api.DiversityRules{MaxShardsPerCountry: 1},
+	}); !utils.IsErr(err, api.ErrGeoIPDisabled) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// limit the number of shards per subnet
+	tt.OK(b.UpdatePlacementSettings(context.Background(), api.PlacementSettings{
+		Diversity: api.DiversityRules{MaxShardsPerSubnet: 1},
+	}))
+	time.Sleep(testWorkerCfg().CacheExpiry) // expire cache
+
+	// assert the hosts are located
+	hosts, err := b.UsableHosts(context.Background())
+	tt.OK(err)
+	for _, h := range hosts {
+		if h.Location == nil || len(h.Location.Subnets) != 1 || h.Location.Subnets[0] != "127.0.0.0/16" {
+			t.Fatalf("unexpected location %+v", h.Location)
+		}
+	}
+
+	// assert the upload fails since all hosts are in the same subnet
+	data := frand.Bytes(64)
+	if _, err := w.UploadObject(context.Background(), bytes.NewReader(data), testBucket, "foo", api.UploadObjectOptions{}); err == nil {
+		t.Fatal("expected upload to fail")
+	}
+
+	// lift the limit and assert the upload succeeds
+	tt.OK(b.UpdatePlacementSettings(context.Background(), api.PlacementSettings{
+		Diversity: api.DiversityRules{MaxShardsPerSubnet: uint64(test.RedundancySettings.TotalShards)},
+	}))
+	time.Sleep(testWorkerCfg().CacheExpiry) // expire cache
+	tt.OKAll(w.UploadObject(context.Background(), bytes.NewReader(data), testBucket, "foo", api.UploadObjectOptions{}))
+}
@@ -147,8 +147,21 @@ func (mm *MemoryManager) AcquireMemory(ctx context.Context, amt uint64) memory.M
 	return &Memory{}
 }
 
+func (mm *MemoryManager) TryAcquireMemory(amt uint64) memory.Memory {
+	select {
+	case <-mm.memBlockChan:
+		return &Memory{}
+	default:
+		return nil
+	}
+}
+
 type settingStoreMock struct{}
 
+func (*settingStoreMock) BandwidthSettings(context.Context) (api.BandwidthSettings, error) {
+	return api.BandwidthSettings{}, nil
+}
+
 func (*settingStoreMock) GougingParams(context.Context) (api.GougingParams, error) {
 	return api.GougingParams{}, nil
 }
@@ -303,10 +303,26 @@ func (os *ObjectStore) MultipartUpload(ctx context.Context, uploadID string) (re
 	return api.MultipartUpload{}, nil
 }
 
+func (os *ObjectStore) RecordObjectAccesses(ctx context.Context, records []api.ObjectAccessRecord) error {
+	return nil
+}
+
 func (os *ObjectStore) RemoveObjects(ctx context.Context, bucket, prefix string) error {
 	return nil
 }
 
+func (os *ObjectStore) CreateTusUpload(ctx context.Context, bucket, key string, length uint64, opts api.CreateTusUploadOptions) (api.TusUpload, error) {
+	return api.TusUpload{}, nil
+}
+
+func (os *ObjectStore) TusUpload(ctx context.Context, uploadID string) (api.TusUpload, error) {
+	return api.TusUpload{}, nil
+}
+
+func (os *ObjectStore) UpdateTusUploadOffset(ctx context.Context, uploadID string, offset, length uint64) (api.TusUpload, error) {
+	return api.TusUpload{}, nil
+}
+
 func (os *ObjectStore) totalSlabBufferSize() (total int) {
 	for _, p := range os.partials {
 		if time.Now().After(p.lockedUntil) {
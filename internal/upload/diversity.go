@@ -0,0 +1,114 @@
+package upload
+
+import (
+	"fmt"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+)
+
+type (
+	// diversity enforces the diversity rules for the shards of a single slab.
+	// It keeps track of the sectors that are uploaded, or are being uploaded,
+	// to every location and only allows uploading a sector to a host if none
+	// of the host's locations would exceed its limit.
+	diversity struct {
+		hosts    map[types.PublicKey][]locationLimit
+		existing map[string]uint64
+		sectors  map[string]map[int]int
+	}
+
+	locationLimit struct {
+		key   string
+		limit uint64
+	}
+)
+
+// newDiversity returns a tracker that enforces the given rules for the hosts
+// at the given locations. The existing locations are the locations of the
+// hosts that already store a shard of the slab. If the rules are not enabled
+// nil is returned, which is a valid tracker that doesn't restrict any host.
+func newDiversity(rules api.DiversityRules, locations map[types.PublicKey]*api.HostLocation, existing []api.HostLocation) *diversity {
+	if !rules.Enabled() {
+		return nil
+	}
+
+	d := &diversity{
+		hosts:    make(map[types.PublicKey][]locationLimit),
+		existing: make(map[string]uint64),
+		sectors:  make(map[string]map[int]int),
+	}
+	for hk, loc := range locations {
+		if loc != nil {
+			d.hosts[hk] = locationLimits(rules, *loc)
+		}
+	}
+	for _, loc := range existing {
+		for _, ll := range locationLimits(rules, loc) {
+			d.existing[ll.key]++
+		}
+	}
+	return d
+}
+
+// canUpload returns whether the sector with the given index can be uploaded
+// to the given host.
+func (d *diversity) canUpload(hk types.PublicKey, idx int) bool {
+	if d == nil {
+		return true
+	}
+	for _, ll := range d.hosts[hk] {
+		sectors := d.sectors[ll.key]
+		if _, reserved := sectors[idx]; reserved {
+			continue // the sector already counts towards the limit
+		} else if d.existing[ll.key]+uint64(len(sectors)) >= ll.limit {
+			return false
+		}
+	}
+	return true
+}
+
+// reserve marks the sector with the given index as being uploaded to the
+// given host.
+func (d *diversity) reserve(hk types.PublicKey, idx int) {
+	if d == nil {
+		return
+	}
+	for _, ll := range d.hosts[hk] {
+		if _, ok := d.sectors[ll.key]; !ok {
+			d.sectors[ll.key] = make(map[int]int)
+		}
+		d.sectors[ll.key][idx]++
+	}
+}
+
+// release undoes a reservation, it's called when the upload of the sector to
+// the given host failed or turned out to be redundant.
+func (d *diversity) release(hk types.PublicKey, idx int) {
+	if d == nil {
+		return
+	}
+	for _, ll := range d.hosts[hk] {
+		sectors := d.sectors[ll.key]
+		if sectors[idx] <= 1 {
+			delete(sectors, idx)
+		} else {
+			sectors[idx]--
+		}
+	}
+}
+
+func locationLimits(rules api.DiversityRules, loc api.HostLocation) (limits []locationLimit) {
+	if rules.MaxShardsPerCountry > 0 && loc.Country != "" {
+		limits = append(limits, locationLimit{key: "country:" + loc.Country, limit: rules.MaxShardsPerCountry})
+	}
+	if rules.MaxShardsPerASN > 0 && loc.ASN != 0 {
+		limits = append(limits, locationLimit{key: fmt.Sprintf("asn:%d", loc.ASN), limit: rules.MaxShardsPerASN})
+	}
+	if rules.MaxShardsPerSubnet > 0 {
+		for _, subnet := range loc.Subnets {
+			limits = append(limits, locationLimit{key: "subnet:" + subnet, limit: rules.MaxShardsPerSubnet})
+		}
+	}
+	return
+}
@@ -0,0 +1,135 @@
+package upload
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+)
+
+type (
+	// progress tracks the progress of an object upload, it allows for
+	// cancelling the upload.
+	progress struct {
+		id        api.UploadID
+		params    Parameters
+		startedAt time.Time
+		cancel    context.CancelFunc
+
+		bytesReceived atomic.Uint64
+		slabsUploaded atomic.Uint64
+
+		mu    sync.Mutex
+		hosts map[types.PublicKey]uint64
+	}
+
+	// progressReader counts the bytes read from the underlying reader.
+	progressReader struct {
+		r io.Reader
+		p *progress
+	}
+)
+
+func newProgress(id api.UploadID, up Parameters, cancel context.CancelFunc) *progress {
+	return &progress{
+		id:        id,
+		params:    up,
+		startedAt: time.Now(),
+		cancel:    cancel,
+		hosts:     make(map[types.PublicKey]uint64),
+	}
+}
+
+func (pr *progressReader) Read(b []byte) (int, error) {
+	n, err := pr.r.Read(b)
+	pr.p.bytesReceived.Add(uint64(n))
+	return n, err
+}
+
+// trackSector registers a sector that was uploaded to the given host.
+func (p *progress) trackSector(hk types.PublicKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.hosts[hk]++
+}
+
+func (p *progress) info() api.UploadProgress {
+	up := api.UploadProgress{
+		ID:            p.id,
+		Bucket:        p.params.Bucket,
+		Key:           p.params.Key,
+		StartedAt:     api.TimeRFC3339(p.startedAt),
+		BytesReceived: p.bytesReceived.Load(),
+		SlabsUploaded: p.slabsUploaded.Load(),
+	}
+	if p.params.Multipart {
+		up.MultipartUploadID = p.params.UploadID
+		up.PartNumber = p.params.PartNumber
+	}
+
+	p.mu.Lock()
+	up.Hosts = make([]api.HostUploadProgress, 0, len(p.hosts))
+	for hk, n := range p.hosts {
+		up.Hosts = append(up.Hosts, api.HostUploadProgress{
+			HostKey:         hk,
+			SectorsUploaded: n,
+		})
+	}
+	p.mu.Unlock()
+
+	sort.Slice(up.Hosts, func(i, j int) bool {
+		return up.Hosts[i].SectorsUploaded > up.Hosts[j].SectorsUploaded
+	})
+	return up
+}
+
+// CancelUpload cancels the object upload with given id. Cancelling an upload
+// aborts all in-flight sector uploads and releases the memory that was
+// acquired for it, the object is never persisted and the sectors that were
+// uploaded already are eventually pruned.
+func (mgr *Manager) CancelUpload(id api.UploadID) error {
+	mgr.mu.Lock()
+	p, exists := mgr.uploads[id]
+	mgr.mu.Unlock()
+	if !exists {
+		return api.ErrUploadNotFound
+	}
+	p.cancel()
+	return nil
+}
+
+// Uploads returns the progress of all object uploads that are in progress,
+// sorted by the time they were started.
+func (mgr *Manager) Uploads() []api.UploadProgress {
+	mgr.mu.Lock()
+	uploads := make([]*progress, 0, len(mgr.uploads))
+	for _, p := range mgr.uploads {
+		uploads = append(uploads, p)
+	}
+	mgr.mu.Unlock()
+
+	sort.Slice(uploads, func(i, j int) bool {
+		return uploads[i].startedAt.Before(uploads[j].startedAt)
+	})
+	infos := make([]api.UploadProgress, len(uploads))
+	for i, p := range uploads {
+		infos[i] = p.info()
+	}
+	return infos
+}
+
+func (mgr *Manager) trackProgress(p *progress) (untrack func()) {
+	mgr.mu.Lock()
+	mgr.uploads[p.id] = p
+	mgr.mu.Unlock()
+	return func() {
+		mgr.mu.Lock()
+		delete(mgr.uploads, p.id)
+		mgr.mu.Unlock()
+	}
+}
@@ -10,6 +10,7 @@ import (
 	rhpv4 "go.sia.tech/core/rhp/v4"
 	"go.sia.tech/core/types"
 	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/host"
 	"go.sia.tech/renterd/v2/internal/hosts"
 	"go.sia.tech/renterd/v2/internal/locking"
 	rhp4 "go.sia.tech/renterd/v2/internal/rhp/v4"
@@ -47,6 +48,7 @@ type (
 		ResponseChan chan SectorUploadResp
 		Root         types.Hash256
 		Overdrive    bool
+		Priority     host.Priority
 	}
 
 	SectorUploadResp struct {
@@ -62,7 +64,7 @@ type (
 	}
 )
 
-func NewUploadRequest(ctx context.Context, data *[rhpv4.SectorSize]byte, idx int, respChan chan SectorUploadResp, root types.Hash256, overdrive bool) *SectorUploadReq {
+func NewUploadRequest(ctx context.Context, data *[rhpv4.SectorSize]byte, idx int, respChan chan SectorUploadResp, root types.Hash256, overdrive bool, priority host.Priority) *SectorUploadReq {
 	return &SectorUploadReq{
 		Ctx:          ctx,
 		Data:         data,
@@ -70,6 +72,7 @@ func NewUploadRequest(ctx context.Context, data *[rhpv4.SectorSize]byte, idx int
 		ResponseChan: respChan,
 		Root:         root,
 		Overdrive:    overdrive,
+		Priority:     priority,
 	}
 }
 
@@ -89,7 +92,7 @@ type (
 		expiry uint64
 		fcid   types.FileContractID
 		host   api.HostInfo
-		queue  []*queuedSectorUploadReq
+		queue  host.Queue[*queuedSectorUploadReq]
 
 		// stats related field
 		consecutiveFailures uint64
@@ -121,7 +124,6 @@ func New(ctx context.Context, cl locking.ContractLocker, cs ContractStore, hm ho
 		expiry: endHeight,
 		fcid:   fcid,
 		host:   hi,
-		queue:  make([]*queuedSectorUploadReq, 0),
 	}
 }
 
@@ -300,7 +302,7 @@ func (u *Uploader) Enqueue(req *SectorUploadReq) bool {
 
 	// enqueue the request
 	u.mu.Lock()
-	u.queue = append(u.queue, &queuedSectorUploadReq{SectorUploadReq: req})
+	u.queue.Push(req.Priority, &queuedSectorUploadReq{SectorUploadReq: req})
 	u.mu.Unlock()
 
 	// signal there's work
@@ -319,7 +321,7 @@ func (u *Uploader) Estimate() float64 {
 	}
 
 	// calculate estimated time
-	numSectors := float64(len(u.queue) + 1)
+	numSectors := float64(u.queue.Len() + 1)
 	return numSectors * estimateP90
 }
 
@@ -381,11 +383,8 @@ func (u *Uploader) pop() *queuedSectorUploadReq {
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
-	if len(u.queue) > 0 {
-		j := u.queue[0]
-		u.queue[0] = nil
-		u.queue = u.queue[1:]
-		return j
+	if req, ok := u.queue.Pop(); ok {
+		return req
 	}
 	return nil
 }
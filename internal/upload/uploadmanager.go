@@ -16,6 +16,7 @@ import (
 
 	"go.sia.tech/core/types"
 	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/host"
 	"go.sia.tech/renterd/v2/internal/hosts"
 	"go.sia.tech/renterd/v2/internal/memory"
 	"go.sia.tech/renterd/v2/internal/upload/uploader"
@@ -60,6 +61,7 @@ type (
 		ContractEndHeight   uint64
 		ContractID          types.FileContractID
 		ContractRenewedFrom types.FileContractID
+		ContractSet         string
 	}
 
 	Manager struct {
@@ -81,6 +83,7 @@ type (
 
 		mu        sync.Mutex
 		uploaders []*uploader.Uploader
+		uploads   map[api.UploadID]*progress
 	}
 
 	Stats struct {
@@ -96,7 +99,12 @@ type (
 	upload struct {
 		id          api.UploadID
 		allowed     map[types.PublicKey]struct{}
+		diversity   api.DiversityRules
+		existing    []api.HostLocation
+		locations   map[types.PublicKey]*api.HostLocation
 		os          ObjectStore
+		priority    host.Priority
+		progress    *progress
 		shutdownCtx context.Context
 	}
 
@@ -108,12 +116,14 @@ type (
 
 	slabUpload struct {
 		uploadID api.UploadID
+		priority host.Priority
 
 		maxOverdrive  uint64
 		lastOverdrive time.Time
 
 		sectors    []*sectorUpload
 		candidates []*candidate // sorted by upload estimate
+		diversity  *diversity
 
 		numLaunched    uint64
 		numInflight    uint64
@@ -121,7 +131,8 @@ type (
 		numUploaded    uint64
 		numSectors     uint64
 
-		mem memory.Memory
+		mem      memory.Memory
+		progress *progress
 
 		errs utils.HostErrorSet
 	}
@@ -170,6 +181,7 @@ func NewManager(ctx context.Context, uploadKey *utils.UploadKey, hm hosts.Manage
 		shutdownCtx: ctx,
 
 		uploaders: make([]*uploader.Uploader, 0),
+		uploads:   make(map[api.UploadID]*progress),
 	}
 }
 
@@ -220,6 +232,17 @@ func (mgr *Manager) Upload(ctx context.Context, r io.Reader, hosts []HostInfo, u
 	// create the object
 	o := object.NewObject(up.EC)
 
+	// create the upload
+	upload, err := mgr.newUpload(up.RS.TotalShards, hosts, up.BH, up.Diversity, host.PriorityUpload)
+	if err != nil {
+		return false, "", err
+	}
+
+	// track the progress of the upload, this allows for cancelling it
+	upload.progress = newProgress(upload.id, up, cancel)
+	defer mgr.trackProgress(upload.progress)()
+	r = &progressReader{r: r, p: upload.progress}
+
 	// create the md5 hasher for the etag
 	// NOTE: we use md5 since it's s3 compatible and clients expect it to be md5
 	hasher := md5.New()
@@ -234,12 +257,6 @@ func (mgr *Manager) Upload(ctx context.Context, r io.Reader, hosts []HostInfo, u
 		return false, "", err
 	}
 
-	// create the upload
-	upload, err := mgr.newUpload(up.RS.TotalShards, hosts, up.BH)
-	if err != nil {
-		return false, "", err
-	}
-
 	// track the upload in the bus
 	if err := mgr.os.TrackUpload(ctx, upload.id); err != nil {
 		return false, "", fmt.Errorf("failed to track upload '%v', err: %w", upload.id, err)
@@ -344,6 +361,7 @@ func (mgr *Manager) Upload(ctx context.Context, r io.Reader, hosts []HostInfo, u
 				return false, "", res.err
 			}
 			responses = append(responses, res)
+			upload.progress.slabsUploaded.Add(1)
 		}
 	}
 
@@ -387,7 +405,7 @@ func (mgr *Manager) Upload(ctx context.Context, r io.Reader, hosts []HostInfo, u
 	return
 }
 
-func (mgr *Manager) UploadPackedSlab(ctx context.Context, rs api.RedundancySettings, ps api.PackedSlab, mem memory.Memory, hosts []HostInfo, bh uint64) (err error) {
+func (mgr *Manager) UploadPackedSlab(ctx context.Context, rs api.RedundancySettings, ps api.PackedSlab, mem memory.Memory, hosts []HostInfo, bh uint64, dr api.DiversityRules) (err error) {
 	// cancel all in-flight requests when the upload is done
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -396,7 +414,7 @@ func (mgr *Manager) UploadPackedSlab(ctx context.Context, rs api.RedundancySetti
 	shards := encryptPartialSlab(ps.Data, ps.EncryptionKey, uint8(rs.MinShards), uint8(rs.TotalShards))
 
 	// create the upload
-	upload, err := mgr.newUpload(len(shards), hosts, bh)
+	upload, err := mgr.newUpload(len(shards), hosts, bh, dr, host.PriorityPackedSlabFlush)
 	if err != nil {
 		return err
 	}
@@ -444,16 +462,21 @@ func (mgr *Manager) UploadPackedSlab(ctx context.Context, rs api.RedundancySetti
 	return nil
 }
 
-func (mgr *Manager) UploadShards(ctx context.Context, s object.Slab, shards [][]byte, hosts []HostInfo, bh uint64, mem memory.Memory) (err error) {
+// UploadShards uploads the given shards of the slab to the given hosts. The
+// existing locations are the locations of the hosts that store the shards of
+// the slab that aren't being uploaded, they count towards the limits of the
+// diversity rules.
+func (mgr *Manager) UploadShards(ctx context.Context, s object.Slab, shards [][]byte, hosts []HostInfo, bh uint64, mem memory.Memory, dr api.DiversityRules, existing []api.HostLocation) (err error) {
 	// cancel all in-flight requests when the upload is done
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	// create the upload
-	upload, err := mgr.newUpload(len(shards), hosts, bh)
+	upload, err := mgr.newUpload(len(shards), hosts, bh, dr, host.PriorityMigration)
 	if err != nil {
 		return err
 	}
+	upload.existing = existing
 
 	// track the upload in the bus
 	if err := mgr.os.TrackUpload(ctx, upload.id); err != nil {
@@ -515,7 +538,7 @@ func (mgr *Manager) candidates(allowed map[types.PublicKey]struct{}) (candidates
 	return
 }
 
-func (mgr *Manager) newUpload(totalShards int, hosts []HostInfo, bh uint64) (*upload, error) {
+func (mgr *Manager) newUpload(totalShards int, hosts []HostInfo, bh uint64, dr api.DiversityRules, priority host.Priority) (*upload, error) {
 	mgr.mu.Lock()
 	defer mgr.mu.Unlock()
 
@@ -527,17 +550,22 @@ func (mgr *Manager) newUpload(totalShards int, hosts []HostInfo, bh uint64) (*up
 		return nil, fmt.Errorf("%v < %v: %w", len(hosts), totalShards, ErrUploadNotEnoughHosts)
 	}
 
-	// create allowed map
+	// create allowed and locations map
 	allowed := make(map[types.PublicKey]struct{})
+	locations := make(map[types.PublicKey]*api.HostLocation)
 	for _, h := range hosts {
 		allowed[h.PublicKey] = struct{}{}
+		locations[h.PublicKey] = h.Location
 	}
 
 	// create upload
 	return &upload{
 		id:          api.NewUploadID(),
 		allowed:     allowed,
+		diversity:   dr,
+		locations:   locations,
 		os:          mgr.os,
+		priority:    priority,
 		shutdownCtx: mgr.shutdownCtx,
 	}, nil
 }
@@ -628,12 +656,15 @@ func (u *upload) newSlabUpload(ctx context.Context, shards [][]byte, uploaders [
 	// create slab upload
 	return &slabUpload{
 		uploadID: u.id,
+		priority: u.priority,
 
 		maxOverdrive: maxOverdrive,
 		mem:          mem,
+		progress:     u.progress,
 
 		sectors:    sectors,
 		candidates: candidates,
+		diversity:  newDiversity(u.diversity, u.locations, u.existing),
 		numSectors: uint64(len(shards)),
 
 		errs: make(utils.HostErrorSet),
@@ -694,7 +725,7 @@ func (u *upload) uploadShards(ctx context.Context, shards [][]byte, candidates [
 	roots := make([]types.Hash256, len(shards))
 	for sI := range shards {
 		s := slab.sectors[sI]
-		requests[sI] = uploader.NewUploadRequest(s.ctx, s.data, sI, respChan, s.root, false)
+		requests[sI] = uploader.NewUploadRequest(s.ctx, s.data, sI, respChan, s.root, false, slab.priority)
 		roots[sI] = slab.sectors[sI].root
 	}
 
@@ -840,7 +871,7 @@ func (s *slabUpload) launch(req *uploader.SectorUploadReq) error {
 	// find candidate
 	var candidate *candidate
 	for _, c := range s.candidates {
-		if c.req != nil {
+		if c.req != nil || !s.diversity.canUpload(c.uploader.PublicKey(), req.Idx) {
 			continue
 		}
 		candidate = c
@@ -860,6 +891,7 @@ func (s *slabUpload) launch(req *uploader.SectorUploadReq) error {
 
 	// update the candidate
 	candidate.req = req
+	s.diversity.reserve(candidate.uploader.PublicKey(), req.Idx)
 	if req.Overdrive {
 		s.lastOverdrive = time.Now()
 		s.numOverdriving++
@@ -893,7 +925,7 @@ func (s *slabUpload) nextRequest(responseChan chan uploader.SectorUploadResp) *u
 		return nil
 	}
 
-	return uploader.NewUploadRequest(nextSector.ctx, nextSector.data, nextSector.index, responseChan, nextSector.root, true)
+	return uploader.NewUploadRequest(nextSector.ctx, nextSector.data, nextSector.index, responseChan, nextSector.root, true, s.priority)
 }
 
 func (s *slabUpload) receive(resp uploader.SectorUploadResp) (bool, bool) {
@@ -916,6 +948,7 @@ func (s *slabUpload) receive(resp uploader.SectorUploadResp) (bool, bool) {
 				break
 			}
 		}
+		s.diversity.release(resp.HK, req.Idx)
 		return false, false
 	}
 
@@ -924,6 +957,7 @@ func (s *slabUpload) receive(resp uploader.SectorUploadResp) (bool, bool) {
 	// result of the sector ctx being closed
 	if resp.Err != nil {
 		s.errs[resp.HK] = resp.Err
+		s.diversity.release(resp.HK, req.Idx)
 		return false, false
 	}
 
@@ -932,6 +966,9 @@ func (s *slabUpload) receive(resp uploader.SectorUploadResp) (bool, bool) {
 
 	// update uploaded sectors
 	s.numUploaded++
+	if s.progress != nil {
+		s.progress.trackSector(resp.HK)
+	}
 
 	// release memory
 	s.mem.ReleaseSome(rhpv4.SectorSize)
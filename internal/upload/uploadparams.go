@@ -21,7 +21,8 @@ type Parameters struct {
 	Packing  bool
 	MimeType string
 
-	Metadata api.ObjectUserMetadata
+	Metadata  api.ObjectUserMetadata
+	Diversity api.DiversityRules
 }
 
 func DefaultParameters(bucket, key string, rs api.RedundancySettings) Parameters {
@@ -56,6 +57,12 @@ func WithCustomEncryptionOffset(offset uint64) Option {
 	}
 }
 
+func WithDiversityRules(dr api.DiversityRules) Option {
+	return func(up *Parameters) {
+		up.Diversity = dr
+	}
+}
+
 func WithMimeType(mimeType string) Option {
 	return func(up *Parameters) {
 		up.MimeType = mimeType
@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.sia.tech/renterd/v2/api"
+	"go.uber.org/zap"
+)
+
+var (
+	_ AccessRecorder = (*objectAccessRecorder)(nil)
+)
+
+type (
+	AccessBus interface {
+		RecordObjectAccesses(ctx context.Context, records []api.ObjectAccessRecord) error
+	}
+
+	AccessRecorder interface {
+		Record(bucket, key string)
+		Stop(context.Context)
+	}
+
+	objectAccessRecorder struct {
+		flushInterval time.Duration
+
+		bus    AccessBus
+		logger *zap.SugaredLogger
+
+		mu       sync.Mutex
+		accesses map[objectID]api.ObjectAccessRecord
+
+		flushCtx   context.Context
+		flushTimer *time.Timer
+	}
+
+	objectID struct {
+		bucket string
+		key    string
+	}
+)
+
+func NewAccessRecorder(ctx context.Context, b AccessBus, flushInterval time.Duration, logger *zap.Logger) AccessRecorder {
+	logger = logger.Named("access")
+	return &objectAccessRecorder{
+		bus:    b,
+		logger: logger.Sugar(),
+
+		flushCtx:      ctx,
+		flushInterval: flushInterval,
+
+		accesses: make(map[objectID]api.ObjectAccessRecord),
+	}
+}
+
+// Record stores a read of the given object until it gets flushed to the bus.
+func (r *objectAccessRecorder) Record(bucket, key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// record the access
+	id := objectID{bucket, key}
+	rec, found := r.accesses[id]
+	if !found {
+		rec = api.ObjectAccessRecord{Bucket: bucket, Key: key}
+	}
+	rec.ReadCount++
+	rec.AccessedAt = api.TimeNow()
+	r.accesses[id] = rec
+
+	// schedule flush
+	if r.flushTimer == nil {
+		r.flushTimer = time.AfterFunc(r.flushInterval, r.flush)
+	}
+}
+
+// Stop stops the flush timer and flushes one last time.
+func (r *objectAccessRecorder) Stop(ctx context.Context) {
+	// stop the flush timer
+	r.mu.Lock()
+	if r.flushTimer != nil {
+		r.flushTimer.Stop()
+	}
+	r.flushCtx = ctx
+	r.mu.Unlock()
+
+	// flush all accesses
+	r.flush()
+
+	// log if we weren't able to flush them
+	r.mu.Lock()
+	if len(r.accesses) > 0 {
+		r.logger.Errorw(fmt.Sprintf("failed to record %d object accesses on worker shutdown", len(r.accesses)))
+	}
+	r.mu.Unlock()
+}
+
+func (r *objectAccessRecorder) flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// NOTE: don't bother flushing if the context is cancelled, we flush on
+	// shutdown and log in case we weren't able to flush all accesses
+	select {
+	case <-r.flushCtx.Done():
+		r.flushTimer = nil
+		return
+	default:
+	}
+
+	if len(r.accesses) > 0 {
+		records := make([]api.ObjectAccessRecord, 0, len(r.accesses))
+		for _, rec := range r.accesses {
+			records = append(records, rec)
+		}
+		if err := r.bus.RecordObjectAccesses(r.flushCtx, records); err != nil {
+			r.logger.Errorw(fmt.Sprintf("failed to record object accesses: %v", err))
+		} else {
+			r.accesses = make(map[objectID]api.ObjectAccessRecord)
+		}
+	}
+	r.flushTimer = nil
+}
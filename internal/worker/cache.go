@@ -12,7 +12,8 @@ import (
 )
 
 const (
-	cacheKeyUsableHosts = "usablehosts"
+	cacheKeyBandwidthSettings = "bandwidthsettings"
+	cacheKeyUsableHosts       = "usablehosts"
 )
 
 type memoryCache struct {
@@ -65,10 +66,12 @@ func (c *memoryCache) Set(key string, value interface{}) {
 
 type (
 	Bus interface {
+		BandwidthSettings(ctx context.Context) (api.BandwidthSettings, error)
 		UsableHosts(ctx context.Context) ([]api.HostInfo, error)
 	}
 
 	WorkerCache interface {
+		BandwidthSettings(ctx context.Context) (api.BandwidthSettings, error)
 		UsableHosts(ctx context.Context) ([]api.HostInfo, error)
 	}
 )
@@ -89,6 +92,18 @@ func NewCache(b Bus, expiry time.Duration, logger *zap.Logger) WorkerCache {
 	}
 }
 
+func (c *cache) BandwidthSettings(ctx context.Context) (bs api.BandwidthSettings, err error) {
+	value, found, expired := c.cache.Get(cacheKeyBandwidthSettings)
+	if !found || expired {
+		bs, err = c.b.BandwidthSettings(ctx)
+		if err == nil {
+			c.cache.Set(cacheKeyBandwidthSettings, bs)
+		}
+		return
+	}
+	return value.(api.BandwidthSettings), nil
+}
+
 func (c *cache) UsableHosts(ctx context.Context) (hosts []api.HostInfo, err error) {
 	value, found, expired := c.cache.Get(cacheKeyUsableHosts)
 	if !found || expired {
@@ -2,8 +2,10 @@ package stores
 
 import (
 	"context"
+	"time"
 
 	"go.sia.tech/core/types"
+	"go.sia.tech/coreutils/chain"
 	"go.sia.tech/renterd/v2/internal/contracts"
 	"go.sia.tech/renterd/v2/stores/sql"
 )
@@ -28,9 +30,21 @@ func (s *SQLStore) FileContractElement(ctx context.Context, fcid types.FileContr
 // ProcessChainUpdate returns a callback function that process a chain update
 // inside a transaction.
 func (s *SQLStore) ProcessChainUpdate(ctx context.Context, applyFn func(sql.ChainUpdateTx) error) error {
-	return s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
-		return tx.ProcessChainUpdate(ctx, applyFn)
+	var announced []types.PublicKey
+	err := s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
+		announced = announced[:0]
+		return tx.ProcessChainUpdate(ctx, func(tx sql.ChainUpdateTx) error {
+			return applyFn(&announcementTracker{ChainUpdateTx: tx, announced: &announced})
+		})
 	})
+	if err != nil {
+		return err
+	}
+
+	// blocklist rules need the addresses of announced hosts to be resolved
+	// which is done outside of the transaction
+	s.triggerBlocklistUpdate(announced)
+	return nil
 }
 
 // ResetChainState deletes all chain data in the database.
@@ -39,3 +53,15 @@ func (s *SQLStore) ResetChainState(ctx context.Context) error {
 		return tx.ResetChainState(ctx)
 	})
 }
+
+// announcementTracker wraps a chain update transaction to keep track of the
+// hosts that announced themselves.
+type announcementTracker struct {
+	sql.ChainUpdateTx
+	announced *[]types.PublicKey
+}
+
+func (tx *announcementTracker) UpdateHost(hk types.PublicKey, v2Ha chain.V2HostAnnouncement, bh uint64, blockID types.BlockID, ts time.Time) error {
+	*tx.announced = append(*tx.announced, hk)
+	return tx.ChainUpdateTx.UpdateHost(hk, v2Ha, bh, blockID, ts)
+}
@@ -4,17 +4,49 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/netip"
+	"sync"
 	"time"
 
 	"go.sia.tech/core/types"
 	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/utils"
 	sql "go.sia.tech/renterd/v2/stores/sql"
+	"go.uber.org/zap"
+)
+
+const (
+	// blocklistResolveThreads is the number of hosts whose addresses are
+	// resolved in parallel when evaluating blocklist rules.
+	blocklistResolveThreads = 16
+
+	// blocklistResolveTimeout is the timeout for resolving the addresses of
+	// a single host when evaluating blocklist rules.
+	blocklistResolveTimeout = 10 * time.Second
+
+	// blocklistResolveExpiry is the duration for which the resolved addresses
+	// of a host are used to match blocklist rules before they are resolved
+	// again.
+	blocklistResolveExpiry = 24 * time.Hour
+
+	// blocklistBatchSize is the number of hosts that are fetched at once when
+	// re-evaluating blocklist rules for announced hosts.
+	blocklistBatchSize = 1000
 )
 
 var (
 	ErrNegativeMaxDowntime = errors.New("max downtime can not be negative")
 )
 
+// resolvedHost contains the resolved IPs of a host and the ASNs they belong
+// to.
+type resolvedHost struct {
+	ips        []netip.Addr
+	asns       []uint32
+	resolvedAt time.Time
+}
+
 // Host returns information about a host.
 func (s *SQLStore) Host(ctx context.Context, hostKey types.PublicKey) (api.Host, error) {
 	hosts, err := s.Hosts(ctx, api.HostOptions{
@@ -83,9 +115,292 @@ func (s *SQLStore) UpdateHostBlocklistEntries(ctx context.Context, add, remove [
 	if len(add)+len(remove) == 0 && !empty {
 		return nil
 	}
-	return s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
-		return tx.UpdateHostBlocklistEntries(ctx, add, remove, empty)
+
+	// parse the rules among the added entries
+	var rules []sql.BlocklistRule
+	for _, entry := range add {
+		rule, ok, err := sql.ParseBlocklistRule(entry)
+		if err != nil {
+			return err
+		} else if !ok {
+			continue
+		} else if rule.ASN != 0 && s.geoIP == nil {
+			return fmt.Errorf("%w: ASN entries require a GeoIP database", api.ErrInvalidBlocklistEntry)
+		}
+		rules = append(rules, rule)
+	}
+
+	// update the entries and match the added rules against all hosts
+	var unresolved []types.PublicKey
+	err = s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
+		if err := tx.UpdateHostBlocklistEntries(ctx, add, remove, empty); err != nil {
+			return err
+		} else if len(rules) == 0 {
+			return nil
+		}
+
+		hosts, err := tx.Hosts(ctx, api.HostOptions{
+			FilterMode:    api.HostFilterModeAll,
+			UsabilityMode: api.UsabilityFilterModeAll,
+			Limit:         -1,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch hosts: %w", err)
+		}
+		unresolved, err = s.applyBlocklistRules(ctx, tx, rules, hosts, s.cachedResolvedHosts(hosts))
+		return err
 	})
+	if err != nil {
+		return err
+	}
+
+	// hosts that weren't resolved yet are matched in the background
+	s.triggerBlocklistUpdate(unresolved)
+	return nil
+}
+
+// applyBlocklistRules matches the given rules against the given hosts and
+// updates which of them are blocked. Hosts that aren't part of 'resolved'
+// keep their current state for rules that need their IPs and are returned.
+func (s *SQLStore) applyBlocklistRules(ctx context.Context, tx sql.DatabaseTx, rules []sql.BlocklistRule, hosts []api.Host, resolved map[types.PublicKey]resolvedHost) ([]types.PublicKey, error) {
+	unresolved := make(map[types.PublicKey]struct{})
+	for _, rule := range rules {
+		// without a GeoIP database ASN rules can't be matched, the hosts
+		// keep their current state rather than being unblocked
+		if rule.ASN != 0 && s.geoIP == nil {
+			continue
+		}
+
+		var blocked, unblocked []types.PublicKey
+		for _, h := range hosts {
+			rh, ok := resolved[h.PublicKey]
+			if rule.NeedsIPs() && !ok {
+				unresolved[h.PublicKey] = struct{}{}
+				continue
+			}
+
+			var hostnames []string
+			for _, addr := range h.V2SiamuxAddresses {
+				if host, _, err := net.SplitHostPort(addr); err == nil {
+					hostnames = append(hostnames, host)
+				}
+			}
+
+			if rule.Matches(hostnames, rh.ips, rh.asns) {
+				blocked = append(blocked, h.PublicKey)
+			} else {
+				unblocked = append(unblocked, h.PublicKey)
+			}
+		}
+		if err := tx.UpdateHostBlocklistRuleHosts(ctx, rule.Entry, blocked, unblocked); err != nil {
+			return nil, err
+		}
+	}
+
+	hks := make([]types.PublicKey, 0, len(unresolved))
+	for hk := range unresolved {
+		hks = append(hks, hk)
+	}
+	return hks, nil
+}
+
+// cachedResolvedHosts returns the resolved addresses of the given hosts
+// without performing any DNS lookups. Hosts that only have IP addresses are
+// resolved right away, for all others the result of the last resolution is
+// used unless it expired.
+func (s *SQLStore) cachedResolvedHosts(hosts []api.Host) map[types.PublicKey]resolvedHost {
+	resolved := make(map[types.PublicKey]resolvedHost)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, h := range hosts {
+		if rh, ok := s.resolvedIPHost(h); ok {
+			resolved[h.PublicKey] = rh
+		} else if rh, ok := s.resolvedHosts[h.PublicKey]; ok && time.Since(rh.resolvedAt) < blocklistResolveExpiry {
+			resolved[h.PublicKey] = rh
+		}
+	}
+	return resolved
+}
+
+// resolvedIPHost returns the resolved host for a host whose addresses are all
+// IP addresses.
+func (s *SQLStore) resolvedIPHost(h api.Host) (resolvedHost, bool) {
+	if len(h.V2SiamuxAddresses) == 0 {
+		return resolvedHost{}, false
+	}
+
+	var ips []netip.Addr
+	for _, addr := range h.V2SiamuxAddresses {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return resolvedHost{}, false
+		}
+		ip, err := netip.ParseAddr(host)
+		if err != nil {
+			return resolvedHost{}, false
+		}
+		ips = append(ips, ip)
+	}
+	return s.newResolvedHost(ips), true
+}
+
+// newResolvedHost looks up the ASNs of the given IPs.
+func (s *SQLStore) newResolvedHost(ips []netip.Addr) resolvedHost {
+	rh := resolvedHost{resolvedAt: time.Now()}
+	for _, ip := range ips {
+		ip = ip.Unmap()
+		rh.ips = append(rh.ips, ip)
+		if s.geoIP != nil {
+			if loc, found := s.geoIP.Lookup(ip); found {
+				rh.asns = append(rh.asns, loc.ASN)
+			}
+		}
+	}
+	return rh
+}
+
+// resolveHosts resolves the addresses of the given hosts in parallel and
+// caches the results, hosts whose addresses can't be resolved are omitted
+// from the result.
+func (s *SQLStore) resolveHosts(ctx context.Context, hosts []api.Host) map[types.PublicKey]resolvedHost {
+	var mu sync.Mutex
+	resolved := make(map[types.PublicKey]resolvedHost)
+
+	var wg sync.WaitGroup
+	sema := make(chan struct{}, blocklistResolveThreads)
+	for _, h := range hosts {
+		wg.Add(1)
+		sema <- struct{}{}
+		go func(h api.Host) {
+			defer func() {
+				<-sema
+				wg.Done()
+			}()
+
+			ctx, cancel := context.WithTimeout(ctx, blocklistResolveTimeout)
+			defer cancel()
+			addrs, err := utils.ResolveHostIPs(ctx, h.V2SiamuxAddresses)
+			if err != nil {
+				s.logger.Debugw("failed to resolve host addresses", zap.Error(err), "hk", h.PublicKey)
+				return
+			}
+
+			var ips []netip.Addr
+			for _, addr := range addrs {
+				if ip, ok := netip.AddrFromSlice(addr.IP); ok {
+					ips = append(ips, ip)
+				}
+			}
+			rh := s.newResolvedHost(ips)
+
+			mu.Lock()
+			resolved[h.PublicKey] = rh
+			mu.Unlock()
+		}(h)
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	for hk, rh := range resolved {
+		s.resolvedHosts[hk] = rh
+	}
+	for hk, rh := range s.resolvedHosts {
+		if time.Since(rh.resolvedAt) >= blocklistResolveExpiry {
+			delete(s.resolvedHosts, hk)
+		}
+	}
+	s.mu.Unlock()
+	return resolved
+}
+
+// blocklistLoop re-evaluates the blocklist rules for hosts that announced
+// themselves since the last iteration.
+func (s *SQLStore) blocklistLoop() {
+	for {
+		select {
+		case <-s.blocklistSigChan:
+		case <-s.shutdownCtx.Done():
+			return
+		}
+
+		s.mu.Lock()
+		hks := make([]types.PublicKey, 0, len(s.announcedHosts))
+		for hk := range s.announcedHosts {
+			hks = append(hks, hk)
+		}
+		clear(s.announcedHosts)
+		s.mu.Unlock()
+
+		if err := s.reapplyBlocklistRules(s.shutdownCtx, hks); err != nil && s.shutdownCtx.Err() == nil {
+			s.logger.Errorw("failed to re-evaluate blocklist rules for announced hosts", zap.Error(err))
+		}
+	}
+}
+
+// triggerBlocklistUpdate schedules the blocklist rules to be re-evaluated for
+// the given hosts.
+func (s *SQLStore) triggerBlocklistUpdate(hks []types.PublicKey) {
+	if len(hks) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	for _, hk := range hks {
+		s.announcedHosts[hk] = struct{}{}
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.blocklistSigChan <- struct{}{}:
+	default:
+	}
+}
+
+// reapplyBlocklistRules resolves the addresses of the given hosts and matches
+// the blocklist rules against them, it's called after hosts re-announce
+// themselves and for hosts that weren't resolved when a rule was added.
+func (s *SQLStore) reapplyBlocklistRules(ctx context.Context, hks []types.PublicKey) error {
+	blocklist, err := s.HostBlocklist(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch blocklist: %w", err)
+	}
+	var rules []sql.BlocklistRule
+	for _, entry := range blocklist {
+		if rule, ok, err := sql.ParseBlocklistRule(entry); err == nil && ok {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	for len(hks) > 0 {
+		batch := hks[:min(len(hks), blocklistBatchSize)]
+		hks = hks[len(batch):]
+
+		hosts, err := s.Hosts(ctx, api.HostOptions{
+			FilterMode:    api.HostFilterModeAll,
+			UsabilityMode: api.UsabilityFilterModeAll,
+			KeyIn:         batch,
+			Limit:         -1,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch hosts: %w", err)
+		}
+
+		// resolve the hosts outside of the transaction, hosts that can't be
+		// resolved keep their current state until they re-announce
+		resolved := s.resolveHosts(ctx, hosts)
+		err = s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
+			_, err := s.applyBlocklistRules(ctx, tx, rules, hosts, resolved)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *SQLStore) HostAllowlist(ctx context.Context) (allowlist []types.PublicKey, err error) {
@@ -104,6 +419,18 @@ func (s *SQLStore) HostBlocklist(ctx context.Context) (blocklist []string, err e
 	return
 }
 
+func (s *SQLStore) UpdateHostLabels(ctx context.Context, hk types.PublicKey, labels []string, notes string) error {
+	return s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
+		return tx.UpdateHostLabels(ctx, hk, labels, notes)
+	})
+}
+
+func (s *SQLStore) RecordHostBenchmark(ctx context.Context, hk types.PublicKey, b api.HostBenchmark) error {
+	return s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
+		return tx.RecordHostBenchmark(ctx, hk, b)
+	})
+}
+
 func (s *SQLStore) RecordHostScans(ctx context.Context, scans []api.HostScan) error {
 	return s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
 		return tx.RecordHostScans(ctx, scans)
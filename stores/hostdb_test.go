@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"go.sia.tech/coreutils/chain"
 	"go.sia.tech/coreutils/rhp/v4/siamux"
 	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/geoip"
 	"go.sia.tech/renterd/v2/internal/gouging"
 	rhp4 "go.sia.tech/renterd/v2/internal/rhp/v4"
 	"go.sia.tech/renterd/v2/internal/test"
@@ -612,7 +614,9 @@ func TestRecordScan(t *testing.T) {
 	// subnets this time.
 	secondScanTime := firstScanTime.Add(time.Hour)
 	settings.Prices.TipHeight = 456
-	if err := ss.RecordHostScans(ctx, []api.HostScan{newTestScan(hk, secondScanTime, settings, true)}); err != nil {
+	scan := newTestScan(hk, secondScanTime, settings, true)
+	scan.Latency = 150 * time.Millisecond
+	if err := ss.RecordHostScans(ctx, []api.HostScan{scan}); err != nil {
 		t.Fatal(err)
 	}
 	host, err = ss.Host(ctx, hk)
@@ -628,6 +632,7 @@ func TestRecordScan(t *testing.T) {
 		LastScan:                time.Time{},
 		LastScanSuccess:         true,
 		SecondToLastScanSuccess: true,
+		ScanLatency:             150 * time.Millisecond,
 		Uptime:                  uptime,
 		Downtime:                downtime,
 		SuccessfulInteractions:  2,
@@ -636,9 +641,12 @@ func TestRecordScan(t *testing.T) {
 		t.Fatal("mismatch")
 	}
 
-	// Record another scan 2 hours after the second one. This time it fails.
+	// Record another scan 2 hours after the second one. This time it fails,
+	// which shouldn't affect the recorded latency.
 	thirdScanTime := secondScanTime.Add(2 * time.Hour)
-	if err := ss.RecordHostScans(ctx, []api.HostScan{newTestScan(hk, thirdScanTime, settings, false)}); err != nil {
+	scan = newTestScan(hk, thirdScanTime, settings, false)
+	scan.Latency = time.Minute
+	if err := ss.RecordHostScans(ctx, []api.HostScan{scan}); err != nil {
 		t.Fatal(err)
 	}
 	host, err = ss.Host(ctx, hk)
@@ -655,6 +663,7 @@ func TestRecordScan(t *testing.T) {
 		LastScan:                time.Time{},
 		LastScanSuccess:         false,
 		SecondToLastScanSuccess: true,
+		ScanLatency:             150 * time.Millisecond,
 		Uptime:                  uptime,
 		Downtime:                downtime,
 		SuccessfulInteractions:  2,
@@ -664,6 +673,145 @@ func TestRecordScan(t *testing.T) {
 	}
 }
 
+func TestRecordHostBenchmark(t *testing.T) {
+	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
+	defer ss.Close()
+	ctx := context.Background()
+
+	// assert recording a benchmark for an unknown host fails
+	hk := types.GeneratePrivateKey().PublicKey()
+	if err := ss.RecordHostBenchmark(ctx, hk, api.HostBenchmark{}); !errors.Is(err, api.ErrHostNotFound) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// add a host
+	if err := ss.addTestHost(hk); err != nil {
+		t.Fatal(err)
+	}
+
+	// assert it hasn't been benchmarked yet
+	host, err := ss.Host(ctx, hk)
+	if err != nil {
+		t.Fatal(err)
+	} else if host.Benchmark != (api.HostBenchmark{}) {
+		t.Fatalf("unexpected benchmark %+v", host.Benchmark)
+	}
+
+	// record a successful benchmark
+	b := api.HostBenchmark{
+		Timestamp:          time.Now().Round(time.Millisecond),
+		Success:            true,
+		UploadThroughput:   1 << 20,
+		DownloadThroughput: 2 << 20,
+		TimeToFirstByte:    100 * time.Millisecond,
+	}
+	if err := ss.RecordHostBenchmark(ctx, hk, b); err != nil {
+		t.Fatal(err)
+	} else if host, err = ss.Host(ctx, hk); err != nil {
+		t.Fatal(err)
+	} else if !host.Benchmark.Timestamp.Equal(b.Timestamp) {
+		t.Fatal("unexpected timestamp", host.Benchmark.Timestamp)
+	}
+	host.Benchmark.Timestamp = b.Timestamp
+	if host.Benchmark != b {
+		t.Fatalf("unexpected benchmark %+v", host.Benchmark)
+	}
+
+	// record a failed benchmark, the measurements should be retained
+	failed := api.HostBenchmark{Timestamp: b.Timestamp.Add(time.Hour)}
+	if err := ss.RecordHostBenchmark(ctx, hk, failed); err != nil {
+		t.Fatal(err)
+	} else if host, err = ss.Host(ctx, hk); err != nil {
+		t.Fatal(err)
+	} else if host.Benchmark.Success || !host.Benchmark.Timestamp.Equal(failed.Timestamp) {
+		t.Fatalf("unexpected benchmark %+v", host.Benchmark)
+	} else if host.Benchmark.UploadThroughput != b.UploadThroughput || host.Benchmark.DownloadThroughput != b.DownloadThroughput || host.Benchmark.TimeToFirstByte != b.TimeToFirstByte {
+		t.Fatalf("unexpected benchmark %+v", host.Benchmark)
+	}
+}
+
+func TestHostLabels(t *testing.T) {
+	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
+	defer ss.Close()
+	ctx := context.Background()
+
+	// assert labelling an unknown host fails
+	hk1 := types.GeneratePrivateKey().PublicKey()
+	if err := ss.UpdateHostLabels(ctx, hk1, []string{"foo"}, ""); !errors.Is(err, api.ErrHostNotFound) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// add two hosts
+	hk2 := types.GeneratePrivateKey().PublicKey()
+	if err := ss.addTestHost(hk1); err != nil {
+		t.Fatal(err)
+	} else if err := ss.addTestHost(hk2); err != nil {
+		t.Fatal(err)
+	}
+
+	// label them
+	if err := ss.UpdateHostLabels(ctx, hk1, []string{"bar", "foo"}, "partner"); err != nil {
+		t.Fatal(err)
+	} else if err := ss.UpdateHostLabels(ctx, hk2, []string{"foo"}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// assert labels and notes are returned
+	h, err := ss.Host(ctx, hk1)
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(h.Labels, []string{"bar", "foo"}) {
+		t.Fatal("unexpected labels", h.Labels)
+	} else if h.Notes != "partner" {
+		t.Fatal("unexpected notes", h.Notes)
+	}
+
+	// assert hosts can be filtered by labels
+	assertFiltered := func(labels []string, expected ...types.PublicKey) {
+		t.Helper()
+		hosts, err := ss.Hosts(ctx, api.HostOptions{
+			FilterMode:    api.HostFilterModeAll,
+			UsabilityMode: api.UsabilityFilterModeAll,
+			Labels:        labels,
+			Limit:         -1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		} else if len(hosts) != len(expected) {
+			t.Fatalf("unexpected number of hosts, %v != %v", len(hosts), len(expected))
+		}
+		for i := range hosts {
+			if hosts[i].PublicKey != expected[i] {
+				t.Fatal("unexpected host", hosts[i].PublicKey)
+			}
+		}
+	}
+	assertFiltered(nil, hk1, hk2)
+	assertFiltered([]string{"foo"}, hk1, hk2)
+	assertFiltered([]string{"foo", "bar"}, hk1)
+	assertFiltered([]string{"baz"})
+
+	// replace the labels of the first host
+	if err := ss.UpdateHostLabels(ctx, hk1, []string{"baz"}, ""); err != nil {
+		t.Fatal(err)
+	} else if h, err = ss.Host(ctx, hk1); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(h.Labels, []string{"baz"}) || h.Notes != "" {
+		t.Fatalf("unexpected labels or notes, %v %q", h.Labels, h.Notes)
+	}
+	assertFiltered([]string{"foo"}, hk2)
+	assertFiltered([]string{"baz"}, hk1)
+
+	// clear the labels
+	if err := ss.UpdateHostLabels(ctx, hk1, nil, ""); err != nil {
+		t.Fatal(err)
+	} else if h, err = ss.Host(ctx, hk1); err != nil {
+		t.Fatal(err)
+	} else if len(h.Labels) != 0 {
+		t.Fatal("unexpected labels", h.Labels)
+	}
+}
+
 func TestRemoveHosts(t *testing.T) {
 	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
 	defer ss.Close()
@@ -1240,6 +1388,130 @@ func TestSQLHostBlocklistBasic(t *testing.T) {
 	}
 }
 
+func TestSQLHostBlocklistRules(t *testing.T) {
+	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
+	defer ss.Close()
+
+	ctx := context.Background()
+
+	// assert invalid rules are rejected
+	for _, entry := range []string{"203.0.113.0/33", "*.", "foo.*.com", "AS0", "AS4294967296"} {
+		if err := ss.UpdateHostBlocklistEntries(ctx, []string{entry}, nil, false); !errors.Is(err, api.ErrInvalidBlocklistEntry) {
+			t.Fatalf("unexpected error for entry '%v': %v", entry, err)
+		}
+	}
+
+	// assert ASN rules require a GeoIP database
+	if err := ss.UpdateHostBlocklistEntries(ctx, []string{"AS64496"}, nil, false); !errors.Is(err, api.ErrInvalidBlocklistEntry) {
+		t.Fatal("unexpected error", err)
+	}
+	db, err := geoip.Parse(strings.NewReader("127.0.0.0\t127.0.0.255\t64496\tUS\tTEST-AS\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss.geoIP = db
+
+	// add hosts
+	hk1, hk2, hk3 := types.PublicKey{1}, types.PublicKey{2}, types.PublicKey{3}
+	if err := ss.addCustomTestHost(hk1, "127.0.0.1:1000"); err != nil {
+		t.Fatal(err)
+	} else if err := ss.addCustomTestHost(hk2, "127.0.1.1:1000"); err != nil {
+		t.Fatal(err)
+	} else if err := ss.addCustomTestHost(hk3, "foo.bar.invalid:1000"); err != nil {
+		t.Fatal(err)
+	}
+
+	assertBlocked := func(hk types.PublicKey, blocked bool) {
+		t.Helper()
+		if h, err := ss.Host(ctx, hk); err != nil {
+			t.Fatal(err)
+		} else if h.Blocked != blocked {
+			t.Fatalf("expected host %v to be blocked: %v", hk, blocked)
+		}
+	}
+	update := func(add, remove []string) {
+		t.Helper()
+		if err := ss.UpdateHostBlocklistEntries(ctx, add, remove, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// assert CIDR ranges are matched against the resolved IPs
+	update([]string{"127.0.0.0/24"}, nil)
+	assertBlocked(hk1, true)
+	assertBlocked(hk2, false)
+	assertBlocked(hk3, false)
+	update(nil, []string{"127.0.0.0/24"})
+	assertBlocked(hk1, false)
+
+	// assert ASNs are matched using the GeoIP database
+	update([]string{"as64496"}, nil)
+	assertBlocked(hk1, true)
+	assertBlocked(hk2, false)
+	update(nil, []string{"as64496"})
+	assertBlocked(hk1, false)
+
+	// assert wildcards only match subdomains, even if the host can't be
+	// resolved
+	update([]string{"*.invalid"}, nil)
+	assertBlocked(hk3, true)
+	update([]string{"*.foo.bar.invalid"}, []string{"*.invalid"})
+	assertBlocked(hk3, false)
+
+	// assert rules are re-evaluated when a host re-announces
+	update([]string{"127.0.0.0/16"}, nil)
+	assertBlocked(hk2, true)
+	err = ss.ProcessChainUpdate(ctx, func(tx sql.ChainUpdateTx) error {
+		return tx.UpdateHost(hk2, chain.V2HostAnnouncement{{
+			Address:  "10.0.0.1:1000",
+			Protocol: siamux.Protocol,
+		}}, 42, types.BlockID{1}, time.Now().UTC().Round(time.Second))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := test.Retry(100, 10*time.Millisecond, func() error {
+		if h, err := ss.Host(ctx, hk2); err != nil {
+			return err
+		} else if h.Blocked {
+			return errors.New("host is still blocked")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	assertBlocked(hk1, true)
+
+	// assert hosts with hostnames are resolved and matched in the background
+	hk4 := types.PublicKey{4}
+	if err := ss.addCustomTestHost(hk4, "localhost:1000"); err != nil {
+		t.Fatal(err)
+	}
+	update([]string{"127.0.0.0/8"}, nil)
+	if err := test.Retry(100, 10*time.Millisecond, func() error {
+		if h, err := ss.Host(ctx, hk4); err != nil {
+			return err
+		} else if !h.Blocked {
+			return errors.New("host isn't blocked")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	update(nil, []string{"127.0.0.0/8", "127.0.0.0/16"})
+	assertBlocked(hk1, false)
+
+	// assert hosts blocked by ASN rules remain blocked without a GeoIP
+	// database
+	update([]string{"AS64496"}, nil)
+	assertBlocked(hk1, true)
+	ss.geoIP = nil
+	if err := ss.reapplyBlocklistRules(ctx, []types.PublicKey{hk1}); err != nil {
+		t.Fatal(err)
+	}
+	assertBlocked(hk1, true)
+}
+
 // newTestScan returns a host interaction with given parameters.
 func newTestScan(hk types.PublicKey, scanTime time.Time, settings rhp4.HostSettings, success bool) api.HostScan {
 	return api.HostScan{
@@ -1267,6 +1539,8 @@ func newTestHostCheck() api.HostChecks {
 			Uptime:           .5,
 			Version:          .6,
 			Prices:           .7,
+			Latency:          .8,
+			Throughput:       .9,
 		},
 		UsabilityBreakdown: api.HostUsabilityBreakdown{
 			Blocked:               false,
@@ -37,6 +37,10 @@ const (
 	// we prune host sectors.
 	hostSectorPruningBatchSize = 10000
 
+	// slabsBatchSize is the number of slabs that are fetched per db
+	// transaction when fetching slabs by their keys.
+	slabsBatchSize = 100
+
 	refreshHealthMinHealthValidity = 12 * time.Hour
 	refreshHealthMaxHealthValidity = 72 * time.Hour
 )
@@ -189,6 +193,14 @@ func (s *SQLStore) Contracts(ctx context.Context, opts api.ContractsOpts) ([]api
 	return contracts, err
 }
 
+func (s *SQLStore) ContractsRenterCost(ctx context.Context, minStartHeight uint64) (cost types.Currency, err error) {
+	err = s.db.Transaction(ctx, func(tx sql.DatabaseTx) (err error) {
+		cost, err = tx.ContractsRenterCost(ctx, minStartHeight)
+		return
+	})
+	return
+}
+
 func (s *SQLStore) ContractRoots(ctx context.Context, id types.FileContractID) (roots []types.Hash256, err error) {
 	err = s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
 		roots, err = tx.ContractRoots(ctx, id)
@@ -219,6 +231,12 @@ func (s *SQLStore) PutContract(ctx context.Context, c api.ContractMetadata) erro
 	})
 }
 
+func (s *SQLStore) UpdateContractManual(ctx context.Context, fcid types.FileContractID, manual bool, renewal *api.ContractRenewalSettings) error {
+	return s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
+		return tx.UpdateContractManual(ctx, fcid, manual, renewal)
+	})
+}
+
 func (s *SQLStore) UpdateContractUsability(ctx context.Context, fcid types.FileContractID, usability string) error {
 	// update usability
 	if err := s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
@@ -251,6 +269,39 @@ func (s *SQLStore) Object(ctx context.Context, bucket, key string) (obj api.Obje
 	return
 }
 
+// RecordObjectAccesses records reads of objects, squashing multiple records
+// for the same object into a single update.
+func (s *SQLStore) RecordObjectAccesses(ctx context.Context, records []api.ObjectAccessRecord) error {
+	if len(records) == 0 {
+		return nil // nothing to do
+	}
+
+	type objectID struct{ bucket, key string }
+	type access struct {
+		readCount  uint64
+		accessedAt time.Time
+	}
+	squashed := make(map[objectID]access)
+	for _, r := range records {
+		id := objectID{r.Bucket, r.Key}
+		a := squashed[id]
+		a.readCount += r.ReadCount
+		if t := time.Time(r.AccessedAt); t.After(a.accessedAt) {
+			a.accessedAt = t
+		}
+		squashed[id] = a
+	}
+
+	return s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
+		for id, a := range squashed {
+			if err := tx.RecordObjectAccess(ctx, id.bucket, id.key, a.readCount, a.accessedAt); err != nil {
+				return fmt.Errorf("failed to record access for object %q in bucket %q: %w", id.key, id.bucket, err)
+			}
+		}
+		return nil
+	})
+}
+
 func (s *SQLStore) RecordContractSpending(ctx context.Context, records []api.ContractSpendingRecord) error {
 	if len(records) == 0 {
 		return nil // nothing to do
@@ -371,6 +422,14 @@ func (s *SQLStore) CopyObject(ctx context.Context, srcBucket, dstBucket, srcPath
 	return
 }
 
+func (s *SQLStore) DeleteContractSectors(ctx context.Context, fcid types.FileContractID, roots []types.Hash256) (deletedSectors int, err error) {
+	err = s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
+		deletedSectors, err = tx.DeleteContractSectors(ctx, fcid, roots)
+		return err
+	})
+	return
+}
+
 func (s *SQLStore) DeleteHostSector(ctx context.Context, hk types.PublicKey, root types.Hash256) (deletedSectors int, err error) {
 	err = s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
 		deletedSectors, err = tx.DeleteHostSector(ctx, hk, root)
@@ -482,6 +541,40 @@ func (s *SQLStore) Slab(ctx context.Context, key object.EncryptionKey) (slab obj
 	return
 }
 
+// Slabs returns the slabs with the given keys together with the buckets of the
+// objects referencing them. Slabs that don't exist are skipped. The slabs are
+// fetched in batches, using a separate transaction for every batch.
+func (s *SQLStore) Slabs(ctx context.Context, keys []object.EncryptionKey) (slabs []api.SlabWithBuckets, err error) {
+	for len(keys) > 0 {
+		batch := keys[:min(len(keys), slabsBatchSize)]
+		keys = keys[len(batch):]
+
+		var batchSlabs []api.SlabWithBuckets
+		err = s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
+			batchSlabs = batchSlabs[:0]
+			for _, key := range batch {
+				slab, err := tx.Slab(ctx, key)
+				if errors.Is(err, api.ErrSlabNotFound) {
+					continue
+				} else if err != nil {
+					return err
+				}
+				buckets, err := tx.SlabBuckets(ctx, key)
+				if err != nil {
+					return err
+				}
+				batchSlabs = append(batchSlabs, api.SlabWithBuckets{Slab: slab, Buckets: buckets})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		slabs = append(slabs, batchSlabs...)
+	}
+	return
+}
+
 func (s *SQLStore) UpdateSlab(ctx context.Context, key object.EncryptionKey, sectors []api.UploadedSector) error {
 	return s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
 		return tx.UpdateSlab(ctx, key, sectors)
@@ -511,15 +604,24 @@ func (s *SQLStore) RefreshHealth(ctx context.Context) error {
 	}
 }
 
+// SlabHealthStats returns the health distribution of all slabs.
+func (s *SQLStore) SlabHealthStats(ctx context.Context, healthCutoff float64) (stats api.SlabHealthStats, err error) {
+	err = s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
+		stats, err = tx.SlabHealthStats(ctx, healthCutoff)
+		return err
+	})
+	return
+}
+
 // SlabsForMigration returns up to 'limit' slabs that do not reach full
-// redundancy. These slabs need to be migrated to good contracts so they are
-// restored to full health.
-func (s *SQLStore) SlabsForMigration(ctx context.Context, healthCutoff float64, limit int) (slabs []api.UnhealthySlab, err error) {
+// redundancy, skipping the first 'offset' ones. These slabs need to be
+// migrated to good contracts so they are restored to full health.
+func (s *SQLStore) SlabsForMigration(ctx context.Context, healthCutoff float64, offset, limit int, rs api.RepairSettings) (slabs []api.UnhealthySlab, err error) {
 	if limit <= -1 {
 		limit = math.MaxInt
 	}
 	err = s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
-		slabs, err = tx.SlabsForMigration(ctx, healthCutoff, limit)
+		slabs, err = tx.SlabsForMigration(ctx, healthCutoff, offset, limit, rs)
 		return err
 	})
 	return
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -623,6 +624,37 @@ func TestAncestorsContracts(t *testing.T) {
 	}
 }
 
+func TestContractsRenterCost(t *testing.T) {
+	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
+	defer ss.Close()
+
+	hk := types.PublicKey{1, 2, 3}
+	if err := ss.addTestHost(hk); err != nil {
+		t.Fatal(err)
+	}
+
+	// create a chain of 3 contracts with start heights 0, 1 and 2
+	fcids := []types.FileContractID{{1}, {2}, {3}}
+	if _, err := ss.addTestContract(fcids[0], hk); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(fcids); i++ {
+		if err := ss.renewTestContract(hk, fcids[i-1], fcids[i], uint64(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// assert archived contracts are included and the start height is
+	// respected
+	for minStartHeight, expected := range []uint64{9, 6, 3, 0} {
+		if cost, err := ss.ContractsRenterCost(context.Background(), uint64(minStartHeight)); err != nil {
+			t.Fatal(err)
+		} else if !cost.Equals(types.NewCurrency64(expected)) {
+			t.Fatalf("unexpected cost for min start height %d, %v != %v", minStartHeight, cost, expected)
+		}
+	}
+}
+
 func TestArchiveContracts(t *testing.T) {
 	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
 	defer ss.Close()
@@ -696,6 +728,7 @@ func newTestContract(fcid types.FileContractID, hk types.PublicKey) api.Contract
 		Usability:          api.ContractUsabilityGood,
 		ContractPrice:      types.NewCurrency64(1),
 		InitialRenterFunds: types.NewCurrency64(2),
+		RenterCost:         types.NewCurrency64(3),
 	}
 }
 
@@ -874,6 +907,7 @@ func TestSQLMetadataStore(t *testing.T) {
 		Usability:          api.ContractUsabilityGood,
 		ContractPrice:      types.NewCurrency64(1),
 		InitialRenterFunds: types.NewCurrency64(2),
+		RenterCost:         types.NewCurrency64(3),
 	}
 
 	expectedObjSlab2 := object.Slab{
@@ -897,6 +931,7 @@ func TestSQLMetadataStore(t *testing.T) {
 		Usability:          api.ContractUsabilityGood,
 		ContractPrice:      types.NewCurrency64(1),
 		InitialRenterFunds: types.NewCurrency64(2),
+		RenterCost:         types.NewCurrency64(3),
 	}
 
 	// compare slabs
@@ -1645,7 +1680,7 @@ func TestSlabsForMigration(t *testing.T) {
 	if err := ss.RefreshHealth(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	slabs, err := ss.SlabsForMigration(context.Background(), 0.99, -1)
+	slabs, err := ss.SlabsForMigration(context.Background(), 0.99, 0, -1, api.RepairSettings{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1663,10 +1698,20 @@ func TestSlabsForMigration(t *testing.T) {
 		t.Fatal("slabs are not returned in the correct order")
 	}
 
+	// assert the slabs can be paged through
+	for offset := 0; offset < len(expected); offset += 3 {
+		page, err := ss.SlabsForMigration(context.Background(), 0.99, offset, 3, api.RepairSettings{})
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(page, expected[offset:min(offset+3, len(expected))]) {
+			t.Fatalf("unexpected page at offset %d: %v", offset, page)
+		}
+	}
+
 	if err := ss.RefreshHealth(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	slabs, err = ss.SlabsForMigration(context.Background(), 0.49, -1)
+	slabs, err = ss.SlabsForMigration(context.Background(), 0.49, 0, -1, api.RepairSettings{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1728,7 +1773,7 @@ func TestSlabsForMigrationNegHealth(t *testing.T) {
 	if err := ss.RefreshHealth(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	slabs, err := ss.SlabsForMigration(context.Background(), 0.99, -1)
+	slabs, err := ss.SlabsForMigration(context.Background(), 0.99, 0, -1, api.RepairSettings{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1737,6 +1782,188 @@ func TestSlabsForMigrationNegHealth(t *testing.T) {
 	}
 }
 
+func TestSlabsForMigrationRepairPriority(t *testing.T) {
+	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
+	defer ss.Close()
+
+	// add a host and a contract
+	hks, err := ss.addTestHosts(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fcids, _, err := ss.addTestContracts(hks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// add two objects
+	for i, key := range []string{"/cold", "/hot"} {
+		obj := object.Object{
+			Key: object.GenerateEncryptionKey(object.EncryptionKeyTypeSalted),
+			Slabs: []object.SlabSlice{
+				{
+					Slab: object.Slab{
+						EncryptionKey: object.GenerateEncryptionKey(object.EncryptionKeyTypeSalted),
+						MinShards:     1,
+						Shards:        newTestShards(hks[0], fcids[0], types.Hash256{byte(i + 1)}),
+					},
+				},
+			},
+		}
+		if _, err := ss.addTestObject(key, obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// make the cold object slightly less healthy than the hot one
+	if err := ss.overrideSlabHealth("/cold", 0.4); err != nil {
+		t.Fatal(err)
+	} else if err := ss.overrideSlabHealth("/hot", 0.5); err != nil {
+		t.Fatal(err)
+	} else if _, err := ss.DB().Exec(context.Background(), "UPDATE slabs SET health_valid_until = ?", time.Now().Add(time.Hour).Unix()); err != nil {
+		t.Fatal(err)
+	}
+
+	// record accesses of the hot object
+	now := time.Now().Round(time.Second)
+	err = ss.RecordObjectAccesses(context.Background(), []api.ObjectAccessRecord{
+		{Bucket: testBucket, Key: "/hot", ReadCount: 5, AccessedAt: api.TimeRFC3339(now.Add(-time.Minute))},
+		{Bucket: testBucket, Key: "/hot", ReadCount: 5, AccessedAt: api.TimeRFC3339(now)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// assert the stats are exposed
+	if obj, err := ss.Object(context.Background(), testBucket, "/hot"); err != nil {
+		t.Fatal(err)
+	} else if obj.ReadCount != 10 {
+		t.Fatal("unexpected read count", obj.ReadCount)
+	} else if !time.Time(obj.LastAccessedAt).Equal(now) {
+		t.Fatal("unexpected last accessed at", obj.LastAccessedAt)
+	}
+	if resp, err := ss.Objects(context.Background(), testBucket, "/", "", "", "", "", "", -1, object.EncryptionKey{}); err != nil {
+		t.Fatal(err)
+	} else if len(resp.Objects) != 2 {
+		t.Fatal("unexpected number of objects", len(resp.Objects))
+	} else if resp.Objects[0].Key != "/cold" || resp.Objects[0].ReadCount != 0 || !time.Time(resp.Objects[0].LastAccessedAt).IsZero() {
+		t.Fatal("unexpected object", resp.Objects[0])
+	} else if resp.Objects[1].Key != "/hot" || resp.Objects[1].ReadCount != 10 {
+		t.Fatal("unexpected object", resp.Objects[1])
+	}
+
+	// helper to assert the order of the slabs
+	assertOrder := func(rs api.RepairSettings, health ...float64) {
+		t.Helper()
+		slabs, err := ss.SlabsForMigration(context.Background(), 0.99, 0, -1, rs)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(slabs) != len(health) {
+			t.Fatalf("unexpected number of slabs, %v != %v", len(slabs), len(health))
+		}
+		for i := range slabs {
+			if slabs[i].Health != health[i] {
+				t.Fatalf("unexpected health at index %d, %v != %v", i, slabs[i].Health, health[i])
+			}
+		}
+	}
+
+	// without boosts the least healthy slab comes first
+	assertOrder(api.RepairSettings{}, 0.4, 0.5)
+
+	// a small boost isn't enough to reorder the slabs
+	assertOrder(api.RepairSettings{AccessBoost: 0.05, AccessWindow: api.DurationMS(time.Hour), HotReadCount: 10}, 0.4, 0.5)
+
+	// a boost larger than the difference in health is
+	assertOrder(api.RepairSettings{AccessBoost: 0.2, AccessWindow: api.DurationMS(time.Hour), HotReadCount: 10}, 0.5, 0.4)
+
+	// unless the object isn't hot enough
+	assertOrder(api.RepairSettings{AccessBoost: 0.2, AccessWindow: api.DurationMS(time.Hour), HotReadCount: 100}, 0.4, 0.5)
+
+	// add the least healthy object to another bucket
+	if err := ss.CreateBucket(context.Background(), "other", api.BucketPolicy{}); err != nil {
+		t.Fatal(err)
+	} else if err := ss.UpdateObjectBlocking(context.Background(), "other", "/other", testETag, testMimeType, testMetadata, object.Object{
+		Key: object.GenerateEncryptionKey(object.EncryptionKeyTypeSalted),
+		Slabs: []object.SlabSlice{{Slab: object.Slab{
+			EncryptionKey: object.GenerateEncryptionKey(object.EncryptionKeyTypeSalted),
+			MinShards:     1,
+			Shards:        newTestShards(hks[0], fcids[0], types.Hash256{3}),
+		}}},
+	}); err != nil {
+		t.Fatal(err)
+	} else if err := ss.overrideSlabHealth("/other", 0.3); err != nil {
+		t.Fatal(err)
+	} else if _, err := ss.DB().Exec(context.Background(), "UPDATE slabs SET health_valid_until = ?", time.Now().Add(time.Hour).Unix()); err != nil {
+		t.Fatal(err)
+	}
+	assertOrder(api.RepairSettings{}, 0.3, 0.4, 0.5)
+
+	// bucket boosts apply to all objects in the bucket
+	assertOrder(api.RepairSettings{BucketBoosts: map[string]float64{testBucket: 0.5, "other": 0.1}}, 0.4, 0.5, 0.3)
+	assertOrder(api.RepairSettings{BucketBoosts: map[string]float64{testBucket: 0.1, "other": 0.5}}, 0.3, 0.4, 0.5)
+
+	// bucket boosts add up with the access boost
+	assertOrder(api.RepairSettings{
+		AccessBoost:  0.2,
+		AccessWindow: api.DurationMS(time.Hour),
+		HotReadCount: 10,
+		BucketBoosts: map[string]float64{testBucket: 0.5},
+	}, 0.5, 0.4, 0.3)
+}
+
+func TestSlabs(t *testing.T) {
+	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
+	defer ss.Close()
+
+	// add a host and a contract
+	hks, err := ss.addTestHosts(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fcids, _, err := ss.addTestContracts(hks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// add a slab that is referenced by objects in two buckets
+	slab := object.Slab{
+		EncryptionKey: object.GenerateEncryptionKey(object.EncryptionKeyTypeSalted),
+		MinShards:     1,
+		Shards:        newTestShards(hks[0], fcids[0], types.Hash256{1}),
+	}
+	obj := object.Object{
+		Key:   object.GenerateEncryptionKey(object.EncryptionKeyTypeSalted),
+		Slabs: []object.SlabSlice{{Slab: slab}},
+	}
+	if err := ss.CreateBucket(context.Background(), "other", api.BucketPolicy{}); err != nil {
+		t.Fatal(err)
+	} else if _, err := ss.addTestObject("/foo", obj); err != nil {
+		t.Fatal(err)
+	} else if err := ss.UpdateObjectBlocking(context.Background(), "other", "/bar", testETag, testMimeType, testMetadata, obj); err != nil {
+		t.Fatal(err)
+	}
+
+	// fetch the slab and a slab that doesn't exist
+	slabs, err := ss.Slabs(context.Background(), []object.EncryptionKey{
+		object.GenerateEncryptionKey(object.EncryptionKeyTypeSalted),
+		slab.EncryptionKey,
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(slabs) != 1 {
+		t.Fatal("expected 1 slab, got", len(slabs))
+	} else if slabs[0].Slab.EncryptionKey != slab.EncryptionKey || len(slabs[0].Slab.Shards) != 1 {
+		t.Fatal("unexpected slab", slabs[0].Slab)
+	}
+
+	buckets := slabs[0].Buckets
+	sort.Strings(buckets)
+	if !reflect.DeepEqual(buckets, []string{"other", testBucket}) {
+		t.Fatal("unexpected buckets", buckets)
+	}
+}
+
 func TestUnhealthySlabsNoContracts(t *testing.T) {
 	// create db
 	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
@@ -1779,7 +2006,7 @@ func TestUnhealthySlabsNoContracts(t *testing.T) {
 	if err := ss.RefreshHealth(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	slabs, err := ss.SlabsForMigration(context.Background(), 0.99, -1)
+	slabs, err := ss.SlabsForMigration(context.Background(), 0.99, 0, -1, api.RepairSettings{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1801,7 +2028,7 @@ func TestUnhealthySlabsNoContracts(t *testing.T) {
 	if err := ss.RefreshHealth(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	slabs, err = ss.SlabsForMigration(context.Background(), 0.99, -1)
+	slabs, err = ss.SlabsForMigration(context.Background(), 0.99, 0, -1, api.RepairSettings{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1871,7 +2098,7 @@ func TestUnhealthySlabsNoRedundancy(t *testing.T) {
 	if err := ss.RefreshHealth(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	slabs, err := ss.SlabsForMigration(context.Background(), 0.99, -1)
+	slabs, err := ss.SlabsForMigration(context.Background(), 0.99, 0, -1, api.RepairSettings{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2045,7 +2272,7 @@ func TestUpdateSlab(t *testing.T) {
 	if err := ss.RefreshHealth(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	toMigrate, err := ss.SlabsForMigration(ctx, 0.99, -1)
+	toMigrate, err := ss.SlabsForMigration(ctx, 0.99, 0, -1, api.RepairSettings{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2090,7 +2317,7 @@ func TestUpdateSlab(t *testing.T) {
 	if err := ss.RefreshHealth(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	toMigrate, err = ss.SlabsForMigration(ctx, 0.99, -1)
+	toMigrate, err = ss.SlabsForMigration(ctx, 0.99, 0, -1, api.RepairSettings{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -3637,6 +3864,78 @@ func TestDeleteHostSector(t *testing.T) {
 	}
 }
 
+func TestDeleteContractSectors(t *testing.T) {
+	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
+	defer ss.Close()
+
+	// create a host with 2 contracts
+	hks, err := ss.addTestHosts(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hk := hks[0]
+	fcids, _, err := ss.addTestContracts([]types.PublicKey{hk, hk})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// create a slab with two sectors, the first one is stored on both
+	// contracts, the second one only on the first contract
+	root1, root2 := types.Hash256{1}, types.Hash256{2}
+	ss.InsertSlab(object.Slab{
+		EncryptionKey: object.GenerateEncryptionKey(object.EncryptionKeyTypeSalted),
+		MinShards:     1,
+		Shards: []object.Sector{
+			{Contracts: map[types.PublicKey][]types.FileContractID{hk: fcids}, Root: root1},
+			{Contracts: map[types.PublicKey][]types.FileContractID{hk: fcids[:1]}, Root: root2},
+		},
+	})
+	if n := ss.Count("contract_sectors"); n != 3 {
+		t.Fatal("expected 3 contract-sector links", n)
+	} else if n := ss.Count("host_sectors"); n != 2 {
+		t.Fatal("expected 2 host-sector links", n)
+	}
+
+	// mark both sectors as lost on the first contract, an unknown root is
+	// ignored
+	if n, err := ss.DeleteContractSectors(context.Background(), fcids[0], []types.Hash256{root1, root2, {3}}); err != nil {
+		t.Fatal(err)
+	} else if n != 2 {
+		t.Fatal("expected 2 deleted sectors", n)
+	}
+
+	// the second contract still stores the first sector, so the host does too
+	if roots, err := ss.ContractRoots(context.Background(), fcids[1]); err != nil {
+		t.Fatal(err)
+	} else if len(roots) != 1 || roots[0] != root1 {
+		t.Fatal("unexpected roots", roots)
+	} else if n := ss.Count("contract_sectors"); n != 1 {
+		t.Fatal("expected 1 contract-sector link", n)
+	} else if n := ss.Count("host_sectors"); n != 1 {
+		t.Fatal("expected 1 host-sector link", n)
+	}
+
+	// the slab's health should be invalidated
+	var validUntil int64
+	if err := ss.DB().QueryRow(context.Background(), "SELECT health_valid_until FROM slabs").Scan(&validUntil); err != nil {
+		t.Fatal(err)
+	} else if time.Now().Before(time.Unix(validUntil, 0)) {
+		t.Fatal("expected health to be invalid")
+	}
+
+	// the lost sectors should be recorded
+	if h, err := ss.Host(context.Background(), hk); err != nil {
+		t.Fatal(err)
+	} else if h.Interactions.LostSectors != 2 {
+		t.Fatalf("expected 2 lost sectors, got %v", h.Interactions.LostSectors)
+	}
+
+	// unknown contracts are reported
+	if _, err := ss.DeleteContractSectors(context.Background(), types.FileContractID{9, 9, 9}, []types.Hash256{root1}); !errors.Is(err, api.ErrContractNotFound) {
+		t.Fatal("unexpected error", err)
+	}
+}
+
 func newTestShards(hk types.PublicKey, fcid types.FileContractID, root types.Hash256) []object.Sector {
 	return []object.Sector{
 		newTestShard(hk, fcid, root),
@@ -3976,7 +4275,7 @@ func TestRenewedContract(t *testing.T) {
 	if err := ss.RefreshHealth(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if slabs, err := ss.SlabsForMigration(context.Background(), 0.99, 10); err != nil {
+	if slabs, err := ss.SlabsForMigration(context.Background(), 0.99, 0, 10, api.RepairSettings{}); err != nil {
 		t.Fatal(err)
 	} else if len(slabs) > 0 {
 		t.Fatal("shouldn't return any slabs", len(slabs))
@@ -4020,7 +4319,7 @@ func TestRenewedContract(t *testing.T) {
 	if err := ss.RefreshHealth(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if slabs, err := ss.SlabsForMigration(context.Background(), 0.99, 10); err != nil {
+	if slabs, err := ss.SlabsForMigration(context.Background(), 0.99, 0, 10, api.RepairSettings{}); err != nil {
 		t.Fatal(err)
 	} else if len(slabs) > 0 {
 		t.Fatal("shouldn't return any slabs", len(slabs))
@@ -4939,3 +5238,192 @@ func TestHostSectors(t *testing.T) {
 		t.Fatal("expected updated at to change")
 	}
 }
+
+func TestSlabHealthStats(t *testing.T) {
+	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
+	defer ss.Close()
+
+	// add test hosts
+	hks, err := ss.addTestHosts(12)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// add test contracts
+	fcids, _, err := ss.addTestContracts(hks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// add three objects with a single slab on 4 hosts each
+	for i := 0; i < 3; i++ {
+		var shards []object.Sector
+		for j := i * 4; j < (i+1)*4; j++ {
+			shards = append(shards, newTestShard(hks[j], fcids[j], types.Hash256{byte(j)}))
+		}
+		if _, err := ss.addTestObject(fmt.Sprintf("/%s%d", t.Name(), i), object.Object{
+			Key: object.GenerateEncryptionKey(object.EncryptionKeyTypeSalted),
+			Slabs: []object.SlabSlice{{Slab: object.Slab{
+				MinShards:     2,
+				EncryptionKey: object.GenerateEncryptionKey(object.EncryptionKeyTypeSalted),
+				Shards:        shards,
+			}}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// mark contracts as bad to bring the health of the second slab to .5
+	// and the health of the third slab to 0
+	for _, fcid := range []types.FileContractID{fcids[4], fcids[8], fcids[9]} {
+		if err := ss.UpdateContractUsability(context.Background(), fcid, api.ContractUsabilityBad); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ss.RefreshHealth(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := ss.SlabHealthStats(context.Background(), .5)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(stats.Buckets) != len(api.HealthMetricBuckets)+1 {
+		t.Fatalf("expected %d buckets, got %d", len(api.HealthMetricBuckets)+1, len(stats.Buckets))
+	} else if stats.Buckets[0].Start != nil || stats.Buckets[len(stats.Buckets)-1].End != nil {
+		t.Fatal("expected outer buckets to be unbounded")
+	} else if stats.HealthCutoff != .5 {
+		t.Fatal("unexpected cutoff", stats.HealthCutoff)
+	} else if stats.BytesBelowCutoff != 4*rhpv4.SectorSize {
+		t.Fatal("unexpected bytes below cutoff", stats.BytesBelowCutoff)
+	}
+
+	// assert the slabs ended up in the right buckets
+	expected := map[int]float64{1: 0, 3: .5, 5: 1}
+	for i, b := range stats.Buckets {
+		minHealth, ok := expected[i]
+		if !ok && b.Slabs != 0 {
+			t.Fatalf("expected bucket %d to be empty, got %+v", i, b)
+		} else if ok && (b.Slabs != 1 || b.MinHealth != minHealth || b.Bytes != 2*rhpv4.SectorSize) {
+			t.Fatalf("unexpected bucket %d: %+v", i, b)
+		}
+	}
+}
+
+func TestManualContracts(t *testing.T) {
+	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
+	defer ss.Close()
+
+	// add test host and contract
+	hk := types.PublicKey{1}
+	if err := ss.addTestHost(hk); err != nil {
+		t.Fatal(err)
+	}
+	fcid := types.FileContractID{1}
+	if c, err := ss.addTestContract(fcid, hk); err != nil {
+		t.Fatal(err)
+	} else if c.Manual || c.Renewal != nil {
+		t.Fatal("contract should not be manual", c.Manual, c.Renewal)
+	}
+
+	// mark the contract as manual
+	renewal := api.ContractRenewalSettings{
+		Period:           100,
+		RenewWindow:      10,
+		RenterFunds:      types.Siacoins(1),
+		MinNewCollateral: types.Siacoins(2),
+	}
+	if err := ss.UpdateContractManual(context.Background(), fcid, true, &renewal); err != nil {
+		t.Fatal(err)
+	}
+	c, err := ss.Contract(context.Background(), fcid)
+	if err != nil {
+		t.Fatal(err)
+	} else if !c.Manual || c.Renewal == nil || *c.Renewal != renewal {
+		t.Fatal("unexpected", c.Manual, c.Renewal)
+	}
+
+	// assert the renewal inherits the manual flag and renewal settings
+	if err := ss.renewTestContract(hk, fcid, types.FileContractID{2}, 1); err != nil {
+		t.Fatal(err)
+	}
+	c, err = ss.Contract(context.Background(), types.FileContractID{2})
+	if err != nil {
+		t.Fatal(err)
+	} else if !c.Manual || c.Renewal == nil || *c.Renewal != renewal {
+		t.Fatal("unexpected", c.Manual, c.Renewal)
+	}
+
+	// assert archived contracts can't be updated
+	if err := ss.UpdateContractManual(context.Background(), fcid, false, nil); !errors.Is(err, api.ErrContractNotFound) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// unmark the renewal
+	if err := ss.UpdateContractManual(context.Background(), types.FileContractID{2}, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	c, err = ss.Contract(context.Background(), types.FileContractID{2})
+	if err != nil {
+		t.Fatal(err)
+	} else if c.Manual || c.Renewal != nil {
+		t.Fatal("unexpected", c.Manual, c.Renewal)
+	}
+}
+
+func TestContractSets(t *testing.T) {
+	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
+	defer ss.Close()
+
+	// add test host and a contract in the archive set
+	hk := types.PublicKey{1}
+	if err := ss.addTestHost(hk); err != nil {
+		t.Fatal(err)
+	}
+	fcid := types.FileContractID{1}
+	c := newTestContract(fcid, hk)
+	c.ContractSet = "archive"
+	if err := ss.PutContract(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := ss.Contract(context.Background(), fcid); err != nil {
+		t.Fatal(err)
+	} else if c.ContractSet != "archive" {
+		t.Fatalf("unexpected contract set %q", c.ContractSet)
+	}
+
+	// assert the renewal inherits the contract set
+	if err := ss.renewTestContract(hk, fcid, types.FileContractID{2}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := ss.Contract(context.Background(), types.FileContractID{2}); err != nil {
+		t.Fatal(err)
+	} else if c.ContractSet != "archive" {
+		t.Fatalf("unexpected contract set %q", c.ContractSet)
+	}
+
+	// assert contract sets are persisted in the autopilot config
+	if err := ss.InitAutopilotConfig(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ap, err := ss.AutopilotConfig(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	} else if ap.ContractSets != nil {
+		t.Fatal("expected no contract sets", ap.ContractSets)
+	}
+	ap.ContractSets = map[string]api.ContractSetConfig{
+		"archive": {
+			Contracts: api.ContractsConfig{Amount: 5, Period: 1000, RenewWindow: 100},
+			Hosts:     api.ContractSetHostFilter{Labels: []string{"archive"}, MaxStoragePrice: types.Siacoins(1)},
+		},
+	}
+	if err := ss.UpdateAutopilotConfig(context.Background(), ap); err != nil {
+		t.Fatal(err)
+	}
+	updated, err := ss.AutopilotConfig(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(updated.ContractSets, ap.ContractSets) {
+		t.Fatalf("unexpected contract sets %+v", updated.ContractSets)
+	}
+}
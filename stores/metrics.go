@@ -24,6 +24,30 @@ func (s *SQLStore) ContractPruneMetrics(ctx context.Context, start time.Time, n
 	return
 }
 
+func (s *SQLStore) HealthMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HealthMetricsQueryOpts) (metrics []api.HealthMetric, err error) {
+	err = s.dbMetrics.Transaction(ctx, func(tx sql.MetricsDatabaseTx) (txErr error) {
+		metrics, txErr = tx.HealthMetrics(ctx, start, n, interval, opts)
+		return
+	})
+	return
+}
+
+func (s *SQLStore) HostMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HostMetricsQueryOpts) (metrics []api.HostMetric, err error) {
+	err = s.dbMetrics.Transaction(ctx, func(tx sql.MetricsDatabaseTx) (txErr error) {
+		metrics, txErr = tx.HostMetrics(ctx, start, n, interval, opts)
+		return
+	})
+	return
+}
+
+func (s *SQLStore) HostNetworkMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HostNetworkMetricsQueryOpts) (metrics []api.HostNetworkMetric, err error) {
+	err = s.dbMetrics.Transaction(ctx, func(tx sql.MetricsDatabaseTx) (txErr error) {
+		metrics, txErr = tx.HostNetworkMetrics(ctx, start, n, interval, opts)
+		return
+	})
+	return
+}
+
 func (s *SQLStore) RecordContractMetric(ctx context.Context, metrics ...api.ContractMetric) error {
 	return s.dbMetrics.Transaction(ctx, func(tx sql.MetricsDatabaseTx) error {
 		return tx.RecordContractMetric(ctx, metrics...)
@@ -36,6 +60,18 @@ func (s *SQLStore) RecordContractPruneMetric(ctx context.Context, metrics ...api
 	})
 }
 
+func (s *SQLStore) RecordHealthMetric(ctx context.Context, metrics ...api.HealthMetric) error {
+	return s.dbMetrics.Transaction(ctx, func(tx sql.MetricsDatabaseTx) error {
+		return tx.RecordHealthMetric(ctx, metrics...)
+	})
+}
+
+func (s *SQLStore) RecordHostMetric(ctx context.Context, metrics ...api.HostMetric) error {
+	return s.dbMetrics.Transaction(ctx, func(tx sql.MetricsDatabaseTx) error {
+		return tx.RecordHostMetric(ctx, metrics...)
+	})
+}
+
 func (s *SQLStore) RecordWalletMetric(ctx context.Context, metrics ...api.WalletMetric) error {
 	return s.dbMetrics.Transaction(ctx, func(tx sql.MetricsDatabaseTx) error {
 		return tx.RecordWalletMetric(ctx, metrics...)
@@ -55,3 +91,25 @@ func (s *SQLStore) PruneMetrics(ctx context.Context, metric string, cutoff time.
 		return tx.PruneMetrics(ctx, metric, cutoff)
 	})
 }
+
+// SpendingReport returns the spending per host in n periods of the given
+// interval. It combines the contract prices of all contracts, including
+// archived ones, with the spending recorded in the contract metrics.
+func (s *SQLStore) SpendingReport(ctx context.Context, start time.Time, n uint64, interval time.Duration) ([]api.SpendingReportItem, error) {
+	var prices, spending []api.SpendingReportItem
+	err := s.db.Transaction(ctx, func(tx sql.DatabaseTx) (txErr error) {
+		prices, txErr = tx.ContractPriceReport(ctx, start, n, interval)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = s.dbMetrics.Transaction(ctx, func(tx sql.MetricsDatabaseTx) (txErr error) {
+		spending, txErr = tx.ContractSpendingReport(ctx, start, n, interval)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sql.MergeSpendingReportItems(prices, spending), nil
+}
@@ -2,6 +2,7 @@ package stores
 
 import (
 	"context"
+	"errors"
 	"math"
 	"sort"
 	"testing"
@@ -235,6 +236,99 @@ func TestContractPruneMetrics(t *testing.T) {
 	}
 }
 
+func TestSpendingReport(t *testing.T) {
+	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
+	defer ss.Close()
+
+	// add two hosts with a contract each, the contracts are created in the
+	// last period of the report
+	hks, err := ss.addTestHosts(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fcids, _, err := ss.addTestContracts(hks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// record cumulative spending of the contracts
+	now := time.Now().Truncate(time.Hour)
+	start := now.Add(-2 * time.Hour)
+	for _, m := range []api.ContractMetric{
+		{Timestamp: api.TimeRFC3339(start.Add(-10 * time.Minute)), ContractID: fcids[0], HostKey: hks[0], UploadSpending: types.NewCurrency64(5)},
+		{Timestamp: api.TimeRFC3339(start.Add(10 * time.Minute)), ContractID: fcids[0], HostKey: hks[0], UploadSpending: types.NewCurrency64(8)},
+		{Timestamp: api.TimeRFC3339(start.Add(30 * time.Minute)), ContractID: fcids[0], HostKey: hks[0], UploadSpending: types.NewCurrency64(10), FundAccountSpending: types.NewCurrency64(4)},
+		{Timestamp: api.TimeRFC3339(start.Add(70 * time.Minute)), ContractID: fcids[0], HostKey: hks[0], UploadSpending: types.NewCurrency64(15), FundAccountSpending: types.NewCurrency64(4)},
+		{Timestamp: api.TimeRFC3339(start.Add(90 * time.Minute)), ContractID: fcids[1], HostKey: hks[1], DeleteSpending: types.NewCurrency64(7)},
+		{Timestamp: api.TimeRFC3339(now.Add(2 * time.Hour)), ContractID: fcids[1], HostKey: hks[1], DeleteSpending: types.NewCurrency64(100)},
+	} {
+		if err := ss.RecordContractMetric(context.Background(), m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// assert the spending is broken down by period and host
+	items, err := ss.SpendingReport(context.Background(), start, 3, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	period := func(i int) api.TimeRFC3339 { return api.TimeRFC3339(start.Add(time.Duration(i) * time.Hour)) }
+	expected := []api.SpendingReportItem{
+		{Period: period(0), HostKey: hks[0], SpendingBreakdown: api.SpendingBreakdown{ContractSpending: api.ContractSpending{Uploads: types.NewCurrency64(5), FundAccount: types.NewCurrency64(4)}}},
+		{Period: period(1), HostKey: hks[0], SpendingBreakdown: api.SpendingBreakdown{ContractSpending: api.ContractSpending{Uploads: types.NewCurrency64(5)}}},
+		{Period: period(1), HostKey: hks[1], SpendingBreakdown: api.SpendingBreakdown{ContractSpending: api.ContractSpending{Deletions: types.NewCurrency64(7)}}},
+		{Period: period(2), HostKey: hks[0], SpendingBreakdown: api.SpendingBreakdown{ContractPrice: types.NewCurrency64(1)}},
+		{Period: period(2), HostKey: hks[1], SpendingBreakdown: api.SpendingBreakdown{ContractPrice: types.NewCurrency64(1)}},
+	}
+	sort.SliceStable(expected, func(i, j int) bool {
+		if expected[i].Period != expected[j].Period {
+			return time.Time(expected[i].Period).Before(time.Time(expected[j].Period))
+		}
+		return expected[i].HostKey.String() < expected[j].HostKey.String()
+	})
+	if !cmp.Equal(items, expected, cmp.Comparer(api.CompareTimeRFC3339)) {
+		t.Fatal("unexpected items", cmp.Diff(items, expected, cmp.Comparer(api.CompareTimeRFC3339)))
+	}
+
+	// assert the report aggregates the items
+	report := api.NewSpendingReport(start, 3, time.Hour, items, "usd", 0.5)
+	if !report.Total.Total.Equals(types.NewCurrency64(23)) {
+		t.Fatalf("unexpected total %v", report.Total.Total)
+	} else if len(report.Hosts) != 2 || len(report.Periods) != 3 {
+		t.Fatalf("unexpected number of hosts or periods, %v %v", len(report.Hosts), len(report.Periods))
+	} else if !report.Periods[1].Total.Equals(types.NewCurrency64(12)) {
+		t.Fatalf("unexpected period total %v", report.Periods[1].Total)
+	}
+
+	// assert a report that starts later only attributes the spending since the
+	// contract's last metric before the start of the report
+	items, err = ss.SpendingReport(context.Background(), period(1).Std(), 1, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	} else if !cmp.Equal(items, expected[1:3], cmp.Comparer(api.CompareTimeRFC3339)) {
+		t.Fatal("unexpected items", cmp.Diff(items, expected[1:3], cmp.Comparer(api.CompareTimeRFC3339)))
+	}
+
+	// prune the metrics before the second metric of the first contract and
+	// assert the first remaining metric isn't attributed in full
+	if err := ss.PruneMetrics(context.Background(), api.MetricContract, start.Add(20*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	items, err = ss.SpendingReport(context.Background(), start, 1, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(items) != 1 {
+		t.Fatalf("unexpected items %+v", items)
+	} else if !items[0].Uploads.Equals(types.NewCurrency64(2)) || !items[0].FundAccount.Equals(types.NewCurrency64(4)) {
+		t.Fatalf("unexpected spending %+v", items[0].ContractSpending)
+	}
+
+	// assert the number of periods is limited
+	if _, err := ss.SpendingReport(context.Background(), start, api.MetricMaxIntervals+1, time.Hour); !errors.Is(err, api.ErrMaxIntervalsExceeded) {
+		t.Fatal("unexpected error", err)
+	}
+}
+
 func TestNormaliseTimestamp(t *testing.T) {
 	tests := []struct {
 		start    time.Time
@@ -310,6 +404,153 @@ func TestWalletMetrics(t *testing.T) {
 	}
 }
 
+func TestHealthMetrics(t *testing.T) {
+	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
+	defer ss.Close()
+
+	// Create metrics to query.
+	start, end := api.HealthMetricBuckets[0], api.HealthMetricBuckets[1]
+	times := []time.Time{time.UnixMilli(3), time.UnixMilli(1), time.UnixMilli(2)}
+	for _, recordedTime := range times {
+		metric := api.HealthMetric{
+			Timestamp: api.TimeRFC3339(recordedTime),
+			SlabHealthStats: api.SlabHealthStats{
+				Buckets: []api.HealthBucket{
+					{End: &start, Slabs: frand.Uint64n(100), MinHealth: -1, Bytes: frand.Uint64n(math.MaxInt64)},
+					{Start: &start, End: &end, Slabs: frand.Uint64n(100), Bytes: frand.Uint64n(math.MaxInt64)},
+				},
+				HealthCutoff:     .75,
+				BytesBelowCutoff: frand.Uint64n(math.MaxUint64),
+			},
+		}
+		if err := ss.RecordHealthMetric(context.Background(), metric); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Fetch all metrics
+	metrics, err := ss.HealthMetrics(context.Background(), time.UnixMilli(1), 3, time.Millisecond, api.HealthMetricsQueryOpts{})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(metrics) != 3 {
+		t.Fatalf("expected 3 metrics, got %v", len(metrics))
+	} else if !sort.SliceIsSorted(metrics, func(i, j int) bool {
+		return time.Time(metrics[i].Timestamp).Before(time.Time(metrics[j].Timestamp))
+	}) {
+		t.Fatalf("expected metrics to be sorted by time, %+v", metrics)
+	}
+	for _, m := range metrics {
+		if m.HealthCutoff != .75 {
+			t.Fatal("unexpected cutoff", m.HealthCutoff)
+		} else if len(m.Buckets) != 2 || m.Buckets[0].Start != nil || *m.Buckets[0].End != start || *m.Buckets[1].Start != start || m.Buckets[0].MinHealth != -1 {
+			t.Fatalf("unexpected buckets %+v", m.Buckets)
+		}
+	}
+
+	// Prune metrics
+	if err := ss.PruneMetrics(context.Background(), api.MetricHealth, time.UnixMilli(3)); err != nil {
+		t.Fatal(err)
+	} else if metrics, err := ss.HealthMetrics(context.Background(), time.UnixMilli(1), 3, time.Millisecond, api.HealthMetricsQueryOpts{}); err != nil {
+		t.Fatal(err)
+	} else if len(metrics) != 1 {
+		t.Fatalf("expected 1 metric, got %v", len(metrics))
+	}
+}
+
+func TestHostMetrics(t *testing.T) {
+	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
+	defer ss.Close()
+
+	// Record metrics for 4 hosts in two periods, the first host is recorded
+	// twice in the first period.
+	hosts := []types.PublicKey{{1}, {2}, {3}, {4}}
+	var metrics []api.HostMetric
+	for i, hk := range hosts {
+		metrics = append(metrics, api.HostMetric{
+			Timestamp:        api.TimeRFC3339(time.UnixMilli(int64(1 + i))),
+			HostKey:          hk,
+			StoragePrice:     types.NewCurrency64(uint64(i + 1)),
+			IngressPrice:     types.NewCurrency(frand.Uint64n(math.MaxUint64), frand.Uint64n(math.MaxUint64)),
+			EgressPrice:      types.NewCurrency64(uint64(10 * (i + 1))),
+			RemainingStorage: uint64(i+1) << 40,
+			Uptime:           float64(i+1) / 4,
+			Score:            float64(i+1) / 10,
+			Usable:           i%2 == 0,
+		})
+	}
+	metrics = append(metrics, api.HostMetric{
+		Timestamp:    api.TimeRFC3339(time.UnixMilli(9)),
+		HostKey:      hosts[0],
+		StoragePrice: types.NewCurrency64(100),
+		Score:        .9,
+	}, api.HostMetric{
+		Timestamp:    api.TimeRFC3339(time.UnixMilli(11)),
+		HostKey:      hosts[1],
+		StoragePrice: types.NewCurrency64(200),
+		Score:        .8,
+		Usable:       true,
+	})
+	if err := ss.RecordHostMetric(context.Background(), metrics...); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fetch the first host's history.
+	history, err := ss.HostMetrics(context.Background(), time.UnixMilli(0), 2, 10*time.Millisecond, api.HostMetricsQueryOpts{HostKey: hosts[0]})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(history) != 1 {
+		t.Fatalf("expected 1 metric, got %v", len(history))
+	} else if m := history[0]; m.HostKey != hosts[0] || !m.StoragePrice.Equals(types.NewCurrency64(1)) || m.RemainingStorage != 1<<40 || m.Uptime != .25 || m.Score != .1 || !m.Usable {
+		t.Fatalf("unexpected metric %+v", m)
+	} else if !m.IngressPrice.Equals(metrics[0].IngressPrice) {
+		t.Fatal("ingress price mismatch")
+	}
+	history, err = ss.HostMetrics(context.Background(), time.UnixMilli(0), 2, 5*time.Millisecond, api.HostMetricsQueryOpts{HostKey: hosts[0]})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(history) != 2 || history[1].Score != .9 {
+		t.Fatalf("unexpected history %+v", history)
+	}
+
+	// Fetch the network metrics, the first period uses the last metric of
+	// the first host.
+	network, err := ss.HostNetworkMetrics(context.Background(), time.UnixMilli(0), 2, 10*time.Millisecond, api.HostNetworkMetricsQueryOpts{})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(network) != 2 {
+		t.Fatalf("expected 2 metrics, got %v", len(network))
+	}
+	m := network[0]
+	if time.Time(m.Timestamp) != time.UnixMilli(0) || m.Hosts != 4 || m.Usable != 1 {
+		t.Fatalf("unexpected metric %+v", m)
+	} else if m.StoragePrice != (api.Percentiles[types.Currency]{
+		P10: types.NewCurrency64(2),
+		P25: types.NewCurrency64(2),
+		P50: types.NewCurrency64(3),
+		P75: types.NewCurrency64(4),
+		P90: types.NewCurrency64(100),
+	}) {
+		t.Fatalf("unexpected storage price percentiles %+v", m.StoragePrice)
+	} else if m.Score != (api.Percentiles[float64]{P10: .2, P25: .2, P50: .3, P75: .4, P90: .9}) {
+		t.Fatalf("unexpected score percentiles %+v", m.Score)
+	} else if m.RemainingStorage.P50 != 2<<40 {
+		t.Fatalf("unexpected remaining storage percentiles %+v", m.RemainingStorage)
+	}
+	m = network[1]
+	if time.Time(m.Timestamp) != time.UnixMilli(10) || m.Hosts != 1 || m.Usable != 1 || m.Score.P10 != .8 || m.Score.P90 != .8 {
+		t.Fatalf("unexpected metric %+v", m)
+	}
+
+	// Prune metrics
+	if err := ss.PruneMetrics(context.Background(), api.MetricHost, time.UnixMilli(10)); err != nil {
+		t.Fatal(err)
+	} else if network, err := ss.HostNetworkMetrics(context.Background(), time.UnixMilli(0), 2, 10*time.Millisecond, api.HostNetworkMetricsQueryOpts{}); err != nil {
+		t.Fatal(err)
+	} else if len(network) != 1 {
+		t.Fatalf("expected 1 metric, got %v", len(network))
+	}
+}
+
 func normaliseTimestamp(start time.Time, interval time.Duration, t sql.UnixTimeMS) sql.UnixTimeMS {
 	startMS := start.UnixMilli()
 	toNormaliseMS := time.Time(t).UnixMilli()
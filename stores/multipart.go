@@ -103,3 +103,86 @@ func (s *SQLStore) CompleteMultipartUpload(ctx context.Context, bucket, key stri
 		ETag: eTag,
 	}, nil
 }
+
+func (s *SQLStore) CreateTusUpload(ctx context.Context, bucket, key string, ec object.EncryptionKey, mimeType string, metadata api.ObjectUserMetadata, length uint64) (resp api.TusUpload, err error) {
+	var prune bool
+	err = s.db.Transaction(ctx, func(tx sql.DatabaseTx) error {
+		uploadID, err := tx.InsertMultipartUpload(ctx, bucket, key, ec, mimeType, metadata)
+		if err != nil {
+			return err
+		} else if err := tx.InsertTusUpload(ctx, uploadID, length); err != nil {
+			return err
+		}
+		resp, err = tx.TusUpload(ctx, uploadID)
+		if err != nil {
+			return err
+		}
+
+		// empty uploads are complete right away
+		if resp.Complete() {
+			prune, err = completeTusUpload(ctx, tx, resp)
+		}
+		return err
+	})
+	if err == nil && prune {
+		s.triggerSlabPruning()
+	}
+	return
+}
+
+func (s *SQLStore) TusUpload(ctx context.Context, uploadID string) (resp api.TusUpload, err error) {
+	err = s.db.Transaction(ctx, func(tx sql.DatabaseTx) (err error) {
+		resp, err = tx.TusUpload(ctx, uploadID)
+		return
+	})
+	return
+}
+
+// UpdateTusUploadOffset advances the offset of a tus upload, once the offset
+// reaches the length of the upload it is completed in the same transaction.
+func (s *SQLStore) UpdateTusUploadOffset(ctx context.Context, uploadID string, offset, length uint64) (resp api.TusUpload, err error) {
+	var prune bool
+	err = s.db.Transaction(ctx, func(tx sql.DatabaseTx) (err error) {
+		resp, err = tx.UpdateTusUploadOffset(ctx, uploadID, offset, length)
+		if err != nil {
+			return err
+		} else if resp.Complete() {
+			prune, err = completeTusUpload(ctx, tx, resp)
+		}
+		return err
+	})
+	if err == nil && prune {
+		s.triggerSlabPruning()
+	}
+	return
+}
+
+// completeTusUpload completes the multipart upload that backs the given tus
+// upload and marks the tus upload as completed.
+func completeTusUpload(ctx context.Context, tx sql.DatabaseTx, upload api.TusUpload) (prune bool, _ error) {
+	resp, err := tx.MultipartUploadParts(ctx, upload.Bucket, upload.Key, upload.ID, 0, -1)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch parts: %w", err)
+	}
+
+	// only consider the parts that were acknowledged, a chunk that failed to
+	// update the offset might have left a dangling part behind
+	var parts []api.MultipartCompletedPart
+	for _, part := range resp.Parts {
+		if part.PartNumber <= upload.Parts {
+			parts = append(parts, api.MultipartCompletedPart{
+				PartNumber: part.PartNumber,
+				ETag:       part.ETag,
+			})
+		}
+	}
+
+	if err := tx.CompleteTusUpload(ctx, upload.ID); err != nil {
+		return false, err
+	} else if prune, err = tx.DeleteObject(ctx, upload.Bucket, upload.Key); err != nil {
+		return false, fmt.Errorf("failed to delete object: %w", err)
+	} else if _, err := tx.CompleteMultipartUpload(ctx, upload.Bucket, upload.Key, upload.ID, parts, api.CompleteMultipartOptions{}); err != nil {
+		return false, fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	return prune, nil
+}
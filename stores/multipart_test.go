@@ -3,6 +3,7 @@ package stores
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"reflect"
 	"sort"
 	"strings"
@@ -309,3 +310,96 @@ func TestMultipartUploadEmptyObjects(t *testing.T) {
 		t.Fatal("unexpected etag")
 	}
 }
+
+func TestTusUploads(t *testing.T) {
+	ss := newTestSQLStore(t, defaultTestSQLStoreConfig)
+	defer ss.Close()
+
+	// create a tus upload
+	upload, err := ss.CreateTusUpload(context.Background(), testBucket, "/foo", object.NoOpKey, testMimeType, testMetadata, 10)
+	if err != nil {
+		t.Fatal(err)
+	} else if upload.Bucket != testBucket || upload.Key != "/foo" || upload.Length != 10 || upload.Offset != 0 || upload.Parts != 0 {
+		t.Fatalf("unexpected upload %+v", upload)
+	}
+
+	// the multipart upload should exist
+	if _, err := ss.MultipartUpload(context.Background(), upload.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// advance the offset
+	upload, err = ss.UpdateTusUploadOffset(context.Background(), upload.ID, 0, 4)
+	if err != nil {
+		t.Fatal(err)
+	} else if upload.Offset != 4 || upload.Parts != 1 {
+		t.Fatalf("unexpected upload %+v", upload)
+	}
+
+	// assert the offset has to match
+	if _, err := ss.UpdateTusUploadOffset(context.Background(), upload.ID, 0, 4); !errors.Is(err, api.ErrTusUploadOffsetMismatch) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// assert the length can't be exceeded
+	if _, err := ss.UpdateTusUploadOffset(context.Background(), upload.ID, 4, 7); !errors.Is(err, api.ErrTusUploadLengthExceeded) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// assert the upload is persisted
+	fetched, err := ss.TusUpload(context.Background(), upload.ID)
+	if err != nil {
+		t.Fatal(err)
+	} else if fetched.Offset != 4 || fetched.Parts != 1 {
+		t.Fatalf("unexpected upload %+v", fetched)
+	}
+
+	// abort the multipart upload, the tus upload should be gone as well
+	if err := ss.AbortMultipartUpload(context.Background(), testBucket, "/foo", upload.ID); err != nil {
+		t.Fatal(err)
+	} else if _, err := ss.TusUpload(context.Background(), upload.ID); !errors.Is(err, api.ErrTusUploadNotFound) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// create another upload and complete it
+	upload, err = ss.CreateTusUpload(context.Background(), testBucket, "/bar", object.NoOpKey, testMimeType, testMetadata, 4)
+	if err != nil {
+		t.Fatal(err)
+	} else if upload, err = ss.UpdateTusUploadOffset(context.Background(), upload.ID, 0, 4); err != nil {
+		t.Fatal(err)
+	} else if !upload.Complete() {
+		t.Fatalf("expected upload to be complete %+v", upload)
+	}
+
+	// assert the object was created and the multipart upload is gone
+	if _, err := ss.Object(context.Background(), testBucket, "/bar"); err != nil {
+		t.Fatal(err)
+	} else if _, err := ss.MultipartUpload(context.Background(), upload.ID); !errors.Is(err, api.ErrMultipartUploadNotFound) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// assert the completed upload is kept around
+	if fetched, err := ss.TusUpload(context.Background(), upload.ID); err != nil {
+		t.Fatal(err)
+	} else if fetched.Offset != 4 || fetched.Length != 4 {
+		t.Fatalf("unexpected upload %+v", fetched)
+	}
+
+	// assert it's gone once it expired
+	if _, err := ss.DB().Exec(context.Background(), "UPDATE tus_uploads SET completed_at = ?", time.Now().Add(-api.TusCompletedUploadExpiry-time.Minute)); err != nil {
+		t.Fatal(err)
+	} else if _, err := ss.TusUpload(context.Background(), upload.ID); !errors.Is(err, api.ErrTusUploadNotFound) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// assert expired uploads are pruned when creating a new one
+	if _, err := ss.CreateTusUpload(context.Background(), testBucket, "/baz", object.NoOpKey, testMimeType, testMetadata, 10); err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err := ss.DB().QueryRow(context.Background(), "SELECT COUNT(*) FROM tus_uploads").Scan(&n); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Fatalf("expected 1 tus upload, got %d", n)
+	}
+}
@@ -10,12 +10,24 @@ import (
 )
 
 const (
-	SettingGouging = "gouging"
-	SettingPinned  = "pinned"
-	SettingS3      = "s3"
-	SettingUpload  = "upload"
+	SettingBandwidth = "bandwidth"
+	SettingGouging   = "gouging"
+	SettingPinned    = "pinned"
+	SettingPlacement = "placement"
+	SettingRepair    = "repair"
+	SettingS3        = "s3"
+	SettingUpload    = "upload"
 )
 
+func (s *SQLStore) BandwidthSettings(ctx context.Context) (bs api.BandwidthSettings, err error) {
+	err = s.fetchSetting(ctx, SettingBandwidth, &bs)
+	return
+}
+
+func (s *SQLStore) UpdateBandwidthSettings(ctx context.Context, bs api.BandwidthSettings) error {
+	return s.updateSetting(ctx, SettingBandwidth, bs)
+}
+
 func (s *SQLStore) GougingSettings(ctx context.Context) (gs api.GougingSettings, err error) {
 	err = s.fetchSetting(ctx, SettingGouging, &gs)
 	return
@@ -34,6 +46,24 @@ func (s *SQLStore) UpdatePinnedSettings(ctx context.Context, ps api.PinnedSettin
 	return s.updateSetting(ctx, SettingPinned, ps)
 }
 
+func (s *SQLStore) PlacementSettings(ctx context.Context) (ps api.PlacementSettings, err error) {
+	err = s.fetchSetting(ctx, SettingPlacement, &ps)
+	return
+}
+
+func (s *SQLStore) UpdatePlacementSettings(ctx context.Context, ps api.PlacementSettings) error {
+	return s.updateSetting(ctx, SettingPlacement, ps)
+}
+
+func (s *SQLStore) RepairSettings(ctx context.Context) (rs api.RepairSettings, err error) {
+	err = s.fetchSetting(ctx, SettingRepair, &rs)
+	return
+}
+
+func (s *SQLStore) UpdateRepairSettings(ctx context.Context, rs api.RepairSettings) error {
+	return s.updateSetting(ctx, SettingRepair, rs)
+}
+
 func (s *SQLStore) UploadSettings(ctx context.Context) (us api.UploadSettings, err error) {
 	err = s.fetchSetting(ctx, SettingUpload, &us)
 	return
@@ -9,6 +9,7 @@ import (
 
 	"go.sia.tech/core/types"
 	"go.sia.tech/renterd/v2/alerts"
+	"go.sia.tech/renterd/v2/internal/geoip"
 	"go.sia.tech/renterd/v2/stores/sql"
 	"go.uber.org/zap"
 )
@@ -19,6 +20,7 @@ type (
 		DB                            sql.Database
 		DBMetrics                     sql.MetricsDatabase
 		Alerts                        alerts.Alerter
+		GeoIP                         *geoip.Database
 		PartialSlabDir                string
 		Migrate                       bool
 		AnnouncementMaxAge            time.Duration
@@ -38,6 +40,7 @@ type (
 		alerts    alerts.Alerter
 		db        sql.Database
 		dbMetrics sql.MetricsDatabase
+		geoIP     *geoip.Database
 		logger    *zap.SugaredLogger
 
 		walletAddress types.Address
@@ -52,11 +55,14 @@ type (
 		shutdownCtx       context.Context
 		shutdownCtxCancel context.CancelFunc
 
+		blocklistSigChan       chan struct{}
 		hostSectorPruneSigChan chan struct{}
 		slabPruneSigChan       chan struct{}
 		wg                     sync.WaitGroup
 
 		mu                      sync.Mutex
+		announcedHosts          map[types.PublicKey]struct{}
+		resolvedHosts           map[types.PublicKey]resolvedHost
 		lastPrunedHostSectorsAt time.Time
 		lastPrunedSlabsAt       time.Time
 		closed                  bool
@@ -95,14 +101,19 @@ func NewSQLStore(cfg Config) (*SQLStore, error) {
 		alerts:    cfg.Alerts,
 		db:        dbMain,
 		dbMetrics: dbMetrics,
+		geoIP:     cfg.GeoIP,
 		logger:    l.Sugar(),
 
 		settings:      make(map[string]string),
 		walletAddress: cfg.WalletAddress,
 
+		blocklistSigChan:       make(chan struct{}, 1),
 		hostSectorPruneSigChan: make(chan struct{}, 1),
 		slabPruneSigChan:       make(chan struct{}, 1),
 
+		announcedHosts: make(map[types.PublicKey]struct{}),
+		resolvedHosts:  make(map[types.PublicKey]resolvedHost),
+
 		lastPrunedHostSectorsAt: time.Now(),
 		lastPrunedSlabsAt:       time.Now(),
 
@@ -121,9 +132,37 @@ func NewSQLStore(cfg Config) (*SQLStore, error) {
 	}
 
 	ss.initPruneLoops()
+	ss.initBlocklistLoop()
+	ss.warnUnmatchedBlocklistRules()
 	return ss, nil
 }
 
+// warnUnmatchedBlocklistRules logs a warning if the blocklist contains ASN
+// rules but no GeoIP database is configured to match them.
+func (s *SQLStore) warnUnmatchedBlocklistRules() {
+	if s.geoIP != nil {
+		return
+	}
+	blocklist, err := s.HostBlocklist(context.Background())
+	if err != nil {
+		s.logger.Warnw("failed to fetch blocklist", zap.Error(err))
+		return
+	}
+	for _, entry := range blocklist {
+		if rule, ok, err := sql.ParseBlocklistRule(entry); err == nil && ok && rule.ASN != 0 {
+			s.logger.Warnw("the blocklist contains ASN entries but no GeoIP database is configured, hosts won't be matched against them", "entry", entry)
+		}
+	}
+}
+
+func (s *SQLStore) initBlocklistLoop() {
+	s.wg.Add(1)
+	go func() {
+		s.blocklistLoop()
+		s.wg.Done()
+	}()
+}
+
 func (s *SQLStore) initPruneLoops() {
 	s.wg.Add(1)
 	go func() {
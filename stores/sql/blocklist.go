@@ -0,0 +1,97 @@
+package sql
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.sia.tech/renterd/v2/api"
+)
+
+// A BlocklistRule is a blocklist entry that can't be matched against the net
+// addresses of a host by the database. CIDR ranges and ASNs are matched
+// against the resolved IPs of a host and wildcards against its hostnames.
+type BlocklistRule struct {
+	Entry string
+
+	Prefix netip.Prefix
+	ASN    uint32
+	Suffix string
+}
+
+// ParseBlocklistRule parses a blocklist entry of the form '203.0.113.0/24',
+// '*.example.com' or 'AS64496'. The boolean indicates whether the entry is a
+// rule, if it isn't, the entry is a plain address that is matched by the
+// database.
+func ParseBlocklistRule(entry string) (BlocklistRule, bool, error) {
+	switch {
+	case strings.Contains(entry, "/"):
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return BlocklistRule{}, false, fmt.Errorf("%w: %v", api.ErrInvalidBlocklistEntry, err)
+		}
+		return BlocklistRule{Entry: entry, Prefix: prefix.Masked()}, true, nil
+	case strings.HasPrefix(entry, "*."):
+		suffix := strings.ToLower(entry[1:])
+		if len(suffix) < 2 || strings.Contains(suffix, "*") {
+			return BlocklistRule{}, false, fmt.Errorf("%w: invalid wildcard '%v'", api.ErrInvalidBlocklistEntry, entry)
+		}
+		return BlocklistRule{Entry: entry, Suffix: suffix}, true, nil
+	case len(entry) > 2 && strings.EqualFold(entry[:2], "AS") && isDigits(entry[2:]):
+		asn, err := strconv.ParseUint(entry[2:], 10, 32)
+		if err != nil || asn == 0 {
+			return BlocklistRule{}, false, fmt.Errorf("%w: invalid ASN '%v'", api.ErrInvalidBlocklistEntry, entry)
+		}
+		return BlocklistRule{Entry: entry, ASN: uint32(asn)}, true, nil
+	case strings.Contains(entry, "*"):
+		return BlocklistRule{}, false, fmt.Errorf("%w: wildcards are only supported as the leftmost label, e.g. '*.example.com'", api.ErrInvalidBlocklistEntry)
+	default:
+		return BlocklistRule{}, false, nil
+	}
+}
+
+// IsBlocklistRule returns true if the given entry is a blocklist rule rather
+// than a plain address.
+func IsBlocklistRule(entry string) bool {
+	_, ok, err := ParseBlocklistRule(entry)
+	return ok && err == nil
+}
+
+// NeedsIPs returns true if the rule is matched against the resolved IPs of a
+// host.
+func (r BlocklistRule) NeedsIPs() bool {
+	return r.Suffix == ""
+}
+
+// Matches returns true if the rule matches any of the given hostnames, IPs or
+// ASNs.
+func (r BlocklistRule) Matches(hostnames []string, ips []netip.Addr, asns []uint32) bool {
+	switch {
+	case r.Suffix != "":
+		for _, hostname := range hostnames {
+			if strings.HasSuffix(strings.ToLower(hostname), r.Suffix) {
+				return true
+			}
+		}
+	case r.ASN != 0:
+		return slices.Contains(asns, r.ASN)
+	case r.Prefix.IsValid():
+		for _, ip := range ips {
+			if r.Prefix.Contains(ip.Unmap()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -96,11 +96,19 @@ type (
 		// duplicates but can contain gaps.
 		CompleteMultipartUpload(ctx context.Context, bucket, key, uploadID string, parts []api.MultipartCompletedPart, opts api.CompleteMultipartOptions) (string, error)
 
+		// CompleteTusUpload marks the tus upload with the given ID as
+		// completed.
+		CompleteTusUpload(ctx context.Context, uploadID string) error
+
 		// Contract returns the metadata of the contract with the given id, if
 		// the requested contract does not exist, or if it is archived,
 		// ErrContractNotFound is returned.
 		Contract(ctx context.Context, id types.FileContractID) (cm api.ContractMetadata, err error)
 
+		// ContractPriceReport returns the contract prices paid per host in n
+		// periods of the given interval, including archived contracts.
+		ContractPriceReport(ctx context.Context, start time.Time, n uint64, interval time.Duration) ([]api.SpendingReportItem, error)
+
 		// ContractRoots returns the roots of the contract with the given ID.
 		ContractRoots(ctx context.Context, fcid types.FileContractID) ([]types.Hash256, error)
 
@@ -108,6 +116,11 @@ type (
 		// opts argument can be used to filter the result.
 		Contracts(ctx context.Context, opts api.ContractsOpts) ([]api.ContractMetadata, error)
 
+		// ContractsRenterCost returns the sum of what the renter paid for
+		// contracts that were formed, renewed or refreshed at or after the
+		// given height, including archived ones.
+		ContractsRenterCost(ctx context.Context, minStartHeight uint64) (types.Currency, error)
+
 		// ContractSize returns the size of the contract with the given ID as
 		// well as the estimated number of bytes that can be pruned from it.
 		ContractSize(ctx context.Context, id types.FileContractID) (api.ContractSize, error)
@@ -130,6 +143,11 @@ type (
 		// api.ErrBucketNotFound.
 		DeleteBucket(ctx context.Context, bucket string) error
 
+		// DeleteContractSectors removes the links between the contract and
+		// the sectors with the given roots, incrementing the lost sector count
+		// of the host in the process.
+		DeleteContractSectors(ctx context.Context, fcid types.FileContractID, roots []types.Hash256) (int, error)
+
 		// DeleteHostSector deletes all contract sector links that a host has
 		// with the given root incrementing the lost sector count in the
 		// process.
@@ -173,6 +191,10 @@ type (
 		// unique upload ID.
 		InsertMultipartUpload(ctx context.Context, bucket, key string, ec object.EncryptionKey, mimeType string, metadata api.ObjectUserMetadata) (string, error)
 
+		// InsertTusUpload creates a new tus upload of given length for the
+		// multipart upload with the given ID.
+		InsertTusUpload(ctx context.Context, uploadID string, length uint64) error
+
 		// InsertObject inserts a new object into the database.
 		InsertObject(ctx context.Context, bucket, key string, o object.Object, mimeType, eTag string, md api.ObjectUserMetadata) error
 
@@ -249,6 +271,22 @@ type (
 		// RecordContractSpending records new spending for a contract
 		RecordContractSpending(ctx context.Context, fcid types.FileContractID, revisionNumber, size uint64, newSpending api.ContractSpending) error
 
+		// RecordObjectAccess increments the read count of an object and bumps
+		// its last access time if 'accessedAt' is more recent.
+		RecordObjectAccess(ctx context.Context, bucket, key string, readCount uint64, accessedAt time.Time) error
+
+		// UpdateHostBlocklistRuleHosts updates which hosts are blocked by
+		// the blocklist rule with the given entry.
+		UpdateHostBlocklistRuleHosts(ctx context.Context, entry string, blocked, unblocked []types.PublicKey) error
+
+		// UpdateHostLabels replaces the labels and notes of a host.
+		UpdateHostLabels(ctx context.Context, hk types.PublicKey, labels []string, notes string) error
+
+		// RecordHostBenchmark records the result of a host benchmark in the
+		// database. The throughput and time to first byte are only updated
+		// if the benchmark succeeded.
+		RecordHostBenchmark(ctx context.Context, hk types.PublicKey, b api.HostBenchmark) error
+
 		// RecordHostScans records the results of host scans in the database
 		// such as recording the settings and price table of a host in case of
 		// success and updating the uptime and downtime of a host.
@@ -298,13 +336,27 @@ type (
 		// Slab returns the slab with the given ID or api.ErrSlabNotFound.
 		Slab(ctx context.Context, key object.EncryptionKey) (object.Slab, error)
 
+		// SlabBuckets returns the names of the buckets of the objects
+		// referencing the slab with the given key.
+		SlabBuckets(ctx context.Context, key object.EncryptionKey) ([]string, error)
+
+		// SlabHealthStats returns the health distribution of all slabs that
+		// aren't buffered.
+		SlabHealthStats(ctx context.Context, healthCutoff float64) (api.SlabHealthStats, error)
+
 		// SlabsForMigration returns up to 'limit' slabs with a health smaller
-		// than or equal to 'healthCutoff'
-		SlabsForMigration(ctx context.Context, healthCutoff float64, limit int) ([]api.UnhealthySlab, error)
+		// than or equal to 'healthCutoff', ordered by their effective health
+		// taking into account the boosts in the repair settings. The first
+		// 'offset' slabs are skipped.
+		SlabsForMigration(ctx context.Context, healthCutoff float64, offset, limit int, rs api.RepairSettings) ([]api.UnhealthySlab, error)
 
 		// Tip returns the sync height.
 		Tip(ctx context.Context) (types.ChainIndex, error)
 
+		// TusUpload returns the tus upload with the given ID or
+		// api.ErrTusUploadNotFound if the upload doesn't exist.
+		TusUpload(ctx context.Context, uploadID string) (api.TusUpload, error)
+
 		// UnspentSiacoinElements returns all wallet outputs in the database.
 		UnspentSiacoinElements(ctx context.Context) (types.ChainIndex, []types.SiacoinElement, error)
 
@@ -318,6 +370,10 @@ type (
 		// UpdateContract sets the given metadata on the contract with given fcid.
 		UpdateContract(ctx context.Context, fcid types.FileContractID, c api.ContractMetadata) error
 
+		// UpdateContractManual marks the given contract as manual or not and
+		// updates its renewal settings.
+		UpdateContractManual(ctx context.Context, fcid types.FileContractID, manual bool, renewal *api.ContractRenewalSettings) error
+
 		// UpdateContractUsability updates the usability of the given contract.
 		UpdateContractUsability(ctx context.Context, fcid types.FileContractID, usability string) error
 
@@ -349,6 +405,11 @@ type (
 		// the health of the updated slabs becomes invalid
 		UpdateSlabHealth(ctx context.Context, limit int64, minValidity, maxValidity time.Duration) (int64, error)
 
+		// UpdateTusUploadOffset advances the offset of a tus upload by the
+		// given length and increments its number of parts. The offset has to
+		// match the upload's current offset.
+		UpdateTusUploadOffset(ctx context.Context, uploadID string, offset, length uint64) (api.TusUpload, error)
+
 		// UpsertContractSectors ensures the given contract-sector links are
 		// present in the database.
 		UpsertContractSectors(ctx context.Context, contractSectors []ContractSector) error
@@ -396,10 +457,25 @@ type (
 		// and options.
 		ContractMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.ContractMetricsQueryOpts) ([]api.ContractMetric, error)
 
+		// ContractSpendingReport returns the contract spending per host in n
+		// periods of the given interval.
+		ContractSpendingReport(ctx context.Context, start time.Time, n uint64, interval time.Duration) ([]api.SpendingReportItem, error)
+
 		// ContractPruneMetrics returns the contract prune metrics for the given
 		// time range and options.
 		ContractPruneMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.ContractPruneMetricsQueryOpts) ([]api.ContractPruneMetric, error)
 
+		// HealthMetrics returns health metrics for the given time range.
+		HealthMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HealthMetricsQueryOpts) ([]api.HealthMetric, error)
+
+		// HostMetrics returns host metrics for the given time range and
+		// options.
+		HostMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HostMetricsQueryOpts) ([]api.HostMetric, error)
+
+		// HostNetworkMetrics returns the distribution of the host metrics
+		// across all hosts for the given time range.
+		HostNetworkMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HostNetworkMetricsQueryOpts) ([]api.HostNetworkMetric, error)
+
 		// PruneMetrics deletes metrics of a certain type older than the given
 		// cutoff time.
 		PruneMetrics(ctx context.Context, metric string, cutoff time.Time) error
@@ -410,6 +486,12 @@ type (
 		// RecordContractPruneMetric records contract prune metrics.
 		RecordContractPruneMetric(ctx context.Context, metrics ...api.ContractPruneMetric) error
 
+		// RecordHealthMetric records health metrics.
+		RecordHealthMetric(ctx context.Context, metrics ...api.HealthMetric) error
+
+		// RecordHostMetric records host metrics.
+		RecordHostMetric(ctx context.Context, metrics ...api.HostMetric) error
+
 		// RecordWalletMetric records wallet metrics.
 		RecordWalletMetric(ctx context.Context, metrics ...api.WalletMetric) error
 
@@ -9,6 +9,8 @@ import (
 	"math"
 	"math/big"
 	"net"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -149,7 +151,7 @@ func AncestorContracts(ctx context.Context, tx sql.Tx, fcid types.FileContractID
 		SELECT
 			c.fcid, c.host_id, c.host_key,
 			c.archival_reason, c.proof_height, c.renewed_from, c.renewed_to, c.revision_height, c.revision_number, c.size, c.start_height, c.state, c.usability, c.window_start, c.window_end,
-			c.contract_price, c.initial_renter_funds,
+			c.contract_price, c.initial_renter_funds, c.renter_cost, c.manual, c.manual_renewal, c.contract_set,
 			c.delete_spending, c.fund_account_spending, c.sector_roots_spending, c.upload_spending
 		FROM contracts AS c
 		WHERE start_height >= ? AND archival_reason IS NOT NULL
@@ -193,6 +195,7 @@ func ArchiveContract(ctx context.Context, tx sql.Tx, fcid types.FileContractID,
 }
 
 func AutopilotConfig(ctx context.Context, tx sql.Tx) (cfg api.AutopilotConfig, err error) {
+	var weights, labelRules, contractSets dsql.NullString
 	err = tx.QueryRow(ctx, `
 SELECT
 	enabled,
@@ -203,9 +206,15 @@ SELECT
 	contracts_upload,
 	contracts_storage,
 	contracts_prune,
+	COALESCE(contracts_budget, '0'),
+	contracts_stagger_renewals,
+	contracts_max_renewals_per_cycle,
 	hosts_max_downtime_hours,
 	hosts_min_protocol_version,
-	hosts_max_consecutive_scan_failures
+	hosts_max_consecutive_scan_failures,
+	hosts_score_weights,
+	hosts_label_rules,
+	contract_sets
 FROM autopilot_config
 WHERE id = ?`, sql.AutopilotID).Scan(
 		&cfg.Enabled,
@@ -216,10 +225,33 @@ WHERE id = ?`, sql.AutopilotID).Scan(
 		&cfg.Contracts.Upload,
 		&cfg.Contracts.Storage,
 		&cfg.Contracts.Prune,
+		(*Currency)(&cfg.Contracts.Budget),
+		&cfg.Contracts.StaggerRenewals,
+		&cfg.Contracts.MaxRenewalsPerCycle,
 		&cfg.Hosts.MaxDowntimeHours,
 		&cfg.Hosts.MinProtocolVersion,
 		&cfg.Hosts.MaxConsecutiveScanFailures,
+		&weights,
+		&labelRules,
+		&contractSets,
 	)
+	if err == nil && weights.Valid {
+		cfg.Hosts.ScoreWeights = new(api.HostScoreWeights)
+		if err = json.Unmarshal([]byte(weights.String), cfg.Hosts.ScoreWeights); err != nil {
+			err = fmt.Errorf("failed to unmarshal score weights: %w", err)
+		}
+	}
+	if err == nil && labelRules.Valid {
+		cfg.Hosts.LabelRules = new(api.HostLabelRules)
+		if err = json.Unmarshal([]byte(labelRules.String), cfg.Hosts.LabelRules); err != nil {
+			err = fmt.Errorf("failed to unmarshal label rules: %w", err)
+		}
+	}
+	if err == nil && contractSets.Valid {
+		if err = json.Unmarshal([]byte(contractSets.String), &cfg.ContractSets); err != nil {
+			err = fmt.Errorf("failed to unmarshal contract sets: %w", err)
+		}
+	}
 	return
 }
 
@@ -283,6 +315,24 @@ func ContractRoots(ctx context.Context, tx sql.Tx, fcid types.FileContractID) ([
 	return roots, nil
 }
 
+func ContractsRenterCost(ctx context.Context, tx sql.Tx, minStartHeight uint64) (types.Currency, error) {
+	rows, err := tx.Query(ctx, "SELECT renter_cost FROM contracts WHERE start_height >= ?", minStartHeight)
+	if err != nil {
+		return types.ZeroCurrency, fmt.Errorf("failed to fetch renter costs: %w", err)
+	}
+	defer rows.Close()
+
+	var total types.Currency
+	for rows.Next() {
+		var cost Currency
+		if err := rows.Scan(&cost); err != nil {
+			return types.ZeroCurrency, fmt.Errorf("failed to scan renter cost: %w", err)
+		}
+		total = total.Add(types.Currency(cost))
+	}
+	return total, rows.Err()
+}
+
 func Contracts(ctx context.Context, tx sql.Tx, opts api.ContractsOpts) ([]api.ContractMetadata, error) {
 	var whereExprs []string
 	var whereArgs []any
@@ -485,6 +535,114 @@ func DeleteBucket(ctx context.Context, tx sql.Tx, bucket string) error {
 	return nil
 }
 
+// DeleteContractSectors removes the links between the contract with the given
+// ID and the sectors with the given roots. Unlike DeleteHostSector, the
+// sectors remain linked to the host's other contracts. The lost sector count
+// of the host is incremented by the number of removed links and the number of
+// removed links is returned.
+func DeleteContractSectors(ctx context.Context, tx sql.Tx, fcid types.FileContractID, roots []types.Hash256) (int, error) {
+	if len(roots) == 0 {
+		return 0, nil
+	}
+
+	// fetch contract
+	var contractID int64
+	var hk PublicKey
+	err := tx.QueryRow(ctx, "SELECT id, host_key FROM contracts WHERE fcid = ?", FileContractID(fcid)).
+		Scan(&contractID, &hk)
+	if errors.Is(err, dsql.ErrNoRows) {
+		return 0, api.ErrContractNotFound
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to fetch contract: %w", err)
+	}
+
+	deleteStmt, err := tx.Prepare(ctx, `
+		DELETE FROM contract_sectors
+		WHERE db_contract_id = ? AND db_sector_id = ?
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare statement to delete contract sectors: %w", err)
+	}
+	defer deleteStmt.Close()
+
+	// remove the host link if none of the host's contracts reference the
+	// sector anymore
+	deleteHostStmt, err := tx.Prepare(ctx, `
+		DELETE FROM host_sectors
+		WHERE db_sector_id = ? AND db_host_id IN (
+			SELECT h.id
+			FROM hosts h
+			WHERE h.public_key = ?
+		) AND NOT EXISTS (
+			SELECT 1
+			FROM contract_sectors cs
+			INNER JOIN contracts c ON c.id = cs.db_contract_id
+			WHERE cs.db_sector_id = ? AND c.host_key = ?
+		)
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare statement to delete host sectors: %w", err)
+	}
+	defer deleteHostStmt.Close()
+
+	invalidateStmt, err := tx.Prepare(ctx, `
+		UPDATE slabs
+		SET health_valid_until = 0
+		WHERE id = (SELECT db_slab_id FROM sectors WHERE id = ?)
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare statement to invalidate slab health: %w", err)
+	}
+	defer invalidateStmt.Close()
+
+	sectorStmt, err := tx.Prepare(ctx, "SELECT id FROM sectors WHERE root = ?")
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare statement to fetch sector id: %w", err)
+	}
+	defer sectorStmt.Close()
+
+	var deleted int64
+	for _, root := range roots {
+		var sectorID int64
+		if err := sectorStmt.QueryRow(ctx, Hash256(root)).Scan(&sectorID); errors.Is(err, dsql.ErrNoRows) {
+			continue
+		} else if err != nil {
+			return 0, fmt.Errorf("failed to fetch sector id: %w", err)
+		}
+
+		res, err := deleteStmt.Exec(ctx, contractID, sectorID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to delete contract sector: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to check number of deleted contract sectors: %w", err)
+		} else if n == 0 {
+			continue
+		}
+		deleted += n
+
+		if _, err := deleteHostStmt.Exec(ctx, sectorID, hk, sectorID, hk); err != nil {
+			return 0, fmt.Errorf("failed to delete host sector: %w", err)
+		} else if _, err := invalidateStmt.Exec(ctx, sectorID); err != nil {
+			return 0, fmt.Errorf("failed to invalidate slab health: %w", err)
+		}
+	}
+
+	// increment host's lost sector count
+	if deleted > 0 {
+		_, err = tx.Exec(ctx, `
+			UPDATE hosts
+			SET lost_sectors = lost_sectors + ?
+			WHERE public_key = ?
+		`, deleted, hk)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update lost sectors: %w", err)
+		}
+	}
+	return int(deleted), nil
+}
+
 func DeleteHostSector(ctx context.Context, tx sql.Tx, hk types.PublicKey, root types.Hash256) (int, error) {
 	// fetch sector id
 	var sectorID int64
@@ -716,6 +874,12 @@ func Hosts(ctx context.Context, tx sql.Tx, opts api.HostOptions) ([]api.Host, er
 		args = append(args, pubKeys...)
 	}
 
+	// filter labels, hosts have to have all of the given labels
+	for _, label := range opts.Labels {
+		whereExprs = append(whereExprs, "EXISTS (SELECT 1 FROM host_labels hl WHERE hl.db_host_id = h.id AND hl.label = ?)")
+		args = append(args, label)
+	}
+
 	// filter usability
 	if opts.UsabilityMode != api.UsabilityFilterModeAll {
 		switch opts.UsabilityMode {
@@ -789,11 +953,18 @@ SELECT
 	h.last_scan,
 	h.last_scan_success,
 	h.second_to_last_scan_success,
+	h.scan_latency,
 	h.uptime,
 	h.downtime,
 	h.successful_interactions,
 	h.failed_interactions,
 	COALESCE(h.lost_sectors, 0),
+	h.last_benchmark,
+	h.last_benchmark_success,
+	h.benchmark_upload_throughput,
+	h.benchmark_download_throughput,
+	h.benchmark_ttfb,
+	COALESCE(h.notes, ""),
 	h.scanned,
 
 	%s,
@@ -815,6 +986,8 @@ SELECT
 	COALESCE(hc.score_uptime,0),
 	COALESCE(hc.score_version,0),
 	COALESCE(hc.score_prices,0),
+	COALESCE(hc.score_latency,0),
+	COALESCE(hc.score_throughput,0),
 
 	COALESCE(hc.gouging_download_err, ""),
 	COALESCE(hc.gouging_gouging_err, ""),
@@ -837,12 +1010,13 @@ LEFT JOIN host_checks hc ON hc.db_host_id = h.id
 		var hostID int64
 		err := rows.Scan(&hostID, &h.KnownSince, &h.LastAnnouncement, (*PublicKey)(&h.PublicKey),
 			(*HostSettings)(&h.V2Settings), &h.Interactions.TotalScans, (*UnixTimeMS)(&h.Interactions.LastScan), &h.Interactions.LastScanSuccess,
-			&h.Interactions.SecondToLastScanSuccess, (*DurationMS)(&h.Interactions.Uptime), (*DurationMS)(&h.Interactions.Downtime),
+			&h.Interactions.SecondToLastScanSuccess, (*DurationMS)(&h.Interactions.ScanLatency), (*DurationMS)(&h.Interactions.Uptime), (*DurationMS)(&h.Interactions.Downtime),
 			&h.Interactions.SuccessfulInteractions, &h.Interactions.FailedInteractions, &h.Interactions.LostSectors,
-			&h.Scanned, &h.Blocked, &h.Checks.UsabilityBreakdown.Blocked, &h.Checks.UsabilityBreakdown.Offline, &h.Checks.UsabilityBreakdown.LowScore, &h.Checks.UsabilityBreakdown.RedundantIP,
+			(*UnixTimeMS)(&h.Benchmark.Timestamp), &h.Benchmark.Success, &h.Benchmark.UploadThroughput, &h.Benchmark.DownloadThroughput, (*DurationMS)(&h.Benchmark.TimeToFirstByte),
+			&h.Notes, &h.Scanned, &h.Blocked, &h.Checks.UsabilityBreakdown.Blocked, &h.Checks.UsabilityBreakdown.Offline, &h.Checks.UsabilityBreakdown.LowScore, &h.Checks.UsabilityBreakdown.RedundantIP,
 			&h.Checks.UsabilityBreakdown.Gouging, &h.Checks.UsabilityBreakdown.LowMaxDuration, &h.Checks.UsabilityBreakdown.NotAcceptingContracts, &h.Checks.UsabilityBreakdown.NotAnnounced, &h.Checks.UsabilityBreakdown.NotCompletingScan,
 			&h.Checks.ScoreBreakdown.Age, &h.Checks.ScoreBreakdown.Collateral, &h.Checks.ScoreBreakdown.Interactions, &h.Checks.ScoreBreakdown.StorageRemaining, &h.Checks.ScoreBreakdown.Uptime,
-			&h.Checks.ScoreBreakdown.Version, &h.Checks.ScoreBreakdown.Prices, &h.Checks.GougingBreakdown.DownloadErr, &h.Checks.GougingBreakdown.GougingErr,
+			&h.Checks.ScoreBreakdown.Version, &h.Checks.ScoreBreakdown.Prices, &h.Checks.ScoreBreakdown.Latency, &h.Checks.ScoreBreakdown.Throughput, &h.Checks.GougingBreakdown.DownloadErr, &h.Checks.GougingBreakdown.GougingErr,
 			&h.Checks.GougingBreakdown.PruneErr, &h.Checks.GougingBreakdown.UploadErr)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan host: %w", err)
@@ -861,6 +1035,14 @@ LEFT JOIN host_checks hc ON hc.db_host_id = h.id
 	if err != nil {
 		return nil, err
 	}
+
+	// fill in labels
+	err = fillInHostLabels(ctx, tx, hostIDs, func(i int, labels []string) {
+		hosts[i].Labels = labels
+	})
+	if err != nil {
+		return nil, err
+	}
 	return hosts, nil
 }
 
@@ -954,6 +1136,28 @@ func InsertObject(ctx context.Context, tx sql.Tx, key string, bucketID, size int
 	return res.LastInsertId()
 }
 
+func InsertTusUpload(ctx context.Context, tx sql.Tx, uploadID string, length uint64) error {
+	// remove completed uploads that expired as well as aborted ones
+	_, err := tx.Exec(ctx, `
+		DELETE FROM tus_uploads
+		WHERE db_multipart_upload_id IS NULL AND (completed_at IS NULL OR completed_at < ?)
+	`, time.Now().Add(-api.TusCompletedUploadExpiry))
+	if err != nil {
+		return fmt.Errorf("failed to prune tus uploads: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO tus_uploads (created_at, upload_id, db_multipart_upload_id, upload_length, upload_offset, num_parts)
+		SELECT ?, mu.upload_id, mu.id, ?, 0, 0
+		FROM multipart_uploads mu
+		WHERE mu.upload_id = ?
+	`, time.Now(), length, uploadID)
+	if err != nil {
+		return fmt.Errorf("failed to create tus upload: %w", err)
+	}
+	return nil
+}
+
 func LoadSlabBuffers(ctx context.Context, tx sql.Tx) (bufferedSlabs []LoadedSlabBuffer, orphanedBuffers []string, err error) {
 	// collect all buffers
 	rows, err := tx.Query(ctx, `
@@ -1611,6 +1815,7 @@ func RecordHostScans(ctx context.Context, tx sql.Tx, scans []api.HostScan) error
 		uptime = CASE WHEN ? AND last_scan > 0 AND last_scan < ? THEN uptime + ? - last_scan ELSE uptime END,
 		last_scan = ?,
 		v2_settings = CASE WHEN ? THEN ? ELSE v2_settings END,
+		scan_latency = CASE WHEN ? THEN ? ELSE scan_latency END,
 		successful_interactions = CASE WHEN ? THEN successful_interactions + 1 ELSE successful_interactions END,
 		failed_interactions = CASE WHEN ? THEN failed_interactions + 1 ELSE failed_interactions END
 		WHERE public_key = ?
@@ -1631,6 +1836,7 @@ func RecordHostScans(ctx context.Context, tx sql.Tx, scans []api.HostScan) error
 			scan.Success, scanTime, scanTime, // uptime
 			scanTime,                                    // last_scan
 			scan.Success, HostSettings(scan.V2Settings), // settings
+			scan.Success, DurationMS(scan.Latency), // scan_latency
 			scan.Success,  // successful_interactions
 			!scan.Success, // failed_interactions
 			PublicKey(scan.HostKey),
@@ -1642,6 +1848,97 @@ func RecordHostScans(ctx context.Context, tx sql.Tx, scans []api.HostScan) error
 	return nil
 }
 
+func UpdateHostLabels(ctx context.Context, tx sql.Tx, hk types.PublicKey, labels []string, notes string) error {
+	var hostID int64
+	if err := tx.QueryRow(ctx, "SELECT id FROM hosts WHERE public_key = ?", PublicKey(hk)).Scan(&hostID); errors.Is(err, dsql.ErrNoRows) {
+		return api.ErrHostNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to fetch host id: %w", err)
+	}
+
+	// update notes
+	if _, err := tx.Exec(ctx, "UPDATE hosts SET notes = ? WHERE id = ?", notes, hostID); err != nil {
+		return fmt.Errorf("failed to update host notes: %w", err)
+	}
+
+	// replace labels
+	if _, err := tx.Exec(ctx, "DELETE FROM host_labels WHERE db_host_id = ?", hostID); err != nil {
+		return fmt.Errorf("failed to delete host labels: %w", err)
+	} else if len(labels) == 0 {
+		return nil
+	}
+	stmt, err := tx.Prepare(ctx, "INSERT INTO host_labels (created_at, db_host_id, label) VALUES (?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement to insert host label: %w", err)
+	}
+	defer stmt.Close()
+	for _, label := range labels {
+		if _, err := stmt.Exec(ctx, time.Now(), hostID, label); err != nil {
+			return fmt.Errorf("failed to insert host label: %w", err)
+		}
+	}
+	return nil
+}
+
+func UpdateHostBlocklistRuleHosts(ctx context.Context, tx sql.Tx, entry string, blocked, unblocked []types.PublicKey) error {
+	deleteStmt, err := tx.Prepare(ctx, `
+		DELETE FROM host_blocklist_entry_hosts
+		WHERE db_blocklist_entry_id = (SELECT id FROM host_blocklist_entries WHERE entry = ?) AND
+		db_host_id = (SELECT id FROM hosts WHERE public_key = ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare delete statement: %w", err)
+	}
+	defer deleteStmt.Close()
+
+	insertStmt, err := tx.Prepare(ctx, `
+		INSERT INTO host_blocklist_entry_hosts (db_blocklist_entry_id, db_host_id)
+		SELECT e.id, h.id FROM host_blocklist_entries e, hosts h
+		WHERE e.entry = ? AND h.public_key = ?`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert statement: %w", err)
+	}
+	defer insertStmt.Close()
+
+	for _, hk := range slices.Concat(blocked, unblocked) {
+		if _, err := deleteStmt.Exec(ctx, entry, PublicKey(hk)); err != nil {
+			return fmt.Errorf("failed to remove host from blocklist: %w", err)
+		}
+	}
+	for _, hk := range blocked {
+		if _, err := insertStmt.Exec(ctx, entry, PublicKey(hk)); err != nil {
+			return fmt.Errorf("failed to insert host into blocklist: %w", err)
+		}
+	}
+	return nil
+}
+
+func RecordHostBenchmark(ctx context.Context, tx sql.Tx, hk types.PublicKey, b api.HostBenchmark) error {
+	res, err := tx.Exec(ctx, `
+		UPDATE hosts SET
+		last_benchmark = ?,
+		last_benchmark_success = ?,
+		benchmark_upload_throughput = CASE WHEN ? THEN ? ELSE benchmark_upload_throughput END,
+		benchmark_download_throughput = CASE WHEN ? THEN ? ELSE benchmark_download_throughput END,
+		benchmark_ttfb = CASE WHEN ? THEN ? ELSE benchmark_ttfb END
+		WHERE public_key = ?
+	`,
+		UnixTimeMS(b.Timestamp),
+		b.Success,
+		b.Success, b.UploadThroughput,
+		b.Success, b.DownloadThroughput,
+		b.Success, DurationMS(b.TimeToFirstByte),
+		PublicKey(hk),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update host with benchmark: %w", err)
+	} else if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return api.ErrHostNotFound
+	}
+	return nil
+}
+
 func RemoveOfflineHosts(ctx context.Context, tx sql.Tx, minRecentFailures uint64, maxDownTime time.Duration) (int64, error) {
 	// fetch contracts belonging to offline hosts
 	rows, err := tx.Query(ctx, `
@@ -1688,7 +1985,7 @@ func QueryContracts(ctx context.Context, tx sql.Tx, whereExprs []string, whereAr
 SELECT
 	c.fcid, c.host_id, c.host_key,
 	c.archival_reason, c.proof_height, c.renewed_from, c.renewed_to, c.revision_height, c.revision_number, c.size, c.start_height, c.state, c.usability, c.window_start, c.window_end,
-	c.contract_price, c.initial_renter_funds,
+	c.contract_price, c.initial_renter_funds, c.renter_cost, c.manual, c.manual_renewal, c.contract_set,
 	c.delete_spending, c.fund_account_spending, c.sector_roots_spending, c.upload_spending
 FROM contracts AS c
 %s
@@ -1768,6 +2065,31 @@ func Setting(ctx context.Context, tx sql.Tx, key string) (string, error) {
 	return value, nil
 }
 
+func SlabBuckets(ctx context.Context, tx sql.Tx, key object.EncryptionKey) ([]string, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT DISTINCT b.name
+		FROM slabs sla
+		INNER JOIN slices sli ON sli.db_slab_id = sla.id
+		INNER JOIN objects o ON o.id = sli.db_object_id
+		INNER JOIN buckets b ON b.id = o.db_bucket_id
+		WHERE sla.key = ?
+	`, EncryptionKey(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch slab buckets: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []string
+	for rows.Next() {
+		var bucket string
+		if err := rows.Scan(&bucket); err != nil {
+			return nil, fmt.Errorf("failed to scan bucket: %w", err)
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, rows.Err()
+}
+
 func Slab(ctx context.Context, tx sql.Tx, key object.EncryptionKey) (object.Slab, error) {
 	// fetch slab
 	var slabID int64
@@ -1906,14 +2228,147 @@ func Tip(ctx context.Context, tx sql.Tx) (types.ChainIndex, error) {
 	}, nil
 }
 
-func SlabsForMigration(ctx context.Context, tx sql.Tx, healthCutoff float64, limit int) ([]api.UnhealthySlab, error) {
-	rows, err := tx.Query(ctx, `
+func TusUpload(ctx context.Context, tx sql.Tx, uploadID string) (api.TusUpload, error) {
+	// completed uploads are kept around until they expire, the multipart
+	// upload is gone at that point
+	var resp api.TusUpload
+	var bucket, key dsql.NullString
+	err := tx.QueryRow(ctx, `
+		SELECT tu.upload_id, b.name, mu.object_id, tu.upload_length, tu.upload_offset, tu.num_parts, tu.created_at
+		FROM tus_uploads tu
+		LEFT JOIN multipart_uploads mu ON mu.id = tu.db_multipart_upload_id
+		LEFT JOIN buckets b ON b.id = mu.db_bucket_id
+		WHERE tu.upload_id = ? AND (mu.id IS NOT NULL OR (tu.completed_at IS NOT NULL AND tu.completed_at >= ?))
+	`, uploadID, time.Now().Add(-api.TusCompletedUploadExpiry)).Scan(&resp.ID, &bucket, &key, &resp.Length, &resp.Offset, &resp.Parts, &resp.CreatedAt)
+	if errors.Is(err, dsql.ErrNoRows) {
+		return api.TusUpload{}, api.ErrTusUploadNotFound
+	} else if err != nil {
+		return api.TusUpload{}, fmt.Errorf("failed to fetch tus upload: %w", err)
+	}
+	resp.Bucket, resp.Key = bucket.String, key.String
+	return resp, nil
+}
+
+func CompleteTusUpload(ctx context.Context, tx sql.Tx, uploadID string) error {
+	_, err := tx.Exec(ctx, "UPDATE tus_uploads SET completed_at = ? WHERE upload_id = ?", time.Now(), uploadID)
+	if err != nil {
+		return fmt.Errorf("failed to mark tus upload as completed: %w", err)
+	}
+	return nil
+}
+
+func SlabHealthStats(ctx context.Context, tx sql.Tx, healthCutoff float64) (api.SlabHealthStats, error) {
+	// initialise the buckets, the first bucket contains all slabs with a
+	// health below the first boundary and the last one all slabs with a health
+	// at or above the last boundary
+	bounds := api.HealthMetricBuckets
+	buckets := make([]api.HealthBucket, len(bounds)+1)
+	for i := range bounds {
+		buckets[i].End = &bounds[i]
+		buckets[i+1].Start = &bounds[i]
+	}
+
+	// group the slabs into their buckets
+	var whenExprs []string
+	var args []any
+	for i, bound := range bounds {
+		whenExprs = append(whenExprs, "WHEN health < ? THEN ?")
+		args = append(args, bound, i)
+	}
+	args = append(args, len(bounds))
+	rows, err := tx.Query(ctx, fmt.Sprintf(`
+		SELECT bucket, COUNT(*), MIN(health), COALESCE(SUM(min_shards), 0)
+		FROM (
+			SELECT CASE %s ELSE ? END AS bucket, health, min_shards
+			FROM slabs
+			WHERE db_buffered_slab_id IS NULL
+		) AS t
+		GROUP BY bucket
+	`, strings.Join(whenExprs, " ")), args...)
+	if err != nil {
+		return api.SlabHealthStats{}, fmt.Errorf("failed to fetch slab health distribution: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bucket int
+		var slabs, shards uint64
+		var minHealth float64
+		if err := rows.Scan(&bucket, &slabs, &minHealth, &shards); err != nil {
+			return api.SlabHealthStats{}, fmt.Errorf("failed to scan slab health distribution: %w", err)
+		} else if bucket < 0 || bucket >= len(buckets) {
+			return api.SlabHealthStats{}, fmt.Errorf("unexpected health bucket %d", bucket)
+		}
+		buckets[bucket].Slabs = slabs
+		buckets[bucket].MinHealth = minHealth
+		buckets[bucket].Bytes = shards * rhpv4.SectorSize
+	}
+	if err := rows.Err(); err != nil {
+		return api.SlabHealthStats{}, err
+	}
+
+	// fetch the amount of data below the cutoff
+	var shards uint64
+	err = tx.QueryRow(ctx, "SELECT COALESCE(SUM(min_shards), 0) FROM slabs WHERE health <= ? AND db_buffered_slab_id IS NULL", healthCutoff).Scan(&shards)
+	if err != nil {
+		return api.SlabHealthStats{}, fmt.Errorf("failed to fetch bytes below health cutoff: %w", err)
+	}
+
+	return api.SlabHealthStats{
+		Buckets:          buckets,
+		HealthCutoff:     healthCutoff,
+		BytesBelowCutoff: shards * rhpv4.SectorSize,
+	}, nil
+}
+
+func SlabsForMigration(ctx context.Context, tx sql.Tx, healthCutoff float64, offset, limit int, rs api.RepairSettings) ([]api.UnhealthySlab, error) {
+	query := `
 		SELECT sla.key, sla.health
 		FROM slabs sla
 		WHERE sla.health <= ? AND sla.health_valid_until > ? AND sla.db_buffered_slab_id IS NULL
-		ORDER BY sla.health ASC
-		LIMIT ?
-	`, healthCutoff, time.Now().Unix(), limit)
+		ORDER BY sla.health ASC, sla.id ASC
+		LIMIT ? OFFSET ?
+	`
+	args := []any{healthCutoff, time.Now().Unix(), limit, offset}
+
+	// if repairs are prioritised, the slabs are ordered by their health minus
+	// the highest boost of the objects they belong to
+	var boostExprs []string
+	var boostArgs []any
+	if rs.AccessBoost > 0 {
+		boostExprs = append(boostExprs, "CASE WHEN o.last_accessed_at >= ? THEN ? * (CASE WHEN o.read_count >= ? THEN 1 ELSE o.read_count * 1.0 / ? END) ELSE 0 END")
+		boostArgs = append(boostArgs, time.Now().Add(-time.Duration(rs.AccessWindow)).Unix(), rs.AccessBoost, rs.HotReadCount, rs.HotReadCount)
+	}
+	if len(rs.BucketBoosts) > 0 {
+		buckets := make([]string, 0, len(rs.BucketBoosts))
+		for bucket := range rs.BucketBoosts {
+			buckets = append(buckets, bucket)
+		}
+		sort.Strings(buckets)
+
+		var whenExprs []string
+		for _, bucket := range buckets {
+			whenExprs = append(whenExprs, "WHEN ? THEN ?")
+			boostArgs = append(boostArgs, bucket, rs.BucketBoosts[bucket])
+		}
+		boostExprs = append(boostExprs, fmt.Sprintf("CASE b.name %s ELSE 0 END", strings.Join(whenExprs, " ")))
+	}
+	if len(boostExprs) > 0 {
+		query = fmt.Sprintf(`
+		SELECT sla.key, sla.health
+		FROM slabs sla
+		LEFT JOIN slices sli ON sli.db_slab_id = sla.id
+		LEFT JOIN objects o ON o.id = sli.db_object_id
+		LEFT JOIN buckets b ON b.id = o.db_bucket_id
+		WHERE sla.health <= ? AND sla.health_valid_until > ? AND sla.db_buffered_slab_id IS NULL
+		GROUP BY sla.id, sla.key, sla.health
+		ORDER BY sla.health - MAX(%s) ASC, sla.health ASC, sla.id ASC
+		LIMIT ? OFFSET ?
+	`, strings.Join(boostExprs, " + "))
+		args = append(args[:2], append(boostArgs, limit, offset)...)
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch unhealthy slabs: %w", err)
 	}
@@ -1967,12 +2422,12 @@ func UpdateContract(ctx context.Context, tx sql.Tx, fcid types.FileContractID, c
 UPDATE contracts SET
 	created_at = ?, fcid = ?,
 	proof_height = ?, renewed_from = ?, revision_height = ?, revision_number = ?, size = ?, start_height = ?, state = ?, usability = ?, window_start = ?, window_end = ?,
-	contract_price = ?, initial_renter_funds = ?,
+	contract_price = ?, initial_renter_funds = ?, renter_cost = ?,
 	delete_spending = ?, fund_account_spending = ?, sector_roots_spending = ?, upload_spending = ?
 WHERE fcid = ?`,
 		time.Now(), FileContractID(c.ID),
 		0, FileContractID(c.RenewedFrom), 0, fmt.Sprint(c.RevisionNumber), c.Size, c.StartHeight, state, usability, c.WindowStart, c.WindowEnd,
-		Currency(c.ContractPrice), Currency(c.InitialRenterFunds),
+		Currency(c.ContractPrice), Currency(c.InitialRenterFunds), Currency(c.RenterCost),
 		ZeroCurrency, ZeroCurrency, ZeroCurrency, ZeroCurrency,
 		FileContractID(c.RenewedFrom),
 	)
@@ -1982,6 +2437,18 @@ WHERE fcid = ?`,
 	return nil
 }
 
+func UpdateContractManual(ctx context.Context, tx sql.Tx, fcid types.FileContractID, manual bool, renewal *api.ContractRenewalSettings) error {
+	res, err := tx.Exec(ctx, `UPDATE contracts SET manual = ?, manual_renewal = ? WHERE fcid = ? AND archival_reason IS NULL`, manual, (*ContractRenewalSettings)(renewal), FileContractID(fcid))
+	if err != nil {
+		return fmt.Errorf("failed to update contract: %w", err)
+	} else if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return api.ErrContractNotFound
+	}
+	return nil
+}
+
 func UpdateContractUsability(ctx context.Context, tx sql.Tx, fcid types.FileContractID, usability string) error {
 	var u ContractUsability
 	if err := u.LoadString(usability); err != nil {
@@ -1996,6 +2463,32 @@ func UpdateContractUsability(ctx context.Context, tx sql.Tx, fcid types.FileCont
 }
 
 func UpdateAutopilotConfig(ctx context.Context, tx sql.Tx, cfg api.AutopilotConfig) error {
+	var weights any
+	if cfg.Hosts.ScoreWeights != nil {
+		b, err := json.Marshal(cfg.Hosts.ScoreWeights)
+		if err != nil {
+			return fmt.Errorf("failed to marshal score weights: %w", err)
+		}
+		weights = string(b)
+	}
+	var labelRules any
+	if cfg.Hosts.LabelRules != nil {
+		b, err := json.Marshal(cfg.Hosts.LabelRules)
+		if err != nil {
+			return fmt.Errorf("failed to marshal label rules: %w", err)
+		}
+		labelRules = string(b)
+	}
+
+	var contractSets any
+	if len(cfg.ContractSets) > 0 {
+		b, err := json.Marshal(cfg.ContractSets)
+		if err != nil {
+			return fmt.Errorf("failed to marshal contract sets: %w", err)
+		}
+		contractSets = string(b)
+	}
+
 	_, err := tx.Exec(ctx, `
 UPDATE autopilot_config
 SET enabled = ?,
@@ -2006,9 +2499,15 @@ SET enabled = ?,
 	contracts_upload = ?,
 	contracts_storage = ?,
 	contracts_prune = ?,
+	contracts_budget = ?,
+	contracts_stagger_renewals = ?,
+	contracts_max_renewals_per_cycle = ?,
 	hosts_max_downtime_hours = ?,
 	hosts_min_protocol_version = ?,
-	hosts_max_consecutive_scan_failures = ?
+	hosts_max_consecutive_scan_failures = ?,
+	hosts_score_weights = ?,
+	hosts_label_rules = ?,
+	contract_sets = ?
 WHERE id = ?`,
 		cfg.Enabled,
 		cfg.Contracts.Amount,
@@ -2018,9 +2517,15 @@ WHERE id = ?`,
 		cfg.Contracts.Upload,
 		cfg.Contracts.Storage,
 		cfg.Contracts.Prune,
+		Currency(cfg.Contracts.Budget),
+		cfg.Contracts.StaggerRenewals,
+		cfg.Contracts.MaxRenewalsPerCycle,
 		cfg.Hosts.MaxDowntimeHours,
 		cfg.Hosts.MinProtocolVersion,
 		cfg.Hosts.MaxConsecutiveScanFailures,
+		weights,
+		labelRules,
+		contractSets,
 		sql.AutopilotID)
 	return err
 }
@@ -2116,6 +2621,35 @@ func UpdateSlab(ctx context.Context, tx Tx, key object.EncryptionKey, updated []
 	return tx.UpsertContractSectors(ctx, upsert)
 }
 
+func UpdateTusUploadOffset(ctx context.Context, tx sql.Tx, uploadID string, offset, length uint64) (api.TusUpload, error) {
+	upload, err := TusUpload(ctx, tx, uploadID)
+	if err != nil {
+		return api.TusUpload{}, err
+	} else if upload.Offset != offset {
+		return api.TusUpload{}, fmt.Errorf("%w: %d != %d", api.ErrTusUploadOffsetMismatch, offset, upload.Offset)
+	} else if offset+length > upload.Length {
+		return api.TusUpload{}, fmt.Errorf("%w: %d > %d", api.ErrTusUploadLengthExceeded, offset+length, upload.Length)
+	}
+
+	// only update the offset if it wasn't updated concurrently
+	res, err := tx.Exec(ctx, `
+		UPDATE tus_uploads
+		SET upload_offset = upload_offset + ?, num_parts = num_parts + 1
+		WHERE upload_offset = ? AND upload_id = ? AND db_multipart_upload_id IS NOT NULL
+	`, length, offset, uploadID)
+	if err != nil {
+		return api.TusUpload{}, fmt.Errorf("failed to update tus upload offset: %w", err)
+	} else if n, err := res.RowsAffected(); err != nil {
+		return api.TusUpload{}, fmt.Errorf("failed to fetch rows affected: %w", err)
+	} else if n != 1 {
+		return api.TusUpload{}, api.ErrTusUploadOffsetMismatch
+	}
+
+	upload.Offset += length
+	upload.Parts++
+	return upload, nil
+}
+
 func UnspentSiacoinElements(ctx context.Context, tx sql.Tx) (ci types.ChainIndex, elements []types.SiacoinElement, err error) {
 	rows, err := tx.Query(ctx, "SELECT output_id, leaf_index, merkle_proof, address, value, maturity_height FROM wallet_outputs")
 	if err != nil {
@@ -2518,6 +3052,21 @@ func RecordContractSpending(ctx context.Context, tx Tx, fcid types.FileContractI
 	return nil
 }
 
+// RecordObjectAccess records that an object was read 'readCount' times, the
+// last time at 'accessedAt'. Objects that were deleted in the meantime are
+// ignored.
+func RecordObjectAccess(ctx context.Context, tx sql.Tx, bucket, key string, readCount uint64, accessedAt time.Time) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE objects
+		SET read_count = read_count + ?, last_accessed_at = CASE WHEN last_accessed_at < ? THEN ? ELSE last_accessed_at END
+		WHERE object_id = ? AND db_bucket_id = (SELECT id FROM buckets WHERE name = ?)
+	`, readCount, accessedAt.Unix(), accessedAt.Unix(), key, bucket)
+	if err != nil {
+		return fmt.Errorf("failed to record object access: %w", err)
+	}
+	return nil
+}
+
 func Object(ctx context.Context, tx Tx, bucket, key string) (api.Object, error) {
 	/// fetch object metadata
 	row := tx.QueryRow(ctx, fmt.Sprintf(`
@@ -2596,6 +3145,45 @@ func Object(ctx context.Context, tx Tx, bucket, key string) (api.Object, error)
 	}, nil
 }
 
+func fillInHostLabels(ctx context.Context, tx sql.Tx, hostIDs []int64, assignFn func(int, []string)) error {
+	if len(hostIDs) == 0 {
+		return nil
+	}
+
+	labelsStmt, err := tx.Prepare(ctx, "SELECT label FROM host_labels WHERE db_host_id = ? ORDER BY label")
+	if err != nil {
+		return fmt.Errorf("failed to prepare stmt for fetching host labels: %w", err)
+	}
+	defer labelsStmt.Close()
+
+	fetchLabels := func(hostID int64) ([]string, error) {
+		rows, err := labelsStmt.Query(ctx, hostID)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		var labels []string
+		for rows.Next() {
+			var label string
+			if err := rows.Scan(&label); err != nil {
+				return nil, err
+			}
+			labels = append(labels, label)
+		}
+		return labels, rows.Err()
+	}
+
+	for i, hostID := range hostIDs {
+		labels, err := fetchLabels(hostID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch labels for host %d: %w", hostID, err)
+		} else if len(labels) > 0 {
+			assignFn(i, labels)
+		}
+	}
+	return nil
+}
+
 func fillInV2Addresses(ctx context.Context, tx sql.Tx, hostIDs []int64, assignFn func(int, []string)) error {
 	// fill in v2 addresses
 	netAddrsStmt, err := tx.Prepare(ctx, "SELECT ha.net_address, ha.protocol FROM host_addresses ha INNER JOIN hosts h ON ha.db_host_id = h.id WHERE h.id = ?")
@@ -2916,7 +3504,7 @@ func listObjectsSlashDelim(ctx context.Context, tx Tx, bucket, prefix, sortBy, s
 	query := fmt.Sprintf(`
 	SELECT %s
 	FROM (
-		SELECT o.db_bucket_id, o.object_id, o.size, o.health, o.mime_type, o.created_at, o.etag
+		SELECT o.db_bucket_id, o.object_id, o.size, o.health, o.mime_type, o.created_at, o.etag, o.read_count, o.last_accessed_at
 		FROM objects o
 		INNER JOIN buckets b ON b.id = o.db_bucket_id
 		WHERE
@@ -2929,7 +3517,7 @@ func listObjectsSlashDelim(ctx context.Context, tx Tx, bucket, prefix, sortBy, s
 
 		UNION ALL
 
-		SELECT MIN(o.db_bucket_id), MIN(SUBSTR(o.object_id, 1, ?+INSTR(SUBSTR(o.object_id, ?), "/"))) as object_id, SUM(o.size) as size, MIN(o.health), '' as mime_type, MAX(o.created_at), '' as etag
+		SELECT MIN(o.db_bucket_id), MIN(SUBSTR(o.object_id, 1, ?+INSTR(SUBSTR(o.object_id, ?), "/"))) as object_id, SUM(o.size) as size, MIN(o.health), '' as mime_type, MAX(o.created_at), '' as etag, SUM(o.read_count), MAX(o.last_accessed_at)
 		FROM objects o
 		INNER JOIN buckets b ON b.id = o.db_bucket_id
 		WHERE
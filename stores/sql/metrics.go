@@ -1,10 +1,13 @@
 package sql
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"time"
 
 	"go.sia.tech/core/types"
@@ -74,6 +77,126 @@ func ContractMetrics(ctx context.Context, tx sql.Tx, start time.Time, n uint64,
 	})
 }
 
+// ContractSpendingReport returns the spending of contracts per host in n
+// periods of the given interval. Since contract metrics record the cumulative
+// spending of a contract, the spending within a period is the difference
+// between a contract's metrics and the latest metric that precedes them.
+func ContractSpendingReport(ctx context.Context, tx sql.Tx, start time.Time, n uint64, interval time.Duration) ([]api.SpendingReportItem, error) {
+	if n > api.MetricMaxIntervals {
+		return nil, api.ErrMaxIntervalsExceeded
+	}
+
+	end := start.Add(time.Duration(n) * interval)
+
+	// every contract's first metric within the report is compared to the
+	// spending it had before the start of the report, that is either its last
+	// metric before the start or the baseline that was kept when pruning its
+	// metrics, contracts without either are new and their first metric is
+	// attributed in full
+	seeds := make(map[FileContractID]api.ContractSpending)
+	err := fetchContractSpendingSeeds(ctx, tx, seeds, `
+		SELECT fcid, upload_spending_lo, upload_spending_hi, fund_account_spending_lo, fund_account_spending_hi, delete_spending_lo, delete_spending_hi, sector_roots_spending_lo, sector_roots_spending_hi
+		FROM contract_spending_baselines
+		WHERE fcid IN (SELECT fcid FROM contracts WHERE timestamp >= ? AND timestamp < ?)`, UnixTimeMS(start), UnixTimeMS(end))
+	if err != nil {
+		return nil, err
+	}
+	err = fetchContractSpendingSeeds(ctx, tx, seeds, `
+		SELECT c.fcid, c.upload_spending_lo, c.upload_spending_hi, c.fund_account_spending_lo, c.fund_account_spending_hi, c.delete_spending_lo, c.delete_spending_hi, c.sector_roots_spending_lo, c.sector_roots_spending_hi
+		FROM contracts c
+		INNER JOIN (
+			SELECT fcid, MAX(timestamp) AS timestamp
+			FROM contracts
+			WHERE timestamp < ? AND fcid IN (SELECT fcid FROM contracts WHERE timestamp >= ? AND timestamp < ?)
+			GROUP BY fcid
+		) prev ON c.fcid = prev.fcid AND c.timestamp = prev.timestamp`, UnixTimeMS(start), UnixTimeMS(start), UnixTimeMS(end))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT timestamp, fcid, host, upload_spending_lo, upload_spending_hi, fund_account_spending_lo, fund_account_spending_hi, delete_spending_lo, delete_spending_hi, sector_roots_spending_lo, sector_roots_spending_hi
+		FROM contracts
+		WHERE timestamp >= ? AND timestamp < ?
+		ORDER BY fcid, timestamp`, UnixTimeMS(start), UnixTimeMS(end))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch contract metrics: %w", err)
+	}
+	defer rows.Close()
+
+	sub := func(x, y types.Currency) types.Currency {
+		z, underflow := x.SubWithUnderflow(y)
+		if underflow {
+			return types.ZeroCurrency
+		}
+		return z
+	}
+
+	report := newSpendingReportItems(start, n, interval)
+	var prevFCID FileContractID
+	var prev api.ContractSpending
+	for rows.Next() {
+		var timestamp UnixTimeMS
+		var fcid FileContractID
+		var hk PublicKey
+		var cs api.ContractSpending
+		if err := rows.Scan(
+			&timestamp,
+			&fcid,
+			&hk,
+			(*Unsigned64)(&cs.Uploads.Lo), (*Unsigned64)(&cs.Uploads.Hi),
+			(*Unsigned64)(&cs.FundAccount.Lo), (*Unsigned64)(&cs.FundAccount.Hi),
+			(*Unsigned64)(&cs.Deletions.Lo), (*Unsigned64)(&cs.Deletions.Hi),
+			(*Unsigned64)(&cs.SectorRoots.Lo), (*Unsigned64)(&cs.SectorRoots.Hi),
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan contract metric: %w", err)
+		}
+		if fcid != prevFCID {
+			prevFCID, prev = fcid, seeds[fcid]
+		}
+
+		report.add(time.Time(timestamp), types.PublicKey(hk), api.SpendingBreakdown{
+			ContractSpending: api.ContractSpending{
+				Uploads:     sub(cs.Uploads, prev.Uploads),
+				FundAccount: sub(cs.FundAccount, prev.FundAccount),
+				Deletions:   sub(cs.Deletions, prev.Deletions),
+				SectorRoots: sub(cs.SectorRoots, prev.SectorRoots),
+			},
+		})
+		prev = cs
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return report.items(), nil
+}
+
+// fetchContractSpendingSeeds adds the spending returned by the given query to
+// the seeds, overwriting any seed that was already present for a contract.
+func fetchContractSpendingSeeds(ctx context.Context, tx sql.Tx, seeds map[FileContractID]api.ContractSpending, query string, args ...any) error {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to fetch contract spending seeds: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var fcid FileContractID
+		var cs api.ContractSpending
+		if err := rows.Scan(
+			&fcid,
+			(*Unsigned64)(&cs.Uploads.Lo), (*Unsigned64)(&cs.Uploads.Hi),
+			(*Unsigned64)(&cs.FundAccount.Lo), (*Unsigned64)(&cs.FundAccount.Hi),
+			(*Unsigned64)(&cs.Deletions.Lo), (*Unsigned64)(&cs.Deletions.Hi),
+			(*Unsigned64)(&cs.SectorRoots.Lo), (*Unsigned64)(&cs.SectorRoots.Hi),
+		); err != nil {
+			return fmt.Errorf("failed to scan contract spending seed: %w", err)
+		}
+		seeds[fcid] = cs
+	}
+	return rows.Err()
+}
+
 func ContractPruneMetrics(ctx context.Context, tx sql.Tx, start time.Time, n uint64, interval time.Duration, opts api.ContractPruneMetricsQueryOpts) ([]api.ContractPruneMetric, error) {
 	return queryPeriods(ctx, tx, start, n, interval, opts, func(rows *sql.LoggedRows) (m api.ContractPruneMetric, err error) {
 		var placeHolder int64
@@ -99,6 +222,148 @@ func ContractPruneMetrics(ctx context.Context, tx sql.Tx, start time.Time, n uin
 	})
 }
 
+func HealthMetrics(ctx context.Context, tx sql.Tx, start time.Time, n uint64, interval time.Duration, opts api.HealthMetricsQueryOpts) ([]api.HealthMetric, error) {
+	return queryPeriods(ctx, tx, start, n, interval, opts, func(rows *sql.LoggedRows) (m api.HealthMetric, err error) {
+		var placeHolder int64
+		var placeHolderTime time.Time
+		var timestamp UnixTimeMS
+		var buckets string
+		err = rows.Scan(
+			&placeHolder,
+			&placeHolderTime,
+			&timestamp,
+			&m.HealthCutoff,
+			(*Unsigned64)(&m.BytesBelowCutoff),
+			&buckets,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to scan health metric: %w", err)
+			return
+		} else if err = json.Unmarshal([]byte(buckets), &m.Buckets); err != nil {
+			err = fmt.Errorf("failed to unmarshal health buckets: %w", err)
+			return
+		}
+		m.Timestamp = api.TimeRFC3339(normaliseTimestamp(start, interval, timestamp))
+		return
+	})
+}
+
+func HostMetrics(ctx context.Context, tx sql.Tx, start time.Time, n uint64, interval time.Duration, opts api.HostMetricsQueryOpts) ([]api.HostMetric, error) {
+	return queryPeriods(ctx, tx, start, n, interval, opts, func(rows *sql.LoggedRows) (m api.HostMetric, err error) {
+		var placeHolder int64
+		var placeHolderTime time.Time
+		var timestamp UnixTimeMS
+		err = rows.Scan(
+			&placeHolder,
+			&placeHolderTime,
+			&timestamp,
+			(*PublicKey)(&m.HostKey),
+			(*Unsigned64)(&m.StoragePrice.Lo), (*Unsigned64)(&m.StoragePrice.Hi),
+			(*Unsigned64)(&m.IngressPrice.Lo), (*Unsigned64)(&m.IngressPrice.Hi),
+			(*Unsigned64)(&m.EgressPrice.Lo), (*Unsigned64)(&m.EgressPrice.Hi),
+			(*Unsigned64)(&m.RemainingStorage),
+			&m.Uptime,
+			&m.Score,
+			&m.Usable,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to scan host metric: %w", err)
+			return
+		}
+		m.Timestamp = api.TimeRFC3339(normaliseTimestamp(start, interval, timestamp))
+		return
+	})
+}
+
+func HostNetworkMetrics(ctx context.Context, tx sql.Tx, start time.Time, n uint64, interval time.Duration, opts api.HostNetworkMetricsQueryOpts) ([]api.HostNetworkMetric, error) {
+	if n > api.MetricMaxIntervals {
+		return nil, api.ErrMaxIntervalsExceeded
+	}
+
+	// fetch the last metric of every host within every period, the rows are
+	// ordered by period so we only need to keep one period in memory
+	rows, err := tx.Query(ctx, `
+		WITH RECURSIVE periods AS (
+			SELECT ? AS period_start
+			UNION ALL
+			SELECT period_start + ?
+			FROM periods
+			WHERE period_start < ? - ?
+		)
+		SELECT i.period, h.storage_price_lo, h.storage_price_hi, h.ingress_price_lo, h.ingress_price_hi, h.egress_price_lo, h.egress_price_hi, h.remaining_storage, h.uptime, h.score, h.usable
+		FROM hosts h
+		INNER JOIN (
+			SELECT p.period_start AS period, MAX(obj.id) AS id
+			FROM periods p
+			INNER JOIN hosts obj ON obj.timestamp >= p.period_start AND obj.timestamp < p.period_start + ?
+			GROUP BY p.period_start, obj.host
+		) i ON h.id = i.id
+		ORDER BY i.period ASC
+	`, UnixTimeMS(start), interval.Milliseconds(), UnixTimeMS(start.Add(time.Duration(n)*interval)), interval.Milliseconds(), interval.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch host metrics: %w", err)
+	}
+	defer rows.Close()
+
+	var metrics []api.HostNetworkMetric
+	var current *api.HostNetworkMetric
+	var storagePrices, ingressPrices, egressPrices []types.Currency
+	var remainingStorage []uint64
+	var uptimes, scores []float64
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.StoragePrice = percentiles(storagePrices, types.Currency.Cmp)
+		current.IngressPrice = percentiles(ingressPrices, types.Currency.Cmp)
+		current.EgressPrice = percentiles(egressPrices, types.Currency.Cmp)
+		current.RemainingStorage = percentiles(remainingStorage, cmp.Compare[uint64])
+		current.Uptime = percentiles(uptimes, cmp.Compare[float64])
+		current.Score = percentiles(scores, cmp.Compare[float64])
+		metrics = append(metrics, *current)
+
+		storagePrices, ingressPrices, egressPrices = storagePrices[:0], ingressPrices[:0], egressPrices[:0]
+		remainingStorage, uptimes, scores = remainingStorage[:0], uptimes[:0], scores[:0]
+	}
+
+	for rows.Next() {
+		var m api.HostMetric
+		var period int64
+		if err := rows.Scan(
+			&period,
+			(*Unsigned64)(&m.StoragePrice.Lo), (*Unsigned64)(&m.StoragePrice.Hi),
+			(*Unsigned64)(&m.IngressPrice.Lo), (*Unsigned64)(&m.IngressPrice.Hi),
+			(*Unsigned64)(&m.EgressPrice.Lo), (*Unsigned64)(&m.EgressPrice.Hi),
+			(*Unsigned64)(&m.RemainingStorage),
+			&m.Uptime,
+			&m.Score,
+			&m.Usable,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan host metric: %w", err)
+		}
+
+		if current == nil || time.Time(current.Timestamp).UnixMilli() != period {
+			flush()
+			current = &api.HostNetworkMetric{Timestamp: api.TimeRFC3339(time.UnixMilli(period))}
+		}
+		current.Hosts++
+		if m.Usable {
+			current.Usable++
+		}
+		storagePrices = append(storagePrices, m.StoragePrice)
+		ingressPrices = append(ingressPrices, m.IngressPrice)
+		egressPrices = append(egressPrices, m.EgressPrice)
+		remainingStorage = append(remainingStorage, m.RemainingStorage)
+		uptimes = append(uptimes, m.Uptime)
+		scores = append(scores, m.Score)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return metrics, nil
+}
+
 func PruneMetrics(ctx context.Context, tx sql.Tx, metric string, cutoff time.Time) error {
 	if metric == "" {
 		return errors.New("metric must be set")
@@ -112,6 +377,10 @@ func PruneMetrics(ctx context.Context, tx sql.Tx, metric string, cutoff time.Tim
 		table = "contract_prunes"
 	case api.MetricContract:
 		table = "contracts"
+	case api.MetricHealth:
+		table = "health"
+	case api.MetricHost:
+		table = "hosts"
 	case api.MetricPerformance:
 		table = "performance"
 	case api.MetricWallet:
@@ -119,10 +388,42 @@ func PruneMetrics(ctx context.Context, tx sql.Tx, metric string, cutoff time.Tim
 	default:
 		return fmt.Errorf("unknown metric '%s'", metric)
 	}
+	if metric == api.MetricContract {
+		if err := updateContractSpendingBaselines(ctx, tx, cutoff); err != nil {
+			return err
+		}
+	}
 	_, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE timestamp < ?", table), UnixTimeMS(cutoff))
 	return err
 }
 
+// updateContractSpendingBaselines remembers the spending of the latest contract
+// metric that is about to be pruned for every contract, that way the spending
+// report doesn't attribute a contract's entire spending to the first metric
+// that remains after pruning.
+func updateContractSpendingBaselines(ctx context.Context, tx sql.Tx, cutoff time.Time) error {
+	_, err := tx.Exec(ctx, `
+		DELETE FROM contract_spending_baselines
+		WHERE fcid IN (SELECT DISTINCT fcid FROM contracts WHERE timestamp < ?)`, UnixTimeMS(cutoff))
+	if err != nil {
+		return fmt.Errorf("failed to delete outdated contract spending baselines: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO contract_spending_baselines (timestamp, fcid, upload_spending_lo, upload_spending_hi, fund_account_spending_lo, fund_account_spending_hi, delete_spending_lo, delete_spending_hi, sector_roots_spending_lo, sector_roots_spending_hi)
+		SELECT c.timestamp, c.fcid, c.upload_spending_lo, c.upload_spending_hi, c.fund_account_spending_lo, c.fund_account_spending_hi, c.delete_spending_lo, c.delete_spending_hi, c.sector_roots_spending_lo, c.sector_roots_spending_hi
+		FROM contracts c
+		INNER JOIN (
+			SELECT fcid, MAX(timestamp) AS timestamp
+			FROM contracts
+			WHERE timestamp < ?
+			GROUP BY fcid
+		) latest ON c.fcid = latest.fcid AND c.timestamp = latest.timestamp`, UnixTimeMS(cutoff))
+	if err != nil {
+		return fmt.Errorf("failed to insert contract spending baselines: %w", err)
+	}
+	return nil
+}
+
 func RecordContractMetric(ctx context.Context, tx sql.Tx, metrics ...api.ContractMetric) error {
 	insertStmt, err := tx.Prepare(ctx, "INSERT INTO contracts (created_at, timestamp, fcid, host, remaining_collateral_lo, remaining_collateral_hi, remaining_funds_lo, remaining_funds_hi, revision_number, upload_spending_lo, upload_spending_hi, fund_account_spending_lo, fund_account_spending_hi, delete_spending_lo, delete_spending_hi, sector_roots_spending_lo, sector_roots_spending_hi) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
@@ -209,6 +510,72 @@ func RecordContractPruneMetric(ctx context.Context, tx sql.Tx, metrics ...api.Co
 	return nil
 }
 
+func RecordHealthMetric(ctx context.Context, tx sql.Tx, metrics ...api.HealthMetric) error {
+	insertStmt, err := tx.Prepare(ctx, "INSERT INTO health (created_at, timestamp, health_cutoff, bytes_below_cutoff, buckets) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement to insert health metric: %w", err)
+	}
+	defer insertStmt.Close()
+
+	for _, metric := range metrics {
+		buckets, err := json.Marshal(metric.Buckets)
+		if err != nil {
+			return fmt.Errorf("failed to marshal health buckets: %w", err)
+		}
+		res, err := insertStmt.Exec(ctx,
+			time.Now().UTC(),
+			UnixTimeMS(metric.Timestamp),
+			metric.HealthCutoff,
+			Unsigned64(metric.BytesBelowCutoff),
+			string(buckets),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert health metric: %w", err)
+		} else if n, err := res.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		} else if n == 0 {
+			return fmt.Errorf("failed to insert health metric: no rows affected")
+		}
+	}
+
+	return nil
+}
+
+func RecordHostMetric(ctx context.Context, tx sql.Tx, metrics ...api.HostMetric) error {
+	insertStmt, err := tx.Prepare(ctx, "INSERT INTO hosts (created_at, timestamp, host, storage_price_lo, storage_price_hi, ingress_price_lo, ingress_price_hi, egress_price_lo, egress_price_hi, remaining_storage, uptime, score, usable) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement to insert host metric: %w", err)
+	}
+	defer insertStmt.Close()
+
+	for _, metric := range metrics {
+		res, err := insertStmt.Exec(ctx,
+			time.Now().UTC(),
+			UnixTimeMS(metric.Timestamp),
+			PublicKey(metric.HostKey),
+			Unsigned64(metric.StoragePrice.Lo),
+			Unsigned64(metric.StoragePrice.Hi),
+			Unsigned64(metric.IngressPrice.Lo),
+			Unsigned64(metric.IngressPrice.Hi),
+			Unsigned64(metric.EgressPrice.Lo),
+			Unsigned64(metric.EgressPrice.Hi),
+			Unsigned64(metric.RemainingStorage),
+			metric.Uptime,
+			metric.Score,
+			metric.Usable,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert host metric: %w", err)
+		} else if n, err := res.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		} else if n == 0 {
+			return fmt.Errorf("failed to insert host metric: no rows affected")
+		}
+	}
+
+	return nil
+}
+
 func RecordWalletMetric(ctx context.Context, tx sql.Tx, metrics ...api.WalletMetric) error {
 	insertStmt, err := tx.Prepare(ctx, "INSERT INTO wallets (created_at, timestamp, confirmed_lo, confirmed_hi, spendable_lo, spendable_hi, unconfirmed_lo, unconfirmed_hi, immature_hi, immature_lo) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
@@ -303,6 +670,14 @@ func queryPeriods[T any](ctx context.Context, tx sql.Tx, start time.Time, n uint
 			query += " AND host_version = ?"
 			params = append(params, opts.HostVersion)
 		}
+	case api.HealthMetricsQueryOpts:
+		table = "health"
+	case api.HostMetricsQueryOpts:
+		table = "hosts"
+		if opts.HostKey != (types.PublicKey{}) {
+			query += " AND host = ?"
+			params = append(params, PublicKey(opts.HostKey))
+		}
 	case api.PerformanceMetricsQueryOpts:
 		table = "performance"
 		if opts.Action != "" {
@@ -436,6 +811,26 @@ func aggregateMetrics(x, y api.ContractMetric) (out api.ContractMetric) {
 	return
 }
 
+// percentiles returns the percentiles of the given values using the nearest
+// rank method, the values are sorted in place.
+func percentiles[T any](values []T, compare func(a, b T) int) (p api.Percentiles[T]) {
+	if len(values) == 0 {
+		return
+	}
+	slices.SortFunc(values, compare)
+	rank := func(percentile float64) T {
+		i := int(math.Ceil(percentile/100*float64(len(values)))) - 1
+		return values[max(i, 0)]
+	}
+	return api.Percentiles[T]{
+		P10: rank(10),
+		P25: rank(25),
+		P50: rank(50),
+		P75: rank(75),
+		P90: rank(90),
+	}
+}
+
 func normaliseTimestamp(start time.Time, interval time.Duration, t UnixTimeMS) UnixTimeMS {
 	startMS := start.UnixMilli()
 	toNormaliseMS := time.Time(t).UnixMilli()
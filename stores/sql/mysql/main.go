@@ -277,10 +277,18 @@ func (tx *MainDatabaseTx) CompleteMultipartUpload(ctx context.Context, bucket, k
 	return eTag, nil
 }
 
+func (tx *MainDatabaseTx) CompleteTusUpload(ctx context.Context, uploadID string) error {
+	return ssql.CompleteTusUpload(ctx, tx, uploadID)
+}
+
 func (tx *MainDatabaseTx) Contract(ctx context.Context, fcid types.FileContractID) (api.ContractMetadata, error) {
 	return ssql.Contract(ctx, tx, fcid)
 }
 
+func (tx *MainDatabaseTx) ContractPriceReport(ctx context.Context, start time.Time, n uint64, interval time.Duration) ([]api.SpendingReportItem, error) {
+	return ssql.ContractPriceReport(ctx, tx, start, n, interval)
+}
+
 func (tx *MainDatabaseTx) ContractRoots(ctx context.Context, fcid types.FileContractID) ([]types.Hash256, error) {
 	return ssql.ContractRoots(ctx, tx, fcid)
 }
@@ -289,6 +297,10 @@ func (tx *MainDatabaseTx) Contracts(ctx context.Context, opts api.ContractsOpts)
 	return ssql.Contracts(ctx, tx, opts)
 }
 
+func (tx *MainDatabaseTx) ContractsRenterCost(ctx context.Context, minStartHeight uint64) (types.Currency, error) {
+	return ssql.ContractsRenterCost(ctx, tx, minStartHeight)
+}
+
 func (tx *MainDatabaseTx) ContractSize(ctx context.Context, id types.FileContractID) (api.ContractSize, error) {
 	return ssql.ContractSize(ctx, tx, id)
 }
@@ -318,6 +330,10 @@ func (tx *MainDatabaseTx) CreateBucket(ctx context.Context, bucket string, bp ap
 	return nil
 }
 
+func (tx *MainDatabaseTx) DeleteContractSectors(ctx context.Context, fcid types.FileContractID, roots []types.Hash256) (int, error) {
+	return ssql.DeleteContractSectors(ctx, tx, fcid, roots)
+}
+
 func (tx *MainDatabaseTx) DeleteHostSector(ctx context.Context, hk types.PublicKey, root types.Hash256) (int, error) {
 	return ssql.DeleteHostSector(ctx, tx, hk, root)
 }
@@ -457,6 +473,10 @@ func (tx *MainDatabaseTx) InsertObject(ctx context.Context, bucket, key string,
 	return nil
 }
 
+func (tx *MainDatabaseTx) InsertTusUpload(ctx context.Context, uploadID string, length uint64) error {
+	return ssql.InsertTusUpload(ctx, tx, uploadID, length)
+}
+
 func (tx *MainDatabaseTx) InvalidateSlabHealthByFCID(ctx context.Context, fcids []types.FileContractID, limit int64) (int64, error) {
 	if len(fcids) == 0 {
 		return 0, nil
@@ -737,17 +757,17 @@ func (tx *MainDatabaseTx) PutContract(ctx context.Context, c api.ContractMetadat
 INSERT INTO contracts (
 	created_at, fcid, host_id, host_key,
 	archival_reason, proof_height, renewed_from, renewed_to, revision_height, revision_number, size, start_height, state, usability, window_start, window_end,
-	contract_price, initial_renter_funds,
+	contract_price, initial_renter_funds, renter_cost, manual, manual_renewal, contract_set,
 	delete_spending, fund_account_spending, sector_roots_spending, upload_spending
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 ON DUPLICATE KEY UPDATE
 	created_at = VALUES(created_at), fcid = VALUES(fcid), host_id = VALUES(host_id), host_key = VALUES(host_key),
 	archival_reason = VALUES(archival_reason), proof_height = VALUES(proof_height), renewed_from = VALUES(renewed_from), renewed_to = VALUES(renewed_to), revision_height = VALUES(revision_height), revision_number = VALUES(revision_number), size = VALUES(size), start_height = VALUES(start_height), state = VALUES(state), usability = VALUES(usability), window_start = VALUES(window_start), window_end = VALUES(window_end),
-	contract_price = VALUES(contract_price), initial_renter_funds = VALUES(initial_renter_funds),
+	contract_price = VALUES(contract_price), initial_renter_funds = VALUES(initial_renter_funds), renter_cost = VALUES(renter_cost), manual = VALUES(manual), manual_renewal = VALUES(manual_renewal), contract_set = VALUES(contract_set),
 	delete_spending = VALUES(delete_spending), fund_account_spending = VALUES(fund_account_spending), sector_roots_spending = VALUES(sector_roots_spending), upload_spending = VALUES(upload_spending)`,
 		time.Now(), ssql.FileContractID(c.ID), hostID, ssql.PublicKey(c.HostKey),
 		ssql.NullableString(c.ArchivalReason), c.ProofHeight, ssql.FileContractID(c.RenewedFrom), ssql.FileContractID(c.RenewedTo), c.RevisionHeight, c.RevisionNumber, c.Size, c.StartHeight, state, usability, c.WindowStart, c.WindowEnd,
-		ssql.Currency(c.ContractPrice), ssql.Currency(c.InitialRenterFunds),
+		ssql.Currency(c.ContractPrice), ssql.Currency(c.InitialRenterFunds), ssql.Currency(c.RenterCost), c.Manual, (*ssql.ContractRenewalSettings)(c.Renewal), ssql.NullableString(c.ContractSet),
 		ssql.Currency(c.Spending.Deletions), ssql.Currency(c.Spending.FundAccount), ssql.Currency(c.Spending.SectorRoots), ssql.Currency(c.Spending.Uploads),
 	)
 	if err != nil {
@@ -760,6 +780,22 @@ func (tx *MainDatabaseTx) RecordContractSpending(ctx context.Context, fcid types
 	return ssql.RecordContractSpending(ctx, tx, fcid, revisionNumber, size, newSpending)
 }
 
+func (tx *MainDatabaseTx) RecordObjectAccess(ctx context.Context, bucket, key string, readCount uint64, accessedAt time.Time) error {
+	return ssql.RecordObjectAccess(ctx, tx, bucket, key, readCount, accessedAt)
+}
+
+func (tx *MainDatabaseTx) UpdateHostBlocklistRuleHosts(ctx context.Context, entry string, blocked, unblocked []types.PublicKey) error {
+	return ssql.UpdateHostBlocklistRuleHosts(ctx, tx, entry, blocked, unblocked)
+}
+
+func (tx *MainDatabaseTx) UpdateHostLabels(ctx context.Context, hk types.PublicKey, labels []string, notes string) error {
+	return ssql.UpdateHostLabels(ctx, tx, hk, labels, notes)
+}
+
+func (tx *MainDatabaseTx) RecordHostBenchmark(ctx context.Context, hk types.PublicKey, b api.HostBenchmark) error {
+	return ssql.RecordHostBenchmark(ctx, tx, hk, b)
+}
+
 func (tx *MainDatabaseTx) RecordHostScans(ctx context.Context, scans []api.HostScan) error {
 	return ssql.RecordHostScans(ctx, tx, scans)
 }
@@ -892,34 +928,50 @@ func (tx MainDatabaseTx) SaveAccounts(ctx context.Context, accounts []api.Accoun
 }
 
 func (tx *MainDatabaseTx) ScanObjectMetadata(s ssql.Scanner, others ...any) (md api.ObjectMetadata, err error) {
-	dst := []any{&md.Key, &md.Size, &md.Health, &md.MimeType, &md.ModTime, &md.ETag, &md.Bucket}
+	var lastAccessedAt int64
+	dst := []any{&md.Key, &md.Size, &md.Health, &md.MimeType, &md.ModTime, &md.ETag, &md.Bucket, &md.ReadCount, &lastAccessedAt}
 	dst = append(dst, others...)
 	if err := s.Scan(dst...); err != nil {
 		return api.ObjectMetadata{}, fmt.Errorf("failed to scan object metadata: %w", err)
 	}
+	if lastAccessedAt > 0 {
+		md.LastAccessedAt = api.TimeRFC3339(time.Unix(lastAccessedAt, 0).UTC())
+	}
 	return md, nil
 }
 
 func (tx *MainDatabaseTx) SelectObjectMetadataExpr() string {
-	return "o.object_id, o.size, o.health, o.mime_type, o.created_at, o.etag, b.name"
+	return "o.object_id, o.size, o.health, o.mime_type, o.created_at, o.etag, b.name, o.read_count, o.last_accessed_at"
 }
 
 func (tx *MainDatabaseTx) Setting(ctx context.Context, key string) (string, error) {
 	return ssql.Setting(ctx, tx, key)
 }
 
+func (tx *MainDatabaseTx) SlabBuckets(ctx context.Context, key object.EncryptionKey) ([]string, error) {
+	return ssql.SlabBuckets(ctx, tx, key)
+}
+
 func (tx *MainDatabaseTx) Slab(ctx context.Context, key object.EncryptionKey) (object.Slab, error) {
 	return ssql.Slab(ctx, tx, key)
 }
 
-func (tx *MainDatabaseTx) SlabsForMigration(ctx context.Context, healthCutoff float64, limit int) ([]api.UnhealthySlab, error) {
-	return ssql.SlabsForMigration(ctx, tx, healthCutoff, limit)
+func (tx *MainDatabaseTx) SlabHealthStats(ctx context.Context, healthCutoff float64) (api.SlabHealthStats, error) {
+	return ssql.SlabHealthStats(ctx, tx, healthCutoff)
+}
+
+func (tx *MainDatabaseTx) SlabsForMigration(ctx context.Context, healthCutoff float64, offset, limit int, rs api.RepairSettings) ([]api.UnhealthySlab, error) {
+	return ssql.SlabsForMigration(ctx, tx, healthCutoff, offset, limit, rs)
 }
 
 func (tx *MainDatabaseTx) Tip(ctx context.Context) (types.ChainIndex, error) {
 	return ssql.Tip(ctx, tx.Tx)
 }
 
+func (tx *MainDatabaseTx) TusUpload(ctx context.Context, uploadID string) (api.TusUpload, error) {
+	return ssql.TusUpload(ctx, tx, uploadID)
+}
+
 func (tx *MainDatabaseTx) UnspentSiacoinElements(ctx context.Context) (ci types.ChainIndex, elements []types.SiacoinElement, err error) {
 	return ssql.UnspentSiacoinElements(ctx, tx.Tx)
 }
@@ -936,6 +988,10 @@ func (tx *MainDatabaseTx) UpdateContract(ctx context.Context, fcid types.FileCon
 	return ssql.UpdateContract(ctx, tx, fcid, c)
 }
 
+func (tx *MainDatabaseTx) UpdateContractManual(ctx context.Context, fcid types.FileContractID, manual bool, renewal *api.ContractRenewalSettings) error {
+	return ssql.UpdateContractManual(ctx, tx, fcid, manual, renewal)
+}
+
 func (tx *MainDatabaseTx) UpdateContractUsability(ctx context.Context, fcid types.FileContractID, usability string) error {
 	return ssql.UpdateContractUsability(ctx, tx, fcid, usability)
 }
@@ -1024,6 +1080,8 @@ func (tx *MainDatabaseTx) UpdateHostBlocklistEntries(ctx context.Context, add, r
 				return fmt.Errorf("failed to insert host blocklist entry: %w", err)
 			} else if entryID, err := res.LastInsertId(); err != nil {
 				return fmt.Errorf("failed to fetch host blocklist entry id: %w", err)
+			} else if ssql.IsBlocklistRule(entry) {
+				continue // rules are matched by the store
 			} else if _, err := joinStmt.Exec(ctx, entryID, entry, entry, fmt.Sprintf("%%.%s", entry)); err != nil {
 				return fmt.Errorf("failed to join host blocklist entry: %w", err)
 			}
@@ -1050,11 +1108,11 @@ func (tx *MainDatabaseTx) UpdateHostCheck(ctx context.Context, hk types.PublicKe
 	_, err := tx.Exec(ctx, `
 		INSERT INTO host_checks (created_at, db_host_id, usability_blocked, usability_offline, usability_low_score,
 			usability_redundant_ip, usability_gouging, usability_low_max_duration, usability_not_accepting_contracts, usability_not_announced, usability_not_completing_scan,
-			score_age, score_collateral, score_interactions, score_storage_remaining, score_uptime, score_version, score_prices,
+			score_age, score_collateral, score_interactions, score_storage_remaining, score_uptime, score_version, score_prices, score_latency, score_throughput,
 			gouging_download_err, gouging_gouging_err, gouging_prune_err, gouging_upload_err)
 	    VALUES (?,
 			(SELECT id FROM hosts WHERE public_key = ?),
-			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON DUPLICATE KEY UPDATE
 			created_at = VALUES(created_at), db_host_id = VALUES(db_host_id),
 			usability_blocked = VALUES(usability_blocked), usability_offline = VALUES(usability_offline), usability_low_score = VALUES(usability_low_score),
@@ -1062,11 +1120,11 @@ func (tx *MainDatabaseTx) UpdateHostCheck(ctx context.Context, hk types.PublicKe
 			usability_not_announced = VALUES(usability_not_announced), usability_not_completing_scan = VALUES(usability_not_completing_scan),
 			score_age = VALUES(score_age), score_collateral = VALUES(score_collateral), score_interactions = VALUES(score_interactions),
 			score_storage_remaining = VALUES(score_storage_remaining), score_uptime = VALUES(score_uptime), score_version = VALUES(score_version),
-			score_prices = VALUES(score_prices), gouging_download_err = VALUES(gouging_download_err),
+			score_prices = VALUES(score_prices), score_latency = VALUES(score_latency), score_throughput = VALUES(score_throughput), gouging_download_err = VALUES(gouging_download_err),
 			gouging_gouging_err = VALUES(gouging_gouging_err), gouging_prune_err = VALUES(gouging_prune_err), gouging_upload_err = VALUES(gouging_upload_err)
 	`, time.Now(), ssql.PublicKey(hk), hc.UsabilityBreakdown.Blocked, hc.UsabilityBreakdown.Offline, hc.UsabilityBreakdown.LowScore,
 		hc.UsabilityBreakdown.RedundantIP, hc.UsabilityBreakdown.Gouging, hc.UsabilityBreakdown.LowMaxDuration, hc.UsabilityBreakdown.NotAcceptingContracts, hc.UsabilityBreakdown.NotAnnounced, hc.UsabilityBreakdown.NotCompletingScan,
-		hc.ScoreBreakdown.Age, hc.ScoreBreakdown.Collateral, hc.ScoreBreakdown.Interactions, hc.ScoreBreakdown.StorageRemaining, hc.ScoreBreakdown.Uptime, hc.ScoreBreakdown.Version, hc.ScoreBreakdown.Prices,
+		hc.ScoreBreakdown.Age, hc.ScoreBreakdown.Collateral, hc.ScoreBreakdown.Interactions, hc.ScoreBreakdown.StorageRemaining, hc.ScoreBreakdown.Uptime, hc.ScoreBreakdown.Version, hc.ScoreBreakdown.Prices, hc.ScoreBreakdown.Latency, hc.ScoreBreakdown.Throughput,
 		hc.GougingBreakdown.DownloadErr, hc.GougingBreakdown.GougingErr, hc.GougingBreakdown.PruneErr, hc.GougingBreakdown.UploadErr,
 	)
 	if err != nil {
@@ -1126,6 +1184,10 @@ func (tx *MainDatabaseTx) UpdateSlabHealth(ctx context.Context, limit int64, min
 	return res.RowsAffected()
 }
 
+func (tx *MainDatabaseTx) UpdateTusUploadOffset(ctx context.Context, uploadID string, offset, length uint64) (api.TusUpload, error) {
+	return ssql.UpdateTusUploadOffset(ctx, tx, uploadID, offset, length)
+}
+
 func (tx *MainDatabaseTx) UpsertContractSectors(ctx context.Context, contractSectors []ssql.ContractSector) error {
 	if len(contractSectors) == 0 {
 		return nil
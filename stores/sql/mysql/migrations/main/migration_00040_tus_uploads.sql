@@ -0,0 +1,15 @@
+CREATE TABLE `tus_uploads` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `upload_id` varchar(64) NOT NULL,
+  `db_multipart_upload_id` bigint unsigned DEFAULT NULL,
+  `upload_length` bigint unsigned NOT NULL,
+  `upload_offset` bigint unsigned NOT NULL DEFAULT 0,
+  `num_parts` bigint unsigned NOT NULL DEFAULT 0,
+  `completed_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_tus_uploads_upload_id` (`upload_id`),
+  UNIQUE KEY `idx_tus_uploads_db_multipart_upload_id` (`db_multipart_upload_id`),
+  KEY `idx_tus_uploads_completed_at` (`completed_at`),
+  CONSTRAINT `fk_tus_uploads_multipart_upload` FOREIGN KEY (`db_multipart_upload_id`) REFERENCES `multipart_uploads` (`id`) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,2 @@
+ALTER TABLE `objects` ADD COLUMN `read_count` bigint unsigned NOT NULL DEFAULT 0;
+ALTER TABLE `objects` ADD COLUMN `last_accessed_at` bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE `autopilot_config` ADD COLUMN `hosts_score_weights` JSON DEFAULT NULL;
+ALTER TABLE `hosts` ADD COLUMN `scan_latency` bigint NOT NULL DEFAULT 0;
+ALTER TABLE `host_checks` ADD COLUMN `score_latency` double NOT NULL DEFAULT 1;
@@ -0,0 +1,6 @@
+ALTER TABLE `hosts` ADD COLUMN `last_benchmark` bigint NOT NULL DEFAULT 0;
+ALTER TABLE `hosts` ADD COLUMN `last_benchmark_success` tinyint(1) NOT NULL DEFAULT 0;
+ALTER TABLE `hosts` ADD COLUMN `benchmark_upload_throughput` bigint unsigned NOT NULL DEFAULT 0;
+ALTER TABLE `hosts` ADD COLUMN `benchmark_download_throughput` bigint unsigned NOT NULL DEFAULT 0;
+ALTER TABLE `hosts` ADD COLUMN `benchmark_ttfb` bigint NOT NULL DEFAULT 0;
+ALTER TABLE `host_checks` ADD COLUMN `score_throughput` double NOT NULL DEFAULT 1;
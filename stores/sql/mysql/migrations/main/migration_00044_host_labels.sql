@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `host_labels` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) NOT NULL,
+  `db_host_id` bigint unsigned NOT NULL,
+  `label` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_host_labels_db_host_id_label` (`db_host_id`, `label`),
+  KEY `idx_host_labels_label` (`label`),
+  CONSTRAINT `fk_host_labels_db_host` FOREIGN KEY (`db_host_id`) REFERENCES `hosts` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+ALTER TABLE `hosts` ADD COLUMN `notes` text DEFAULT NULL;
+ALTER TABLE `autopilot_config` ADD COLUMN `hosts_label_rules` JSON DEFAULT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE `autopilot_config` ADD COLUMN `contracts_budget` longtext DEFAULT NULL;
+ALTER TABLE `contracts` ADD COLUMN `renter_cost` longtext;
+UPDATE `contracts` SET `renter_cost` = '0';
@@ -0,0 +1,2 @@
+ALTER TABLE `autopilot_config` ADD COLUMN `contracts_stagger_renewals` boolean NOT NULL DEFAULT false;
+ALTER TABLE `autopilot_config` ADD COLUMN `contracts_max_renewals_per_cycle` bigint unsigned NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE `contracts` ADD COLUMN `manual` boolean NOT NULL DEFAULT false;
+ALTER TABLE `contracts` ADD COLUMN `manual_renewal` JSON DEFAULT NULL;
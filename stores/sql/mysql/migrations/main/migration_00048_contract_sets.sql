@@ -0,0 +1,2 @@
+ALTER TABLE `contracts` ADD COLUMN `contract_set` varchar(191) DEFAULT NULL;
+ALTER TABLE `autopilot_config` ADD COLUMN `contract_sets` JSON DEFAULT NULL;
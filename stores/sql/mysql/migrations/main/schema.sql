@@ -45,6 +45,13 @@ CREATE TABLE `hosts` (
   `failed_interactions` double DEFAULT NULL,
   `lost_sectors` bigint unsigned DEFAULT NULL,
   `last_announcement` datetime(3) DEFAULT NULL,
+  `scan_latency` bigint NOT NULL DEFAULT 0,
+  `last_benchmark` bigint NOT NULL DEFAULT 0,
+  `last_benchmark_success` tinyint(1) NOT NULL DEFAULT 0,
+  `benchmark_upload_throughput` bigint unsigned NOT NULL DEFAULT 0,
+  `benchmark_download_throughput` bigint unsigned NOT NULL DEFAULT 0,
+  `benchmark_ttfb` bigint NOT NULL DEFAULT 0,
+  `notes` text DEFAULT NULL,
   PRIMARY KEY (`id`),
   UNIQUE KEY `public_key` (`public_key`),
   KEY `idx_hosts_public_key` (`public_key`),
@@ -77,6 +84,11 @@ CREATE TABLE `contracts` (
 
   `contract_price` longtext,
   `initial_renter_funds` longtext,
+  `renter_cost` longtext,
+
+  `manual` boolean NOT NULL DEFAULT false,
+  `manual_renewal` JSON DEFAULT NULL,
+  `contract_set` varchar(191) DEFAULT NULL,
 
   `delete_spending` longtext,
   `fund_account_spending` longtext,
@@ -209,6 +221,18 @@ CREATE TABLE `host_addresses` (
   CONSTRAINT `fk_host_addresses_db_host` FOREIGN KEY (`db_host_id`) REFERENCES `hosts` (`id`) ON DELETE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 
+-- dbHostLabel
+CREATE TABLE `host_labels` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) NOT NULL,
+  `db_host_id` bigint unsigned NOT NULL,
+  `label` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_host_labels_db_host_id_label` (`db_host_id`, `label`),
+  KEY `idx_host_labels_label` (`label`),
+  CONSTRAINT `fk_host_labels_db_host` FOREIGN KEY (`db_host_id`) REFERENCES `hosts` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
 -- dbBlocklistEntry
 CREATE TABLE `host_blocklist_entries` (
   `id` bigint unsigned NOT NULL AUTO_INCREMENT,
@@ -272,6 +296,8 @@ CREATE TABLE `objects` (
   `size` bigint DEFAULT NULL,
   `mime_type` longtext,
   `etag` varchar(191) DEFAULT NULL,
+  `read_count` bigint unsigned NOT NULL DEFAULT 0,
+  `last_accessed_at` bigint NOT NULL DEFAULT 0,
   PRIMARY KEY (`id`),
   UNIQUE KEY `idx_object_bucket` (`db_bucket_id`,`object_id`),
   KEY `idx_objects_db_bucket_id` (`db_bucket_id`),
@@ -364,6 +390,8 @@ CREATE TABLE `host_checks` (
   `score_uptime` double NOT NULL,
   `score_version` double NOT NULL,
   `score_prices` double NOT NULL,
+  `score_latency` double NOT NULL DEFAULT 1,
+  `score_throughput` double NOT NULL DEFAULT 1,
 
   `gouging_download_err` text,
   `gouging_gouging_err` text,
@@ -492,11 +520,35 @@ CREATE TABLE `autopilot_config` (
   `contracts_upload` bigint unsigned DEFAULT NULL,
   `contracts_storage` bigint unsigned DEFAULT NULL,
   `contracts_prune` boolean NOT NULL DEFAULT false,
+  `contracts_budget` longtext DEFAULT NULL,
+  `contracts_stagger_renewals` boolean NOT NULL DEFAULT false,
+  `contracts_max_renewals_per_cycle` bigint unsigned NOT NULL DEFAULT 0,
 
   `hosts_max_downtime_hours` bigint unsigned DEFAULT NULL,
   `hosts_min_protocol_version` varchar(191) DEFAULT NULL,
   `hosts_max_consecutive_scan_failures` bigint unsigned DEFAULT NULL,
+  `hosts_score_weights` JSON DEFAULT NULL,
+  `hosts_label_rules` JSON DEFAULT NULL,
+
+  `contract_sets` JSON DEFAULT NULL,
 
   PRIMARY KEY (`id`),
   CHECK (`id` = 1)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- tus uploads
+CREATE TABLE `tus_uploads` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `upload_id` varchar(64) NOT NULL,
+  `db_multipart_upload_id` bigint unsigned DEFAULT NULL,
+  `upload_length` bigint unsigned NOT NULL,
+  `upload_offset` bigint unsigned NOT NULL DEFAULT 0,
+  `num_parts` bigint unsigned NOT NULL DEFAULT 0,
+  `completed_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_tus_uploads_upload_id` (`upload_id`),
+  UNIQUE KEY `idx_tus_uploads_db_multipart_upload_id` (`db_multipart_upload_id`),
+  KEY `idx_tus_uploads_completed_at` (`completed_at`),
+  CONSTRAINT `fk_tus_uploads_multipart_upload` FOREIGN KEY (`db_multipart_upload_id`) REFERENCES `multipart_uploads` (`id`) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS `health` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `timestamp` bigint NOT NULL,
+  `health_cutoff` double NOT NULL,
+  `bytes_below_cutoff` bigint NOT NULL,
+  `buckets` text NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_health_timestamp` (`timestamp`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
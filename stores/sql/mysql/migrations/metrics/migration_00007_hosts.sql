@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS `hosts` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `timestamp` bigint NOT NULL,
+  `host` varbinary(32) NOT NULL,
+  `storage_price_lo` bigint NOT NULL,
+  `storage_price_hi` bigint NOT NULL,
+  `ingress_price_lo` bigint NOT NULL,
+  `ingress_price_hi` bigint NOT NULL,
+  `egress_price_lo` bigint NOT NULL,
+  `egress_price_hi` bigint NOT NULL,
+  `remaining_storage` bigint NOT NULL,
+  `uptime` double NOT NULL,
+  `score` double NOT NULL,
+  `usable` boolean NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_hosts_timestamp` (`timestamp`),
+  KEY `idx_hosts_host_timestamp` (`host`,`timestamp`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS `contract_spending_baselines` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `timestamp` bigint NOT NULL,
+  `fcid` varbinary(32) NOT NULL,
+  `upload_spending_lo` bigint NOT NULL,
+  `upload_spending_hi` bigint NOT NULL,
+  `fund_account_spending_lo` bigint NOT NULL,
+  `fund_account_spending_hi` bigint NOT NULL,
+  `delete_spending_lo` bigint NOT NULL,
+  `delete_spending_hi` bigint NOT NULL,
+  `sector_roots_spending_lo` bigint NOT NULL,
+  `sector_roots_spending_hi` bigint NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_contract_spending_baselines_fcid` (`fcid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -73,3 +73,53 @@ CREATE TABLE `wallets` (
   KEY `idx_unconfirmed` (`unconfirmed_lo`,`unconfirmed_hi`),
   KEY `idx_wallets_immature` (`immature_lo`,`immature_hi`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- dbHealthMetric
+CREATE TABLE `health` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `timestamp` bigint NOT NULL,
+  `health_cutoff` double NOT NULL,
+  `bytes_below_cutoff` bigint NOT NULL,
+  `buckets` text NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_health_timestamp` (`timestamp`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- dbHostMetric
+CREATE TABLE `hosts` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `timestamp` bigint NOT NULL,
+  `host` varbinary(32) NOT NULL,
+  `storage_price_lo` bigint NOT NULL,
+  `storage_price_hi` bigint NOT NULL,
+  `ingress_price_lo` bigint NOT NULL,
+  `ingress_price_hi` bigint NOT NULL,
+  `egress_price_lo` bigint NOT NULL,
+  `egress_price_hi` bigint NOT NULL,
+  `remaining_storage` bigint NOT NULL,
+  `uptime` double NOT NULL,
+  `score` double NOT NULL,
+  `usable` boolean NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_hosts_timestamp` (`timestamp`),
+  KEY `idx_hosts_host_timestamp` (`host`,`timestamp`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- dbContractSpendingBaseline
+CREATE TABLE `contract_spending_baselines` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `timestamp` bigint NOT NULL,
+  `fcid` varbinary(32) NOT NULL,
+  `upload_spending_lo` bigint NOT NULL,
+  `upload_spending_hi` bigint NOT NULL,
+  `fund_account_spending_lo` bigint NOT NULL,
+  `fund_account_spending_hi` bigint NOT NULL,
+  `delete_spending_lo` bigint NOT NULL,
+  `delete_spending_hi` bigint NOT NULL,
+  `sector_roots_spending_lo` bigint NOT NULL,
+  `sector_roots_spending_hi` bigint NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_contract_spending_baselines_fcid` (`fcid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -31,6 +31,13 @@ type ContractRow struct {
 	// cost fields
 	ContractPrice      Currency
 	InitialRenterFunds Currency
+	RenterCost         Currency
+
+	// manual fields
+	Manual        bool
+	ManualRenewal *ContractRenewalSettings
+
+	ContractSet NullableString
 
 	// spending fields
 	DeleteSpending      Currency
@@ -43,7 +50,8 @@ func (r *ContractRow) Scan(s Scanner) error {
 	return s.Scan(
 		&r.FCID, &r.HostID, &r.HostKey,
 		&r.ArchivalReason, &r.ProofHeight, &r.RenewedFrom, &r.RenewedTo, &r.RevisionHeight, &r.RevisionNumber, &r.Size, &r.StartHeight, &r.State, &r.Usability, &r.WindowStart, &r.WindowEnd,
-		&r.ContractPrice, &r.InitialRenterFunds,
+		&r.ContractPrice, &r.InitialRenterFunds, &r.RenterCost,
+		&r.Manual, &r.ManualRenewal, &r.ContractSet,
 		&r.DeleteSpending, &r.FundAccountSpending, &r.SectorRootsSpending, &r.UploadSpending,
 	)
 }
@@ -62,6 +70,12 @@ func (r *ContractRow) ContractMetadata() api.ContractMetadata {
 
 		ContractPrice:      types.Currency(r.ContractPrice),
 		InitialRenterFunds: types.Currency(r.InitialRenterFunds),
+		RenterCost:         types.Currency(r.RenterCost),
+
+		Manual:  r.Manual,
+		Renewal: (*api.ContractRenewalSettings)(r.ManualRenewal),
+
+		ContractSet: string(r.ContractSet),
 
 		ArchivalReason: string(r.ArchivalReason),
 		ProofHeight:    r.ProofHeight,
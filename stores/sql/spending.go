@@ -0,0 +1,122 @@
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.sia.tech/core/types"
+	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/sql"
+)
+
+type (
+	spendingReportKey struct {
+		period int64
+		hk     types.PublicKey
+	}
+
+	// spendingReportItems aggregates spending into the periods of a spending
+	// report.
+	spendingReportItems struct {
+		start    time.Time
+		end      time.Time
+		interval time.Duration
+		spending map[spendingReportKey]api.SpendingBreakdown
+	}
+)
+
+func newSpendingReportItems(start time.Time, n uint64, interval time.Duration) *spendingReportItems {
+	return &spendingReportItems{
+		start:    start,
+		end:      start.Add(time.Duration(n) * interval),
+		interval: interval,
+		spending: make(map[spendingReportKey]api.SpendingBreakdown),
+	}
+}
+
+// add adds the given spending to the period that contains the given
+// timestamp, spending outside of the report's periods is ignored.
+func (r *spendingReportItems) add(timestamp time.Time, hk types.PublicKey, b api.SpendingBreakdown) {
+	if timestamp.Before(r.start) || !timestamp.Before(r.end) {
+		return
+	} else if b.Total().IsZero() {
+		return
+	}
+	key := spendingReportKey{
+		period: int64(timestamp.Sub(r.start) / r.interval),
+		hk:     hk,
+	}
+	r.spending[key] = r.spending[key].Add(b)
+}
+
+// items returns the aggregated spending sorted by period and host.
+func (r *spendingReportItems) items() []api.SpendingReportItem {
+	items := make([]api.SpendingReportItem, 0, len(r.spending))
+	for key, b := range r.spending {
+		items = append(items, api.SpendingReportItem{
+			Period:            api.TimeRFC3339(r.start.Add(time.Duration(key.period) * r.interval)),
+			HostKey:           key.hk,
+			SpendingBreakdown: b,
+		})
+	}
+	slices.SortFunc(items, compareSpendingReportItems)
+	return items
+}
+
+// ContractPriceReport returns the contract prices paid to hosts in n periods
+// of the given interval. Archived contracts are included, so renewals are
+// accounted for in the period they took place.
+func ContractPriceReport(ctx context.Context, tx sql.Tx, start time.Time, n uint64, interval time.Duration) ([]api.SpendingReportItem, error) {
+	if n > api.MetricMaxIntervals {
+		return nil, api.ErrMaxIntervalsExceeded
+	}
+
+	rows, err := tx.Query(ctx, "SELECT created_at, host_key, COALESCE(contract_price, '0') FROM contracts WHERE created_at IS NOT NULL")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch contracts: %w", err)
+	}
+	defer rows.Close()
+
+	report := newSpendingReportItems(start, n, interval)
+	for rows.Next() {
+		var createdAt time.Time
+		var hk PublicKey
+		var price types.Currency
+		if err := rows.Scan(&createdAt, &hk, (*Currency)(&price)); err != nil {
+			return nil, fmt.Errorf("failed to scan contract: %w", err)
+		}
+		report.add(createdAt, types.PublicKey(hk), api.SpendingBreakdown{ContractPrice: price})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return report.items(), nil
+}
+
+// MergeSpendingReportItems merges the given items, summing up the spending of
+// items that share the same period and host.
+func MergeSpendingReportItems(items ...[]api.SpendingReportItem) []api.SpendingReportItem {
+	indices := make(map[spendingReportKey]int)
+	var merged []api.SpendingReportItem
+	for _, item := range slices.Concat(items...) {
+		key := spendingReportKey{period: time.Time(item.Period).UnixMilli(), hk: item.HostKey}
+		if i, ok := indices[key]; ok {
+			merged[i].SpendingBreakdown = merged[i].SpendingBreakdown.Add(item.SpendingBreakdown)
+			continue
+		}
+		indices[key] = len(merged)
+		merged = append(merged, item)
+	}
+	slices.SortFunc(merged, compareSpendingReportItems)
+	return merged
+}
+
+func compareSpendingReportItems(a, b api.SpendingReportItem) int {
+	if c := time.Time(a.Period).Compare(time.Time(b.Period)); c != 0 {
+		return c
+	}
+	return bytes.Compare(a.HostKey[:], b.HostKey[:])
+}
@@ -402,6 +402,12 @@ func (c chainUpdateTx) UpdateHost(hk types.PublicKey, v2Ha chain.V2HostAnnouncem
 	}
 
 	for _, row := range entries {
+		// rules need the host's addresses to be resolved, they are
+		// re-evaluated by the store once the update is applied
+		if ssql.IsBlocklistRule(row.entry) {
+			continue
+		}
+
 		var blocked bool
 		for _, value := range values {
 			if value == row.entry || strings.HasSuffix(value, "."+row.entry) {
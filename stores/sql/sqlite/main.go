@@ -281,10 +281,18 @@ func (tx *MainDatabaseTx) CompleteMultipartUpload(ctx context.Context, bucket, k
 	return eTag, nil
 }
 
+func (tx *MainDatabaseTx) CompleteTusUpload(ctx context.Context, uploadID string) error {
+	return ssql.CompleteTusUpload(ctx, tx, uploadID)
+}
+
 func (tx *MainDatabaseTx) Contract(ctx context.Context, fcid types.FileContractID) (api.ContractMetadata, error) {
 	return ssql.Contract(ctx, tx, fcid)
 }
 
+func (tx *MainDatabaseTx) ContractPriceReport(ctx context.Context, start time.Time, n uint64, interval time.Duration) ([]api.SpendingReportItem, error) {
+	return ssql.ContractPriceReport(ctx, tx, start, n, interval)
+}
+
 func (tx *MainDatabaseTx) ContractRoots(ctx context.Context, fcid types.FileContractID) ([]types.Hash256, error) {
 	return ssql.ContractRoots(ctx, tx, fcid)
 }
@@ -293,6 +301,10 @@ func (tx *MainDatabaseTx) Contracts(ctx context.Context, opts api.ContractsOpts)
 	return ssql.Contracts(ctx, tx, opts)
 }
 
+func (tx *MainDatabaseTx) ContractsRenterCost(ctx context.Context, minStartHeight uint64) (types.Currency, error) {
+	return ssql.ContractsRenterCost(ctx, tx, minStartHeight)
+}
+
 func (tx *MainDatabaseTx) ContractSize(ctx context.Context, id types.FileContractID) (api.ContractSize, error) {
 	return ssql.ContractSize(ctx, tx, id)
 }
@@ -322,6 +334,10 @@ func (tx *MainDatabaseTx) CreateBucket(ctx context.Context, bucket string, bp ap
 	return nil
 }
 
+func (tx *MainDatabaseTx) DeleteContractSectors(ctx context.Context, fcid types.FileContractID, roots []types.Hash256) (int, error) {
+	return ssql.DeleteContractSectors(ctx, tx, fcid, roots)
+}
+
 func (tx *MainDatabaseTx) DeleteHostSector(ctx context.Context, hk types.PublicKey, root types.Hash256) (int, error) {
 	return ssql.DeleteHostSector(ctx, tx, hk, root)
 }
@@ -502,6 +518,10 @@ func (tx *MainDatabaseTx) InsertObject(ctx context.Context, bucket, key string,
 	return nil
 }
 
+func (tx *MainDatabaseTx) InsertTusUpload(ctx context.Context, uploadID string, length uint64) error {
+	return ssql.InsertTusUpload(ctx, tx, uploadID, length)
+}
+
 func (tx *MainDatabaseTx) InvalidateSlabHealthByFCID(ctx context.Context, fcids []types.FileContractID, limit int64) (int64, error) {
 	if len(fcids) == 0 {
 		return 0, nil
@@ -738,17 +758,17 @@ func (tx *MainDatabaseTx) PutContract(ctx context.Context, c api.ContractMetadat
 INSERT INTO contracts (
 	created_at, fcid, host_id, host_key,
 	archival_reason, proof_height, renewed_from, renewed_to, revision_height, revision_number, size, start_height, state, usability, window_start, window_end,
-	contract_price, initial_renter_funds,
+	contract_price, initial_renter_funds, renter_cost, manual, manual_renewal, contract_set,
 	delete_spending, fund_account_spending, sector_roots_spending, upload_spending
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 ON CONFLICT(fcid) DO UPDATE SET
 	fcid = EXCLUDED.fcid, host_id = EXCLUDED.host_id, host_key = EXCLUDED.host_key,
 	archival_reason = EXCLUDED.archival_reason, proof_height = EXCLUDED.proof_height, renewed_from = EXCLUDED.renewed_from, renewed_to = EXCLUDED.renewed_to, revision_height = EXCLUDED.revision_height, revision_number = EXCLUDED.revision_number, size = EXCLUDED.size, start_height = EXCLUDED.start_height, state = EXCLUDED.state, usability = EXCLUDED.usability, window_start = EXCLUDED.window_start, window_end = EXCLUDED.window_end,
-	contract_price = EXCLUDED.contract_price, initial_renter_funds = EXCLUDED.initial_renter_funds,
+	contract_price = EXCLUDED.contract_price, initial_renter_funds = EXCLUDED.initial_renter_funds, renter_cost = EXCLUDED.renter_cost, manual = EXCLUDED.manual, manual_renewal = EXCLUDED.manual_renewal, contract_set = EXCLUDED.contract_set,
 	delete_spending = EXCLUDED.delete_spending, fund_account_spending = EXCLUDED.fund_account_spending, sector_roots_spending = EXCLUDED.sector_roots_spending, upload_spending = EXCLUDED.upload_spending`,
 		time.Now(), ssql.FileContractID(c.ID), hostID, ssql.PublicKey(c.HostKey),
 		ssql.NullableString(c.ArchivalReason), c.ProofHeight, ssql.FileContractID(c.RenewedFrom), ssql.FileContractID(c.RenewedTo), c.RevisionHeight, c.RevisionNumber, c.Size, c.StartHeight, state, usability, c.WindowStart, c.WindowEnd,
-		ssql.Currency(c.ContractPrice), ssql.Currency(c.InitialRenterFunds),
+		ssql.Currency(c.ContractPrice), ssql.Currency(c.InitialRenterFunds), ssql.Currency(c.RenterCost), c.Manual, (*ssql.ContractRenewalSettings)(c.Renewal), ssql.NullableString(c.ContractSet),
 		ssql.Currency(c.Spending.Deletions), ssql.Currency(c.Spending.FundAccount), ssql.Currency(c.Spending.SectorRoots), ssql.Currency(c.Spending.Uploads),
 	)
 	if err != nil {
@@ -761,6 +781,22 @@ func (tx *MainDatabaseTx) RecordContractSpending(ctx context.Context, fcid types
 	return ssql.RecordContractSpending(ctx, tx, fcid, revisionNumber, size, newSpending)
 }
 
+func (tx *MainDatabaseTx) RecordObjectAccess(ctx context.Context, bucket, key string, readCount uint64, accessedAt time.Time) error {
+	return ssql.RecordObjectAccess(ctx, tx, bucket, key, readCount, accessedAt)
+}
+
+func (tx *MainDatabaseTx) UpdateHostBlocklistRuleHosts(ctx context.Context, entry string, blocked, unblocked []types.PublicKey) error {
+	return ssql.UpdateHostBlocklistRuleHosts(ctx, tx, entry, blocked, unblocked)
+}
+
+func (tx *MainDatabaseTx) UpdateHostLabels(ctx context.Context, hk types.PublicKey, labels []string, notes string) error {
+	return ssql.UpdateHostLabels(ctx, tx, hk, labels, notes)
+}
+
+func (tx *MainDatabaseTx) RecordHostBenchmark(ctx context.Context, hk types.PublicKey, b api.HostBenchmark) error {
+	return ssql.RecordHostBenchmark(ctx, tx, hk, b)
+}
+
 func (tx *MainDatabaseTx) RecordHostScans(ctx context.Context, scans []api.HostScan) error {
 	return ssql.RecordHostScans(ctx, tx, scans)
 }
@@ -891,18 +927,26 @@ func (tx *MainDatabaseTx) SaveAccounts(ctx context.Context, accounts []api.Accou
 
 func (tx *MainDatabaseTx) ScanObjectMetadata(s ssql.Scanner, others ...any) (md api.ObjectMetadata, err error) {
 	var createdAt string
-	dst := []any{&md.Key, &md.Size, &md.Health, &md.MimeType, &createdAt, &md.ETag, &md.Bucket}
+	var lastAccessedAt int64
+	dst := []any{&md.Key, &md.Size, &md.Health, &md.MimeType, &createdAt, &md.ETag, &md.Bucket, &md.ReadCount, &lastAccessedAt}
 	dst = append(dst, others...)
 	if err := s.Scan(dst...); err != nil {
 		return api.ObjectMetadata{}, fmt.Errorf("failed to scan object metadata: %w", err)
 	} else if *(*time.Time)(&md.ModTime), err = time.Parse(time.DateTime, createdAt); err != nil {
 		return api.ObjectMetadata{}, fmt.Errorf("failed to parse created at time: %w", err)
 	}
+	if lastAccessedAt > 0 {
+		md.LastAccessedAt = api.TimeRFC3339(time.Unix(lastAccessedAt, 0).UTC())
+	}
 	return md, nil
 }
 
 func (tx *MainDatabaseTx) SelectObjectMetadataExpr() string {
-	return "o.object_id, o.size, o.health, o.mime_type, DATETIME(o.created_at), o.etag, b.name"
+	return "o.object_id, o.size, o.health, o.mime_type, DATETIME(o.created_at), o.etag, b.name, o.read_count, o.last_accessed_at"
+}
+
+func (tx *MainDatabaseTx) UpdateContractManual(ctx context.Context, fcid types.FileContractID, manual bool, renewal *api.ContractRenewalSettings) error {
+	return ssql.UpdateContractManual(ctx, tx, fcid, manual, renewal)
 }
 
 func (tx *MainDatabaseTx) UpdateContractUsability(ctx context.Context, fcid types.FileContractID, usability string) error {
@@ -913,18 +957,30 @@ func (tx *MainDatabaseTx) Setting(ctx context.Context, key string) (string, erro
 	return ssql.Setting(ctx, tx, key)
 }
 
+func (tx *MainDatabaseTx) SlabBuckets(ctx context.Context, key object.EncryptionKey) ([]string, error) {
+	return ssql.SlabBuckets(ctx, tx, key)
+}
+
 func (tx *MainDatabaseTx) Slab(ctx context.Context, key object.EncryptionKey) (object.Slab, error) {
 	return ssql.Slab(ctx, tx, key)
 }
 
-func (tx *MainDatabaseTx) SlabsForMigration(ctx context.Context, healthCutoff float64, limit int) ([]api.UnhealthySlab, error) {
-	return ssql.SlabsForMigration(ctx, tx, healthCutoff, limit)
+func (tx *MainDatabaseTx) SlabHealthStats(ctx context.Context, healthCutoff float64) (api.SlabHealthStats, error) {
+	return ssql.SlabHealthStats(ctx, tx, healthCutoff)
+}
+
+func (tx *MainDatabaseTx) SlabsForMigration(ctx context.Context, healthCutoff float64, offset, limit int, rs api.RepairSettings) ([]api.UnhealthySlab, error) {
+	return ssql.SlabsForMigration(ctx, tx, healthCutoff, offset, limit, rs)
 }
 
 func (tx *MainDatabaseTx) Tip(ctx context.Context) (types.ChainIndex, error) {
 	return ssql.Tip(ctx, tx.Tx)
 }
 
+func (tx *MainDatabaseTx) TusUpload(ctx context.Context, uploadID string) (api.TusUpload, error) {
+	return ssql.TusUpload(ctx, tx, uploadID)
+}
+
 func (tx *MainDatabaseTx) UnspentSiacoinElements(ctx context.Context) (ci types.ChainIndex, elements []types.SiacoinElement, err error) {
 	return ssql.UnspentSiacoinElements(ctx, tx.Tx)
 }
@@ -1026,6 +1082,8 @@ func (tx *MainDatabaseTx) UpdateHostBlocklistEntries(ctx context.Context, add, r
 				return fmt.Errorf("failed to insert host blocklist entry: %w", err)
 			} else if entryID, err := res.LastInsertId(); err != nil {
 				return fmt.Errorf("failed to fetch host blocklist entry id: %w", err)
+			} else if ssql.IsBlocklistRule(entry) {
+				continue // rules are matched by the store
 			} else if _, err := joinStmt.Exec(ctx, entryID, entry, entry, fmt.Sprintf("%%.%s", entry)); err != nil {
 				return fmt.Errorf("failed to join host blocklist entry: %w", err)
 			}
@@ -1052,11 +1110,11 @@ func (tx *MainDatabaseTx) UpdateHostCheck(ctx context.Context, hk types.PublicKe
 	_, err := tx.Exec(ctx, `
 	    INSERT INTO host_checks (created_at, db_host_id, usability_blocked, usability_offline, usability_low_score,
 	        usability_redundant_ip, usability_gouging, usability_low_max_duration, usability_not_accepting_contracts, usability_not_announced, usability_not_completing_scan,
-	        score_age, score_collateral, score_interactions, score_storage_remaining, score_uptime, score_version, score_prices,
+	        score_age, score_collateral, score_interactions, score_storage_remaining, score_uptime, score_version, score_prices, score_latency, score_throughput,
 	        gouging_download_err, gouging_gouging_err, gouging_prune_err, gouging_upload_err)
 	    VALUES (?,
 			(SELECT id FROM hosts WHERE public_key = ?),
-			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	    ON CONFLICT (db_host_id) DO UPDATE SET
 	        created_at = EXCLUDED.created_at, db_host_id = EXCLUDED.db_host_id,
 	        usability_blocked = EXCLUDED.usability_blocked, usability_offline = EXCLUDED.usability_offline, usability_low_score = EXCLUDED.usability_low_score,
@@ -1064,11 +1122,11 @@ func (tx *MainDatabaseTx) UpdateHostCheck(ctx context.Context, hk types.PublicKe
 	        usability_not_announced = EXCLUDED.usability_not_announced, usability_not_completing_scan = EXCLUDED.usability_not_completing_scan,
 	        score_age = EXCLUDED.score_age, score_collateral = EXCLUDED.score_collateral, score_interactions = EXCLUDED.score_interactions,
 	        score_storage_remaining = EXCLUDED.score_storage_remaining, score_uptime = EXCLUDED.score_uptime, score_version = EXCLUDED.score_version,
-	        score_prices = EXCLUDED.score_prices, gouging_download_err = EXCLUDED.gouging_download_err,
+	        score_prices = EXCLUDED.score_prices, score_latency = EXCLUDED.score_latency, score_throughput = EXCLUDED.score_throughput, gouging_download_err = EXCLUDED.gouging_download_err,
 	        gouging_gouging_err = EXCLUDED.gouging_gouging_err, gouging_prune_err = EXCLUDED.gouging_prune_err, gouging_upload_err = EXCLUDED.gouging_upload_err
 	    `, time.Now(), ssql.PublicKey(hk), hc.UsabilityBreakdown.Blocked, hc.UsabilityBreakdown.Offline, hc.UsabilityBreakdown.LowScore,
 		hc.UsabilityBreakdown.RedundantIP, hc.UsabilityBreakdown.Gouging, hc.UsabilityBreakdown.LowMaxDuration, hc.UsabilityBreakdown.NotAcceptingContracts, hc.UsabilityBreakdown.NotAnnounced, hc.UsabilityBreakdown.NotCompletingScan,
-		hc.ScoreBreakdown.Age, hc.ScoreBreakdown.Collateral, hc.ScoreBreakdown.Interactions, hc.ScoreBreakdown.StorageRemaining, hc.ScoreBreakdown.Uptime, hc.ScoreBreakdown.Version, hc.ScoreBreakdown.Prices,
+		hc.ScoreBreakdown.Age, hc.ScoreBreakdown.Collateral, hc.ScoreBreakdown.Interactions, hc.ScoreBreakdown.StorageRemaining, hc.ScoreBreakdown.Uptime, hc.ScoreBreakdown.Version, hc.ScoreBreakdown.Prices, hc.ScoreBreakdown.Latency, hc.ScoreBreakdown.Throughput,
 		hc.GougingBreakdown.DownloadErr, hc.GougingBreakdown.GougingErr, hc.GougingBreakdown.PruneErr, hc.GougingBreakdown.UploadErr,
 	)
 	if err != nil {
@@ -1124,6 +1182,10 @@ func (tx *MainDatabaseTx) UpdateSlabHealth(ctx context.Context, limit int64, min
 	return res.RowsAffected()
 }
 
+func (tx *MainDatabaseTx) UpdateTusUploadOffset(ctx context.Context, uploadID string, offset, length uint64) (api.TusUpload, error) {
+	return ssql.UpdateTusUploadOffset(ctx, tx, uploadID, offset, length)
+}
+
 func (tx *MainDatabaseTx) UpsertContractSectors(ctx context.Context, contractSectors []ssql.ContractSector) error {
 	if len(contractSectors) == 0 {
 		return nil
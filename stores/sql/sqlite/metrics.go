@@ -80,6 +80,10 @@ func (tx *MetricsDatabaseTx) ContractMetrics(ctx context.Context, start time.Tim
 	return ssql.ContractMetrics(ctx, tx, start, n, interval, ssql.ContractMetricsQueryOpts{ContractMetricsQueryOpts: opts})
 }
 
+func (tx *MetricsDatabaseTx) ContractSpendingReport(ctx context.Context, start time.Time, n uint64, interval time.Duration) ([]api.SpendingReportItem, error) {
+	return ssql.ContractSpendingReport(ctx, tx, start, n, interval)
+}
+
 func (tx *MetricsDatabaseTx) ContractPruneMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.ContractPruneMetricsQueryOpts) ([]api.ContractPruneMetric, error) {
 	return ssql.ContractPruneMetrics(ctx, tx, start, n, interval, opts)
 }
@@ -88,6 +92,18 @@ func (tx *MetricsDatabaseTx) PruneMetrics(ctx context.Context, metric string, cu
 	return ssql.PruneMetrics(ctx, tx, metric, cutoff)
 }
 
+func (tx *MetricsDatabaseTx) HealthMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HealthMetricsQueryOpts) ([]api.HealthMetric, error) {
+	return ssql.HealthMetrics(ctx, tx, start, n, interval, opts)
+}
+
+func (tx *MetricsDatabaseTx) HostMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HostMetricsQueryOpts) ([]api.HostMetric, error) {
+	return ssql.HostMetrics(ctx, tx, start, n, interval, opts)
+}
+
+func (tx *MetricsDatabaseTx) HostNetworkMetrics(ctx context.Context, start time.Time, n uint64, interval time.Duration, opts api.HostNetworkMetricsQueryOpts) ([]api.HostNetworkMetric, error) {
+	return ssql.HostNetworkMetrics(ctx, tx, start, n, interval, opts)
+}
+
 func (tx *MetricsDatabaseTx) RecordContractMetric(ctx context.Context, metrics ...api.ContractMetric) error {
 	return ssql.RecordContractMetric(ctx, tx, metrics...)
 }
@@ -96,6 +112,14 @@ func (tx *MetricsDatabaseTx) RecordContractPruneMetric(ctx context.Context, metr
 	return ssql.RecordContractPruneMetric(ctx, tx, metrics...)
 }
 
+func (tx *MetricsDatabaseTx) RecordHealthMetric(ctx context.Context, metrics ...api.HealthMetric) error {
+	return ssql.RecordHealthMetric(ctx, tx, metrics...)
+}
+
+func (tx *MetricsDatabaseTx) RecordHostMetric(ctx context.Context, metrics ...api.HostMetric) error {
+	return ssql.RecordHostMetric(ctx, tx, metrics...)
+}
+
 func (tx *MetricsDatabaseTx) RecordWalletMetric(ctx context.Context, metrics ...api.WalletMetric) error {
 	return ssql.RecordWalletMetric(ctx, tx, metrics...)
 }
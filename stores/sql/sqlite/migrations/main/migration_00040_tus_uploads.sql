@@ -0,0 +1,4 @@
+CREATE TABLE `tus_uploads` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`upload_id` text NOT NULL,`db_multipart_upload_id` integer DEFAULT NULL,`upload_length` integer NOT NULL,`upload_offset` integer NOT NULL DEFAULT 0,`num_parts` integer NOT NULL DEFAULT 0,`completed_at` datetime DEFAULT NULL,CONSTRAINT `fk_tus_uploads_multipart_upload` FOREIGN KEY (`db_multipart_upload_id`) REFERENCES `multipart_uploads`(`id`) ON DELETE SET NULL);
+CREATE UNIQUE INDEX `idx_tus_uploads_db_multipart_upload_id` ON `tus_uploads`(`db_multipart_upload_id`);
+CREATE UNIQUE INDEX `idx_tus_uploads_upload_id` ON `tus_uploads`(`upload_id`);
+CREATE INDEX `idx_tus_uploads_completed_at` ON `tus_uploads`(`completed_at`);
@@ -0,0 +1,2 @@
+ALTER TABLE `objects` ADD COLUMN `read_count` integer NOT NULL DEFAULT 0;
+ALTER TABLE `objects` ADD COLUMN `last_accessed_at` integer NOT NULL DEFAULT 0;
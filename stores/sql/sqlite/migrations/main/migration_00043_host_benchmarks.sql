@@ -0,0 +1,6 @@
+ALTER TABLE `hosts` ADD COLUMN `last_benchmark` integer NOT NULL DEFAULT 0;
+ALTER TABLE `hosts` ADD COLUMN `last_benchmark_success` numeric NOT NULL DEFAULT 0;
+ALTER TABLE `hosts` ADD COLUMN `benchmark_upload_throughput` integer NOT NULL DEFAULT 0;
+ALTER TABLE `hosts` ADD COLUMN `benchmark_download_throughput` integer NOT NULL DEFAULT 0;
+ALTER TABLE `hosts` ADD COLUMN `benchmark_ttfb` integer NOT NULL DEFAULT 0;
+ALTER TABLE `host_checks` ADD COLUMN `score_throughput` REAL NOT NULL DEFAULT 1;
@@ -0,0 +1,11 @@
+CREATE TABLE `host_labels` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime NOT NULL,
+    `db_host_id` integer NOT NULL,
+    `label` text NOT NULL,
+    CONSTRAINT `fk_host_labels_db_host` FOREIGN KEY (`db_host_id`) REFERENCES `hosts`(`id`) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX `idx_host_labels_db_host_id_label` ON `host_labels`(`db_host_id`, `label`);
+CREATE INDEX `idx_host_labels_label` ON `host_labels`(`label`);
+ALTER TABLE `hosts` ADD COLUMN `notes` text DEFAULT NULL;
+ALTER TABLE `autopilot_config` ADD COLUMN `hosts_label_rules` text DEFAULT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE `autopilot_config` ADD COLUMN `contracts_stagger_renewals` integer NOT NULL DEFAULT 0;
+ALTER TABLE `autopilot_config` ADD COLUMN `contracts_max_renewals_per_cycle` integer NOT NULL DEFAULT 0;
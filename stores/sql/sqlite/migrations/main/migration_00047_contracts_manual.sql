@@ -0,0 +1,2 @@
+ALTER TABLE `contracts` ADD COLUMN `manual` integer NOT NULL DEFAULT 0;
+ALTER TABLE `contracts` ADD COLUMN `manual_renewal` text DEFAULT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE `contracts` ADD COLUMN `contract_set` text DEFAULT NULL;
+ALTER TABLE `autopilot_config` ADD COLUMN `contract_sets` text DEFAULT NULL;
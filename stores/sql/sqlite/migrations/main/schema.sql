@@ -17,7 +17,14 @@ CREATE TABLE `hosts` (
 `successful_interactions` real,
 `failed_interactions` real,
 `lost_sectors` integer,
-`last_announcement` datetime);
+`last_announcement` datetime,
+`scan_latency` integer NOT NULL DEFAULT 0,
+`last_benchmark` integer NOT NULL DEFAULT 0,
+`last_benchmark_success` numeric NOT NULL DEFAULT 0,
+`benchmark_upload_throughput` integer NOT NULL DEFAULT 0,
+`benchmark_download_throughput` integer NOT NULL DEFAULT 0,
+`benchmark_ttfb` integer NOT NULL DEFAULT 0,
+`notes` text DEFAULT NULL);
 CREATE INDEX `idx_hosts_recent_scan_failures` ON `hosts`(`recent_scan_failures`);
 CREATE INDEX `idx_hosts_recent_downtime` ON `hosts`(`recent_downtime`);
 CREATE INDEX `idx_hosts_scanned` ON `hosts`(`scanned`);
@@ -25,7 +32,7 @@ CREATE INDEX `idx_hosts_last_scan` ON `hosts`(`last_scan`);
 CREATE INDEX `idx_hosts_public_key` ON `hosts`(`public_key`);
 
 -- dbContract
-CREATE TABLE contracts (`id` integer PRIMARY KEY AUTOINCREMENT, `created_at` datetime, `fcid` blob NOT NULL UNIQUE, `host_id` integer, `host_key` blob NOT NULL, `archival_reason` text DEFAULT NULL, `proof_height` integer DEFAULT 0, `renewed_from` blob, `renewed_to` blob, `revision_height` integer DEFAULT 0, `revision_number` text NOT NULL DEFAULT "0", `size` integer, `start_height` integer NOT NULL, `state` integer NOT NULL DEFAULT 0, `usability` integer NOT NULL, `window_start` integer NOT NULL DEFAULT 0, `window_end` integer NOT NULL DEFAULT 0, `contract_price` text, `initial_renter_funds` text, `delete_spending` text, `fund_account_spending` text, `sector_roots_spending` text, `upload_spending` text, CONSTRAINT `fk_contracts_host` FOREIGN KEY (`host_id`) REFERENCES `hosts`(`id`));
+CREATE TABLE contracts (`id` integer PRIMARY KEY AUTOINCREMENT, `created_at` datetime, `fcid` blob NOT NULL UNIQUE, `host_id` integer, `host_key` blob NOT NULL, `archival_reason` text DEFAULT NULL, `proof_height` integer DEFAULT 0, `renewed_from` blob, `renewed_to` blob, `revision_height` integer DEFAULT 0, `revision_number` text NOT NULL DEFAULT "0", `size` integer, `start_height` integer NOT NULL, `state` integer NOT NULL DEFAULT 0, `usability` integer NOT NULL, `window_start` integer NOT NULL DEFAULT 0, `window_end` integer NOT NULL DEFAULT 0, `contract_price` text, `initial_renter_funds` text, `renter_cost` text, `manual` integer NOT NULL DEFAULT 0, `manual_renewal` text DEFAULT NULL, `contract_set` text DEFAULT NULL, `delete_spending` text, `fund_account_spending` text, `sector_roots_spending` text, `upload_spending` text, CONSTRAINT `fk_contracts_host` FOREIGN KEY (`host_id`) REFERENCES `hosts`(`id`));
 CREATE INDEX `idx_contracts_archival_reason` ON `contracts`(`archival_reason`);
 CREATE INDEX `idx_contracts_fcid` ON `contracts`(`fcid`);
 CREATE INDEX `idx_contracts_host_id` ON `contracts`(`host_id`);
@@ -45,7 +52,7 @@ CREATE TABLE `buckets` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` date
 CREATE INDEX `idx_buckets_name` ON `buckets`(`name`);
 
 -- dbObject
-CREATE TABLE `objects` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`db_bucket_id` integer NOT NULL, `object_id` text,`key` blob,`health` real NOT NULL DEFAULT 1,`size` integer,`mime_type` text,`etag` text,CONSTRAINT `fk_objects_db_bucket` FOREIGN KEY (`db_bucket_id`) REFERENCES `buckets`(`id`));
+CREATE TABLE `objects` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`db_bucket_id` integer NOT NULL, `object_id` text,`key` blob,`health` real NOT NULL DEFAULT 1,`size` integer,`mime_type` text,`etag` text,`read_count` integer NOT NULL DEFAULT 0,`last_accessed_at` integer NOT NULL DEFAULT 0,CONSTRAINT `fk_objects_db_bucket` FOREIGN KEY (`db_bucket_id`) REFERENCES `buckets`(`id`));
 CREATE INDEX `idx_objects_db_bucket_id` ON `objects`(`db_bucket_id`);
 CREATE INDEX `idx_objects_etag` ON `objects`(`etag`);
 CREATE INDEX `idx_objects_health` ON `objects`(`health`);
@@ -114,6 +121,17 @@ CREATE TABLE `host_addresses` (
 );
 CREATE INDEX `idx_host_addresses_db_host_id` ON `host_addresses`(`db_host_id`);
 
+-- host_labels contains the labels an operator attached to a host
+CREATE TABLE `host_labels` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime NOT NULL,
+    `db_host_id` integer NOT NULL,
+    `label` text NOT NULL,
+    CONSTRAINT `fk_host_labels_db_host` FOREIGN KEY (`db_host_id`) REFERENCES `hosts`(`id`) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX `idx_host_labels_db_host_id_label` ON `host_labels`(`db_host_id`, `label`);
+CREATE INDEX `idx_host_labels_label` ON `host_labels`(`label`);
+
 -- dbConsensusInfo
 CREATE TABLE `consensus_infos` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`height` integer,`block_id` blob);
 
@@ -171,6 +189,8 @@ CREATE TABLE `host_checks` (
 `score_uptime` REAL NOT NULL,
 `score_version` REAL NOT NULL,
 `score_prices` REAL NOT NULL,
+`score_latency` REAL NOT NULL DEFAULT 1,
+`score_throughput` REAL NOT NULL DEFAULT 1,
 `gouging_download_err` TEXT,
 `gouging_gouging_err` TEXT,
 `gouging_prune_err` TEXT,
@@ -240,4 +260,10 @@ CREATE TABLE `contract_elements` (
 CREATE UNIQUE INDEX `idx_contract_elements_db_contract_id` ON `contract_elements`(`db_contract_id`);
 
 -- autopilot config
-CREATE TABLE autopilot_config (id INTEGER PRIMARY KEY CHECK (id = 1), created_at datetime, enabled integer NOT NULL DEFAULT 0, contracts_amount integer, contracts_period integer, contracts_renew_window integer, contracts_download integer, contracts_upload integer, contracts_storage integer, contracts_prune integer NOT NULL DEFAULT 0, hosts_max_downtime_hours integer, hosts_min_protocol_version text, hosts_max_consecutive_scan_failures integer);
+CREATE TABLE autopilot_config (id INTEGER PRIMARY KEY CHECK (id = 1), created_at datetime, enabled integer NOT NULL DEFAULT 0, contracts_amount integer, contracts_period integer, contracts_renew_window integer, contracts_download integer, contracts_upload integer, contracts_storage integer, contracts_prune integer NOT NULL DEFAULT 0, contracts_budget text DEFAULT NULL, contracts_stagger_renewals integer NOT NULL DEFAULT 0, contracts_max_renewals_per_cycle integer NOT NULL DEFAULT 0, hosts_max_downtime_hours integer, hosts_min_protocol_version text, hosts_max_consecutive_scan_failures integer, hosts_score_weights text DEFAULT NULL, hosts_label_rules text DEFAULT NULL, contract_sets text DEFAULT NULL);
+
+-- tus uploads
+CREATE TABLE `tus_uploads` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`upload_id` text NOT NULL,`db_multipart_upload_id` integer DEFAULT NULL,`upload_length` integer NOT NULL,`upload_offset` integer NOT NULL DEFAULT 0,`num_parts` integer NOT NULL DEFAULT 0,`completed_at` datetime DEFAULT NULL,CONSTRAINT `fk_tus_uploads_multipart_upload` FOREIGN KEY (`db_multipart_upload_id`) REFERENCES `multipart_uploads`(`id`) ON DELETE SET NULL);
+CREATE UNIQUE INDEX `idx_tus_uploads_db_multipart_upload_id` ON `tus_uploads`(`db_multipart_upload_id`);
+CREATE UNIQUE INDEX `idx_tus_uploads_upload_id` ON `tus_uploads`(`upload_id`);
+CREATE INDEX `idx_tus_uploads_completed_at` ON `tus_uploads`(`completed_at`);
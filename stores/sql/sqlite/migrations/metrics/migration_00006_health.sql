@@ -0,0 +1,2 @@
+CREATE TABLE `health` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`timestamp` BIGINT NOT NULL,`health_cutoff` REAL NOT NULL,`bytes_below_cutoff` BIGINT NOT NULL,`buckets` text NOT NULL);
+CREATE INDEX `idx_health_timestamp` ON `health`(`timestamp`);
@@ -0,0 +1,3 @@
+CREATE TABLE `hosts` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`timestamp` BIGINT NOT NULL,`host` blob NOT NULL,`storage_price_lo` BIGINT NOT NULL,`storage_price_hi` BIGINT NOT NULL,`ingress_price_lo` BIGINT NOT NULL,`ingress_price_hi` BIGINT NOT NULL,`egress_price_lo` BIGINT NOT NULL,`egress_price_hi` BIGINT NOT NULL,`remaining_storage` BIGINT NOT NULL,`uptime` REAL NOT NULL,`score` REAL NOT NULL,`usable` integer NOT NULL);
+CREATE INDEX `idx_hosts_timestamp` ON `hosts`(`timestamp`);
+CREATE INDEX `idx_hosts_host_timestamp` ON `hosts`(`host`,`timestamp`);
@@ -0,0 +1 @@
+CREATE TABLE `contract_spending_baselines` (`id` integer PRIMARY KEY AUTOINCREMENT,`timestamp` BIGINT NOT NULL,`fcid` blob NOT NULL UNIQUE,`upload_spending_lo` BIGINT NOT NULL,`upload_spending_hi` BIGINT NOT NULL,`fund_account_spending_lo` BIGINT NOT NULL,`fund_account_spending_hi` BIGINT NOT NULL,`delete_spending_lo` BIGINT NOT NULL,`delete_spending_hi` BIGINT NOT NULL,`sector_roots_spending_lo` BIGINT NOT NULL,`sector_roots_spending_hi` BIGINT NOT NULL);
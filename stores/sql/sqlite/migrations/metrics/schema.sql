@@ -29,3 +29,15 @@ CREATE INDEX `idx_spendable` ON `wallets`(`spendable_lo`,`spendable_hi`);
 CREATE INDEX `idx_confirmed` ON `wallets`(`confirmed_lo`,`confirmed_hi`);
 CREATE INDEX `idx_wallets_immature` ON `wallets`(`immature_lo`,`immature_hi`);
 CREATE INDEX `idx_wallets_timestamp` ON `wallets`(`timestamp`);
+
+-- dbHealthMetric
+CREATE TABLE `health` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`timestamp` BIGINT NOT NULL,`health_cutoff` REAL NOT NULL,`bytes_below_cutoff` BIGINT NOT NULL,`buckets` text NOT NULL);
+CREATE INDEX `idx_health_timestamp` ON `health`(`timestamp`);
+
+-- dbHostMetric
+CREATE TABLE `hosts` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`timestamp` BIGINT NOT NULL,`host` blob NOT NULL,`storage_price_lo` BIGINT NOT NULL,`storage_price_hi` BIGINT NOT NULL,`ingress_price_lo` BIGINT NOT NULL,`ingress_price_hi` BIGINT NOT NULL,`egress_price_lo` BIGINT NOT NULL,`egress_price_hi` BIGINT NOT NULL,`remaining_storage` BIGINT NOT NULL,`uptime` REAL NOT NULL,`score` REAL NOT NULL,`usable` integer NOT NULL);
+CREATE INDEX `idx_hosts_timestamp` ON `hosts`(`timestamp`);
+CREATE INDEX `idx_hosts_host_timestamp` ON `hosts`(`host`,`timestamp`);
+
+-- dbContractSpendingBaseline
+CREATE TABLE `contract_spending_baselines` (`id` integer PRIMARY KEY AUTOINCREMENT,`timestamp` BIGINT NOT NULL,`fcid` blob NOT NULL UNIQUE,`upload_spending_lo` BIGINT NOT NULL,`upload_spending_hi` BIGINT NOT NULL,`fund_account_spending_lo` BIGINT NOT NULL,`fund_account_spending_hi` BIGINT NOT NULL,`delete_spending_lo` BIGINT NOT NULL,`delete_spending_hi` BIGINT NOT NULL,`sector_roots_spending_lo` BIGINT NOT NULL,`sector_roots_spending_hi` BIGINT NOT NULL);
@@ -17,6 +17,7 @@ import (
 	"go.sia.tech/coreutils/chain"
 	"go.sia.tech/coreutils/rhp/v4/siamux"
 	"go.sia.tech/coreutils/wallet"
+	"go.sia.tech/renterd/v2/api"
 	"go.sia.tech/renterd/v2/internal/rhp/v4"
 	"go.sia.tech/renterd/v2/object"
 )
@@ -30,24 +31,25 @@ var (
 )
 
 type (
-	BCurrency      types.Currency
-	BigInt         big.Int
-	BusSetting     string
-	Currency       types.Currency
-	FileContractID types.FileContractID
-	Hash256        types.Hash256
-	MerkleProof    struct{ Hashes []types.Hash256 }
-	NullableString string
-	PublicKey      types.PublicKey
-	EncryptionKey  object.EncryptionKey
-	Uint64Str      uint64
-	UnixTimeMS     time.Time
-	DurationMS     time.Duration
-	Unsigned64     uint64
-	FileContract   types.V2FileContract
-	ChainProtocol  chain.Protocol
-	HostSettings   rhp.HostSettings
-	TransactionSet struct{ Set []types.V2Transaction }
+	BCurrency               types.Currency
+	BigInt                  big.Int
+	BusSetting              string
+	Currency                types.Currency
+	FileContractID          types.FileContractID
+	Hash256                 types.Hash256
+	MerkleProof             struct{ Hashes []types.Hash256 }
+	NullableString          string
+	PublicKey               types.PublicKey
+	EncryptionKey           object.EncryptionKey
+	Uint64Str               uint64
+	UnixTimeMS              time.Time
+	DurationMS              time.Duration
+	Unsigned64              uint64
+	FileContract            types.V2FileContract
+	ChainProtocol           chain.Protocol
+	ContractRenewalSettings api.ContractRenewalSettings
+	HostSettings            rhp.HostSettings
+	TransactionSet          struct{ Set []types.V2Transaction }
 
 	FileContractStateElement struct {
 		ID int64 // db_contract_id
@@ -82,6 +84,7 @@ var (
 	_ scannerValuer = (*FileContract)(nil)
 	_ scannerValuer = (*ChainProtocol)(nil)
 	_ scannerValuer = (*HostSettings)(nil)
+	_ scannerValuer = (*ContractRenewalSettings)(nil)
 )
 
 // Scan implements the sql.Scanner interface.
@@ -531,6 +534,27 @@ func (hs HostSettings) Value() (driver.Value, error) {
 	return json.Marshal(hs)
 }
 
+// Scan scans value into ContractRenewalSettings, implements sql.Scanner
+// interface.
+func (rs *ContractRenewalSettings) Scan(value interface{}) error {
+	var bytes []byte
+	switch value := value.(type) {
+	case string:
+		bytes = []byte(value)
+	case []byte:
+		bytes = value
+	default:
+		return errors.New(fmt.Sprint("failed to unmarshal ContractRenewalSettings value:", value))
+	}
+	return json.Unmarshal(bytes, rs)
+}
+
+// Value returns a ContractRenewalSettings value, implements driver.Valuer
+// interface.
+func (rs ContractRenewalSettings) Value() (driver.Value, error) {
+	return json.Marshal(rs)
+}
+
 // Scan scans value into a TransactionSet, implements sql.Scanner interface.
 func (txnSet *TransactionSet) Scan(value interface{}) error {
 	b, ok := value.([]byte)
@@ -239,6 +239,18 @@ func (c *Client) UploadStats(ctx context.Context) (resp api.UploadStatsResponse,
 	return
 }
 
+// CancelUpload cancels the object upload with given id.
+func (c *Client) CancelUpload(ctx context.Context, id api.UploadID) (err error) {
+	err = c.c.DELETE(ctx, fmt.Sprintf("/upload/%s", id))
+	return
+}
+
+// Uploads returns the progress of all object uploads that are in progress.
+func (c *Client) Uploads(ctx context.Context) (resp []api.UploadProgress, err error) {
+	err = c.c.GET(ctx, "/uploads", &resp)
+	return
+}
+
 func (c *Client) object(ctx context.Context, bucket, key string, opts api.DownloadObjectOptions) (_ io.ReadCloser, _ http.Header, err error) {
 	values := url.Values{}
 	values.Set("bucket", bucket)
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/utils"
+)
+
+// CreateTusUpload creates a new resumable upload of given length using the tus
+// protocol and returns its id.
+func (c *Client) CreateTusUpload(ctx context.Context, bucket, key string, length uint64, opts api.CreateTusUploadOptions) (string, error) {
+	c.c.Custom("POST", "/tus", nil, nil)
+
+	md := map[string]string{
+		"bucket": bucket,
+		"key":    key,
+	}
+	if opts.MimeType != "" {
+		md["filetype"] = opts.MimeType
+	}
+	for k, v := range opts.Metadata {
+		md[k] = v
+	}
+
+	req, err := c.newTusRequest(ctx, "POST", "/tus", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Upload-Length", strconv.FormatUint(length, 10))
+	req.Header.Set("Upload-Metadata", formatTusMetadata(md))
+	header, _, err := utils.DoRequest(req, nil)
+	if err != nil {
+		return "", err
+	}
+	return path.Base(header.Get("Location")), nil
+}
+
+// TerminateTusUpload terminates a resumable upload, discarding the data that
+// was uploaded so far.
+func (c *Client) TerminateTusUpload(ctx context.Context, id string) error {
+	c.c.Custom("DELETE", fmt.Sprintf("/tus/%s", id), nil, nil)
+
+	req, err := c.newTusRequest(ctx, "DELETE", fmt.Sprintf("/tus/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = utils.DoRequest(req, nil)
+	return err
+}
+
+// TusUploadOffset returns the offset and length of a resumable upload.
+func (c *Client) TusUploadOffset(ctx context.Context, id string) (offset, length uint64, err error) {
+	c.c.Custom("HEAD", fmt.Sprintf("/tus/%s", id), nil, nil)
+
+	req, err := c.newTusRequest(ctx, "HEAD", fmt.Sprintf("/tus/%s", id), nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	header, statusCode, err := utils.DoRequest(req, nil)
+	if err != nil && statusCode == http.StatusNotFound {
+		return 0, 0, api.ErrTusUploadNotFound
+	} else if err != nil {
+		return 0, 0, err
+	} else if offset, err = strconv.ParseUint(header.Get("Upload-Offset"), 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse Upload-Offset header: %w", err)
+	} else if length, err = strconv.ParseUint(header.Get("Upload-Length"), 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse Upload-Length header: %w", err)
+	}
+	return offset, length, nil
+}
+
+// UploadTusChunk appends the data in r to the resumable upload at the given
+// offset and returns the new offset of the upload.
+func (c *Client) UploadTusChunk(ctx context.Context, r io.Reader, id string, offset uint64) (uint64, error) {
+	c.c.Custom("PATCH", fmt.Sprintf("/tus/%s", id), []byte{}, nil)
+
+	req, err := c.newTusRequest(ctx, "PATCH", fmt.Sprintf("/tus/%s", id), r)
+	if err != nil {
+		return 0, err
+	} else if req.ContentLength, err = sizeFromSeeker(r); err != nil {
+		return 0, fmt.Errorf("failed to get content length from seeker: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/offset+octet-stream")
+	req.Header.Set("Upload-Offset", strconv.FormatUint(offset, 10))
+	header, _, err := utils.DoRequest(req, nil)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(header.Get("Upload-Offset"), 10, 64)
+}
+
+func (c *Client) newTusRequest(ctx context.Context, method, route string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%v%v", c.c.BaseURL, route), body)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth("", c.c.Password)
+	req.Header.Set("Tus-Resumable", api.TusVersion)
+	return req, nil
+}
+
+func formatTusMetadata(md map[string]string) string {
+	pairs := make([]string, 0, len(md))
+	for k, v := range md {
+		pairs = append(pairs, fmt.Sprintf("%s %s", k, base64.StdEncoding.EncodeToString([]byte(v))))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,192 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/download"
+	"go.sia.tech/renterd/v2/internal/test"
+	"lukechampine.com/frand"
+)
+
+func TestDownloadPrefetch(t *testing.T) {
+	// create test worker
+	w := newTestWorker(t, newTestWorkerCfg())
+
+	// add hosts to worker
+	w.AddHosts(testRedundancySettings.TotalShards)
+
+	// convenience variables
+	dl := w.downloadManager
+	ul := w.uploadManager
+
+	// create test data spanning two slabs
+	slabSize := testRedundancySettings.SlabSizeNoRedundancy()
+	data := frand.Bytes(int(2 * slabSize))
+
+	// upload data
+	params := testParameters(t.Name())
+	_, _, err := ul.Upload(context.Background(), bytes.NewReader(data), w.UploadHosts(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// grab the object
+	o, err := w.os.Object(context.Background(), testBucket, t.Name(), api.GetObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(o.Object.Slabs) != 2 {
+		t.Fatal("expected 2 slabs")
+	}
+
+	// define a helper to download a range
+	downloadRange := func(offset, length uint64, hosts []api.HostInfo) error {
+		t.Helper()
+		var buf bytes.Buffer
+		err := dl.DownloadObject(context.Background(), &buf, *o.Object, offset, length, hosts)
+		if err != nil {
+			return err
+		} else if !bytes.Equal(data[offset:offset+length], buf.Bytes()) {
+			t.Fatal("data mismatch")
+		}
+		return nil
+	}
+
+	// read the first two chunks sequentially
+	const chunkSize = 1 << 20
+	if err := downloadRange(0, chunkSize, w.UsableHosts()); err != nil {
+		t.Fatal(err)
+	} else if err := downloadRange(chunkSize, chunkSize, w.UsableHosts()); err != nil {
+		t.Fatal(err)
+	}
+
+	// assert the remainder of the first slab and the second slab got prefetched
+	if err := test.Retry(100, 100*time.Millisecond, func() error {
+		if n := dl.Stats().NumPrefetchedSlabs; n != 2 {
+			return fmt.Errorf("expected 2 prefetched slabs, got %d", n)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// assert the next read crossing the slab boundary is served from memory
+	if err := downloadRange(2*chunkSize, slabSize, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// assert the first slab is released once the stream moved past it
+	if err := downloadRange(2*chunkSize+slabSize, chunkSize, nil); err != nil {
+		t.Fatal(err)
+	} else if n := dl.Stats().NumPrefetchedSlabs; n != 1 {
+		t.Fatalf("expected 1 prefetched slab, got %d", n)
+	}
+
+	// assert a random read releases all prefetched slabs
+	if err := downloadRange(0, chunkSize, nil); !errors.Is(err, download.ErrDownloadNotEnoughHosts) {
+		t.Fatal("expected not enough hosts error", err)
+	} else if n := dl.Stats().NumPrefetchedSlabs; n != 0 {
+		t.Fatalf("expected 0 prefetched slabs, got %d", n)
+	}
+
+	// assert the failed read didn't move the read position, continuing the
+	// stream prefetches the remainder of the second slab
+	if err := downloadRange(3*chunkSize+slabSize, chunkSize, w.UsableHosts()); err != nil {
+		t.Fatal(err)
+	} else if err := test.Retry(100, 100*time.Millisecond, func() error {
+		if n := dl.Stats().NumPrefetchedSlabs; n != 1 {
+			return fmt.Errorf("expected 1 prefetched slab, got %d", n)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestObjectRanges(t *testing.T) {
+	// create test worker
+	w := newTestWorker(t, newTestWorkerCfg())
+
+	// add hosts to worker
+	w.AddHosts(testRedundancySettings.TotalShards)
+
+	// upload data
+	data := frand.Bytes(1 << 12)
+	params := testParameters("/" + t.Name())
+	_, _, err := w.uploadManager.Upload(context.Background(), bytes.NewReader(data), w.UploadHosts(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// define a helper to fetch ranges of the object
+	getRanges := func(header string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/object%s?bucket=%s", params.Key, testBucket), nil)
+		req.Header.Set("Range", header)
+		rec := httptest.NewRecorder()
+		w.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	// request overlapping and disjoint ranges
+	rec := getRanges("bytes=3000-3099,0-99,50-199")
+	if rec.Code != http.StatusPartialContent {
+		t.Fatalf("unexpected status %v: %v", rec.Code, rec.Body.String())
+	}
+
+	// assert the response contains the coalesced ranges in order
+	mediaType, mediaParams, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	} else if mediaType != "multipart/byteranges" {
+		t.Fatalf("unexpected media type %v", mediaType)
+	}
+	mr := multipart.NewReader(rec.Body, mediaParams["boundary"])
+	for _, r := range []api.DownloadRange{{Offset: 0, Length: 200}, {Offset: 3000, Length: 100}} {
+		part, err := mr.NextPart()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cr, err := api.ParseContentRange(part.Header.Get("Content-Range"))
+		if err != nil {
+			t.Fatal(err)
+		} else if cr.Offset != r.Offset || cr.Length != r.Length || cr.Size != int64(len(data)) {
+			t.Fatalf("unexpected content range %+v", cr)
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		} else if !bytes.Equal(content, data[r.Offset:r.Offset+r.Length]) {
+			t.Fatal("data mismatch")
+		}
+	}
+	if _, err := mr.NextPart(); !errors.Is(err, io.EOF) {
+		t.Fatal("expected no more parts", err)
+	}
+
+	// request ranges that coalesce into a single range
+	rec = getRanges("bytes=0-99,100-199")
+	if rec.Code != http.StatusPartialContent {
+		t.Fatalf("unexpected status %v: %v", rec.Code, rec.Body.String())
+	} else if cr := rec.Header().Get("Content-Range"); cr != fmt.Sprintf("bytes 0-199/%d", len(data)) {
+		t.Fatalf("unexpected content range %v", cr)
+	} else if !bytes.Equal(rec.Body.Bytes(), data[:200]) {
+		t.Fatal("data mismatch")
+	}
+
+	// request ranges that don't overlap with the object
+	rec = getRanges(fmt.Sprintf("bytes=%d-,%d-", len(data), len(data)+1))
+	if rec.Code != http.StatusRequestedRangeNotSatisfiable {
+		t.Fatalf("unexpected status %v", rec.Code)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 
 	"go.sia.tech/renterd/v2/api"
 )
@@ -21,6 +23,20 @@ type (
 		seekOffset  int64
 		dataOffset  int64
 	}
+
+	// rangesReader implements io.ReadSeeker for serving multiple ranges of an
+	// object. Seeking to the start of a range lazily starts the download of
+	// that range, the first Read after the seek then returns its content.
+	rangesReader struct {
+		downloadFn func(w io.Writer, offset, length int64) error
+		ranges     []api.DownloadRange
+		size       int64
+
+		mu     sync.Mutex
+		closed bool
+		offset int64
+		pr     *io.PipeReader
+	}
 )
 
 func newContentReader(r io.Reader, size int64, offset int64) io.ReadSeeker {
@@ -55,7 +71,113 @@ func (cr *contentReader) Read(p []byte) (int, error) {
 	return cr.r.Read(p)
 }
 
+func newRangesReader(ranges []api.DownloadRange, size int64, downloadFn func(w io.Writer, offset, length int64) error) io.ReadSeekCloser {
+	return &rangesReader{
+		downloadFn: downloadFn,
+		ranges:     ranges,
+		size:       size,
+	}
+}
+
+func (rr *rangesReader) Close() error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.closed = true
+	if rr.pr != nil {
+		return rr.pr.Close()
+	}
+	return nil
+}
+
+func (rr *rangesReader) Seek(offset int64, whence int) (int64, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	if offset == 0 && whence == io.SeekEnd {
+		offset = rr.size
+	} else if whence != io.SeekStart {
+		return 0, errors.New("unexpected seek")
+	}
+
+	// abort the ongoing download
+	if rr.pr != nil && offset != rr.offset {
+		rr.pr.Close()
+		rr.pr = nil
+	}
+	rr.offset = offset
+	return rr.offset, nil
+}
+
+func (rr *rangesReader) Read(p []byte) (int, error) {
+	rr.mu.Lock()
+	if rr.closed {
+		rr.mu.Unlock()
+		return 0, io.ErrClosedPipe
+	}
+
+	// start downloading the range that contains the offset
+	if rr.pr == nil {
+		var length int64
+		for _, r := range rr.ranges {
+			if rr.offset >= r.Offset && rr.offset < r.Offset+r.Length {
+				length = r.Offset + r.Length - rr.offset
+				break
+			}
+		}
+		if length == 0 {
+			rr.mu.Unlock()
+			return 0, fmt.Errorf("rangesReader: offset %v is not within any of the requested ranges", rr.offset)
+		}
+
+		pr, pw := io.Pipe()
+		go func(offset int64) {
+			pw.CloseWithError(rr.downloadFn(pw, offset, length))
+		}(rr.offset)
+		rr.pr = pr
+	}
+	pr := rr.pr
+	rr.mu.Unlock()
+
+	n, err := pr.Read(p)
+
+	rr.mu.Lock()
+	if rr.pr == pr {
+		rr.offset += int64(n)
+	}
+	rr.mu.Unlock()
+	return n, err
+}
+
+// formatRangeHeader formats the given ranges as the value of a "Range" header.
+func formatRangeHeader(ranges []api.DownloadRange) string {
+	specs := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		specs = append(specs, fmt.Sprintf("%d-%d", r.Offset, r.Offset+r.Length-1))
+	}
+	return "bytes=" + strings.Join(specs, ",")
+}
+
 func serveContent(rw http.ResponseWriter, req *http.Request, name string, content io.Reader, hor api.HeadObjectResponse) {
+	setContentHeaders(rw, hor)
+
+	// create a content reader
+	rs := newContentReader(content, hor.Size, hor.Range.Offset)
+
+	http.ServeContent(rw, req, name, hor.LastModified.Std(), rs)
+}
+
+// serveContentRanges serves multiple ranges of an object as a
+// multipart/byteranges response. The request's "Range" header is replaced with
+// the coalesced ranges of the response to ensure http.ServeContent only seeks
+// to the start of those.
+func serveContentRanges(rw http.ResponseWriter, req *http.Request, name string, gor api.GetObjectRangesResponse) {
+	setContentHeaders(rw, gor.HeadObjectResponse)
+
+	req.Header.Set("Range", formatRangeHeader(gor.Ranges))
+	http.ServeContent(rw, req, name, gor.LastModified.Std(), gor.Content)
+}
+
+func setContentHeaders(rw http.ResponseWriter, hor api.HeadObjectResponse) {
 	// set content type and etag
 	rw.Header().Set("Content-Type", hor.ContentType)
 	rw.Header().Set("ETag", api.FormatETag(hor.Etag))
@@ -64,9 +186,4 @@ func serveContent(rw http.ResponseWriter, req *http.Request, name string, conten
 	for k, v := range hor.Metadata {
 		rw.Header().Set(fmt.Sprintf("%s%s", api.ObjectMetadataPrefix, k), v)
 	}
-
-	// create a content reader
-	rs := newContentReader(content, hor.Size, hor.Range.Offset)
-
-	http.ServeContent(rw, req, name, hor.LastModified.Std(), rs)
 }
@@ -0,0 +1,308 @@
+package worker
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.sia.tech/jape"
+	"go.sia.tech/renterd/v2/api"
+	"go.sia.tech/renterd/v2/internal/utils"
+)
+
+const (
+	tusContentType = "application/offset+octet-stream"
+
+	tusMetadataBucket   = "bucket"
+	tusMetadataFilename = "filename"
+	tusMetadataFiletype = "filetype"
+	tusMetadataKey      = "key"
+)
+
+var errTusUploadLocked = errors.New("tus upload is locked by another request")
+
+// countingReader counts the number of bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n uint64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += uint64(n)
+	return n, err
+}
+
+// parseTusMetadata parses the value of an "Upload-Metadata" header, which
+// consists of comma separated key-value pairs where the key and the base64
+// encoded value are separated by a space.
+func parseTusMetadata(header string) (map[string]string, error) {
+	md := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, encoded, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for metadata key '%s': %w", key, err)
+		}
+		md[key] = string(value)
+	}
+	return md, nil
+}
+
+// checkTusResumable sets the "Tus-Resumable" header on the response and
+// verifies the client speaks the same version of the protocol.
+func checkTusResumable(jc jape.Context) bool {
+	jc.ResponseWriter.Header().Set("Tus-Resumable", api.TusVersion)
+	if jc.Request.Header.Get("Tus-Resumable") != api.TusVersion {
+		jc.ResponseWriter.Header().Set("Tus-Version", api.TusVersion)
+		jc.Error(fmt.Errorf("unsupported tus version, only %s is supported", api.TusVersion), http.StatusPreconditionFailed)
+		return false
+	}
+	return true
+}
+
+// lockTusUpload prevents concurrent requests from appending to the same tus
+// upload through this worker.
+func (w *Worker) lockTusUpload(id string) (unlock func(), _ error) {
+	w.uploadsMu.Lock()
+	defer w.uploadsMu.Unlock()
+	if _, locked := w.tusUploads[id]; locked {
+		return nil, errTusUploadLocked
+	}
+	w.tusUploads[id] = struct{}{}
+	return func() {
+		w.uploadsMu.Lock()
+		delete(w.tusUploads, id)
+		w.uploadsMu.Unlock()
+	}, nil
+}
+
+func (w *Worker) tusHandlerOPTIONS(jc jape.Context) {
+	jc.ResponseWriter.Header().Set("Tus-Resumable", api.TusVersion)
+	jc.ResponseWriter.Header().Set("Tus-Version", api.TusVersion)
+	jc.ResponseWriter.Header().Set("Tus-Extension", api.TusExtensions)
+	jc.ResponseWriter.WriteHeader(http.StatusNoContent)
+}
+
+func (w *Worker) tusHandlerPOST(jc jape.Context) {
+	jc.Custom(nil, nil)
+	if !checkTusResumable(jc) {
+		return
+	}
+
+	// parse the upload length, deferring the length is not supported
+	length, err := strconv.ParseUint(jc.Request.Header.Get("Upload-Length"), 10, 64)
+	if err != nil {
+		jc.Error(fmt.Errorf("invalid Upload-Length header: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// parse the metadata
+	md, err := parseTusMetadata(jc.Request.Header.Get("Upload-Metadata"))
+	if err != nil {
+		jc.Error(err, http.StatusBadRequest)
+		return
+	}
+
+	// the bucket can be passed as metadata or in the query string
+	bucket := md[tusMetadataBucket]
+	if bucket == "" && jc.DecodeForm("bucket", &bucket) != nil {
+		return
+	} else if bucket == "" {
+		jc.Error(api.ErrBucketMissing, http.StatusBadRequest)
+		return
+	}
+
+	// the key defaults to the name of the file
+	key := md[tusMetadataKey]
+	if key == "" {
+		key = md[tusMetadataFilename]
+	}
+	if key == "" {
+		jc.Error(errors.New("either the 'key' or the 'filename' metadata has to be set"), http.StatusBadRequest)
+		return
+	} else if !strings.HasPrefix(key, "/") {
+		key = "/" + key
+	}
+
+	// all other metadata is stored as user metadata
+	opts := api.CreateTusUploadOptions{
+		MimeType: md[tusMetadataFiletype],
+		Metadata: make(api.ObjectUserMetadata),
+	}
+	for k, v := range md {
+		switch k {
+		case tusMetadataBucket, tusMetadataFilename, tusMetadataFiletype, tusMetadataKey:
+		default:
+			opts.Metadata[k] = v
+		}
+	}
+
+	upload, err := w.bus.CreateTusUpload(jc.Request.Context(), bucket, key, length, opts)
+	if utils.IsErr(err, api.ErrBucketNotFound) {
+		jc.Error(err, http.StatusNotFound)
+		return
+	} else if jc.Check("failed to create tus upload", err) != nil {
+		return
+	}
+
+	// the location is relative to the creation endpoint
+	location := upload.ID
+	if !strings.HasSuffix(jc.Request.URL.Path, "/") {
+		location = "tus/" + upload.ID
+	}
+	jc.ResponseWriter.Header().Set("Location", location)
+	jc.ResponseWriter.Header().Set("Upload-Offset", strconv.FormatUint(upload.Offset, 10))
+	jc.ResponseWriter.WriteHeader(http.StatusCreated)
+}
+
+func (w *Worker) tusUploadHandlerHEAD(jc jape.Context) {
+	jc.Custom(nil, nil)
+	if !checkTusResumable(jc) {
+		return
+	}
+
+	upload, err := w.bus.TusUpload(jc.Request.Context(), jc.PathParam("id"))
+	if utils.IsErr(err, api.ErrTusUploadNotFound) {
+		jc.Error(err, http.StatusNotFound)
+		return
+	} else if jc.Check("failed to fetch tus upload", err) != nil {
+		return
+	}
+
+	jc.ResponseWriter.Header().Set("Cache-Control", "no-store")
+	jc.ResponseWriter.Header().Set("Upload-Offset", strconv.FormatUint(upload.Offset, 10))
+	jc.ResponseWriter.Header().Set("Upload-Length", strconv.FormatUint(upload.Length, 10))
+	jc.ResponseWriter.WriteHeader(http.StatusOK)
+}
+
+func (w *Worker) tusUploadHandlerPATCH(jc jape.Context) {
+	jc.Custom((*[]byte)(nil), nil)
+	ctx := jc.Request.Context()
+	if !checkTusResumable(jc) {
+		return
+	} else if ct := jc.Request.Header.Get("Content-Type"); ct != tusContentType {
+		jc.Error(fmt.Errorf("invalid Content-Type '%s', expected '%s'", ct, tusContentType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	offset, err := strconv.ParseUint(jc.Request.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		jc.Error(fmt.Errorf("invalid Upload-Offset header: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// lock the upload
+	id := jc.PathParam("id")
+	unlock, err := w.lockTusUpload(id)
+	if err != nil {
+		jc.Error(err, http.StatusLocked)
+		return
+	}
+	defer unlock()
+
+	// fetch the upload and verify the offset
+	upload, err := w.bus.TusUpload(ctx, id)
+	if utils.IsErr(err, api.ErrTusUploadNotFound) {
+		jc.Error(err, http.StatusNotFound)
+		return
+	} else if jc.Check("failed to fetch tus upload", err) != nil {
+		return
+	} else if offset != upload.Offset {
+		jc.Error(fmt.Errorf("%w: %d != %d", api.ErrTusUploadOffsetMismatch, offset, upload.Offset), http.StatusConflict)
+		return
+	}
+
+	// verify the chunk fits
+	remaining := upload.Length - upload.Offset
+	if jc.Request.ContentLength > 0 && uint64(jc.Request.ContentLength) > remaining {
+		jc.Error(fmt.Errorf("%w: chunk of %d bytes exceeds the remaining %d bytes", api.ErrTusUploadLengthExceeded, jc.Request.ContentLength, remaining), http.StatusBadRequest)
+		return
+	} else if jc.Request.ContentLength == 0 {
+		jc.ResponseWriter.Header().Set("Upload-Offset", strconv.FormatUint(upload.Offset, 10))
+		jc.ResponseWriter.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// the slab size determines the granularity at which data is committed
+	up, err := w.bus.UploadParams(ctx)
+	if jc.Check("failed to fetch upload params", err) != nil {
+		return
+	}
+	slabSize := up.RedundancySettings.SlabSizeNoRedundancy()
+
+	// upload the body slab by slab, every slab is stored as the next part of
+	// the multipart upload and the offset is advanced right after, that way a
+	// dropped connection only loses the data of the slab that was in flight
+	body := bufio.NewReader(io.LimitReader(jc.Request.Body, int64(remaining)))
+	for !upload.Complete() {
+		if _, err := body.Peek(1); errors.Is(err, io.EOF) {
+			break
+		} else if jc.Check("failed to read tus chunk", err) != nil {
+			return
+		}
+
+		chunkLength := min(slabSize, upload.Length-upload.Offset)
+		cr := &countingReader{r: io.LimitReader(body, int64(chunkLength))}
+		encryptionOffset := int(upload.Offset)
+		_, err = w.UploadMultipartUploadPart(ctx, cr, upload.Bucket, upload.Key, upload.ID, upload.Parts+1, api.UploadMultipartUploadPartOptions{
+			EncryptionOffset: &encryptionOffset,
+			ContentLength:    int64(chunkLength),
+		})
+		if utils.IsErr(err, api.ErrConsensusNotSynced) {
+			jc.Error(err, http.StatusServiceUnavailable)
+			return
+		} else if utils.IsErr(err, api.ErrMultipartUploadNotFound) {
+			jc.Error(err, http.StatusNotFound)
+			return
+		} else if jc.Check("couldn't upload tus chunk", err) != nil {
+			return
+		}
+
+		// advance the offset
+		upload, err = w.bus.UpdateTusUploadOffset(ctx, upload.ID, upload.Offset, cr.n)
+		if utils.IsErr(err, api.ErrTusUploadOffsetMismatch) {
+			jc.Error(err, http.StatusConflict)
+			return
+		} else if jc.Check("failed to update tus upload offset", err) != nil {
+			return
+		}
+	}
+
+	jc.ResponseWriter.Header().Set("Upload-Offset", strconv.FormatUint(upload.Offset, 10))
+	jc.ResponseWriter.WriteHeader(http.StatusNoContent)
+}
+
+func (w *Worker) tusUploadHandlerDELETE(jc jape.Context) {
+	ctx := jc.Request.Context()
+	if !checkTusResumable(jc) {
+		return
+	}
+
+	upload, err := w.bus.TusUpload(ctx, jc.PathParam("id"))
+	if utils.IsErr(err, api.ErrTusUploadNotFound) {
+		jc.Error(err, http.StatusNotFound)
+		return
+	} else if jc.Check("failed to fetch tus upload", err) != nil {
+		return
+	} else if upload.Complete() {
+		jc.Error(errors.New("tus upload was already completed"), http.StatusConflict)
+		return
+	}
+
+	err = w.bus.AbortMultipartUpload(ctx, upload.Bucket, upload.Key, upload.ID)
+	if jc.Check("failed to terminate tus upload", err) != nil {
+		return
+	}
+	jc.ResponseWriter.WriteHeader(http.StatusNoContent)
+}
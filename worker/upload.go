@@ -29,6 +29,13 @@ func (w *Worker) upload(ctx context.Context, bucket, key string, rs api.Redundan
 		opt(&up)
 	}
 
+	// limit the bandwidth
+	bs, err := w.cache.BandwidthSettings(ctx)
+	if err != nil {
+		return "", fmt.Errorf("couldn't fetch bandwidth settings from bus: %w", err)
+	}
+	r = w.uploadLimiter.Reader(ctx, r, bucket, bs.Buckets[bucket].MaxUpload)
+
 	// if not given, try decide on a mime type using the file extension
 	if !up.Multipart && up.MimeType == "" {
 		up.MimeType = mime.TypeByExtension(filepath.Ext(up.Key))
@@ -145,7 +152,9 @@ func (w *Worker) threadedUploadPackedSlabs(rs api.RedundancySettings) {
 	wg.Wait()
 }
 
-func (w *Worker) hostContracts(ctx context.Context) (hosts []upload.HostInfo, _ error) {
+// hostContracts returns the hosts and contracts in the given contract set
+// that can be used to upload data, the empty set refers to the default set.
+func (w *Worker) hostContracts(ctx context.Context, contractSet string) (hosts []upload.HostInfo, _ error) {
 	usableHosts, err := w.bus.UsableHosts(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't fetch usable hosts from bus: %v", err)
@@ -162,6 +171,9 @@ func (w *Worker) hostContracts(ctx context.Context) (hosts []upload.HostInfo, _
 	}
 
 	for _, c := range contracts {
+		if c.ContractSet != contractSet {
+			continue
+		}
 		if h, ok := hmap[c.HostKey]; ok {
 			hosts = append(hosts, upload.HostInfo{
 				HostInfo:            h,
@@ -174,9 +186,34 @@ func (w *Worker) hostContracts(ctx context.Context) (hosts []upload.HostInfo, _
 	return
 }
 
+// bucketHostContracts returns the hosts and contracts that can be used to upload
+// data to the given bucket according to the given placement settings. Only
+// contracts in the bucket's contract set are returned and if the bucket is
+// pinned to a group of hosts, only the hosts in that group are returned.
+func (w *Worker) bucketHostContracts(ctx context.Context, ps api.PlacementSettings, bucket string) ([]upload.HostInfo, error) {
+	hosts, err := w.hostContracts(ctx, ps.ContractSet(bucket))
+	if err != nil {
+		return nil, err
+	}
+
+	pinnedHosts, pinned := ps.PinnedHosts(bucket)
+	if !pinned {
+		return hosts, nil
+	}
+
+	filtered := hosts[:0]
+	for _, h := range hosts {
+		if _, ok := pinnedHosts[h.PublicKey]; ok {
+			filtered = append(filtered, h)
+		}
+	}
+	return filtered, nil
+}
+
 func (w *Worker) uploadPackedSlab(ctx context.Context, mem memory.Memory, ps api.PackedSlab, rs api.RedundancySettings) error {
-	// fetch host & contract info
-	contracts, err := w.hostContracts(ctx)
+	// fetch host & contract info, packed slabs only contain data of buckets
+	// in the default contract set
+	contracts, err := w.hostContracts(ctx, "")
 	if err != nil {
 		return fmt.Errorf("couldn't fetch contracts from bus: %v", err)
 	}
@@ -191,7 +228,7 @@ func (w *Worker) uploadPackedSlab(ctx context.Context, mem memory.Memory, ps api
 	ctx = gouging.WithChecker(ctx, w.bus, up.GougingParams)
 
 	// upload packed slab
-	err = w.uploadManager.UploadPackedSlab(ctx, rs, ps, mem, contracts, up.CurrentHeight)
+	err = w.uploadManager.UploadPackedSlab(ctx, rs, ps, mem, contracts, up.CurrentHeight, up.PlacementSettings.Diversity)
 	if err != nil {
 		return fmt.Errorf("couldn't upload packed slab, err: %v", err)
 	}
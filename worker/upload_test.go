@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -189,7 +191,7 @@ func TestUploadPackedSlab(t *testing.T) {
 
 	// upload the packed slab
 	mem := mm.AcquireMemory(context.Background(), params.RS.SlabSize())
-	err = ul.UploadPackedSlab(context.Background(), params.RS, ps, mem, w.UploadHosts(), 0)
+	err = ul.UploadPackedSlab(context.Background(), params.RS, ps, mem, w.UploadHosts(), 0, api.DiversityRules{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -352,7 +354,7 @@ func TestMigrateLostSector(t *testing.T) {
 
 	// migrate the shard away from the bad host
 	mem := mm.AcquireMemory(context.Background(), rhpv4.SectorSize)
-	err = ul.UploadShards(context.Background(), o.Object.Slabs[0].Slab, shards, hosts, 0, mem)
+	err = ul.UploadShards(context.Background(), o.Object.Slabs[0].Slab, shards, hosts, 0, mem, api.DiversityRules{}, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -466,7 +468,7 @@ func TestUploadShards(t *testing.T) {
 
 	// migrate those shards away from bad hosts
 	mem := mm.AcquireMemory(context.Background(), uint64(len(badIndices))*rhpv4.SectorSize)
-	err = ul.UploadShards(context.Background(), o.Object.Slabs[0].Slab, shards, hosts, 0, mem)
+	err = ul.UploadShards(context.Background(), o.Object.Slabs[0].Slab, shards, hosts, 0, mem, api.DiversityRules{}, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -722,3 +724,157 @@ func TestPinnedObject(t *testing.T) {
 		t.Fatal("downloaded data does not match original data")
 	}
 }
+
+func TestUploadProgress(t *testing.T) {
+	// create test worker
+	w := newTestWorker(t, newTestWorkerCfg())
+
+	// add hosts to worker
+	w.AddHosts(testRedundancySettings.TotalShards)
+
+	// convenience variables
+	ul := w.uploadManager
+	slabSize := int(testRedundancySettings.SlabSizeNoRedundancy())
+
+	// start an upload that only receives the data of its first slab
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go pw.Write(frand.Bytes(slabSize))
+
+	params := testParameters(t.Name())
+	errChan := make(chan error, 1)
+	go func() {
+		_, _, err := ul.Upload(context.Background(), pr, w.UploadHosts(), params)
+		errChan <- err
+	}()
+
+	// wait until the first slab was uploaded
+	var progress api.UploadProgress
+	if err := test.Retry(100, 10*time.Millisecond, func() error {
+		uploads := ul.Uploads()
+		if len(uploads) != 1 {
+			return fmt.Errorf("expected 1 upload, got %d", len(uploads))
+		} else if uploads[0].SlabsUploaded != 1 {
+			return fmt.Errorf("expected 1 uploaded slab, got %d", uploads[0].SlabsUploaded)
+		}
+		progress = uploads[0]
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// assert the progress
+	if progress.Bucket != testBucket || progress.Key != t.Name() {
+		t.Fatal("unexpected upload", progress)
+	} else if progress.BytesReceived != uint64(slabSize) {
+		t.Fatal("unexpected bytes received", progress.BytesReceived)
+	}
+	var sectors uint64
+	for _, h := range progress.Hosts {
+		sectors += h.SectorsUploaded
+	}
+	if sectors != uint64(testRedundancySettings.TotalShards) {
+		t.Fatal("unexpected number of uploaded sectors", sectors)
+	}
+
+	// cancel the upload through the API
+	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/upload/%s", progress.ID), nil)
+	rec := httptest.NewRecorder()
+	w.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatal("unexpected status code", rec.Code, rec.Body.String())
+	}
+
+	// assert the upload was cancelled and is no longer tracked
+	select {
+	case err := <-errChan:
+		if !errors.Is(err, upload.ErrUploadCancelled) {
+			t.Fatal("unexpected error", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("upload wasn't cancelled")
+	}
+	if uploads := ul.Uploads(); len(uploads) != 0 {
+		t.Fatal("expected no uploads", uploads)
+	} else if _, err := w.os.Object(context.Background(), testBucket, t.Name(), api.GetObjectOptions{}); !errors.Is(err, api.ErrObjectNotFound) {
+		t.Fatal("expected object to not exist", err)
+	}
+
+	// cancelling it again should fail
+	rec = httptest.NewRecorder()
+	w.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatal("unexpected status code", rec.Code)
+	}
+}
+
+func TestUploadDiversity(t *testing.T) {
+	// create test worker
+	w := newTestWorker(t, newTestWorkerCfg())
+
+	// add hosts to worker
+	w.AddHosts(testRedundancySettings.TotalShards * 2)
+
+	// convenience variables
+	os := w.os
+	ul := w.uploadManager
+
+	// spread the hosts over 4 countries
+	hosts := w.UploadHosts()
+	countries := make(map[types.PublicKey]string)
+	for i := range hosts {
+		country := fmt.Sprintf("C%d", i%4)
+		hosts[i].Location = &api.HostLocation{Country: country, ASN: uint32(i)}
+		countries[hosts[i].PublicKey] = country
+	}
+
+	// upload data with at most 2 shards per country
+	params := testParameters(t.Name())
+	params.Diversity = api.DiversityRules{MaxShardsPerCountry: 2}
+	_, _, err := ul.Upload(context.Background(), bytes.NewReader(frand.Bytes(128)), hosts, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// assert the limit was respected
+	o, err := os.Object(context.Background(), testBucket, t.Name(), api.GetObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	perCountry := make(map[string]int)
+	for _, shard := range o.Object.Slabs[0].Shards {
+		for hk := range shard.Contracts {
+			perCountry[countries[hk]]++
+		}
+	}
+	for country, n := range perCountry {
+		if n > 2 {
+			t.Fatalf("too many shards in country %v, %d > 2", country, n)
+		}
+	}
+
+	// assert the upload fails if the rules can't be satisfied
+	params.Diversity = api.DiversityRules{MaxShardsPerCountry: 1}
+	_, _, err = ul.Upload(context.Background(), bytes.NewReader(frand.Bytes(128)), hosts, params)
+	if !errors.Is(err, upload.ErrNoCandidateUploader) {
+		t.Fatal("expected no candidate uploader error", err)
+	}
+
+	// assert the existing shards count towards the limit when migrating, the
+	// shards can only be uploaded to the hosts in the last country
+	existing := []api.HostLocation{{Country: "C0"}, {Country: "C0"}, {Country: "C1"}, {Country: "C1"}, {Country: "C2"}, {Country: "C2"}}
+	shards := [][]byte{frand.Bytes(rhpv4.SectorSize), frand.Bytes(rhpv4.SectorSize)}
+	mem := w.ulmm.AcquireMemory(context.Background(), uint64(len(shards))*rhpv4.SectorSize)
+	err = ul.UploadShards(context.Background(), o.Object.Slabs[0].Slab, shards, hosts, 0, mem, api.DiversityRules{MaxShardsPerCountry: 2}, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// assert the upload fails if the last country is full too
+	existing = append(existing, api.HostLocation{Country: "C3"})
+	mem = w.ulmm.AcquireMemory(context.Background(), uint64(len(shards))*rhpv4.SectorSize)
+	err = ul.UploadShards(context.Background(), o.Object.Slabs[0].Slab, shards, hosts, 0, mem, api.DiversityRules{MaxShardsPerCountry: 2}, existing)
+	if !errors.Is(err, upload.ErrNoCandidateUploader) {
+		t.Fatal("expected no candidate uploader error", err)
+	}
+}
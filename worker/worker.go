@@ -26,6 +26,7 @@ import (
 	"go.sia.tech/renterd/v2/build"
 	"go.sia.tech/renterd/v2/config"
 	"go.sia.tech/renterd/v2/internal/accounts"
+	"go.sia.tech/renterd/v2/internal/bandwidth"
 	"go.sia.tech/renterd/v2/internal/contracts"
 	"go.sia.tech/renterd/v2/internal/download"
 	"go.sia.tech/renterd/v2/internal/gouging"
@@ -110,15 +111,23 @@ type (
 		UpdateSlab(ctx context.Context, key object.EncryptionKey, sectors []api.UploadedSector) error
 
 		// NOTE: used by worker
+		AbortMultipartUpload(ctx context.Context, bucket, key string, uploadID string) (err error)
 		Bucket(_ context.Context, bucket string) (api.Bucket, error)
 		Object(ctx context.Context, bucket, key string, opts api.GetObjectOptions) (api.Object, error)
 		DeleteObject(ctx context.Context, bucket, key string) error
 		MultipartUpload(ctx context.Context, uploadID string) (resp api.MultipartUpload, err error)
 		PackedSlabsForUpload(ctx context.Context, lockingDuration time.Duration, minShards, totalShards uint8, limit int) ([]api.PackedSlab, error)
+		RecordObjectAccesses(ctx context.Context, records []api.ObjectAccessRecord) error
 		RemoveObjects(ctx context.Context, bucket, prefix string) error
+
+		// NOTE: used for tus uploads
+		CreateTusUpload(ctx context.Context, bucket, key string, length uint64, opts api.CreateTusUploadOptions) (api.TusUpload, error)
+		TusUpload(ctx context.Context, uploadID string) (api.TusUpload, error)
+		UpdateTusUploadOffset(ctx context.Context, uploadID string, offset, length uint64) (api.TusUpload, error)
 	}
 
 	SettingStore interface {
+		BandwidthSettings(ctx context.Context) (api.BandwidthSettings, error)
 		GougingParams(ctx context.Context) (api.GougingParams, error)
 		UploadParams(ctx context.Context) (api.UploadParams, error)
 	}
@@ -144,12 +153,17 @@ type Worker struct {
 	uploadManager   *upload.Manager
 	hostManager     hosts.Manager
 
+	downloadLimiter *bandwidth.Limiter
+	uploadLimiter   *bandwidth.Limiter
+
 	accounts *accounts.Manager
 	cache    iworker.WorkerCache
 
 	uploadsMu            sync.Mutex
 	uploadingPackedSlabs map[string]struct{}
+	tusUploads           map[string]struct{}
 
+	accessRecorder           iworker.AccessRecorder
 	contractSpendingRecorder contracts.SpendingRecorder
 
 	shutdownCtx       context.Context
@@ -196,6 +210,7 @@ func (w *Worker) downloadsStatsHandlerGET(jc jape.Context) {
 		AvgOverdrivePct:      math.Floor(stats.AvgOverdrivePct*100*100) / 100,
 		HealthyDownloaders:   stats.HealthyDownloaders,
 		NumDownloaders:       stats.NumDownloaders,
+		NumPrefetchedSlabs:   stats.NumPrefetchedSlabs,
 		DownloadersStats:     dss,
 	})
 }
@@ -225,6 +240,23 @@ func (w *Worker) uploadsStatsHandlerGET(jc jape.Context) {
 	})
 }
 
+func (w *Worker) uploadsHandlerGET(jc jape.Context) {
+	jc.Encode(w.uploadManager.Uploads())
+}
+
+func (w *Worker) uploadHandlerDELETE(jc jape.Context) {
+	var id api.UploadID
+	if jc.DecodeParam("id", &id) != nil {
+		return
+	}
+	err := w.uploadManager.CancelUpload(id)
+	if errors.Is(err, api.ErrUploadNotFound) {
+		jc.Error(err, http.StatusNotFound)
+		return
+	}
+	jc.Check("failed to cancel upload", err)
+}
+
 func (w *Worker) objectHandlerHEAD(jc jape.Context) {
 	// parse bucket
 	var bucket string
@@ -298,7 +330,10 @@ func (w *Worker) objectHandlerGET(jc jape.Context) {
 	}
 
 	dr, err := api.ParseDownloadRange(jc.Request)
-	if errors.Is(err, http_range.ErrInvalid) || errors.Is(err, api.ErrMultiRangeNotSupported) {
+	if errors.Is(err, api.ErrMultiRangeNotSupported) {
+		w.objectRangesHandlerGET(jc, bucket, key)
+		return
+	} else if errors.Is(err, http_range.ErrInvalid) {
 		jc.Error(err, http.StatusBadRequest)
 		return
 	} else if errors.Is(err, http_range.ErrNoOverlap) {
@@ -335,6 +370,34 @@ func (w *Worker) objectHandlerGET(jc jape.Context) {
 	serveContent(jc.ResponseWriter, jc.Request, key, gor.Content, gor.HeadObjectResponse)
 }
 
+func (w *Worker) objectRangesHandlerGET(jc jape.Context, bucket, key string) {
+	gor, err := w.GetObjectRanges(jc.Request.Context(), bucket, key, jc.Request.Header.Get("Range"))
+	if utils.IsErr(err, api.ErrObjectNotFound) {
+		jc.Error(err, http.StatusNotFound)
+		return
+	} else if errors.Is(err, http_range.ErrInvalid) {
+		jc.Error(err, http.StatusBadRequest)
+		return
+	} else if errors.Is(err, http_range.ErrNoOverlap) {
+		jc.Error(err, http.StatusRequestedRangeNotSatisfiable)
+		return
+	} else if jc.Check("couldn't get object", err) != nil {
+		return
+	}
+	defer gor.Content.Close()
+
+	// check whether the caller wants to force a download
+	var dl bool
+	if jc.DecodeForm("dl", &dl) != nil {
+		return
+	} else if dl {
+		jc.ResponseWriter.Header().Set("Content-Disposition", "attachment")
+	}
+
+	// serve the ranges
+	serveContentRanges(jc.ResponseWriter, jc.Request, key, *gor)
+}
+
 func (w *Worker) objectHandlerPUT(jc jape.Context) {
 	jc.Custom((*[]byte)(nil), nil)
 	ctx := jc.Request.Context()
@@ -620,7 +683,10 @@ func New(cfg config.Worker, masterKey [32]byte, b Bus, l *zap.Logger) (*Worker,
 		logger:               l.Sugar(),
 		rhp4Client:           rhp4.New(dialer),
 		startTime:            time.Now(),
+		downloadLimiter:      bandwidth.NewLimiter(cfg.DownloadMaxBandwidth),
+		uploadLimiter:        bandwidth.NewLimiter(cfg.UploadMaxBandwidth),
 		uploadingPackedSlabs: make(map[string]struct{}),
+		tusUploads:           make(map[string]struct{}),
 		shutdownCtx:          shutdownCtx,
 		shutdownCtxCancel:    shutdownCancel,
 	}
@@ -631,6 +697,7 @@ func New(cfg config.Worker, masterKey [32]byte, b Bus, l *zap.Logger) (*Worker,
 
 	uploadKey := w.masterKey.DeriveUploadKey()
 
+	w.accessRecorder = iworker.NewAccessRecorder(w.shutdownCtx, w.bus, cfg.BusFlushInterval, l)
 	w.contractSpendingRecorder = contracts.NewSpendingRecorder(w.shutdownCtx, w.bus, cfg.BusFlushInterval, l)
 	hm := hosts.NewManager(w.masterKey, w.accounts, w.contractSpendingRecorder, dialer, l)
 	w.hostManager = hm
@@ -667,6 +734,15 @@ func (w *Worker) Handler() http.Handler {
 
 		"GET    /stats/downloads": w.downloadsStatsHandlerGET,
 		"GET    /stats/uploads":   w.uploadsStatsHandlerGET,
+
+		"OPTIONS /tus":     w.tusHandlerOPTIONS,
+		"POST    /tus":     w.tusHandlerPOST,
+		"HEAD    /tus/:id": w.tusUploadHandlerHEAD,
+		"PATCH   /tus/:id": w.tusUploadHandlerPATCH,
+		"DELETE  /tus/:id": w.tusUploadHandlerDELETE,
+
+		"GET    /uploads":    w.uploadsHandlerGET,
+		"DELETE /upload/:id": w.uploadHandlerDELETE,
 	})
 }
 
@@ -683,6 +759,7 @@ func (w *Worker) Shutdown(ctx context.Context) error {
 	w.accounts.Shutdown(ctx)
 
 	// stop recorders
+	w.accessRecorder.Stop(ctx)
 	w.contractSpendingRecorder.Stop(ctx)
 
 	return nil
@@ -752,6 +829,12 @@ func (w *Worker) GetObject(ctx context.Context, bucket, key string, opts api.Dow
 	}
 	obj := *res.Object
 
+	// only count reads from the start of the object, otherwise an object that
+	// is streamed using range requests would be counted many times
+	if hor.Range.Offset == 0 {
+		w.accessRecorder.Record(bucket, key)
+	}
+
 	// adjust range
 	if opts.Range == nil {
 		opts.Range = &api.DownloadRange{}
@@ -759,18 +842,6 @@ func (w *Worker) GetObject(ctx context.Context, bucket, key string, opts api.Dow
 	opts.Range.Offset = hor.Range.Offset
 	opts.Range.Length = hor.Range.Length
 
-	// fetch gouging params
-	gp, err := w.bus.GougingParams(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("couldn't fetch gouging parameters from bus: %w", err)
-	}
-
-	// fetch usable hosts
-	hosts, err := w.cache.UsableHosts(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("couldn't fetch contracts from bus: %w", err)
-	}
-
 	// prepare the content
 	var content io.ReadCloser
 	if opts.Range.Length == 0 || obj.TotalSize() == 0 {
@@ -779,19 +850,9 @@ func (w *Worker) GetObject(ctx context.Context, bucket, key string, opts api.Dow
 		content = io.NopCloser(bytes.NewReader(nil))
 	} else {
 		// otherwise return a pipe reader
-		downloadFn := func(wr io.Writer, offset, length int64) error {
-			ctx = gouging.WithChecker(ctx, w.bus, gp)
-			err = w.downloadManager.DownloadObject(ctx, wr, obj, uint64(offset), uint64(length), hosts)
-			if err != nil {
-				w.logger.Error(err)
-				if !errors.Is(err, download.ErrShuttingDown) &&
-					!errors.Is(err, download.ErrDownloadCancelled) &&
-					!errors.Is(err, io.ErrClosedPipe) {
-					w.registerAlert(newDownloadFailedAlert(bucket, key, offset, length, int64(len(hosts)), err))
-				}
-				return fmt.Errorf("failed to download object: %w", err)
-			}
-			return nil
+		downloadFn, err := w.objectDownloadFn(ctx, bucket, key, obj)
+		if err != nil {
+			return nil, err
 		}
 		pr, pw := io.Pipe()
 		go func() {
@@ -807,6 +868,39 @@ func (w *Worker) GetObject(ctx context.Context, bucket, key string, opts api.Dow
 	}, nil
 }
 
+// GetObjectRanges returns the content of multiple ranges of an object. The
+// ranges are parsed from the given "Range" header, overlapping and adjacent
+// ranges are coalesced. The returned content only supports seeking to the
+// start of one of the returned ranges, after which the download of that range
+// is started.
+func (w *Worker) GetObjectRanges(ctx context.Context, bucket, key, rangeHeader string) (*api.GetObjectRangesResponse, error) {
+	// head object
+	hor, res, err := w.headObject(ctx, bucket, key, false, api.HeadObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't fetch object: %w", err)
+	}
+
+	// parse the ranges
+	ranges, err := api.ParseDownloadRanges(rangeHeader, hor.Size)
+	if err != nil {
+		return nil, err
+	} else if len(ranges) > 0 && ranges[0].Offset == 0 {
+		w.accessRecorder.Record(bucket, key)
+	}
+
+	// prepare the content
+	downloadFn, err := w.objectDownloadFn(ctx, bucket, key, *res.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.GetObjectRangesResponse{
+		Content:            newRangesReader(ranges, hor.Size, downloadFn),
+		Ranges:             ranges,
+		HeadObjectResponse: *hor,
+	}, nil
+}
+
 // PinnedObject returns a PinnedObject representation of the object
 // identified by the given bucket and key. It enables users to share
 // objects with other users and retrieve data using external SDKs.
@@ -873,17 +967,25 @@ func (w *Worker) UploadObject(ctx context.Context, r io.Reader, bucket, key stri
 	ctx = gouging.WithChecker(ctx, w.bus, up.GougingParams)
 
 	// fetch host & contract info
-	contracts, err := w.hostContracts(ctx)
+	ps := up.PlacementSettings
+	contracts, err := w.bucketHostContracts(ctx, ps, bucket)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't fetch contracts from bus: %w", err)
 	}
 
+	// packed slabs contain data from multiple buckets, so we can't pack data
+	// of buckets that are pinned to a set of hosts or use a contract set
+	if _, pinned := ps.PinnedHosts(bucket); pinned || ps.ContractSet(bucket) != "" {
+		up.UploadPacking = false
+	}
+
 	// upload
 	eTag, err := w.upload(ctx, bucket, key, up.RedundancySettings, r, contracts,
 		upload.WithBlockHeight(up.CurrentHeight),
 		upload.WithMimeType(opts.MimeType),
 		upload.WithPacking(up.UploadPacking),
 		upload.WithObjectUserMetadata(opts.Metadata),
+		upload.WithDiversityRules(ps.Diversity),
 	)
 	if err != nil {
 		w.logger.With(zap.Error(err)).With("key", key).With("bucket", bucket).Error("failed to upload object")
@@ -910,6 +1012,19 @@ func (w *Worker) UploadMultipartUploadPart(ctx context.Context, r io.Reader, buc
 		return nil, fmt.Errorf("couldn't fetch multipart upload: %w", err)
 	}
 
+	// fetch host & contract info
+	ps := up.PlacementSettings
+	contracts, err := w.bucketHostContracts(ctx, ps, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't fetch contracts from bus: %w", err)
+	}
+
+	// packed slabs contain data from multiple buckets, so we can't pack data
+	// of buckets that are pinned to a set of hosts or use a contract set
+	if _, pinned := ps.PinnedHosts(bucket); pinned || ps.ContractSet(bucket) != "" {
+		up.UploadPacking = false
+	}
+
 	// attach gouging checker to the context
 	ctx = gouging.WithChecker(ctx, w.bus, up.GougingParams)
 
@@ -920,6 +1035,7 @@ func (w *Worker) UploadMultipartUploadPart(ctx context.Context, r io.Reader, buc
 		upload.WithCustomKey(mu.EncryptionKey),
 		upload.WithPartNumber(partNumber),
 		upload.WithUploadID(uploadID),
+		upload.WithDiversityRules(ps.Diversity),
 	}
 
 	// make sure only one of the following is set
@@ -931,12 +1047,6 @@ func (w *Worker) UploadMultipartUploadPart(ctx context.Context, r io.Reader, buc
 		uploadOpts = append(uploadOpts, upload.WithCustomEncryptionOffset(uint64(*opts.EncryptionOffset)))
 	}
 
-	// fetch host & contract info
-	contracts, err := w.hostContracts(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("couldn't fetch contracts from bus: %w", err)
-	}
-
 	// upload
 	eTag, err := w.upload(ctx, bucket, path, up.RedundancySettings, r, contracts, uploadOpts...)
 	if err != nil {
@@ -951,6 +1061,44 @@ func (w *Worker) UploadMultipartUploadPart(ctx context.Context, r io.Reader, buc
 	}, nil
 }
 
+// objectDownloadFn returns a function that downloads a range of the given
+// object.
+func (w *Worker) objectDownloadFn(ctx context.Context, bucket, key string, obj object.Object) (func(wr io.Writer, offset, length int64) error, error) {
+	// fetch gouging params
+	gp, err := w.bus.GougingParams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't fetch gouging parameters from bus: %w", err)
+	}
+
+	// fetch usable hosts
+	hosts, err := w.cache.UsableHosts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't fetch contracts from bus: %w", err)
+	}
+
+	// fetch bandwidth settings
+	bs, err := w.cache.BandwidthSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't fetch bandwidth settings from bus: %w", err)
+	}
+
+	ctx = gouging.WithChecker(ctx, w.bus, gp)
+	return func(wr io.Writer, offset, length int64) error {
+		wr = w.downloadLimiter.Writer(ctx, wr, bucket, bs.Buckets[bucket].MaxDownload)
+		err := w.downloadManager.DownloadObject(ctx, wr, obj, uint64(offset), uint64(length), hosts)
+		if err != nil {
+			w.logger.Error(err)
+			if !errors.Is(err, download.ErrShuttingDown) &&
+				!errors.Is(err, download.ErrDownloadCancelled) &&
+				!errors.Is(err, io.ErrClosedPipe) {
+				w.registerAlert(newDownloadFailedAlert(bucket, key, offset, length, int64(len(hosts)), err))
+			}
+			return fmt.Errorf("failed to download object: %w", err)
+		}
+		return nil
+	}, nil
+}
+
 func (w *Worker) initAccounts(refillInterval time.Duration) (err error) {
 	if w.accounts != nil {
 		panic("priceTables already initialized") // developer error